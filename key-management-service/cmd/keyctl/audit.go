@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// auditQuery は監査イベントの絞り込み条件をクエリ文字列に変換する。
+func auditQuery(since, until, operation string, limit, offset int) (string, error) {
+	q := url.Values{}
+	for name, v := range map[string]string{"since": since, "until": until} {
+		if v == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			return "", fmt.Errorf("--%s must be RFC3339 (e.g. 2025-01-01T00:00:00Z): %w", name, err)
+		}
+		q.Set(name, v)
+	}
+	if operation != "" {
+		q.Set("operation", operation)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+	return q.Encode(), nil
+}
+
+// auditCmd はテナントの監査イベントを表示するコマンド。
+// サーバーでAUDIT_PERSIST=trueが設定されている場合のみ利用できる。
+func auditCmd() *cobra.Command {
+	var tenantID, since, until, operation string
+	var limit, offset int
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "List persisted audit events for a tenant",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			query, err := auditQuery(since, until, operation, limit, offset)
+			if err != nil {
+				return err
+			}
+			url := fmt.Sprintf("%s/v1/tenants/%s/audit", apiURL, tenantID)
+			if query != "" {
+				url += "?" + query
+			}
+			body, err := doRequest(http.MethodGet, url, nil, http.StatusOK)
+			if err != nil {
+				return err
+			}
+
+			var result struct {
+				Events []struct {
+					Operation  string `json:"operation"`
+					TenantID   string `json:"tenant_id"`
+					Generation uint   `json:"generation,omitempty"`
+					Result     string `json:"result"`
+					RequestID  string `json:"request_id,omitempty"`
+					Timestamp  string `json:"timestamp"`
+				} `json:"events"`
+				Limit  int `json:"limit"`
+				Offset int `json:"offset"`
+			}
+			return renderBody(body, &result, func(any) string {
+				var sb strings.Builder
+				fmt.Fprintf(&sb, "%-21s %-20s %-12s %-8s %s\n", "TIMESTAMP", "OPERATION", "GENERATION", "RESULT", "REQUEST_ID")
+				for _, e := range result.Events {
+					fmt.Fprintf(&sb, "%-21s %-20s %-12d %-8s %s\n", e.Timestamp, e.Operation, e.Generation, e.Result, e.RequestID)
+				}
+				return sb.String()
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().StringVar(&since, "since", "", "Only events at or after this time (RFC3339)")
+	cmd.Flags().StringVar(&until, "until", "", "Only events at or before this time (RFC3339)")
+	cmd.Flags().StringVar(&operation, "operation", "", "Only events for this operation (e.g. ROTATE_KEY)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of events (server default: 100)")
+	cmd.Flags().IntVar(&offset, "offset", 0, "Number of events to skip")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"key-management-service/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+// errIntegrityProblems はcheckで不整合が見つかった場合のエラー。終了コード1で終了する。
+var errIntegrityProblems = errors.New("integrity problems found")
+
+// integrityResult はcheckの結果。
+type integrityResult struct {
+	TenantID                   string   `json:"tenant_id"`
+	OK                         bool     `json:"ok"`
+	KeyCount                   int      `json:"key_count"`
+	MaxGeneration              uint     `json:"max_generation"`
+	MissingGenerations         []string `json:"missing_generations"`
+	DuplicateGenerations       []uint   `json:"duplicate_generations"`
+	DuplicateActiveGenerations []uint   `json:"duplicate_active_generations"`
+}
+
+// checkCmd はテナントの鍵の世代の整合性を確認するコマンド。
+// 不整合を報告するのみで修復は行わない。不整合がある場合は終了コード1で終了する。
+func checkCmd() *cobra.Command {
+	var tenantID string
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check a tenant's key generations for gaps and duplicates (exit 1 if any)",
+		Long: "Report missing generations, generations stored more than once, and generations with more than one\n" +
+			"active key for a tenant. Diagnostic only: nothing is modified.\n" +
+			"Connects directly to the database (DATABASE_URL, DB_DRIVER).",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			keyService, err := newDBKeyService()
+			if err != nil {
+				return err
+			}
+			report, err := keyService.CheckTenantIntegrity(context.Background(), tenantID)
+			if errors.Is(err, domain.ErrKeyNotFound) {
+				return fmt.Errorf("tenant %q has no keys", tenantID)
+			}
+			if err != nil {
+				return fmt.Errorf("check failed: %w", err)
+			}
+
+			result := newIntegrityResult(report)
+			if err := render(output, result, func(any) string {
+				return formatIntegrityResult(result)
+			}); err != nil {
+				return err
+			}
+			if !result.OK {
+				// 結果は表示済みのため、終了コードのみで伝える
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return errIntegrityProblems
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
+
+// newIntegrityResult は確認結果を出力形式に変換する。
+func newIntegrityResult(r *domain.IntegrityReport) integrityResult {
+	missing := make([]string, 0, len(r.MissingGenerations))
+	for _, g := range r.MissingGenerations {
+		missing = append(missing, g.String())
+	}
+	return integrityResult{
+		TenantID:                   r.TenantID,
+		OK:                         r.OK(),
+		KeyCount:                   r.KeyCount,
+		MaxGeneration:              r.MaxGeneration,
+		MissingGenerations:         missing,
+		DuplicateGenerations:       append([]uint{}, r.DuplicateGenerations...),
+		DuplicateActiveGenerations: append([]uint{}, r.DuplicateActiveGenerations...),
+	}
+}
+
+// formatIntegrityResult は確認結果をテキスト形式にする。
+func formatIntegrityResult(r integrityResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tenant %q: %d key(s), max generation %d", r.TenantID, r.KeyCount, r.MaxGeneration)
+	if r.OK {
+		b.WriteString("\nOK: no gaps or duplicates")
+		return b.String()
+	}
+	if len(r.MissingGenerations) > 0 {
+		fmt.Fprintf(&b, "\nMissing generations: %s", strings.Join(r.MissingGenerations, ", "))
+	}
+	if len(r.DuplicateGenerations) > 0 {
+		fmt.Fprintf(&b, "\nDuplicate generations: %s", joinGenerations(r.DuplicateGenerations))
+	}
+	if len(r.DuplicateActiveGenerations) > 0 {
+		fmt.Fprintf(&b, "\nGenerations with multiple active keys: %s", joinGenerations(r.DuplicateActiveGenerations))
+	}
+	return b.String()
+}
+
+// joinGenerations は世代番号をカンマ区切りの文字列にする。
+func joinGenerations(gens []uint) string {
+	s := make([]string, len(gens))
+	for i, g := range gens {
+		s[i] = fmt.Sprintf("%d", g)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"key-management-service/internal/domain"
+)
+
+func TestFormatIntegrityResult(t *testing.T) {
+	result := newIntegrityResult(&domain.IntegrityReport{
+		TenantID:                   "tenant-001",
+		KeyCount:                   5,
+		MaxGeneration:              7,
+		MissingGenerations:         []domain.GenerationRange{{From: 1, To: 1}, {From: 4, To: 6}},
+		DuplicateGenerations:       []uint{2},
+		DuplicateActiveGenerations: []uint{2},
+	})
+	out := formatIntegrityResult(result)
+	for _, want := range []string{"max generation 7", "Missing generations: 1, 4-6", "Duplicate generations: 2", "multiple active keys: 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %q in output, got %q", want, out)
+		}
+	}
+
+	ok := formatIntegrityResult(newIntegrityResult(&domain.IntegrityReport{TenantID: "tenant-001", KeyCount: 2, MaxGeneration: 2}))
+	if !strings.Contains(ok, "OK") {
+		t.Errorf("want OK for a consistent tenant, got %q", ok)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// tenantCompletionTimeout は--tenantの補完候補を取得する際のタイムアウト。
+// 補完中にシェルが長く固まらないよう、--timeoutより短くする。
+const tenantCompletionTimeout = 2 * time.Second
+
+// tenantCompletionLimit は--tenantの補完候補として取得するテナント数の上限。
+const tenantCompletionLimit = 1000
+
+// completionCmd はシェル補完スクリプトを標準出力に書き出すコマンド。
+func completionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate the shell completion script",
+		Long: `Generate the completion script for the given shell and write it to stdout.
+
+When KEYCTL_API_URL (or --api-url) is set, the --tenant flag completes
+tenant IDs fetched from the API.
+
+  bash:       source <(keyctl completion bash)
+  zsh:        keyctl completion zsh > "${fpath[1]}/_keyctl"
+  fish:       keyctl completion fish > ~/.config/fish/completions/keyctl.fish
+  powershell: keyctl completion powershell | Out-String | Invoke-Expression`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(stdout)
+			case "fish":
+				return root.GenFishCompletion(stdout, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(stdout)
+			}
+		},
+	}
+}
+
+// registerTenantCompletion は--tenantフラグを持つすべてのコマンドにテナントIDの補完を登録する。
+func registerTenantCompletion(cmd *cobra.Command) {
+	if cmd.Flags().Lookup("tenant") != nil {
+		// 同じコマンドへの二重登録のみが失敗するため、エラーは無視してよい
+		_ = cmd.RegisterFlagCompletionFunc("tenant", completeTenantIDs)
+	}
+	for _, sub := range cmd.Commands() {
+		registerTenantCompletion(sub)
+	}
+}
+
+// completeTenantIDs はAPIのテナント一覧から入力中の文字列で始まるテナントIDを補完候補として返す。
+// 補完時はPersistentPreRunEが実行されないため、APIのURLとHTTPクライアントをここで用意する。
+// APIに接続できない場合は候補を返さない。
+func completeTenantIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	baseURL := apiURL
+	if baseURL == "" {
+		baseURL = os.Getenv("KEYCTL_API_URL")
+	}
+	if baseURL == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client := httpClient
+	if client == nil {
+		client = &http.Client{Timeout: tenantCompletionTimeout}
+	}
+
+	resp, err := client.Get(fmt.Sprintf("%s/v1/tenants?limit=%d", baseURL, tenantCompletionLimit))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var result struct {
+		Tenants []struct {
+			TenantID string `json:"tenant_id"`
+		} `json:"tenants"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ids := make([]string, 0, len(result.Tenants))
+	for _, t := range result.Tenants {
+		if strings.HasPrefix(t.TenantID, toComplete) {
+			ids = append(ids, t.TenantID)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// executeRoot はルートコマンドを引数付きで実行し、標準出力を返す。
+func executeRoot(t *testing.T, args ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	prevURL, prevClient, prevOut := apiURL, httpClient, stdout
+	stdout = &buf
+	t.Cleanup(func() { apiURL, httpClient, stdout = prevURL, prevClient, prevOut })
+
+	root := newRootCmd()
+	root.SetOut(&buf)
+	root.SetArgs(args)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("keyctl %s: %v", strings.Join(args, " "), err)
+	}
+	return buf.String()
+}
+
+func TestCompletionCmd(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			if out := executeRoot(t, "completion", shell); !strings.Contains(out, "keyctl") {
+				t.Errorf("want a completion script for keyctl, got %q", out)
+			}
+		})
+	}
+}
+
+func TestCompletionCmd_RejectsUnknownShell(t *testing.T) {
+	root := newRootCmd()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"completion", "tcsh"})
+	if err := root.Execute(); err == nil {
+		t.Fatal("want an error for an unsupported shell")
+	}
+}
+
+func TestCompleteTenantIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tenants" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"tenants":[{"tenant_id":"tenant-001","key_count":1},{"tenant_id":"tenant-002","key_count":2},{"tenant_id":"other","key_count":1}]}`)
+	}))
+	defer server.Close()
+	t.Setenv("KEYCTL_API_URL", server.URL)
+
+	out := executeRoot(t, "__complete", "get", "--tenant", "ten")
+	if !strings.Contains(out, "tenant-001\ntenant-002\n") || strings.Contains(out, "other") {
+		t.Errorf("want tenant-001 and tenant-002 as candidates, got %q", out)
+	}
+}
+
+func TestCompleteTenantIDs_WithoutAPIURL(t *testing.T) {
+	t.Setenv("KEYCTL_API_URL", "")
+
+	out := executeRoot(t, "__complete", "get", "--tenant", "")
+	if strings.Contains(out, "tenant") {
+		t.Errorf("want no candidates without an API URL, got %q", out)
+	}
+}
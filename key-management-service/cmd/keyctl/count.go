@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+// countCmd はテナントの鍵数を表示するコマンド。
+func countCmd() *cobra.Command {
+	var tenantID string
+	cmd := &cobra.Command{
+		Use:   "count",
+		Short: "Count keys for a tenant by status",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			url := fmt.Sprintf("%s/v1/tenants/%s/keys/count", apiURL, tenantID)
+			body, err := doRequest(http.MethodGet, url, nil, http.StatusOK)
+			if err != nil {
+				return err
+			}
+
+			var result struct {
+				TenantID   string `json:"tenant_id"`
+				Active     int    `json:"active"`
+				Deprecated int    `json:"deprecated"`
+				Disabled   int    `json:"disabled"`
+				Total      int    `json:"total"`
+			}
+			return renderBody(body, &result, func(any) string {
+				return fmt.Sprintf("%-10s %-11s %-10s %s\n%-10d %-11d %-10d %d",
+					"ACTIVE", "DEPRECATED", "DISABLED", "TOTAL", result.Active, result.Deprecated, result.Disabled, result.Total)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+// deprecateCmd は鍵を非推奨にするコマンド。
+// 非推奨の鍵は既存データの復号には使えるが、現在の鍵には選ばれない。
+func deprecateCmd() *cobra.Command {
+	var tenantID string
+	var generation uint
+	cmd := &cobra.Command{
+		Use:   "deprecate",
+		Short: "Deprecate a key so that it only decrypts existing data",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" || generation == 0 {
+				return fmt.Errorf("--tenant and --generation are required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			url := fmt.Sprintf("%s/v1/tenants/%s/keys/%d/deprecate", apiURL, tenantID, generation)
+			body, err := doRequest(http.MethodPost, url, nil, http.StatusOK)
+			if err != nil {
+				return err
+			}
+
+			var result keyMetadataResult
+			return renderBody(body, &result, func(any) string {
+				return fmt.Sprintf("Deprecated key for tenant %q (generation: %d, status: %s)",
+					result.TenantID, result.Generation, result.Status)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().UintVar(&generation, "generation", 0, "Key generation (required)")
+	for _, name := range []string{"tenant", "generation"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+		}
+	}
+	return cmd
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// errEnvironmentsDiffer はdiffで差分が見つかった場合のエラー。終了コード1で終了する。
+var errEnvironmentsDiffer = errors.New("environments differ")
+
+// 差分の種類。
+const (
+	diffOnlyInSource   = "only_in_source"
+	diffOnlyInTarget   = "only_in_target"
+	diffStatusMismatch = "status_mismatch"
+)
+
+// environmentKeys は環境ごとのテナント→世代→ステータス。鍵本体は含めない。
+type environmentKeys map[string]map[uint]string
+
+// keyDiff は2つの環境の間の1世代分の差分。
+type keyDiff struct {
+	TenantID     string `json:"tenant_id"`
+	Generation   uint   `json:"generation"`
+	Change       string `json:"change"`
+	SourceStatus string `json:"source_status,omitempty"`
+	TargetStatus string `json:"target_status,omitempty"`
+}
+
+// diffResult はdiffの結果。
+type diffResult struct {
+	SourceURL   string    `json:"source_url"`
+	TargetURL   string    `json:"target_url"`
+	Tenants     int       `json:"tenants"`
+	Differences []keyDiff `json:"differences"`
+}
+
+// diffCmd は2つの環境のテナント・世代・ステータスを比較するコマンド。
+// 鍵一覧のAPI（メタデータのみ）を使用するため、鍵本体は取得・表示しない。差分がある場合は終了コード1で終了する。
+func diffCmd() *cobra.Command {
+	var sourceURL, targetURL string
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare tenants and key generations between two environments (exit 1 if they differ)",
+		Long: "List tenants and generations present in only one of the two environments and generations whose\n" +
+			"status differs. Only key metadata is fetched; key material is never requested or printed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if sourceURL == "" || targetURL == "" {
+				return fmt.Errorf("--source-url and --target-url are required")
+			}
+			source, err := fetchEnvironmentKeys(sourceURL)
+			if err != nil {
+				return fmt.Errorf("source %s: %w", sourceURL, err)
+			}
+			target, err := fetchEnvironmentKeys(targetURL)
+			if err != nil {
+				return fmt.Errorf("target %s: %w", targetURL, err)
+			}
+
+			result := diffResult{
+				SourceURL:   sourceURL,
+				TargetURL:   targetURL,
+				Tenants:     countTenants(source, target),
+				Differences: diffEnvironments(source, target),
+			}
+			if err := render(output, result, func(any) string {
+				return formatDiffResult(result)
+			}); err != nil {
+				return err
+			}
+			if len(result.Differences) > 0 {
+				// 結果は表示済みのため、終了コードのみで伝える
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return errEnvironmentsDiffer
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&sourceURL, "source-url", "", "API endpoint URL of the source environment (required)")
+	cmd.Flags().StringVar(&targetURL, "target-url", "", "API endpoint URL of the target environment (required)")
+	for _, name := range []string{"source-url", "target-url"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+		}
+	}
+	return cmd
+}
+
+// fetchEnvironmentKeys はbaseURLの環境の全テナントの世代とステータスを取得する。
+func fetchEnvironmentKeys(baseURL string) (environmentKeys, error) {
+	tenants, err := listAllTenantsAt(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	env := make(environmentKeys, len(tenants))
+	for _, tenantID := range tenants {
+		body, err := doRequest(http.MethodGet, fmt.Sprintf("%s/v1/tenants/%s/keys", baseURL, tenantID), nil, http.StatusOK)
+		if err != nil {
+			return nil, fmt.Errorf("listing keys of tenant %q: %w", tenantID, err)
+		}
+		var list keyListResult
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+		gens := make(map[uint]string, len(list.Keys))
+		for _, k := range list.Keys {
+			gens[k.Generation] = k.Status
+		}
+		env[tenantID] = gens
+	}
+	return env, nil
+}
+
+// diffEnvironments はsourceとtargetの差分をテナントID・世代の順で返す。
+func diffEnvironments(source, target environmentKeys) []keyDiff {
+	diffs := []keyDiff{}
+	for tenantID, srcGens := range source {
+		dstGens := target[tenantID]
+		for gen, srcStatus := range srcGens {
+			dstStatus, ok := dstGens[gen]
+			switch {
+			case !ok:
+				diffs = append(diffs, keyDiff{TenantID: tenantID, Generation: gen, Change: diffOnlyInSource, SourceStatus: srcStatus})
+			case dstStatus != srcStatus:
+				diffs = append(diffs, keyDiff{TenantID: tenantID, Generation: gen, Change: diffStatusMismatch, SourceStatus: srcStatus, TargetStatus: dstStatus})
+			}
+		}
+	}
+	for tenantID, dstGens := range target {
+		srcGens := source[tenantID]
+		for gen, dstStatus := range dstGens {
+			if _, ok := srcGens[gen]; !ok {
+				diffs = append(diffs, keyDiff{TenantID: tenantID, Generation: gen, Change: diffOnlyInTarget, TargetStatus: dstStatus})
+			}
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].TenantID != diffs[j].TenantID {
+			return diffs[i].TenantID < diffs[j].TenantID
+		}
+		return diffs[i].Generation < diffs[j].Generation
+	})
+	return diffs
+}
+
+// countTenants はいずれかの環境に存在するテナントの数を返す。
+func countTenants(source, target environmentKeys) int {
+	n := len(source)
+	for tenantID := range target {
+		if _, ok := source[tenantID]; !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// formatDiffResult は比較結果をテキスト形式にする。
+func formatDiffResult(r diffResult) string {
+	if len(r.Differences) == 0 {
+		return fmt.Sprintf("No differences across %d tenant(s)", r.Tenants)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-64s %-12s %-16s %-10s %s\n", "TENANT", "GENERATION", "CHANGE", "SOURCE", "TARGET")
+	for _, d := range r.Differences {
+		fmt.Fprintf(&b, "%-64s %-12d %-16s %-10s %s\n", d.TenantID, d.Generation, d.Change, orDash(d.SourceStatus), orDash(d.TargetStatus))
+	}
+	fmt.Fprintf(&b, "%d difference(s) across %d tenant(s)", len(r.Differences), r.Tenants)
+	return b.String()
+}
+
+// orDash は空文字列を"-"に置き換える。
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffEnvironments(t *testing.T) {
+	source := environmentKeys{
+		"tenant-a": {1: "disabled", 2: "active"},
+		"tenant-b": {1: "active"},
+	}
+	target := environmentKeys{
+		"tenant-a": {1: "active", 2: "active", 3: "active"},
+		"tenant-c": {1: "active"},
+	}
+
+	got := diffEnvironments(source, target)
+	want := []keyDiff{
+		{TenantID: "tenant-a", Generation: 1, Change: diffStatusMismatch, SourceStatus: "disabled", TargetStatus: "active"},
+		{TenantID: "tenant-a", Generation: 3, Change: diffOnlyInTarget, TargetStatus: "active"},
+		{TenantID: "tenant-b", Generation: 1, Change: diffOnlyInSource, SourceStatus: "active"},
+		{TenantID: "tenant-c", Generation: 1, Change: diffOnlyInTarget, TargetStatus: "active"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffEnvironments() =\n%+v\nwant\n%+v", got, want)
+	}
+	if n := countTenants(source, target); n != 3 {
+		t.Errorf("want 3 tenants, got %d", n)
+	}
+	if diffs := diffEnvironments(source, source); len(diffs) != 0 {
+		t.Errorf("want no differences for identical environments, got %+v", diffs)
+	}
+}
+
+func TestDiffCmd_NeverRequestsKeyMaterial(t *testing.T) {
+	newEnv := func(keys string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/v1/tenants":
+				_, _ = w.Write([]byte(`{"tenants":[{"tenant_id":"tenant-a","key_count":1}]}`))
+			case "/v1/tenants/tenant-a/keys":
+				_, _ = w.Write([]byte(keys))
+			default:
+				t.Errorf("unexpected request: %s", r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	source := newEnv(`{"keys":[{"tenant_id":"tenant-a","generation":1,"status":"active"}]}`)
+	defer source.Close()
+	target := newEnv(`{"keys":[{"tenant_id":"tenant-a","generation":1,"status":"disabled"}]}`)
+	defer target.Close()
+
+	prevURL, prevClient, prevOut := apiURL, httpClient, stdout
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { apiURL, httpClient, stdout = prevURL, prevClient, prevOut }()
+
+	root := newRootCmd()
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs([]string{"diff", "--source-url", source.URL, "--target-url", target.URL})
+	if err := root.Execute(); !errors.Is(err, errEnvironmentsDiffer) {
+		t.Fatalf("want errEnvironmentsDiffer, got %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, diffStatusMismatch) || !strings.Contains(out, "1 difference(s)") {
+		t.Errorf("want a status mismatch in output, got:\n%s", out)
+	}
+}
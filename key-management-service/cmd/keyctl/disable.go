@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// disableCmd は鍵の無効化コマンド。
+// --generationで1世代、--from/--toで範囲内の世代を一括で無効化する。
+func disableCmd() *cobra.Command {
+	var tenantID string
+	var generation uint
+	var from, to uint
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Disable a key (or a range of generations) for a tenant",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			useRange := cmd.Flags().Changed("from") || cmd.Flags().Changed("to")
+			if useRange && generation != 0 {
+				return fmt.Errorf("--generation cannot be combined with --from/--to")
+			}
+			if !useRange && generation == 0 {
+				return fmt.Errorf("--generation or --from/--to is required")
+			}
+			if useRange && (from == 0 || to == 0) {
+				return fmt.Errorf("both --from and --to are required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+			if useRange {
+				return disableRange(tenantID, from, to, reason)
+			}
+
+			url := fmt.Sprintf("%s/v1/tenants/%s/keys/%d", apiURL, tenantID, generation)
+			var reqBody io.Reader
+			if reason != "" {
+				payload, err := json.Marshal(map[string]string{"reason": reason})
+				if err != nil {
+					return fmt.Errorf("encoding request: %w", err)
+				}
+				reqBody = bytes.NewReader(payload)
+			}
+			body, err := doRequest(http.MethodDelete, url, reqBody, http.StatusAccepted)
+			if err != nil {
+				return err
+			}
+
+			// サーバーが返す無効化後の保存値を表示する
+			var result keyMetadataResult
+			return renderBody(body, &result, func(any) string {
+				text := fmt.Sprintf("Disabled key for tenant %q (generation: %d, status: %s, disabled_at: %s)",
+					result.TenantID, result.Generation, result.Status, result.DisabledAt)
+				if result.DisabledReason != "" {
+					text += fmt.Sprintf("\nReason: %s", result.DisabledReason)
+				}
+				return text
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().UintVar(&generation, "generation", 0, "Key generation")
+	cmd.Flags().UintVar(&from, "from", 0, "First generation of the range to disable (inclusive, used with --to)")
+	cmd.Flags().UintVar(&to, "to", 0, "Last generation of the range to disable (inclusive, used with --from)")
+	cmd.Flags().StringVar(&reason, "reason", "", "Reason for disabling the key (recorded with the key)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
+
+// disableBatchResult は鍵一括無効化のレスポンス形式。
+type disableBatchResult struct {
+	TenantID string `json:"tenant_id"`
+	Results  []struct {
+		Generation uint   `json:"generation"`
+		Status     string `json:"status"`
+	} `json:"results"`
+}
+
+// disableRange はfromからtoまでの世代を一括で無効化し、世代ごとの結果を表示する。
+func disableRange(tenantID string, from, to uint, reason string) error {
+	payload, err := json.Marshal(map[string]any{"from": from, "to": to, "reason": reason})
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	url := fmt.Sprintf("%s/v1/tenants/%s/keys/disable-batch", apiURL, tenantID)
+	body, err := doRequest(http.MethodPost, url, bytes.NewReader(payload), http.StatusOK)
+	if err != nil {
+		return err
+	}
+
+	var result disableBatchResult
+	return renderBody(body, &result, func(any) string {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%-12s %s\n", "GENERATION", "STATUS")
+		for _, r := range result.Results {
+			fmt.Fprintf(&sb, "%-12d %s\n", r.Generation, r.Status)
+		}
+		return sb.String()
+	})
+}
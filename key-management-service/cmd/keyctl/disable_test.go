@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDisableRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/tenants/tenant-a/keys/disable-batch" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req struct {
+			From   uint   `json:"from"`
+			To     uint   `json:"to"`
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.From != 1 || req.To != 3 || req.Reason != "compromised" {
+			t.Errorf("unexpected request body: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tenant_id":"tenant-a","results":[
+			{"generation":1,"status":"disabled"},
+			{"generation":2,"status":"already_disabled"},
+			{"generation":3,"status":"not_found"}]}`))
+	}))
+	defer server.Close()
+
+	prevURL, prevClient, prevOut := apiURL, httpClient, stdout
+	var buf bytes.Buffer
+	apiURL, httpClient, stdout = server.URL, server.Client(), &buf
+	defer func() { apiURL, httpClient, stdout = prevURL, prevClient, prevOut }()
+
+	if err := disableRange("tenant-a", 1, 3, "compromised"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"already_disabled", "not_found"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorの確認結果。
+const (
+	doctorPass = "PASS"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+	doctorSkip = "SKIP"
+)
+
+// errDoctorFailed はdoctorで必須の確認に1件以上失敗した場合のエラー。
+var errDoctorFailed = errors.New("one or more critical checks failed")
+
+// doctorCheck はdoctorの確認項目1件の結果。
+// Criticalな項目がFAILの場合のみ終了コードを非0とする。
+type doctorCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail"`
+	Critical bool   `json:"critical"`
+}
+
+// doctorReport はdoctorの結果全体。
+type doctorReport struct {
+	APIURL string        `json:"api_url"`
+	Checks []doctorCheck `json:"checks"`
+	OK     bool          `json:"ok"`
+}
+
+// add は確認結果を追加する。
+func (r *doctorReport) add(name, status, detail string, critical bool) {
+	r.Checks = append(r.Checks, doctorCheck{Name: name, Status: status, Detail: detail, Critical: critical})
+	if critical && status == doctorFail {
+		r.OK = false
+	}
+}
+
+// doctorCmd は接続先と設定を確認するコマンド。
+func doctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check API connectivity, TLS, readiness and authentication",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report := runDoctor()
+			if err := render(output, report, func(any) string { return formatDoctorReport(report) }); err != nil {
+				return err
+			}
+			if !report.OK {
+				// 結果は表示済みのため、終了コードのみで伝える
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return errDoctorFailed
+			}
+			return nil
+		},
+	}
+}
+
+// runDoctor は設定済みのAPI URLとHTTPクライアントで各項目を順に確認する。
+// 接続自体に失敗した場合、以降のAPIの確認はSKIPとする。
+func runDoctor() doctorReport {
+	report := doctorReport{APIURL: apiURL, OK: true}
+
+	if apiURL == "" {
+		report.add("api-url", doctorFail, "not set (use --api-url or KEYCTL_API_URL)", true)
+		return report
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		report.add("api-url", doctorFail, fmt.Sprintf("%q is not an http(s) URL", apiURL), true)
+		return report
+	}
+	report.add("api-url", doctorPass, apiURL, true)
+
+	if u.Scheme == "https" {
+		report.add("tls", doctorPass, "using HTTPS", false)
+	} else {
+		report.add("tls", doctorWarn, "using plain HTTP; keys are sent unencrypted", false)
+	}
+
+	status, _, err := doctorGet("/healthz")
+	switch {
+	case err != nil:
+		report.add("healthz", doctorFail, describeConnError(err), true)
+		for _, name := range []string{"readyz", "auth", "version"} {
+			report.add(name, doctorSkip, "server is not reachable", false)
+		}
+		return report
+	case status != http.StatusOK:
+		report.add("healthz", doctorFail, fmt.Sprintf("unexpected status %d", status), true)
+	default:
+		report.add("healthz", doctorPass, "server is reachable", true)
+	}
+
+	status, _, err = doctorGet("/readyz")
+	switch {
+	case err != nil:
+		report.add("readyz", doctorFail, describeConnError(err), true)
+	case status == http.StatusServiceUnavailable:
+		report.add("readyz", doctorFail, "server is not ready (database unavailable)", true)
+	case status != http.StatusOK:
+		report.add("readyz", doctorFail, fmt.Sprintf("unexpected status %d", status), true)
+	default:
+		report.add("readyz", doctorPass, "server is ready", true)
+	}
+
+	// 副作用のない一覧取得で認証を確認する
+	status, _, err = doctorGet("/v1/tenants?limit=1")
+	switch {
+	case err != nil:
+		report.add("auth", doctorFail, describeConnError(err), true)
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		report.add("auth", doctorFail, fmt.Sprintf("request was rejected with status %d; check credentials", status), true)
+	case status != http.StatusOK:
+		report.add("auth", doctorFail, fmt.Sprintf("unexpected status %d", status), true)
+	default:
+		report.add("auth", doctorPass, "API request succeeded", true)
+	}
+
+	status, body, err := doctorGet("/version")
+	var server buildInfoResult
+	switch {
+	case err != nil:
+		report.add("version", doctorWarn, describeConnError(err), false)
+	case status != http.StatusOK || json.Unmarshal(body, &server) != nil:
+		report.add("version", doctorWarn, fmt.Sprintf("could not read server version (status %d)", status), false)
+	case server.Version != version:
+		report.add("version", doctorWarn, fmt.Sprintf("client %s differs from server %s", version, server.Version), false)
+	default:
+		report.add("version", doctorPass, fmt.Sprintf("client and server are %s", version), false)
+	}
+	return report
+}
+
+// doctorGet はAPIにGETリクエストを送り、ステータスコードとボディを返す。
+// ステータスコードによらずエラーとしないため、doRequestは使わない。
+func doctorGet(path string) (int, []byte, error) {
+	resp, err := httpClient.Get(apiURL + path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("reading response: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// describeConnError は接続エラーを運用者向けの説明に変換する。TLSの検証エラーは区別して表示する。
+func describeConnError(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var certInvalid x509.CertificateInvalidError
+	var verification *tls.CertificateVerificationError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostname) ||
+		errors.As(err, &certInvalid) || errors.As(err, &verification) {
+		return fmt.Sprintf("TLS verification failed: %v", err)
+	}
+	return fmt.Sprintf("cannot connect: %v", err)
+}
+
+// formatDoctorReport はdoctorの結果をチェックリスト形式のテキストにする。
+func formatDoctorReport(report doctorReport) string {
+	var b strings.Builder
+	for _, c := range report.Checks {
+		fmt.Fprintf(&b, "[%s] %-8s %s\n", c.Status, c.Name, c.Detail)
+	}
+	if report.OK {
+		b.WriteString("All critical checks passed")
+	} else {
+		b.WriteString("One or more critical checks failed")
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newDoctorServer はdoctorの確認対象となるテスト用サーバーを起動する。
+// authorizedがfalseの場合、APIは401を返す。
+func newDoctorServer(t *testing.T, authorized bool) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/healthz":
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case "/readyz":
+			fmt.Fprint(w, `{"status":"ready"}`)
+		case "/version":
+			fmt.Fprintf(w, `{"version":%q}`, version)
+		case "/v1/tenants":
+			if !authorized {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"code":"UNAUTHENTICATED","message":"missing credentials"}`)
+				return
+			}
+			fmt.Fprint(w, `{"tenants":[]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	prevURL, prevClient := apiURL, httpClient
+	apiURL, httpClient = server.URL, server.Client()
+	t.Cleanup(func() { apiURL, httpClient = prevURL, prevClient })
+}
+
+// checkStatuses は確認項目名ごとの結果を返す。
+func checkStatuses(report doctorReport) map[string]string {
+	statuses := make(map[string]string, len(report.Checks))
+	for _, c := range report.Checks {
+		statuses[c.Name] = c.Status
+	}
+	return statuses
+}
+
+func TestRunDoctor_Healthy(t *testing.T) {
+	newDoctorServer(t, true)
+
+	report := runDoctor()
+	if !report.OK {
+		t.Fatalf("want OK, got %+v", report.Checks)
+	}
+	statuses := checkStatuses(report)
+	for _, name := range []string{"api-url", "healthz", "readyz", "auth", "version"} {
+		if statuses[name] != doctorPass {
+			t.Errorf("%s: want PASS, got %s", name, statuses[name])
+		}
+	}
+	// httptest.Serverは平文HTTPのため警告のみ
+	if statuses["tls"] != doctorWarn {
+		t.Errorf("tls: want WARN, got %s", statuses["tls"])
+	}
+}
+
+func TestRunDoctor_Unauthenticated(t *testing.T) {
+	newDoctorServer(t, false)
+
+	report := runDoctor()
+	if report.OK {
+		t.Fatal("want failure when the API rejects credentials")
+	}
+	statuses := checkStatuses(report)
+	if statuses["auth"] != doctorFail {
+		t.Errorf("auth: want FAIL, got %s", statuses["auth"])
+	}
+	if statuses["healthz"] != doctorPass || statuses["readyz"] != doctorPass {
+		t.Errorf("want healthz and readyz to pass, got %+v", statuses)
+	}
+}
+
+func TestRunDoctor_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	unreachable := server.URL
+	server.Close()
+
+	prevURL, prevClient := apiURL, httpClient
+	apiURL, httpClient = unreachable, &http.Client{}
+	t.Cleanup(func() { apiURL, httpClient = prevURL, prevClient })
+
+	report := runDoctor()
+	if report.OK {
+		t.Fatal("want failure when the server is not reachable")
+	}
+	statuses := checkStatuses(report)
+	if statuses["healthz"] != doctorFail || statuses["auth"] != doctorSkip {
+		t.Errorf("want healthz FAIL and auth SKIP, got %+v", statuses)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// errTenantHasNoKeys はexistsでテナントに鍵が存在しなかった場合のエラー。終了コード1で終了する。
+var errTenantHasNoKeys = errors.New("tenant has no keys")
+
+// existsCmd はテナントに鍵が存在するかを表示するコマンド。
+// 存在する場合はtrueを表示して終了コード0、存在しない場合はfalseを表示して終了コード1で終了する。
+func existsCmd() *cobra.Command {
+	var tenantID string
+	cmd := &cobra.Command{
+		Use:   "exists",
+		Short: "Check whether a tenant has any key (exit 0 if true, 1 if false)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			exists, err := tenantExists(tenantID)
+			if err != nil {
+				return err
+			}
+			result := struct {
+				TenantID string `json:"tenant_id"`
+				Exists   bool   `json:"exists"`
+			}{TenantID: tenantID, Exists: exists}
+			if err := render(output, result, func(any) string {
+				return fmt.Sprintf("%t", exists)
+			}); err != nil {
+				return err
+			}
+			if !exists {
+				// 結果は表示済みのため、終了コードのみで伝える
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return errTenantHasNoKeys
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
+
+// tenantExists はHEAD /v1/tenants/{tenant_id}/keys でテナントに鍵が存在するかを確認する。
+func tenantExists(tenantID string) (bool, error) {
+	url := fmt.Sprintf("%s/v1/tenants/%s/keys", apiURL, tenantID)
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("API request failed: %w", err)
+	}
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", closeErr)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		// HEADのレスポンスにはボディがないため、ステータスコードのみで報告する
+		return false, handleErrorResponse(resp.StatusCode, nil)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTenantExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("want HEAD, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/v1/tenants/tenant-a/keys":
+			w.WriteHeader(http.StatusOK)
+		case "/v1/tenants/tenant-b/keys":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	prevURL, prevClient := apiURL, httpClient
+	apiURL, httpClient = server.URL, server.Client()
+	defer func() { apiURL, httpClient = prevURL, prevClient }()
+
+	if exists, err := tenantExists("tenant-a"); err != nil || !exists {
+		t.Errorf("tenant-a: want true, got %v (err: %v)", exists, err)
+	}
+	if exists, err := tenantExists("tenant-b"); err != nil || exists {
+		t.Errorf("tenant-b: want false, got %v (err: %v)", exists, err)
+	}
+	if _, err := tenantExists("tenant-c"); err == nil {
+		t.Error("tenant-c: want error for status 500")
+	}
+}
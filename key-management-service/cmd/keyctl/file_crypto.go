@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// 暗号化ファイルの形式:
+//
+//	ヘッダー（21バイト）: マジック "KMSF" | 形式(1) | 世代(uint32) | チャンクサイズ(uint32) | nonceプレフィックス(8)
+//	本体: チャンクごとのAES-GCM暗号文（各チャンクは平文チャンクサイズ+16バイト、最後のチャンクのみ短い）
+//
+// 形式は1が非圧縮、2がgzipで圧縮した平文を暗号化したもの。復号時は形式に従って透過的に展開する。
+// 各チャンクのnonceは nonceプレフィックス | チャンク番号(uint32) で、最後のチャンクはプレフィックスの先頭ビットを反転する。
+// ヘッダー全体を追加認証データとするため、世代・チャンクサイズの改ざんやチャンクの入れ替え・切り詰めは復号時に検出される。
+const (
+	fileMagic          = "KMSF"
+	fileFormatPlain    = 1
+	fileFormatGzip     = 2
+	fileHeaderSize     = 4 + 1 + 4 + 4 + 8
+	defaultFileChunk   = 64 * 1024
+	maxFileChunk       = 16 * 1024 * 1024
+	fileNoncePrefixLen = 8
+)
+
+// errInvalidEncryptedFile は暗号化ファイルの形式が不正な場合のエラー。
+var errInvalidEncryptedFile = errors.New("not a keyctl encrypted file")
+
+// fileCryptoResult はファイルの暗号化・復号結果の出力形式。
+type fileCryptoResult struct {
+	TenantID   string `json:"tenant_id"`
+	Generation uint   `json:"generation"`
+	Input      string `json:"input"`
+	Output     string `json:"output"`
+	Bytes      int64  `json:"bytes"`
+}
+
+// encryptFileCmd はテナントの鍵でローカルファイルを暗号化するコマンド。
+func encryptFileCmd() *cobra.Command {
+	var tenantID, in, out string
+	var generation uint
+	var compress bool
+	cmd := &cobra.Command{
+		Use:   "encrypt-file",
+		Short: "Encrypt a local file with a tenant's AES key (AES-GCM, processed in chunks)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+			key, err := fetchKey(tenantID, generation)
+			if err != nil {
+				return err
+			}
+			defer clear(key.material)
+
+			n, err := transformFile(in, out, func(w io.Writer, r io.Reader) (int64, error) {
+				return encryptStream(w, r, key.material, key.Generation, defaultFileChunk, compress)
+			})
+			if err != nil {
+				return err
+			}
+			result := fileCryptoResult{TenantID: tenantID, Generation: key.Generation, Input: in, Output: out, Bytes: n}
+			return render(output, result, func(any) string {
+				return fmt.Sprintf("Encrypted %s (%d bytes) with generation %d to %s", in, n, key.Generation, out)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().UintVar(&generation, "generation", 0, "Key generation (optional, defaults to current)")
+	cmd.Flags().StringVar(&in, "in", "", "Plaintext file to encrypt (required)")
+	cmd.Flags().StringVar(&out, "out", "", "Path to write the encrypted file (required)")
+	cmd.Flags().BoolVar(&compress, "compress", false, "Compress the plaintext with gzip before encrypting (decrypt-file inflates it automatically)")
+	for _, name := range []string{"tenant", "in", "out"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+		}
+	}
+	return cmd
+}
+
+// decryptFileCmd はencrypt-fileで暗号化したファイルを復号するコマンド。
+// 暗号化に使用した世代はファイルのヘッダーから読み取る。
+func decryptFileCmd() *cobra.Command {
+	var tenantID, in, out string
+	cmd := &cobra.Command{
+		Use:   "decrypt-file",
+		Short: "Decrypt a file produced by encrypt-file with the tenant's key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+			var generation uint
+			n, err := transformFile(in, out, func(w io.Writer, r io.Reader) (int64, error) {
+				return decryptStream(w, r, func(gen uint) ([]byte, error) {
+					generation = gen
+					key, err := fetchKey(tenantID, gen)
+					if err != nil {
+						return nil, err
+					}
+					return key.material, nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+			result := fileCryptoResult{TenantID: tenantID, Generation: generation, Input: in, Output: out, Bytes: n}
+			return render(output, result, func(any) string {
+				return fmt.Sprintf("Decrypted %s (%d bytes) with generation %d to %s", in, n, generation, out)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().StringVar(&in, "in", "", "Encrypted file to decrypt (required)")
+	cmd.Flags().StringVar(&out, "out", "", "Path to write the plaintext (required)")
+	for _, name := range []string{"tenant", "in", "out"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+		}
+	}
+	return cmd
+}
+
+// fetchedKey はAPIから取得した鍵。materialはデコード済みの鍵素材。
+type fetchedKey struct {
+	keyResult
+	material []byte
+}
+
+// fetchKey はテナントの鍵を取得する。generationが0の場合は現在の鍵を取得する。
+// ファイルの暗号化に使用するため、AES鍵以外はエラーとする。
+func fetchKey(tenantID string, generation uint) (*fetchedKey, error) {
+	url := fmt.Sprintf("%s/v1/tenants/%s/keys/current", apiURL, tenantID)
+	if generation > 0 {
+		url = fmt.Sprintf("%s/v1/tenants/%s/keys/%d", apiURL, tenantID, generation)
+	}
+	body, err := doRequest(http.MethodGet, url, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var key fetchedKey
+	if err := json.Unmarshal(body, &key.keyResult); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	if key.KeyType != "" && key.KeyType != "aes" {
+		return nil, fmt.Errorf("generation %d is a %s key; only aes keys can encrypt files", key.Generation, key.KeyType)
+	}
+	if key.material, err = base64.StdEncoding.DecodeString(key.Key); err != nil {
+		return nil, fmt.Errorf("decoding key: %w", err)
+	}
+	return &key, nil
+}
+
+// transformFile はinを読み込んでfnで変換した結果をoutに書き込む。
+// 途中で失敗した場合に不完全なファイルを残さないよう、同じディレクトリの一時ファイルに書き込んでから置き換える。
+func transformFile(in, out string, fn func(w io.Writer, r io.Reader) (int64, error)) (int64, error) {
+	src, err := os.Open(in)
+	if err != nil {
+		return 0, fmt.Errorf("opening input: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	tmp, err := os.CreateTemp(filepath.Dir(out), "."+filepath.Base(out)+".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("creating output: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	w := bufio.NewWriter(tmp)
+	n, err := fn(w, bufio.NewReader(src))
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), out); err != nil {
+		return 0, fmt.Errorf("writing output: %w", err)
+	}
+	return n, nil
+}
+
+// chunkNonce はチャンク番号と最後のチャンクかどうかからnonceを生成する。
+func chunkNonce(prefix []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	if last {
+		nonce[0] ^= 0x80
+	}
+	binary.BigEndian.PutUint32(nonce[fileNoncePrefixLen:], index)
+	return nonce
+}
+
+// encryptStream はrの平文をchunkSizeごとにAES-GCMで暗号化してwに書き込み、平文のバイト数を返す。
+// compressの場合は平文をgzipで圧縮してから暗号化する。
+func encryptStream(w io.Writer, r io.Reader, key []byte, generation uint, chunkSize int, compress bool) (int64, error) {
+	aead, err := newFileAEAD(key)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := &countingReader{r: r}
+	var src io.Reader = counter
+	format := byte(fileFormatPlain)
+	if compress {
+		format = fileFormatGzip
+		pr, pw := io.Pipe()
+		defer func() { _ = pr.Close() }()
+		go func() {
+			gz := gzip.NewWriter(pw)
+			_, err := io.Copy(gz, counter)
+			if err == nil {
+				err = gz.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+		src = pr
+	}
+
+	header := make([]byte, fileHeaderSize)
+	copy(header, fileMagic)
+	header[4] = format
+	binary.BigEndian.PutUint32(header[5:], uint32(generation))
+	binary.BigEndian.PutUint32(header[9:], uint32(chunkSize))
+	prefix := header[13:]
+	if _, err := rand.Read(prefix); err != nil {
+		return 0, fmt.Errorf("generating nonce: %w", err)
+	}
+	if _, err := w.Write(header); err != nil {
+		return 0, fmt.Errorf("writing output: %w", err)
+	}
+
+	br := bufio.NewReaderSize(src, chunkSize)
+	plain := make([]byte, chunkSize)
+	sealed := make([]byte, 0, chunkSize+aead.Overhead())
+	for index := uint32(0); ; index++ {
+		n, err := io.ReadFull(br, plain)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return counter.n, fmt.Errorf("reading input: %w", err)
+		}
+		// チャンクが埋まった場合は、続きがなければ最後のチャンクとする
+		last := err != nil
+		if !last {
+			if _, peekErr := br.Peek(1); errors.Is(peekErr, io.EOF) {
+				last = true
+			}
+		}
+		sealed = aead.Seal(sealed[:0], chunkNonce(prefix, index, last), plain[:n], header)
+		if _, err := w.Write(sealed); err != nil {
+			return counter.n, fmt.Errorf("writing output: %w", err)
+		}
+		if last {
+			clear(plain)
+			return counter.n, nil
+		}
+		if index == ^uint32(0) {
+			return counter.n, errors.New("input is too large")
+		}
+	}
+}
+
+// decryptStream はencryptStreamで暗号化したrを復号してwに書き込み、平文のバイト数を返す。
+// 復号に使用する鍵はヘッダーの世代を引数にkeyFnで取得する。圧縮されている場合は展開して書き込む。
+func decryptStream(w io.Writer, r io.Reader, keyFn func(generation uint) ([]byte, error)) (int64, error) {
+	header := make([]byte, fileHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, errInvalidEncryptedFile
+	}
+	if !bytes.Equal(header[:4], []byte(fileMagic)) {
+		return 0, errInvalidEncryptedFile
+	}
+	format := header[4]
+	if format != fileFormatPlain && format != fileFormatGzip {
+		return 0, fmt.Errorf("unsupported encrypted file format %d", format)
+	}
+	generation := uint(binary.BigEndian.Uint32(header[5:]))
+	chunkSize := int(binary.BigEndian.Uint32(header[9:]))
+	if generation == 0 || chunkSize <= 0 || chunkSize > maxFileChunk {
+		return 0, errInvalidEncryptedFile
+	}
+
+	key, err := keyFn(generation)
+	if err != nil {
+		return 0, err
+	}
+	defer clear(key)
+	aead, err := newFileAEAD(key)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := &countingWriter{w: w}
+	if format == fileFormatPlain {
+		err := openChunks(counter, r, aead, header, chunkSize)
+		return counter.n, err
+	}
+
+	// 復号したチャンクを順にgzipの展開へ渡す
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		gz, err := gzip.NewReader(pr)
+		if err == nil {
+			_, err = io.Copy(counter, gz)
+		}
+		if err != nil {
+			err = fmt.Errorf("decompressing: %w", err)
+		}
+		pr.CloseWithError(err)
+		done <- err
+	}()
+	err = openChunks(pw, r, aead, header, chunkSize)
+	pw.CloseWithError(err)
+	if inflateErr := <-done; err == nil {
+		err = inflateErr
+	}
+	return counter.n, err
+}
+
+// openChunks はヘッダーに続くチャンクを順に復号してwに書き込む。
+func openChunks(w io.Writer, r io.Reader, aead cipher.AEAD, header []byte, chunkSize int) error {
+	prefix := header[13:]
+	br := bufio.NewReaderSize(r, chunkSize+aead.Overhead())
+	sealed := make([]byte, chunkSize+aead.Overhead())
+	plain := make([]byte, 0, chunkSize)
+	defer clear(plain[:cap(plain)])
+	for index := uint32(0); ; index++ {
+		n, err := io.ReadFull(br, sealed)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("reading input: %w", err)
+		}
+		last := err != nil
+		if !last {
+			if _, peekErr := br.Peek(1); errors.Is(peekErr, io.EOF) {
+				last = true
+			}
+		}
+		plain, err = aead.Open(plain[:0], chunkNonce(prefix, index, last), sealed[:n], header)
+		if err != nil {
+			return errors.New("decryption failed: the file is corrupted, truncated or was encrypted with a different key")
+		}
+		if _, err := w.Write(plain); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// countingReader は読み込んだバイト数を数えるio.Reader。
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingWriter は書き込んだバイト数を数えるio.Writer。
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// newFileAEAD はファイルの暗号化に使用するAES-GCMを生成する。
+func newFileAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AES key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
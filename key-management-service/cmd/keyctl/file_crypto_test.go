@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newFakeKeyServer は世代1・2のAES鍵を返すテスト用APIサーバーを起動する。現在の鍵は世代2。
+func newFakeKeyServer(t *testing.T) {
+	t.Helper()
+	keys := map[uint][]byte{1: make([]byte, 32), 2: make([]byte, 32)}
+	for _, k := range keys {
+		if _, err := rand.Read(k); err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+	}
+	respond := func(w http.ResponseWriter, gen uint) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"tenant_id":"tenant-001","generation":%d,"key_type":"aes","key_bits":256,"key":%q}`,
+			gen, base64.StdEncoding.EncodeToString(keys[gen]))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tenants/tenant-001/keys/current", "/v1/tenants/tenant-001/keys/2":
+			respond(w, 2)
+		case "/v1/tenants/tenant-001/keys/1":
+			respond(w, 1)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":"KEY_NOT_FOUND","message":"key not found"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	prevURL, prevClient, prevOut := apiURL, httpClient, stdout
+	apiURL, httpClient, stdout = server.URL, server.Client(), &bytes.Buffer{}
+	t.Cleanup(func() { apiURL, httpClient, stdout = prevURL, prevClient, prevOut })
+}
+
+func runFileCommand(t *testing.T, cmd func() *cobra.Command, args ...string) error {
+	t.Helper()
+	c := cmd()
+	c.SetArgs(args)
+	c.SetOut(&bytes.Buffer{})
+	c.SetErr(&bytes.Buffer{})
+	return c.Execute()
+}
+
+func TestEncryptDecryptFile_RoundTrip(t *testing.T) {
+	newFakeKeyServer(t)
+	dir := t.TempDir()
+
+	// 複数チャンクにまたがるサイズ
+	plaintext := make([]byte, 3*defaultFileChunk+123)
+	if _, err := rand.Read(plaintext); err != nil {
+		t.Fatalf("failed to generate plaintext: %v", err)
+	}
+	plainPath := filepath.Join(dir, "plain.bin")
+	if err := os.WriteFile(plainPath, plaintext, 0o600); err != nil {
+		t.Fatalf("failed to write plaintext: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name           string
+		args           []string
+		wantGeneration string
+	}{
+		{name: "current key", wantGeneration: "generation 2"},
+		{name: "older generation", args: []string{"--generation", "1"}, wantGeneration: "generation 1"},
+		{name: "compressed", args: []string{"--compress"}, wantGeneration: "generation 2"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cipherPath := filepath.Join(dir, "cipher.bin")
+			outPath := filepath.Join(dir, "out.bin")
+
+			args := append([]string{"--tenant", "tenant-001", "--in", plainPath, "--out", cipherPath}, tt.args...)
+			if err := runFileCommand(t, encryptFileCmd, args...); err != nil {
+				t.Fatalf("encrypt-file failed: %v", err)
+			}
+			encrypted, err := os.ReadFile(cipherPath)
+			if err != nil {
+				t.Fatalf("failed to read encrypted file: %v", err)
+			}
+			if bytes.Contains(encrypted, plaintext[:64]) {
+				t.Fatal("encrypted file contains plaintext")
+			}
+
+			out := &bytes.Buffer{}
+			stdout = out
+			if err := runFileCommand(t, decryptFileCmd, "--tenant", "tenant-001", "--in", cipherPath, "--out", outPath); err != nil {
+				t.Fatalf("decrypt-file failed: %v", err)
+			}
+			decrypted, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatalf("failed to read decrypted file: %v", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Fatal("decrypted file does not match the original")
+			}
+			// 暗号化に使用した世代をヘッダーから読み取って復号する
+			if !strings.Contains(out.String(), tt.wantGeneration) {
+				t.Errorf("want output to mention %s, got %q", tt.wantGeneration, out.String())
+			}
+		})
+	}
+}
+
+func TestEncryptDecryptStream_EdgeSizes(t *testing.T) {
+	key := make([]byte, 32)
+	const chunk = 16
+	for _, size := range []int{0, 1, chunk - 1, chunk, chunk + 1, 2 * chunk, 5*chunk + 3} {
+		plaintext := bytes.Repeat([]byte{'x'}, size)
+		var sealed bytes.Buffer
+		if _, err := encryptStream(&sealed, bytes.NewReader(plaintext), key, 1, chunk, false); err != nil {
+			t.Fatalf("size %d: encrypt failed: %v", size, err)
+		}
+		var opened bytes.Buffer
+		n, err := decryptStream(&opened, &sealed, func(uint) ([]byte, error) { return bytes.Clone(key), nil })
+		if err != nil {
+			t.Fatalf("size %d: decrypt failed: %v", size, err)
+		}
+		if n != int64(size) || !bytes.Equal(opened.Bytes(), plaintext) {
+			t.Errorf("size %d: round trip mismatch (got %d bytes)", size, n)
+		}
+	}
+}
+
+func TestEncryptDecryptStream_Compressed(t *testing.T) {
+	key := make([]byte, 32)
+	keyFn := func(uint) ([]byte, error) { return bytes.Clone(key), nil }
+	plaintext := bytes.Repeat([]byte("compressible payload "), 20000)
+
+	var compressed, plain bytes.Buffer
+	n, err := encryptStream(&compressed, bytes.NewReader(plaintext), key, 1, defaultFileChunk, true)
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if n != int64(len(plaintext)) {
+		t.Errorf("want %d plaintext bytes, got %d", len(plaintext), n)
+	}
+	if _, err := encryptStream(&plain, bytes.NewReader(plaintext), key, 1, defaultFileChunk, false); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if compressed.Bytes()[4] != fileFormatGzip || plain.Bytes()[4] != fileFormatPlain {
+		t.Fatalf("unexpected format bytes: compressed=%d plain=%d", compressed.Bytes()[4], plain.Bytes()[4])
+	}
+	if compressed.Len() >= plain.Len()/10 {
+		t.Errorf("want compressed output to be much smaller: %d vs %d bytes", compressed.Len(), plain.Len())
+	}
+
+	// 圧縮・非圧縮のどちらも復号時に形式を判別して同じ平文に戻る
+	for name, sealed := range map[string][]byte{"compressed": compressed.Bytes(), "uncompressed": plain.Bytes()} {
+		var opened bytes.Buffer
+		n, err := decryptStream(&opened, bytes.NewReader(sealed), keyFn)
+		if err != nil {
+			t.Fatalf("%s: decrypt failed: %v", name, err)
+		}
+		if n != int64(len(plaintext)) || !bytes.Equal(opened.Bytes(), plaintext) {
+			t.Errorf("%s: round trip mismatch (got %d bytes)", name, n)
+		}
+	}
+}
+
+func TestDecryptStream_RejectsCorruptCompressedData(t *testing.T) {
+	key := make([]byte, 32)
+	aead, err := newFileAEAD(key)
+	if err != nil {
+		t.Fatalf("failed to create AEAD: %v", err)
+	}
+	// 正しく暗号化されているがgzipとして不正な平文
+	header := make([]byte, fileHeaderSize)
+	copy(header, fileMagic)
+	header[4] = fileFormatGzip
+	binary.BigEndian.PutUint32(header[5:], 1)
+	binary.BigEndian.PutUint32(header[9:], 16)
+	sealed := aead.Seal(bytes.Clone(header), chunkNonce(header[13:], 0, true), []byte("not gzip"), header)
+
+	_, err = decryptStream(&bytes.Buffer{}, bytes.NewReader(sealed), func(uint) ([]byte, error) { return bytes.Clone(key), nil })
+	if err == nil || !strings.Contains(err.Error(), "decompressing") {
+		t.Errorf("want decompression error, got %v", err)
+	}
+}
+
+func TestDecryptStream_DetectsTampering(t *testing.T) {
+	key := make([]byte, 32)
+	const chunk = 16
+	var sealed bytes.Buffer
+	if _, err := encryptStream(&sealed, bytes.NewReader(bytes.Repeat([]byte{'x'}, 3*chunk)), key, 1, chunk, false); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	keyFn := func(uint) ([]byte, error) { return bytes.Clone(key), nil }
+	chunkLen := chunk + 16
+
+	tests := map[string][]byte{
+		// 暗号文の1バイトを改ざん
+		"flipped byte": func() []byte {
+			b := bytes.Clone(sealed.Bytes())
+			b[fileHeaderSize+1] ^= 1
+			return b
+		}(),
+		// 最後のチャンクを削除（チャンク境界での切り詰め）
+		"truncated": sealed.Bytes()[:fileHeaderSize+2*chunkLen],
+		// ヘッダーの世代を改ざん
+		"header": func() []byte {
+			b := bytes.Clone(sealed.Bytes())
+			b[8] ^= 2
+			return b
+		}(),
+		"not encrypted": []byte("plain text file"),
+	}
+	for name, data := range tests {
+		if _, err := decryptStream(&bytes.Buffer{}, bytes.NewReader(data), keyFn); err == nil {
+			t.Errorf("%s: want error, got nil", name)
+		}
+	}
+}
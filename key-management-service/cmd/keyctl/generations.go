@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// 世代ごとの取得結果のステータス。
+const (
+	generationStatusOK    = "ok"
+	generationStatusError = "error"
+)
+
+// generationKey は--all-generationsで取得した1世代分の結果。
+type generationKey struct {
+	Generation uint   `json:"generation"`
+	Status     string `json:"status"`
+	Key        string `json:"key,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// fetchAllGenerations はテナントの鍵一覧を取得し、有効な各世代の鍵を個別に取得する。
+// 無効化済みの世代や個別取得に失敗した世代は中断せずにエラーステータスとして結果に含める。
+func fetchAllGenerations(tenantID string) ([]generationKey, error) {
+	body, err := doRequest(http.MethodGet, fmt.Sprintf("%s/v1/tenants/%s/keys", apiURL, tenantID), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Keys []keyMetadataResult `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	results := make([]generationKey, 0, len(list.Keys))
+	for _, k := range list.Keys {
+		if k.Status != "active" {
+			results = append(results, generationKey{
+				Generation: k.Generation,
+				Status:     generationStatusError,
+				Error:      fmt.Sprintf("key is %s", k.Status),
+			})
+			continue
+		}
+
+		body, err := doRequest(http.MethodGet, fmt.Sprintf("%s/v1/tenants/%s/keys/%d", apiURL, tenantID, k.Generation), nil, http.StatusOK)
+		if err != nil {
+			results = append(results, generationKey{Generation: k.Generation, Status: generationStatusError, Error: err.Error()})
+			continue
+		}
+		var key keyResult
+		if err := json.Unmarshal(body, &key); err != nil {
+			results = append(results, generationKey{Generation: k.Generation, Status: generationStatusError, Error: fmt.Sprintf("parsing response: %v", err)})
+			continue
+		}
+		results = append(results, generationKey{Generation: k.Generation, Status: generationStatusOK, Key: key.Key})
+	}
+	return results, nil
+}
+
+// formatGenerationKeys は全世代の取得結果をテキスト表示用に整形する。
+func formatGenerationKeys(results []generationKey) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-12s %-8s %s\n", "GENERATION", "STATUS", "KEY")
+	for _, r := range results {
+		value := r.Key
+		if r.Status != generationStatusOK {
+			value = r.Error
+		}
+		fmt.Fprintf(&sb, "%-12d %-8s %s\n", r.Generation, r.Status, value)
+	}
+	return sb.String()
+}
+
+// writeGenerationFiles は取得できた世代の鍵を--out-dirへ1世代1ファイルで書き出す。
+// 取得できなかった世代はファイルを作らず、標準エラー出力に警告を表示する。
+func writeGenerationFiles(out outDirOptions, tenantID string, results []generationKey) error {
+	files := make([]keyFile, 0, len(results))
+	for _, r := range results {
+		if r.Status != generationStatusOK {
+			fmt.Fprintf(os.Stderr, "warning: generation %d skipped: %s\n", r.Generation, r.Error)
+			continue
+		}
+		files = append(files, keyFile{Generation: r.Generation, Value: r})
+	}
+	paths, err := writeKeyFiles(out.dir, tenantID, files, out.force)
+	if err != nil {
+		return err
+	}
+	return renderWrittenFiles(paths)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchAllGenerations(t *testing.T) {
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/tenants/tenant-001/keys":
+			fmt.Fprint(w, `{"keys":[
+				{"generation":1,"status":"active"},
+				{"generation":2,"status":"disabled"},
+				{"generation":3,"status":"active"}]}`)
+		case "/v1/tenants/tenant-001/keys/1":
+			fetched = append(fetched, r.URL.Path)
+			fmt.Fprint(w, `{"generation":1,"key":"a2V5LTE="}`)
+		case "/v1/tenants/tenant-001/keys/3":
+			fetched = append(fetched, r.URL.Path)
+			fmt.Fprint(w, `{"generation":3,"key":"a2V5LTM="}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	prevURL, prevClient := apiURL, httpClient
+	apiURL, httpClient = server.URL, server.Client()
+	t.Cleanup(func() { apiURL, httpClient = prevURL, prevClient })
+
+	results, err := fetchAllGenerations("tenant-001")
+	if err != nil {
+		t.Fatalf("fetchAllGenerations failed: %v", err)
+	}
+
+	want := []generationKey{
+		{Generation: 1, Status: generationStatusOK, Key: "a2V5LTE="},
+		{Generation: 2, Status: generationStatusError, Error: "key is disabled"},
+		{Generation: 3, Status: generationStatusOK, Key: "a2V5LTM="},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("want %d results, got %d", len(want), len(results))
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d: want %+v, got %+v", i, want[i], results[i])
+		}
+	}
+	if len(fetched) != 2 {
+		t.Errorf("want only active generations fetched, got %v", fetched)
+	}
+
+	// JSON出力は{generation, key}の配列になる
+	buf := captureStdout(t)
+	if err := render("json", results, nil); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("want JSON array, got %q: %v", buf.String(), err)
+	}
+	if decoded[0]["key"] != "a2V5LTE=" || decoded[1]["status"] != generationStatusError {
+		t.Errorf("unexpected JSON output: %s", buf.String())
+	}
+}
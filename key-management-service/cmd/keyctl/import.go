@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// importCmd はバックアップバンドルから鍵をインポートするコマンド。
+func importCmd() *cobra.Command {
+	var tenantID string
+	var filePath string
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import wrapped keys for a tenant from a bundle file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			if filePath == "" {
+				return fmt.Errorf("--file is required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			bundle, err := os.ReadFile(filePath)
+			if err != nil {
+				return fmt.Errorf("reading bundle file: %w", err)
+			}
+			if !json.Valid(bundle) {
+				return fmt.Errorf("bundle file %s is not valid JSON", filePath)
+			}
+
+			url := fmt.Sprintf("%s/v1/tenants/%s/keys/import", apiURL, tenantID)
+			body, err := doRequest(http.MethodPost, url, bytes.NewReader(bundle), http.StatusCreated)
+			if err != nil {
+				return err
+			}
+
+			var result struct {
+				Keys []keyMetadataResult `json:"keys"`
+			}
+			return renderBody(body, &result, func(any) string {
+				return fmt.Sprintf("Imported %d key(s) for tenant %q", len(result.Keys), tenantID)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().StringVar(&filePath, "file", "", "Path to the JSON bundle file (required)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	if err := cmd.MarkFlagRequired("file"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
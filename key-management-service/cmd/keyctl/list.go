@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// clearScreen はカーソルを左上に移動して画面を消去するエスケープシーケンス。
+const clearScreen = "\033[H\033[2J"
+
+// listCmd は鍵一覧の取得コマンド。
+func listCmd() *cobra.Command {
+	var tenantID string
+	var since string
+	var minGen, maxGen uint
+	var watch bool
+	var interval int
+	var out outDirOptions
+	var format string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all keys for a tenant",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+			if watch && output != "text" {
+				return fmt.Errorf("--watch supports only --output text")
+			}
+			if watch && interval < 1 {
+				return fmt.Errorf("--interval must be at least 1 second")
+			}
+			if watch && out.dir != "" {
+				return fmt.Errorf("--watch cannot be combined with --out-dir")
+			}
+			// テンプレートの誤りはAPIを呼び出す前に検出する
+			var tmpl *template.Template
+			if format != "" {
+				if output != "text" || watch || out.dir != "" {
+					return fmt.Errorf("--format cannot be combined with --output %s, --watch or --out-dir", output)
+				}
+				var err error
+				if tmpl, err = parseKeyTemplate(format); err != nil {
+					return err
+				}
+			}
+
+			query, err := listFilterQuery(since, minGen, maxGen)
+			if err != nil {
+				return err
+			}
+			url := fmt.Sprintf("%s/v1/tenants/%s/keys", apiURL, tenantID)
+			if query != "" {
+				url += "?" + query
+			}
+
+			if watch {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+				defer stop()
+				return watchKeys(ctx, url, time.Duration(interval)*time.Second)
+			}
+
+			keys, err := fetchKeyList(url)
+			if err != nil {
+				return err
+			}
+			if out.dir != "" {
+				files := make([]keyFile, len(keys))
+				for i, k := range keys {
+					files[i] = keyFile{Generation: k.Generation, Value: k}
+				}
+				paths, err := writeKeyFiles(out.dir, tenantID, files, out.force)
+				if err != nil {
+					return err
+				}
+				return renderWrittenFiles(paths)
+			}
+			if tmpl != nil {
+				return executeKeyTemplate(stdout, tmpl, keys)
+			}
+			return render(output, keyListResult{Keys: keys}, func(any) string {
+				return keyTable(keys, nil)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().StringVar(&since, "since", "", "Only keys created at or after this time (RFC3339)")
+	cmd.Flags().UintVar(&minGen, "min-gen", 0, "Minimum generation (inclusive)")
+	cmd.Flags().UintVar(&maxGen, "max-gen", 0, "Maximum generation (inclusive)")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Re-fetch and redraw the list until interrupted, highlighting new generations")
+	cmd.Flags().IntVar(&interval, "interval", 5, "Seconds between refreshes with --watch")
+	addOutDirFlags(cmd, &out)
+	cmd.Flags().StringVar(&format, "format", "", "Print each key with a Go template, e.g. '{{.Generation}} {{.Status}}' (fields: TenantID, Generation, KeyType, KeyBits, Status, KMSKeyName, CreatedAt, ExpiresAt, LastUsedAt, DisabledAt, DisabledReason, IsCurrent)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
+
+// keyListResult は鍵一覧のレスポンス形式。
+type keyListResult struct {
+	Keys []keyMetadataResult `json:"keys"`
+}
+
+// keyTable は鍵一覧を表形式の文字列にする。
+// highlightがnilでない場合は各行に印の列を付け、highlightに含まれる世代を強調表示する。
+func keyTable(keys []keyMetadataResult, highlight map[uint]bool) string {
+	var sb strings.Builder
+	if highlight != nil {
+		sb.WriteString("  ")
+	}
+	fmt.Fprintf(&sb, "%-12s %-6s %-6s %-10s %-8s %-25s %s\n", "GENERATION", "TYPE", "BITS", "STATUS", "CURRENT", "CREATED_AT", "LAST_USED_AT")
+	for _, k := range keys {
+		lastUsed := k.LastUsedAt
+		if lastUsed == "" {
+			lastUsed = "-"
+		}
+		// 現在の鍵（keyctl getが返す世代）に印を付ける
+		current := ""
+		if k.IsCurrent {
+			current = "*"
+		}
+		row := fmt.Sprintf("%-12d %-6s %-6d %-10s %-8s %-25s %s", k.Generation, k.KeyType, k.KeyBits, k.Status, current, k.CreatedAt, lastUsed)
+		switch {
+		case highlight == nil:
+			sb.WriteString(row)
+		case highlight[k.Generation]:
+			// 新しい世代は太字の緑で表示する
+			fmt.Fprintf(&sb, "\033[1;32m+ %s\033[0m", row)
+		default:
+			sb.WriteString("  " + row)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// parseKeyTemplate は--formatのテンプレートを解析する。
+// 存在しないフィールドの参照も実行時まで分からないため、空の鍵に対して一度実行して検出する。
+func parseKeyTemplate(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, keyMetadataResult{}); err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// executeKeyTemplate は鍵ごとにtmplを実行し、1行ずつwに出力する。
+func executeKeyTemplate(w io.Writer, tmpl *template.Template, keys []keyMetadataResult) error {
+	for _, k := range keys {
+		if err := tmpl.Execute(w, k); err != nil {
+			return fmt.Errorf("executing --format template: %w", err)
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newGenerations はprevになくcurに現れた世代を返す。
+func newGenerations(prev, cur []keyMetadataResult) map[uint]bool {
+	seen := make(map[uint]bool, len(prev))
+	for _, k := range prev {
+		seen[k.Generation] = true
+	}
+	added := make(map[uint]bool)
+	for _, k := range cur {
+		if !seen[k.Generation] {
+			added[k.Generation] = true
+		}
+	}
+	return added
+}
+
+// watchKeys はctxが終了するまでinterval間隔で鍵一覧を取得し、画面を消去して再描画する。
+// 前回の取得以降に現れた世代を強調表示する。取得に失敗した場合はエラーを表示して次の取得を待つ。
+func watchKeys(ctx context.Context, url string, interval time.Duration) error {
+	var prev []keyMetadataResult
+	first := true
+	for {
+		var sb strings.Builder
+		sb.WriteString(clearScreen)
+		fmt.Fprintf(&sb, "Every %s: %s (%s)\n\n", interval, url, time.Now().UTC().Format(time.RFC3339))
+
+		keys, err := fetchKeyList(url)
+		if err != nil {
+			fmt.Fprintf(&sb, "error: %v\n", err)
+		} else {
+			highlight := map[uint]bool{}
+			if !first {
+				highlight = newGenerations(prev, keys)
+			}
+			sb.WriteString(keyTable(keys, highlight))
+			prev, first = keys, false
+		}
+		if _, err := fmt.Fprint(stdout, sb.String()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fetchKeyList は鍵一覧を取得する。
+func fetchKeyList(url string) ([]keyMetadataResult, error) {
+	body, err := doRequest(http.MethodGet, url, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var result keyListResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	return result.Keys, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewGenerations(t *testing.T) {
+	prev := []keyMetadataResult{{Generation: 1}, {Generation: 2}}
+	cur := []keyMetadataResult{{Generation: 1}, {Generation: 2}, {Generation: 3}, {Generation: 4}}
+
+	added := newGenerations(prev, cur)
+	if len(added) != 2 || !added[3] || !added[4] {
+		t.Errorf("want generations 3 and 4, got %v", added)
+	}
+
+	// 変化がない場合と世代が消えた場合は何も強調しない
+	if added := newGenerations(cur, cur); len(added) != 0 {
+		t.Errorf("want no new generations, got %v", added)
+	}
+	if added := newGenerations(cur, prev); len(added) != 0 {
+		t.Errorf("want no new generations, got %v", added)
+	}
+}
+
+func TestKeyTable_Highlight(t *testing.T) {
+	keys := []keyMetadataResult{
+		{Generation: 1, KeyType: "aes", KeyBits: 256, Status: "active", CreatedAt: "2026-01-01T00:00:00Z"},
+		{Generation: 2, KeyType: "aes", KeyBits: 256, Status: "active", CreatedAt: "2026-02-01T00:00:00Z"},
+	}
+
+	lines := strings.Split(strings.TrimRight(keyTable(keys, map[uint]bool{2: true}), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want header and 2 rows, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[1], "  1 ") {
+		t.Errorf("want generation 1 unmarked, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "+ 2 ") || !strings.HasPrefix(lines[2], "\033[1;32m") {
+		t.Errorf("want generation 2 highlighted, got %q", lines[2])
+	}
+
+	// 通常の一覧では印の列を付けない
+	plain := keyTable(keys, nil)
+	if strings.Contains(plain, "\033[") || !strings.HasPrefix(plain, "GENERATION") {
+		t.Errorf("want plain table, got %q", plain)
+	}
+}
+
+func TestKeyTable_MarksCurrent(t *testing.T) {
+	keys := []keyMetadataResult{
+		{Generation: 1, KeyType: "aes", KeyBits: 256, Status: "active", CreatedAt: "2026-01-01T00:00:00Z"},
+		{Generation: 2, KeyType: "aes", KeyBits: 256, Status: "active", CreatedAt: "2026-02-01T00:00:00Z", IsCurrent: true},
+		{Generation: 3, KeyType: "aes", KeyBits: 256, Status: "disabled", CreatedAt: "2026-03-01T00:00:00Z"},
+	}
+
+	lines := strings.Split(strings.TrimRight(keyTable(keys, nil), "\n"), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], "CURRENT") {
+		t.Fatalf("want header with CURRENT and 3 rows, got %q", lines)
+	}
+	for i, want := range []bool{false, true, false} {
+		if got := strings.Contains(lines[i+1], " * "); got != want {
+			t.Errorf("generation %d: want current mark %v, got %q", i+1, want, lines[i+1])
+		}
+	}
+}
+
+func TestListCmd_Format(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"keys":[
+			{"tenant_id":"tenant-001","generation":1,"key_type":"aes","key_bits":256,"status":"disabled"},
+			{"tenant_id":"tenant-001","generation":2,"key_type":"hmac","key_bits":512,"status":"active","is_current":true}]}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "{{.Generation}} {{.Status}}", want: "1 disabled\n2 active\n"},
+		{format: "{{.TenantID}}:{{.Generation}}\t{{.KeyType}}-{{.KeyBits}}{{if .IsCurrent}} *{{end}}", want: "tenant-001:1\taes-256\ntenant-001:2\thmac-512 *\n"},
+	}
+	for _, tt := range tests {
+		if got := executeRoot(t, "--api-url", server.URL, "list", "--tenant", "tenant-001", "--format", tt.format); got != tt.want {
+			t.Errorf("--format %q: want %q, got %q", tt.format, tt.want, got)
+		}
+	}
+
+	// 不正なテンプレートはAPIを呼び出す前にエラーにする
+	requests = 0
+	for _, format := range []string{"{{.Generation", "{{.NoSuchField}}"} {
+		root := newRootCmd()
+		root.SetArgs([]string{"--api-url", server.URL, "list", "--tenant", "tenant-001", "--format", format})
+		root.SetOut(&strings.Builder{})
+		root.SetErr(&strings.Builder{})
+		if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --format template") {
+			t.Errorf("--format %q: want template error, got %v", format, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("want no API request for invalid templates, got %d", requests)
+	}
+}
@@ -4,71 +4,207 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"runtime"
+	"strconv"
 	"time"
 
+	"key-management-service/internal/domain"
+
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
-const version = "1.0.0"
+// ビルド情報。ビルド時に-ldflags "-X main.version=... -X main.commit=... -X main.buildTime=..."で埋め込む。
+var (
+	version   = "1.0.0"
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
+// exitCodeMigrationFailed はマイグレーションの適用が途中で失敗した場合の終了コード。
+const exitCodeMigrationFailed = 3
 
 var (
 	apiURL  string
 	output  string
 	timeout time.Duration
+	verbose int
 )
 
+// keyMetadataResult は鍵メタデータのレスポンス形式。
+type keyMetadataResult struct {
+	TenantID       string `json:"tenant_id"`
+	Generation     uint   `json:"generation"`
+	KeyType        string `json:"key_type"`
+	KeyBits        int    `json:"key_bits"`
+	Status         string `json:"status"`
+	KMSKeyName     string `json:"kms_key_name,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
+	LastUsedAt     string `json:"last_used_at,omitempty"`
+	DisabledAt     string `json:"disabled_at,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+	IsCurrent      bool   `json:"is_current"`
+}
+
+// keyResult は鍵取得のレスポンス形式。
+type keyResult struct {
+	TenantID   string `json:"tenant_id"`
+	Generation uint   `json:"generation"`
+	KeyType    string `json:"key_type"`
+	KeyBits    int    `json:"key_bits"`
+	Key        string `json:"key"`
+	Status     string `json:"status,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"`
+}
+
 // HTTPクライアント
 var httpClient *http.Client
 
 func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		if errors.Is(err, domain.ErrMigrationFailed) {
+			os.Exit(exitCodeMigrationFailed)
+		}
+		os.Exit(1)
+	}
+}
+
+// newRootCmd はサブコマンドを登録したルートコマンドを生成する。
+func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "keyctl",
 		Short: "Key Management Service CLI",
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(output); err != nil {
+				return err
+			}
 			if apiURL == "" {
 				apiURL = os.Getenv("KEYCTL_API_URL")
 			}
 			httpClient = &http.Client{Timeout: timeout}
+			if verbose > 0 {
+				httpClient.Transport = newLoggingTransport(http.DefaultTransport, verboseLogHandler, verbose)
+			}
+			return nil
 		},
 	}
 
 	// グローバルフラグ
 	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "", "API endpoint URL (or set KEYCTL_API_URL)")
-	rootCmd.PersistentFlags().StringVar(&output, "output", "text", "Output format: text, json")
+	rootCmd.PersistentFlags().StringVar(&output, "output", "text", "Output format: text, json, yaml")
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 30*time.Second, "Request timeout")
+	rootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Log API requests and responses to stderr without bodies (-vv also logs timing)")
 
 	// サブコマンド登録
 	rootCmd.AddCommand(createCmd())
 	rootCmd.AddCommand(getCmd())
+	rootCmd.AddCommand(encryptFileCmd())
+	rootCmd.AddCommand(decryptFileCmd())
 	rootCmd.AddCommand(rotateCmd())
 	rootCmd.AddCommand(listCmd())
 	rootCmd.AddCommand(disableCmd())
+	rootCmd.AddCommand(deprecateCmd())
+	rootCmd.AddCommand(softDeleteCmd())
+	rootCmd.AddCommand(restoreCmd())
+	rootCmd.AddCommand(importCmd())
+	rootCmd.AddCommand(countCmd())
+	rootCmd.AddCommand(existsCmd())
+	rootCmd.AddCommand(statusCmd())
+	rootCmd.AddCommand(doctorCmd())
+	rootCmd.AddCommand(waitReadyCmd())
+	rootCmd.AddCommand(tenantsCmd())
+	rootCmd.AddCommand(auditCmd())
+	rootCmd.AddCommand(rewrapCmd())
 	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(maintenanceCmd())
+	rootCmd.AddCommand(checkCmd())
+	rootCmd.AddCommand(diffCmd())
 	rootCmd.AddCommand(versionCmd())
+	rootCmd.AddCommand(completionCmd())
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	// Cobraが自動で追加するcompletionコマンドの代わりにcompletionCmdを使用する
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	registerTenantCompletion(rootCmd)
+	return rootCmd
+}
+
+// buildInfoResult はビルド情報の形式。
+type buildInfoResult struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
 }
 
 // versionCmd はバージョン情報を表示する。
+// --serverを指定した場合はサーバーの/versionも取得し、クライアントと並べて表示する。
 func versionCmd() *cobra.Command {
-	return &cobra.Command{
+	var server bool
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("keyctl version %s\n", version)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result := struct {
+				Client buildInfoResult  `json:"client"`
+				Server *buildInfoResult `json:"server,omitempty"`
+			}{
+				Client: buildInfoResult{Version: version, Commit: commit, BuildTime: buildTime, GoVersion: runtime.Version()},
+			}
+			if server {
+				if apiURL == "" {
+					return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+				}
+				body, err := doRequest(http.MethodGet, apiURL+"/version", nil, http.StatusOK)
+				if err != nil {
+					return err
+				}
+				result.Server = &buildInfoResult{}
+				if err := json.Unmarshal(body, result.Server); err != nil {
+					return fmt.Errorf("parsing response: %w", err)
+				}
+				if result.Server.Version != result.Client.Version {
+					fmt.Fprintf(os.Stderr, "warning: client version %s differs from server version %s\n", result.Client.Version, result.Server.Version)
+				}
+			}
+			return render(output, result, func(any) string {
+				text := fmt.Sprintf("keyctl version %s", version)
+				if result.Server != nil {
+					text += fmt.Sprintf("\nserver version %s (commit: %s, built: %s, %s)",
+						result.Server.Version, result.Server.Commit, result.Server.BuildTime, result.Server.GoVersion)
+				}
+				return text
+			})
 		},
 	}
+	cmd.Flags().BoolVar(&server, "server", false, "Also query the server's /version")
+	return cmd
+}
+
+// keySpecQuery は鍵生成指定をクエリ文字列に変換する。
+func keySpecQuery(keyType string, keyBits int) string {
+	q := url.Values{}
+	if keyType != "" {
+		q.Set("key_type", keyType)
+	}
+	if keyBits > 0 {
+		q.Set("key_bits", strconv.Itoa(keyBits))
+	}
+	return q.Encode()
 }
 
 // createCmd は鍵の生成コマンド。
 func createCmd() *cobra.Command {
 	var tenantID string
+	var keyType string
+	var keyBits int
+	var idempotencyKey string
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new key for a tenant",
@@ -81,7 +217,14 @@ func createCmd() *cobra.Command {
 			}
 
 			url := fmt.Sprintf("%s/v1/tenants/%s/keys", apiURL, tenantID)
-			resp, err := httpClient.Post(url, "application/json", nil)
+			if query := keySpecQuery(keyType, keyBits); query != "" {
+				url += "?" + query
+			}
+			req, err := newIdempotentPost(url, idempotencyKey)
+			if err != nil {
+				return err
+			}
+			resp, err := httpClient.Do(req)
 			if err != nil {
 				return fmt.Errorf("API request failed: %w", err)
 			}
@@ -100,19 +243,16 @@ func createCmd() *cobra.Command {
 				return handleErrorResponse(resp.StatusCode, body)
 			}
 
-			if output == "json" {
-				fmt.Println(string(body))
-			} else {
-				var result map[string]interface{}
-				if err := json.Unmarshal(body, &result); err != nil {
-					return fmt.Errorf("parsing response: %w", err)
-				}
-				fmt.Printf("Created key for tenant %q (generation: %.0f)\n", tenantID, result["generation"])
-			}
-			return nil
+			var result keyMetadataResult
+			return renderBody(body, &result, func(any) string {
+				return fmt.Sprintf("Created key for tenant %q (generation: %d)", tenantID, result.Generation)
+			})
 		},
 	}
 	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().StringVar(&keyType, "key-type", "", "Key type: aes, hmac (default: aes)")
+	cmd.Flags().IntVar(&keyBits, "key-bits", 0, "Key size in bits: aes 128/192/256, hmac 256/384/512 (default: 256 for aes, 512 for hmac)")
+	cmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "Idempotency key for safe retries (default: a generated UUID)")
 	if err := cmd.MarkFlagRequired("tenant"); err != nil {
 		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
 	}
@@ -123,6 +263,8 @@ func createCmd() *cobra.Command {
 func getCmd() *cobra.Command {
 	var tenantID string
 	var generation uint
+	var allGenerations bool
+	var out outDirOptions
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Get a key for a tenant",
@@ -133,6 +275,22 @@ func getCmd() *cobra.Command {
 			if apiURL == "" {
 				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
 			}
+			if allGenerations && generation > 0 {
+				return fmt.Errorf("--all-generations cannot be combined with --generation")
+			}
+
+			if allGenerations {
+				results, err := fetchAllGenerations(tenantID)
+				if err != nil {
+					return err
+				}
+				if out.dir != "" {
+					return writeGenerationFiles(out, tenantID, results)
+				}
+				return render(output, results, func(any) string {
+					return formatGenerationKeys(results)
+				})
+			}
 
 			var url string
 			if generation > 0 {
@@ -160,20 +318,26 @@ func getCmd() *cobra.Command {
 				return handleErrorResponse(resp.StatusCode, body)
 			}
 
-			if output == "json" {
-				fmt.Println(string(body))
-			} else {
-				var result map[string]interface{}
+			var result keyResult
+			if out.dir != "" {
 				if err := json.Unmarshal(body, &result); err != nil {
 					return fmt.Errorf("parsing response: %w", err)
 				}
-				fmt.Println(result["key"])
+				paths, err := writeKeyFiles(out.dir, tenantID, []keyFile{{Generation: result.Generation, Value: result}}, out.force)
+				if err != nil {
+					return err
+				}
+				return renderWrittenFiles(paths)
 			}
-			return nil
+			return renderBody(body, &result, func(any) string {
+				return result.Key
+			})
 		},
 	}
 	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
 	cmd.Flags().UintVar(&generation, "generation", 0, "Key generation (optional, defaults to current)")
+	cmd.Flags().BoolVar(&allGenerations, "all-generations", false, "Fetch the key of every generation (disabled generations are reported as errors)")
+	addOutDirFlags(cmd, &out)
 	if err := cmd.MarkFlagRequired("tenant"); err != nil {
 		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
 	}
@@ -183,188 +347,188 @@ func getCmd() *cobra.Command {
 // rotateCmd は鍵のローテーションコマンド。
 func rotateCmd() *cobra.Command {
 	var tenantID string
+	var keyType string
+	var keyBits int
+	var idempotencyKey string
+	var all, dryRun bool
+	var concurrency int
+	var ifOlderThan string
 	cmd := &cobra.Command{
 		Use:   "rotate",
-		Short: "Rotate key for a tenant",
+		Short: "Rotate key for a tenant (or every tenant with --all)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if tenantID == "" {
-				return fmt.Errorf("--tenant is required")
+			if all == (tenantID != "") {
+				return fmt.Errorf("exactly one of --tenant or --all is required")
 			}
 			if apiURL == "" {
 				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
 			}
 
-			url := fmt.Sprintf("%s/v1/tenants/%s/keys/rotate", apiURL, tenantID)
-			resp, err := httpClient.Post(url, "application/json", nil)
-			if err != nil {
-				return fmt.Errorf("API request failed: %w", err)
-			}
-			defer func() {
-				if closeErr := resp.Body.Close(); closeErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", closeErr)
+			if all {
+				if idempotencyKey != "" {
+					return fmt.Errorf("--idempotency-key cannot be combined with --all")
 				}
-			}()
-
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return fmt.Errorf("reading response: %w", err)
-			}
-
-			if resp.StatusCode != http.StatusCreated {
-				return handleErrorResponse(resp.StatusCode, body)
-			}
-
-			if output == "json" {
-				fmt.Println(string(body))
-			} else {
-				var result map[string]interface{}
-				if err := json.Unmarshal(body, &result); err != nil {
-					return fmt.Errorf("parsing response: %w", err)
+				if concurrency < 1 {
+					return fmt.Errorf("--concurrency must be at least 1")
 				}
-				fmt.Printf("Rotated key for tenant %q (new generation: %.0f)\n", tenantID, result["generation"])
-			}
-			return nil
-		},
-	}
-	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
-	if err := cmd.MarkFlagRequired("tenant"); err != nil {
-		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
-	}
-	return cmd
-}
-
-// listCmd は鍵一覧の取得コマンド。
-func listCmd() *cobra.Command {
-	var tenantID string
-	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "List all keys for a tenant",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if tenantID == "" {
-				return fmt.Errorf("--tenant is required")
-			}
-			if apiURL == "" {
-				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
-			}
-
-			url := fmt.Sprintf("%s/v1/tenants/%s/keys", apiURL, tenantID)
-			resp, err := httpClient.Get(url)
-			if err != nil {
-				return fmt.Errorf("API request failed: %w", err)
-			}
-			defer func() {
-				if closeErr := resp.Body.Close(); closeErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", closeErr)
+				if ifOlderThan != "" {
+					return fmt.Errorf("--if-older-than cannot be combined with --all")
 				}
-			}()
-
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return fmt.Errorf("reading response: %w", err)
+				return runRotateAll(keySpecQuery(keyType, keyBits), concurrency, dryRun)
 			}
-
-			if resp.StatusCode != http.StatusOK {
-				return handleErrorResponse(resp.StatusCode, body)
+			if dryRun {
+				return fmt.Errorf("--dry-run is only supported with --all")
 			}
-
-			if output == "json" {
-				fmt.Println(string(body))
-			} else {
-				var result struct {
-					Keys []struct {
-						Generation uint   `json:"generation"`
-						Status     string `json:"status"`
-						CreatedAt  string `json:"created_at"`
-					} `json:"keys"`
-				}
-				if err := json.Unmarshal(body, &result); err != nil {
-					return fmt.Errorf("parsing response: %w", err)
+			if ifOlderThan != "" {
+				maxAge, err := parseKeyAge(ifOlderThan)
+				if err != nil {
+					return err
 				}
+				return rotateIfOlderThan(tenantID, keySpecQuery(keyType, keyBits), idempotencyKey, maxAge, time.Now())
+			}
 
-				fmt.Printf("%-12s %-10s %s\n", "GENERATION", "STATUS", "CREATED_AT")
-				for _, k := range result.Keys {
-					fmt.Printf("%-12d %-10s %s\n", k.Generation, k.Status, k.CreatedAt)
-				}
+			result, err := rotateTenant(tenantID, keySpecQuery(keyType, keyBits), idempotencyKey)
+			if err != nil {
+				return err
 			}
-			return nil
+			return render(output, result, func(any) string {
+				return fmt.Sprintf("Rotated key for tenant %q (new generation: %d)", tenantID, result.Generation)
+			})
 		},
 	}
-	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
-	if err := cmd.MarkFlagRequired("tenant"); err != nil {
-		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
-	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required unless --all)")
+	cmd.Flags().StringVar(&keyType, "key-type", "", "Key type: aes, hmac (default: aes)")
+	cmd.Flags().IntVar(&keyBits, "key-bits", 0, "Key size in bits: aes 128/192/256, hmac 256/384/512 (default: 256 for aes, 512 for hmac)")
+	cmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "Idempotency key for safe retries (default: a generated UUID)")
+	cmd.Flags().BoolVar(&all, "all", false, "Rotate keys for every tenant")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Number of tenants rotated in parallel with --all")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --all, list the tenants that would be rotated without rotating")
+	cmd.Flags().StringVar(&ifOlderThan, "if-older-than", "", "Only rotate if the current key is older than this, e.g. 30d or 720h (skipping exits 0)")
 	return cmd
 }
 
-// disableCmd は鍵の無効化コマンド。
-func disableCmd() *cobra.Command {
-	var tenantID string
-	var generation uint
-	cmd := &cobra.Command{
-		Use:   "disable",
-		Short: "Disable a key for a tenant",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if tenantID == "" {
-				return fmt.Errorf("--tenant is required")
-			}
-			if generation == 0 {
-				return fmt.Errorf("--generation is required")
-			}
-			if apiURL == "" {
-				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
-			}
+// rotateTenant はテナントの鍵をローテーションし、新しい世代のメタデータを返す。
+func rotateTenant(tenantID, query, idempotencyKey string) (*keyMetadataResult, error) {
+	url := fmt.Sprintf("%s/v1/tenants/%s/keys/rotate", apiURL, tenantID)
+	if query != "" {
+		url += "?" + query
+	}
+	req, err := newIdempotentPost(url, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", closeErr)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, handleErrorResponse(resp.StatusCode, body)
+	}
 
-			url := fmt.Sprintf("%s/v1/tenants/%s/keys/%d", apiURL, tenantID, generation)
-			req, err := http.NewRequest(http.MethodDelete, url, nil)
-			if err != nil {
-				return fmt.Errorf("creating request: %w", err)
-			}
+	var result keyMetadataResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	return &result, nil
+}
 
-			resp, err := httpClient.Do(req)
-			if err != nil {
-				return fmt.Errorf("API request failed: %w", err)
-			}
-			defer func() {
-				if closeErr := resp.Body.Close(); closeErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", closeErr)
-				}
-			}()
+// listFilterQuery は鍵一覧の絞り込み条件をクエリ文字列に変換する。
+func listFilterQuery(since string, minGen, maxGen uint) (string, error) {
+	q := url.Values{}
+	if since != "" {
+		if _, err := time.Parse(time.RFC3339, since); err != nil {
+			return "", fmt.Errorf("--since must be RFC3339 (e.g. 2025-01-01T00:00:00Z): %w", err)
+		}
+		q.Set("created_after", since)
+	}
+	if minGen > 0 && maxGen > 0 && minGen > maxGen {
+		return "", fmt.Errorf("--min-gen must be less than or equal to --max-gen")
+	}
+	if minGen > 0 {
+		q.Set("min_generation", strconv.FormatUint(uint64(minGen), 10))
+	}
+	if maxGen > 0 {
+		q.Set("max_generation", strconv.FormatUint(uint64(maxGen), 10))
+	}
+	return q.Encode(), nil
+}
 
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return fmt.Errorf("reading response: %w", err)
-			}
+// newIdempotentPost はIdempotency-Keyヘッダー付きのPOSTリクエストを生成する。
+// keyが空の場合はUUIDを生成する。再送時に同じ結果を得るには同じキーを指定する。
+func newIdempotentPost(url, key string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if key == "" {
+		key = uuid.New().String()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+	return req, nil
+}
 
-			if resp.StatusCode != http.StatusAccepted {
-				return handleErrorResponse(resp.StatusCode, body)
-			}
+// doRequest はAPIリクエストを送信し、期待するステータスコードの場合にレスポンスボディを返す。
+func doRequest(method, url string, body io.Reader, wantStatus int) ([]byte, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-			if output == "json" {
-				fmt.Println("{}")
-			} else {
-				fmt.Printf("Disabled key for tenant %q (generation: %d)\n", tenantID, generation)
-			}
-			return nil
-		},
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
 	}
-	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
-	cmd.Flags().UintVar(&generation, "generation", 0, "Key generation (required)")
-	if err := cmd.MarkFlagRequired("tenant"); err != nil {
-		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", closeErr)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
 	}
-	if err := cmd.MarkFlagRequired("generation"); err != nil {
-		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+
+	if resp.StatusCode != wantStatus {
+		return nil, handleErrorResponse(resp.StatusCode, respBody)
 	}
-	return cmd
+	return respBody, nil
 }
 
 func handleErrorResponse(statusCode int, body []byte) error {
 	var errResp struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+		Details   []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"details"`
 	}
 	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&errResp); err == nil && errResp.Message != "" {
-		return fmt.Errorf("error: %s", errResp.Message)
+		msg := errResp.Message
+		if errResp.RequestID != "" {
+			msg = fmt.Sprintf("%s (request_id: %s)", msg, errResp.RequestID)
+		}
+		// フィールド単位の検証エラーは1行ずつ表示する
+		for _, d := range errResp.Details {
+			msg += fmt.Sprintf("\n  %s: %s", d.Field, d.Message)
+		}
+		return fmt.Errorf("error: %s", msg)
 	}
 	return fmt.Errorf("error: server returned status %d", statusCode)
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"key-management-service/config"
+	"key-management-service/internal/infra"
+	"key-management-service/internal/repository"
+	"key-management-service/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+// maintenanceCmd はデータベースの保守操作のコマンド。
+// migrateと同様にAPIを経由せず、データベースに直接接続する。
+func maintenanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "One-shot database maintenance operations",
+	}
+	cmd.AddCommand(backfillStatusCmd())
+	return cmd
+}
+
+// newDBKeyService はDATABASE_URL・DB_DRIVERのデータベースに直接接続するKeyServiceを生成する。
+// 保守・診断の操作はKMSを使用しないため、KMSクライアントは設定しない。
+func newDBKeyService() (*usecase.KeyService, error) {
+	dsn, err := config.EnvOrFile("DATABASE_URL")
+	if err != nil {
+		return nil, err
+	}
+	if dsn == "" {
+		return nil, fmt.Errorf("DATABASE_URL (or DATABASE_URL_FILE) environment variable is required")
+	}
+
+	// CLIではトレーシング無効
+	cfg := &config.Config{
+		DBDriver:    os.Getenv("DB_DRIVER"),
+		OtelEnabled: false,
+	}
+	db, err := infra.NewDB(dsn, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return usecase.NewKeyService(repository.NewKeyRepository(db), nil), nil
+}
+
+// backfillStatusResult はbackfill-statusの結果。
+type backfillStatusResult struct {
+	DryRun    bool `json:"dry_run"`
+	Activated int  `json:"activated"`
+	Disabled  int  `json:"disabled"`
+	Total     int  `json:"total"`
+}
+
+// backfillStatusCmd はステータスが空の鍵にステータスを設定するコマンド。
+func backfillStatusCmd() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "backfill-status",
+		Short: "Set the status of keys whose status is empty",
+		Long: "Set status to active for keys whose status is NULL or empty (for example rows inserted by a manual import),\n" +
+			"or to disabled when the key has a disabled_at timestamp. Runs in a single transaction.\n" +
+			"Connects directly to the database (DATABASE_URL, DB_DRIVER).",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyService, err := newDBKeyService()
+			if err != nil {
+				return err
+			}
+			res, err := keyService.BackfillStatus(context.Background(), dryRun)
+			if err != nil {
+				return fmt.Errorf("backfill failed: %w", err)
+			}
+
+			result := backfillStatusResult{
+				DryRun:    res.DryRun,
+				Activated: res.Activated,
+				Disabled:  res.Disabled,
+				Total:     res.Total(),
+			}
+			return render(output, result, func(any) string {
+				if result.DryRun {
+					return fmt.Sprintf("Dry run: %d key(s) would be backfilled (%d active, %d disabled)",
+						result.Total, result.Activated, result.Disabled)
+				}
+				return fmt.Sprintf("Backfilled %d key(s) (%d active, %d disabled)",
+					result.Total, result.Activated, result.Disabled)
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only count the keys that would be updated")
+	return cmd
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/tabwriter"
 
 	"key-management-service/config"
@@ -22,6 +24,8 @@ var migrateCmd = &cobra.Command{
 	Long:  "Manage database migrations for the key management service",
 }
 
+var migrateDryRun bool
+
 var migrateUpCmd = &cobra.Command{
 	Use:   "up",
 	Short: "Apply pending migrations",
@@ -30,13 +34,17 @@ var migrateUpCmd = &cobra.Command{
 		ctx := context.Background()
 
 		// DB接続情報を環境変数から取得
-		dsn := os.Getenv("DATABASE_URL")
+		dsn, err := config.EnvOrFile("DATABASE_URL")
+		if err != nil {
+			return err
+		}
 		if dsn == "" {
-			return fmt.Errorf("DATABASE_URL environment variable is required")
+			return fmt.Errorf("DATABASE_URL (or DATABASE_URL_FILE) environment variable is required")
 		}
 
 		// CLIではトレーシング無効
 		cfg := &config.Config{
+			DBDriver:    os.Getenv("DB_DRIVER"),
 			OtelEnabled: false,
 		}
 
@@ -63,9 +71,19 @@ var migrateUpCmd = &cobra.Command{
 		migrationRepo := repository.NewMigrationRepository(db)
 		migrationService := usecase.NewMigrationService(migrationRepo, db, absPath)
 
+		// ドライラン: 実行計画のみ表示
+		if migrateDryRun {
+			return printMigrationPlan(ctx, migrationService)
+		}
+
 		// マイグレーション実行
 		appliedCount, err := migrationService.ApplyMigrations(ctx)
 		if err != nil {
+			var migErr *domain.MigrationError
+			if errors.As(err, &migErr) {
+				fmt.Fprintf(os.Stderr, "Applied %d migration(s) before failure.\n", migErr.Applied)
+				fmt.Fprintf(os.Stderr, "Failed at version %s: %v\n", migErr.Version, migErr.Err)
+			}
 			return fmt.Errorf("migration failed: %w", err)
 		}
 
@@ -87,13 +105,17 @@ var migrateStatusCmd = &cobra.Command{
 		ctx := context.Background()
 
 		// DB接続情報を環境変数から取得
-		dsn := os.Getenv("DATABASE_URL")
+		dsn, err := config.EnvOrFile("DATABASE_URL")
+		if err != nil {
+			return err
+		}
 		if dsn == "" {
-			return fmt.Errorf("DATABASE_URL environment variable is required")
+			return fmt.Errorf("DATABASE_URL (or DATABASE_URL_FILE) environment variable is required")
 		}
 
 		// CLIではトレーシング無効
 		cfg := &config.Config{
+			DBDriver:    os.Getenv("DB_DRIVER"),
 			OtelEnabled: false,
 		}
 
@@ -158,7 +180,28 @@ var migrateStatusCmd = &cobra.Command{
 	},
 }
 
+// printMigrationPlan は未適用マイグレーションとそのSQLを表示する。何も適用しない。
+func printMigrationPlan(ctx context.Context, migrationService *usecase.MigrationService) error {
+	plan, err := migrationService.PlanMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to plan migrations: %w", err)
+	}
+
+	if len(plan) == 0 {
+		fmt.Println("No pending migrations.")
+		return nil
+	}
+
+	fmt.Printf("Dry run: %d migration(s) would be applied (nothing was changed).\n", len(plan))
+	for _, migration := range plan {
+		fmt.Printf("\n== %s %s (%s)\n", migration.Version, migration.Name, migration.FilePath)
+		fmt.Println(strings.TrimRight(migration.SQL, "\n"))
+	}
+	return nil
+}
+
 func init() {
+	migrateUpCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show pending migrations and their SQL without applying them")
 	migrateCmd.AddCommand(migrateUpCmd)
 	migrateCmd.AddCommand(migrateStatusCmd)
 }
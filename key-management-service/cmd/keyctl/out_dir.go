@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// outDirFileMode は--out-dirで書き出すファイルの権限。鍵や鍵のメタデータを含むため所有者のみ読み書きできる。
+const outDirFileMode = 0o600
+
+// outDirOptions は--out-dir・--forceの指定。
+type outDirOptions struct {
+	dir   string
+	force bool
+}
+
+// addOutDirFlags は鍵を1世代1ファイルで書き出す--out-dir・--forceをcmdに追加する。
+func addOutDirFlags(cmd *cobra.Command, o *outDirOptions) {
+	cmd.Flags().StringVar(&o.dir, "out-dir", "", "Write each generation to <out-dir>/<tenant>-<generation>.json (mode 0600) instead of printing")
+	cmd.Flags().BoolVar(&o.force, "force", false, "Overwrite existing files in --out-dir")
+}
+
+// keyFile は--out-dirに書き出す1世代分の内容。
+type keyFile struct {
+	Generation uint
+	Value      any
+}
+
+// writtenFilesResult は--out-dirで書き出したファイルの一覧。
+type writtenFilesResult struct {
+	Files []string `json:"files"`
+}
+
+// keyFileName はテナント・世代の書き出し先のファイル名を返す。
+func keyFileName(tenantID string, generation uint) string {
+	return fmt.Sprintf("%s-%d.json", tenantID, generation)
+}
+
+// writeKeyFiles はfilesを世代ごとにdir/<tenant>-<generation>.jsonへJSONで書き出し、書き出したパスを返す。
+// forceでない場合、既存のファイルが1つでもあれば何も書き出さずにエラーを返す。
+// 上書きしたファイルも権限をoutDirFileModeに揃える。
+func writeKeyFiles(dir, tenantID string, files []keyFile, force bool) ([]string, error) {
+	// テナントIDがパスとして解釈されてdirの外に書き出さないようにする
+	if tenantID == "" || tenantID == "." || tenantID == ".." || strings.ContainsAny(tenantID, `/\`) {
+		return nil, fmt.Errorf("tenant ID %q cannot be used as a file name", tenantID)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = filepath.Join(dir, keyFileName(tenantID, f.Generation))
+		if force {
+			continue
+		}
+		if _, err := os.Stat(paths[i]); err == nil {
+			return nil, fmt.Errorf("%s already exists (use --force to overwrite)", paths[i])
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("checking %s: %w", paths[i], err)
+		}
+	}
+
+	for i, f := range files {
+		b, err := json.MarshalIndent(f.Value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding generation %d: %w", f.Generation, err)
+		}
+		if err := writeKeyFile(paths[i], append(b, '\n'), force); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// writeKeyFile はdataをpathに書き出す。forceでない場合は既存のファイルを上書きしない。
+func writeKeyFile(path string, data []byte, force bool) (err error) {
+	flag := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flag, outDirFileMode)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		}
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("closing %s: %w", path, closeErr)
+		}
+	}()
+	// 既存のファイルを上書きした場合は作成時の権限が適用されないため、明示的に揃える
+	if err := f.Chmod(outDirFileMode); err != nil {
+		return fmt.Errorf("setting permissions on %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// renderWrittenFiles は書き出したファイルの一覧を表示する。
+func renderWrittenFiles(paths []string) error {
+	return render(output, writtenFilesResult{Files: paths}, func(any) string {
+		var sb strings.Builder
+		for _, p := range paths {
+			fmt.Fprintf(&sb, "wrote %s\n", p)
+		}
+		return sb.String()
+	})
+}
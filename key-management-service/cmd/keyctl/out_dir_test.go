@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteKeyFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "export")
+	files := []keyFile{
+		{Generation: 1, Value: keyMetadataResult{TenantID: "tenant-001", Generation: 1, Status: "active"}},
+		{Generation: 2, Value: keyMetadataResult{TenantID: "tenant-001", Generation: 2, Status: "disabled"}},
+	}
+
+	paths, err := writeKeyFiles(dir, "tenant-001", files, false)
+	if err != nil {
+		t.Fatalf("writeKeyFiles failed: %v", err)
+	}
+	for i, name := range []string{"tenant-001-1.json", "tenant-001-2.json"} {
+		path := filepath.Join(dir, name)
+		if paths[i] != path {
+			t.Errorf("want path %s, got %s", path, paths[i])
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("want %s to be created: %v", name, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("%s: want mode 0600, got %o", name, perm)
+		}
+		var got keyMetadataResult
+		b, _ := os.ReadFile(path)
+		if err := json.Unmarshal(b, &got); err != nil || got.Generation != files[i].Generation {
+			t.Errorf("%s: want generation %d, got %s (%v)", name, files[i].Generation, b, err)
+		}
+	}
+
+	t.Run("--forceなしでは上書きしない", func(t *testing.T) {
+		updated := []keyFile{{Generation: 3, Value: "new"}, {Generation: 2, Value: "new"}}
+		if _, err := writeKeyFiles(dir, "tenant-001", updated, false); err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Fatalf("want error suggesting --force, got %v", err)
+		}
+		// 既存のファイルが1つでもあれば何も書き出さない
+		if _, err := os.Stat(filepath.Join(dir, "tenant-001-3.json")); !os.IsNotExist(err) {
+			t.Errorf("want no file written for generation 3, got %v", err)
+		}
+		if b, _ := os.ReadFile(filepath.Join(dir, "tenant-001-2.json")); strings.Contains(string(b), "new") {
+			t.Errorf("want existing file kept, got %s", b)
+		}
+	})
+
+	t.Run("--forceで上書きし権限を揃える", func(t *testing.T) {
+		path := filepath.Join(dir, "tenant-001-2.json")
+		if err := os.Chmod(path, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writeKeyFiles(dir, "tenant-001", []keyFile{{Generation: 2, Value: "new"}}, true); err != nil {
+			t.Fatalf("writeKeyFiles with force failed: %v", err)
+		}
+		b, _ := os.ReadFile(path)
+		if strings.TrimSpace(string(b)) != `"new"` {
+			t.Errorf("want overwritten content, got %s", b)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+			t.Errorf("want mode 0600 after overwrite, got %o", info.Mode().Perm())
+		}
+	})
+
+	t.Run("パスを含むテナントID", func(t *testing.T) {
+		if _, err := writeKeyFiles(dir, "../tenant", files, true); err == nil {
+			t.Error("want error for tenant ID containing a path separator")
+		}
+	})
+}
+
+func TestListCmd_OutDir(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"keys":[{"tenant_id":"tenant-001","generation":1,"status":"active"},{"tenant_id":"tenant-001","generation":2,"status":"active","is_current":true}]}`)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	out := executeRoot(t, "--api-url", server.URL, "list", "--tenant", "tenant-001", "--out-dir", dir)
+	for _, name := range []string{"tenant-001-1.json", "tenant-001-2.json"} {
+		if !strings.Contains(out, filepath.Join(dir, name)) {
+			t.Errorf("want %s reported, got %q", name, out)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("want %s to be created: %v", name, err)
+		}
+	}
+
+	// 2回目は既存のファイルがあるため失敗し、--forceで上書きできる
+	root := newRootCmd()
+	root.SetArgs([]string{"--api-url", server.URL, "list", "--tenant", "tenant-001", "--out-dir", dir})
+	root.SetOut(&strings.Builder{})
+	root.SetErr(&strings.Builder{})
+	if err := root.Execute(); err == nil {
+		t.Error("want error when files already exist")
+	}
+	executeRoot(t, "--api-url", server.URL, "list", "--tenant", "tenant-001", "--out-dir", dir, "--force")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormats は--outputで指定できる出力形式。
+var outputFormats = []string{"text", "json", "yaml"}
+
+// stdout はコマンド結果の出力先（テストで差し替える）。
+var stdout io.Writer = os.Stdout
+
+// validateOutput は--outputの値が対応している形式か検証する。
+func validateOutput(output string) error {
+	if !slices.Contains(outputFormats, output) {
+		return fmt.Errorf("unsupported --output %q (must be one of: %s)", output, strings.Join(outputFormats, ", "))
+	}
+	return nil
+}
+
+// render は出力形式に応じてvを表示する。
+// json・yamlではAPIレスポンスと同じフィールド名で出力し、textではtextFnの結果を出力する。
+func render(output string, v any, textFn func(v any) string) error {
+	switch output {
+	case "json":
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding output: %w", err)
+		}
+		_, err = fmt.Fprintln(stdout, string(b))
+		return err
+	case "yaml":
+		// JSONのフィールド名を保つため、一度JSONを経由して汎用的な値に変換する
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encoding output: %w", err)
+		}
+		return writeJSONAsYAML(b)
+	default:
+		_, err := fmt.Fprintln(stdout, strings.TrimRight(textFn(v), "\n"))
+		return err
+	}
+}
+
+// writeJSONAsYAML はJSONを汎用的な値（オブジェクトはmap[string]any）にデコードしてYAMLで出力する。
+func writeJSONAsYAML(b []byte) error {
+	var generic any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+	y, err := yaml.Marshal(generic)
+	if err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+	_, err = stdout.Write(y)
+	return err
+}
+
+// renderBody はAPIレスポンスのボディを表示する。
+// json・yamlではvの定義にないフィールドも落とさないよう、ボディをそのまま（yamlは変換して）出力する。
+// textではボディをvにデコードしてtextFnの結果を出力する。
+func renderBody(body []byte, v any, textFn func(v any) string) error {
+	switch output {
+	case "json":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, body, "", "  "); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		_, err := fmt.Fprintln(stdout, buf.String())
+		return err
+	case "yaml":
+		if !json.Valid(body) {
+			return fmt.Errorf("parsing response: invalid JSON")
+		}
+		return writeJSONAsYAML(body)
+	default:
+		if err := json.Unmarshal(body, v); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		return render(output, v, textFn)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type renderSample struct {
+	TenantID   string `json:"tenant_id"`
+	Generation uint   `json:"generation"`
+}
+
+// captureStdout はテスト中の出力先をバッファに切り替える。
+func captureStdout(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := stdout
+	stdout = &buf
+	t.Cleanup(func() { stdout = prev })
+	return &buf
+}
+
+func TestRender(t *testing.T) {
+	sample := &renderSample{TenantID: "tenant-001", Generation: 3}
+	textFn := func(v any) string {
+		s := v.(*renderSample)
+		return "tenant " + s.TenantID + "\n"
+	}
+
+	tests := []struct {
+		output string
+		want   []string
+	}{
+		{output: "text", want: []string{"tenant tenant-001\n"}},
+		{output: "json", want: []string{`"tenant_id": "tenant-001"`, `"generation": 3`}},
+		{output: "yaml", want: []string{"tenant_id: tenant-001\n", "generation: 3\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			buf := captureStdout(t)
+			if err := render(tt.output, sample, textFn); err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("want output containing %q, got %q", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRenderBody(t *testing.T) {
+	prev := output
+	t.Cleanup(func() { output = prev })
+	// renderSampleにないフィールドを含むレスポンス
+	body := []byte(`{"tenant_id":"tenant-001","generation":3,"kms_key_name":"projects/p/locations/l/keyRings/r/cryptoKeys/k"}`)
+	textFn := func(v any) string {
+		return "tenant " + v.(*renderSample).TenantID
+	}
+
+	tests := []struct {
+		output string
+		want   []string
+	}{
+		{output: "text", want: []string{"tenant tenant-001\n"}},
+		{output: "json", want: []string{`"tenant_id": "tenant-001"`, `"kms_key_name": "projects/p/locations/l/keyRings/r/cryptoKeys/k"`}},
+		{output: "yaml", want: []string{"tenant_id: tenant-001\n", "kms_key_name: projects/p/locations/l/keyRings/r/cryptoKeys/k\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			buf := captureStdout(t)
+			output = tt.output
+			if err := renderBody(body, &renderSample{}, textFn); err != nil {
+				t.Fatalf("renderBody failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("want output containing %q, got %q", want, buf.String())
+				}
+			}
+		})
+	}
+
+	output = "json"
+	if err := renderBody([]byte("not json"), &renderSample{}, textFn); err == nil {
+		t.Error("want error for invalid response body, got nil")
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	for _, output := range []string{"text", "json", "yaml"} {
+		if err := validateOutput(output); err != nil {
+			t.Errorf("%s: unexpected error: %v", output, err)
+		}
+	}
+	if err := validateOutput("xml"); err == nil {
+		t.Error("want error for unsupported format, got nil")
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"key-management-service/config"
+	"key-management-service/internal/infra"
+	"key-management-service/internal/repository"
+	"key-management-service/internal/usecase"
+
+	"github.com/spf13/cobra"
+)
+
+// errRewrapFailed はrewrapで1件以上の鍵の再ラップに失敗した場合のエラー。
+var errRewrapFailed = errors.New("rewrap failed for one or more keys")
+
+// rewrapResult はrewrapの結果。
+type rewrapResult struct {
+	FromKMSKey string `json:"from_kms_key"`
+	ToKMSKey   string `json:"to_kms_key"`
+	Rewrapped  int    `json:"rewrapped"`
+	Failed     int    `json:"failed"`
+}
+
+// rewrapCmd は旧KMS鍵でラップされた鍵を現在のKMS鍵で再ラップするコマンド。
+// migrateと同様にAPIを経由せず、データベースとCloud KMSに直接接続する。
+func rewrapCmd() *cobra.Command {
+	var fromKMSKey string
+	var batchSize int
+	var includeUnrecorded bool
+	cmd := &cobra.Command{
+		Use:   "rewrap",
+		Short: "Re-wrap keys wrapped under a retired KMS key with the current KMS key",
+		Long: "Re-wrap every key whose kms_key_name matches --from-kms-key with the KMS key in KMS_KEY_NAME\n" +
+			"(or the tenant's KMS key in TENANT_KMS_KEY_NAMES).\n" +
+			"Connects directly to the database (DATABASE_URL, DB_DRIVER) and Cloud KMS.\n" +
+			"Keys that fail are left under the old KMS key, so the command can be re-run safely.\n" +
+			"With --include-unrecorded, keys created before kms_key_name was recorded (empty kms_key_name)\n" +
+			"are also decrypted with --from-kms-key and re-wrapped.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if fromKMSKey == "" {
+				return fmt.Errorf("--from-kms-key is required")
+			}
+			dsn, err := config.EnvOrFile("DATABASE_URL")
+			if err != nil {
+				return err
+			}
+			if dsn == "" {
+				return fmt.Errorf("DATABASE_URL (or DATABASE_URL_FILE) environment variable is required")
+			}
+			kmsKeyName, err := config.EnvOrFile("KMS_KEY_NAME")
+			if err != nil {
+				return err
+			}
+			if kmsKeyName == "" {
+				return fmt.Errorf("KMS_KEY_NAME (or KMS_KEY_NAME_FILE) environment variable is required")
+			}
+
+			tenantKMSKeyNames, err := config.TenantKMSKeyNames()
+			if err != nil {
+				return err
+			}
+
+			// CLIではトレーシング無効
+			cfg := &config.Config{
+				DBDriver:    os.Getenv("DB_DRIVER"),
+				OtelEnabled: false,
+			}
+			db, err := infra.NewDB(dsn, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+
+			kmsClient, err := infra.NewKMSClient(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create KMS client: %w", err)
+			}
+			defer func() { _ = kmsClient.Close() }()
+
+			// テナント専用のKMS鍵（TENANT_KMS_KEY_NAMES）が設定されたテナントの鍵はサーバーと同様にそのKMS鍵で再ラップする
+			tenantKMSKeys := make([]usecase.TenantKMSKey, 0, len(tenantKMSKeyNames))
+			for tenantID, name := range tenantKMSKeyNames {
+				tenantKMSKeys = append(tenantKMSKeys, usecase.TenantKMSKey{
+					TenantID: tenantID,
+					Name:     name,
+					Client:   kmsClient.WithKeyName(name),
+				})
+			}
+			keyService := usecase.NewKeyService(repository.NewKeyRepository(db), kmsClient,
+				usecase.WithKMSKeyName(kmsKeyName),
+				usecase.WithTenantKMSKeys(tenantKMSKeys...),
+			)
+			res, err := keyService.RewrapByKMSKey(ctx, fromKMSKey, kmsClient.WithKeyName(fromKMSKey), batchSize, includeUnrecorded)
+			if err != nil {
+				if res != nil {
+					fmt.Fprintf(os.Stderr, "Re-wrapped %d key(s) before failure.\n", res.Rewrapped)
+				}
+				return fmt.Errorf("rewrap failed: %w", err)
+			}
+
+			result := rewrapResult{
+				FromKMSKey: res.FromKMSKeyName,
+				ToKMSKey:   res.ToKMSKeyName,
+				Rewrapped:  res.Rewrapped,
+				Failed:     res.Failed,
+			}
+			if err := render(output, result, func(any) string {
+				return fmt.Sprintf("Re-wrapped %d key(s), %d failed (from %s to %s)",
+					result.Rewrapped, result.Failed, result.FromKMSKey, result.ToKMSKey)
+			}); err != nil {
+				return err
+			}
+			if result.Failed > 0 {
+				// 結果は表示済みのため、終了コードのみで伝える
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return errRewrapFailed
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&fromKMSKey, "from-kms-key", "", "KMS key name the keys are currently wrapped under (required)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", usecase.DefaultRewrapBatchSize, "Number of keys to fetch and re-wrap per batch")
+	cmd.Flags().BoolVar(&includeUnrecorded, "include-unrecorded", false, "Also re-wrap keys with no recorded kms_key_name, decrypting them with --from-kms-key")
+	if err := cmd.MarkFlagRequired("from-kms-key"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
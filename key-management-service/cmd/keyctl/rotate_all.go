@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// tenantsPageLimit はテナント一覧を取得する際の1ページあたりの件数。
+const tenantsPageLimit = 1000
+
+// rotateAllResult は--allでのテナントごとのローテーション結果。
+type rotateAllResult struct {
+	TenantID   string `json:"tenant_id"`
+	Status     string `json:"status"`
+	Generation uint   `json:"generation,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// rotateAllSummary は--allの実行結果の集計。
+type rotateAllSummary struct {
+	DryRun  bool              `json:"dry_run,omitempty"`
+	Total   int               `json:"total"`
+	Rotated int               `json:"rotated"`
+	Failed  int               `json:"failed"`
+	Results []rotateAllResult `json:"results"`
+}
+
+// errRotateAllFailed は--allで1件以上のローテーションに失敗した場合のエラー。
+var errRotateAllFailed = errors.New("rotation failed for one or more tenants")
+
+// listAllTenants はテナント一覧をページングしながら全件取得する。
+func listAllTenants() ([]string, error) {
+	return listAllTenantsAt(apiURL)
+}
+
+// listAllTenantsAt はbaseURLのAPIからテナント一覧をページングしながら全件取得する。
+func listAllTenantsAt(baseURL string) ([]string, error) {
+	var tenants []string
+	for offset := 0; ; offset += tenantsPageLimit {
+		url := fmt.Sprintf("%s/v1/tenants?limit=%d&offset=%d", baseURL, tenantsPageLimit, offset)
+		body, err := doRequest(http.MethodGet, url, nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Tenants []struct {
+				TenantID string `json:"tenant_id"`
+			} `json:"tenants"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+		for _, t := range page.Tenants {
+			tenants = append(tenants, t.TenantID)
+		}
+		if len(page.Tenants) < tenantsPageLimit {
+			return tenants, nil
+		}
+	}
+}
+
+// rotateAll は全テナントの鍵を最大concurrency件ずつ並行してローテーションする。
+// 結果はtenantsと同じ順序で返す。
+func rotateAll(tenants []string, query string, concurrency int) rotateAllSummary {
+	summary := rotateAllSummary{Total: len(tenants), Results: make([]rotateAllResult, len(tenants))}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, tenantID := range tenants {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result, err := rotateTenant(tenantID, query, "")
+			if err != nil {
+				summary.Results[i] = rotateAllResult{TenantID: tenantID, Status: "failed", Error: err.Error()}
+				return
+			}
+			summary.Results[i] = rotateAllResult{TenantID: tenantID, Status: "rotated", Generation: result.Generation}
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range summary.Results {
+		if r.Status == "rotated" {
+			summary.Rotated++
+		} else {
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+// runRotateAll は全テナントのローテーション（--dry-runの場合は対象の一覧）を表示する。
+// 1件でも失敗した場合はエラーを返し、終了コードを非0にする。
+func runRotateAll(query string, concurrency int, dryRun bool) error {
+	tenants, err := listAllTenants()
+	if err != nil {
+		return fmt.Errorf("listing tenants: %w", err)
+	}
+
+	var summary rotateAllSummary
+	if dryRun {
+		summary = rotateAllSummary{DryRun: true, Total: len(tenants), Results: make([]rotateAllResult, len(tenants))}
+		for i, tenantID := range tenants {
+			summary.Results[i] = rotateAllResult{TenantID: tenantID, Status: "would_rotate"}
+		}
+	} else {
+		summary = rotateAll(tenants, query, concurrency)
+	}
+
+	if err := render(output, summary, func(any) string {
+		return formatRotateAllSummary(summary)
+	}); err != nil {
+		return err
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%w (%d of %d)", errRotateAllFailed, summary.Failed, summary.Total)
+	}
+	return nil
+}
+
+// formatRotateAllSummary は--allの実行結果をテキスト表示用に整形する。
+func formatRotateAllSummary(summary rotateAllSummary) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-64s %-14s %s\n", "TENANT", "STATUS", "DETAIL")
+	for _, r := range summary.Results {
+		detail := r.Error
+		if r.Status == "rotated" {
+			detail = fmt.Sprintf("generation %d", r.Generation)
+		}
+		fmt.Fprintf(&sb, "%-64s %-14s %s\n", r.TenantID, r.Status, detail)
+	}
+	if summary.DryRun {
+		fmt.Fprintf(&sb, "%d tenant(s) would be rotated (dry run)\n", summary.Total)
+	} else {
+		fmt.Fprintf(&sb, "Rotated %d of %d tenant(s), %d failed\n", summary.Rotated, summary.Total, summary.Failed)
+	}
+	return sb.String()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newRotateServer はテナント一覧とローテーションに応答するテスト用サーバーを起動する。
+// failingに含まれるテナントのローテーションは500を返す。
+func newRotateServer(t *testing.T, tenants []string, failing map[string]bool) (*sync.Map, *httptest.Server) {
+	t.Helper()
+	var rotated sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/tenants" {
+			var entries []string
+			if r.URL.Query().Get("offset") == "0" {
+				for _, id := range tenants {
+					entries = append(entries, fmt.Sprintf(`{"tenant_id":%q,"key_count":1}`, id))
+				}
+			}
+			fmt.Fprintf(w, `{"tenants":[%s]}`, strings.Join(entries, ","))
+			return
+		}
+		tenantID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/tenants/"), "/keys/rotate")
+		if !ok || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if failing[tenantID] {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"code":"INTERNAL_ERROR","message":"internal server error"}`)
+			return
+		}
+		rotated.Store(tenantID, true)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"tenant_id":%q,"generation":2}`, tenantID)
+	}))
+	t.Cleanup(server.Close)
+
+	prevURL, prevClient := apiURL, httpClient
+	apiURL, httpClient = server.URL, server.Client()
+	t.Cleanup(func() { apiURL, httpClient = prevURL, prevClient })
+	return &rotated, server
+}
+
+func TestRunRotateAll_AllSuccess(t *testing.T) {
+	tenants := []string{"tenant-a", "tenant-b", "tenant-c", "tenant-d", "tenant-e"}
+	rotated, _ := newRotateServer(t, tenants, nil)
+	buf := captureStdout(t)
+
+	if err := runRotateAll("", 2, false); err != nil {
+		t.Fatalf("runRotateAll failed: %v", err)
+	}
+	for _, id := range tenants {
+		if _, ok := rotated.Load(id); !ok {
+			t.Errorf("want %s rotated", id)
+		}
+	}
+	if !strings.Contains(buf.String(), "Rotated 5 of 5 tenant(s), 0 failed") {
+		t.Errorf("unexpected summary: %s", buf.String())
+	}
+}
+
+func TestRunRotateAll_PartialFailure(t *testing.T) {
+	tenants := []string{"tenant-a", "tenant-b", "tenant-c"}
+	rotated, _ := newRotateServer(t, tenants, map[string]bool{"tenant-b": true})
+	buf := captureStdout(t)
+
+	err := runRotateAll("", 2, false)
+	if !errors.Is(err, errRotateAllFailed) {
+		t.Fatalf("want errRotateAllFailed, got %v", err)
+	}
+	// 失敗したテナントがあっても残りのテナントはローテーションされる
+	for _, id := range []string{"tenant-a", "tenant-c"} {
+		if _, ok := rotated.Load(id); !ok {
+			t.Errorf("want %s rotated", id)
+		}
+	}
+	out := buf.String()
+	if !strings.Contains(out, "Rotated 2 of 3 tenant(s), 1 failed") || !strings.Contains(out, "internal server error") {
+		t.Errorf("unexpected summary: %s", out)
+	}
+}
+
+func TestRunRotateAll_DryRun(t *testing.T) {
+	tenants := []string{"tenant-a", "tenant-b"}
+	rotated, _ := newRotateServer(t, tenants, nil)
+	buf := captureStdout(t)
+
+	if err := runRotateAll("", 2, true); err != nil {
+		t.Fatalf("runRotateAll failed: %v", err)
+	}
+	rotated.Range(func(key, _ any) bool {
+		t.Errorf("want no rotation in dry run, got %v", key)
+		return true
+	})
+	if !strings.Contains(buf.String(), "2 tenant(s) would be rotated (dry run)") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
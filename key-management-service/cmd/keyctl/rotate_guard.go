@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rotateSkippedResult は--if-older-thanによりローテーションを見送った結果。
+type rotateSkippedResult struct {
+	TenantID   string `json:"tenant_id"`
+	Generation uint   `json:"generation"`
+	Skipped    bool   `json:"skipped"`
+	AgeDays    int    `json:"age_days"`
+}
+
+// parseKeyAge は--if-older-thanのしきい値を解析する。
+// time.ParseDurationの形式（例: 720h）に加えて日数（例: 30d）を受け付ける。
+func parseKeyAge(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("--if-older-than must be a number of days (e.g. 30d) or a duration (e.g. 720h), got %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("--if-older-than must be a number of days (e.g. 30d) or a duration (e.g. 720h), got %q", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("--if-older-than must be positive, got %q", s)
+	}
+	return d, nil
+}
+
+// fetchCurrentKeyMetadata はテナントの現在の鍵のメタデータを返す。
+// 鍵本体を取得しないよう、/keys/currentではなく鍵一覧のis_currentを使用する。
+func fetchCurrentKeyMetadata(tenantID string) (*keyMetadataResult, error) {
+	body, err := doRequest(http.MethodGet, fmt.Sprintf("%s/v1/tenants/%s/keys", apiURL, tenantID), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var list keyListResult
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	for i := range list.Keys {
+		if list.Keys[i].IsCurrent {
+			return &list.Keys[i], nil
+		}
+	}
+	return nil, fmt.Errorf("tenant %q has no current key", tenantID)
+}
+
+// rotateIfOlderThan は現在の鍵がmaxAgeより古い場合のみローテーションする。
+// 見送った場合は鍵の経過日数を表示し、エラーにはしない（自動化で終了コード0とするため）。
+func rotateIfOlderThan(tenantID, query, idempotencyKey string, maxAge time.Duration, now time.Time) error {
+	current, err := fetchCurrentKeyMetadata(tenantID)
+	if err != nil {
+		return err
+	}
+	createdAt, err := time.Parse(time.RFC3339, current.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("parsing created_at of generation %d: %w", current.Generation, err)
+	}
+
+	age := now.Sub(createdAt)
+	if age <= maxAge {
+		skipped := rotateSkippedResult{
+			TenantID:   tenantID,
+			Generation: current.Generation,
+			Skipped:    true,
+			AgeDays:    int(age / (24 * time.Hour)),
+		}
+		return render(output, skipped, func(any) string {
+			return fmt.Sprintf("Skipped rotation for tenant %q, current key is %d days old", tenantID, skipped.AgeDays)
+		})
+	}
+
+	result, err := rotateTenant(tenantID, query, idempotencyKey)
+	if err != nil {
+		return err
+	}
+	return render(output, result, func(any) string {
+		return fmt.Sprintf("Rotated key for tenant %q (new generation: %d)", tenantID, result.Generation)
+	})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRotateGuardServer は現在の鍵がageだけ経過しているテナントの鍵一覧とローテーションに応答するテスト用サーバーを起動する。
+// ローテーションが呼ばれたかどうかを返り値のポインタで確認できる。
+func newRotateGuardServer(t *testing.T, age time.Duration) *bool {
+	t.Helper()
+	rotated := false
+	createdAt := time.Now().Add(-age).UTC().Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/tenants/tenant-001/keys":
+			fmt.Fprintf(w, `{"keys":[{"tenant_id":"tenant-001","generation":1,"created_at":"2020-01-01T00:00:00Z","is_current":false},`+
+				`{"tenant_id":"tenant-001","generation":2,"created_at":%q,"is_current":true}]}`, createdAt)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/tenants/tenant-001/keys/rotate":
+			rotated = true
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"tenant_id":"tenant-001","generation":3,"is_current":true}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("KEYCTL_API_URL", server.URL)
+	return &rotated
+}
+
+func TestRotateCmd_IfOlderThan_SkipsYoungKey(t *testing.T) {
+	rotated := newRotateGuardServer(t, 10*24*time.Hour+time.Hour)
+
+	out := executeRoot(t, "rotate", "--tenant", "tenant-001", "--if-older-than", "30d")
+	if *rotated {
+		t.Error("want rotation skipped for a 10 day old key")
+	}
+	if !strings.Contains(out, "current key is 10 days old") {
+		t.Errorf("want skip message, got %q", out)
+	}
+}
+
+func TestRotateCmd_IfOlderThan_RotatesOldKey(t *testing.T) {
+	rotated := newRotateGuardServer(t, 45*24*time.Hour)
+
+	out := executeRoot(t, "rotate", "--tenant", "tenant-001", "--if-older-than", "30d")
+	if !*rotated {
+		t.Error("want rotation for a 45 day old key")
+	}
+	if !strings.Contains(out, "new generation: 3") {
+		t.Errorf("want rotate message, got %q", out)
+	}
+}
+
+func TestParseKeyAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "720h", want: 720 * time.Hour},
+		{in: "0d", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "month", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseKeyAge(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseKeyAge(%q) = %v, %v; want %v (error: %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+// secondsPerDay は経過秒数を日数に換算する際の1日の秒数。
+const secondsPerDay = 24 * 60 * 60
+
+// keyStatusResult は現在の鍵のステータスのレスポンス形式。
+type keyStatusResult struct {
+	TenantID         string `json:"tenant_id"`
+	Generation       uint   `json:"generation"`
+	CreatedAt        string `json:"created_at"`
+	AgeSeconds       int64  `json:"age_seconds"`
+	RotationInterval string `json:"rotation_interval"`
+	RotationDue      bool   `json:"rotation_due"`
+}
+
+// statusCmd は現在の鍵の経過時間とローテーション期限を表示するコマンド。
+func statusCmd() *cobra.Command {
+	var tenantID string
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the age and rotation status of a tenant's current key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			url := fmt.Sprintf("%s/v1/tenants/%s/keys/current/status", apiURL, tenantID)
+			body, err := doRequest(http.MethodGet, url, nil, http.StatusOK)
+			if err != nil {
+				return err
+			}
+
+			var result keyStatusResult
+			return renderBody(body, &result, func(any) string {
+				due := "not due"
+				if result.RotationDue {
+					due = "DUE"
+				}
+				return fmt.Sprintf("Tenant %q: generation %d, created %s, %d days old, rotation %s (interval %s)",
+					result.TenantID, result.Generation, result.CreatedAt,
+					result.AgeSeconds/secondsPerDay, due, result.RotationInterval)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// tenantsCmd はテナント関連のコマンド。
+func tenantsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "tenants",
+		Aliases: []string{"tenant"},
+		Short:   "Tenant operations",
+	}
+	cmd.AddCommand(tenantsListCmd())
+	cmd.AddCommand(tenantSetRotationCmd())
+	return cmd
+}
+
+// tenantsListCmd は鍵を持つテナントの一覧を表示するコマンド。
+func tenantsListCmd() *cobra.Command {
+	var limit, offset int
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tenants that have keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			url := fmt.Sprintf("%s/v1/tenants?limit=%d&offset=%d", apiURL, limit, offset)
+			body, err := doRequest(http.MethodGet, url, nil, http.StatusOK)
+			if err != nil {
+				return err
+			}
+
+			var result struct {
+				Tenants []struct {
+					TenantID string `json:"tenant_id"`
+					KeyCount int    `json:"key_count"`
+				} `json:"tenants"`
+				Limit  int `json:"limit"`
+				Offset int `json:"offset"`
+			}
+			return renderBody(body, &result, func(any) string {
+				var sb strings.Builder
+				fmt.Fprintf(&sb, "%-64s %s\n", "TENANT", "KEYS")
+				for _, t := range result.Tenants {
+					fmt.Fprintf(&sb, "%-64s %d\n", t.TenantID, t.KeyCount)
+				}
+				return sb.String()
+			})
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of tenants to list (1-1000)")
+	cmd.Flags().IntVar(&offset, "offset", 0, "Number of tenants to skip")
+	return cmd
+}
+
+// tenantSettingsResult はテナント設定のレスポンス形式。
+type tenantSettingsResult struct {
+	TenantID         string `json:"tenant_id"`
+	RotationInterval string `json:"rotation_interval"`
+	UpdatedAt        string `json:"updated_at,omitempty"`
+}
+
+// tenantSetRotationCmd はテナントの自動ローテーション間隔を設定するコマンド。
+func tenantSetRotationCmd() *cobra.Command {
+	var tenantID, interval string
+	cmd := &cobra.Command{
+		Use:   "set-rotation",
+		Short: "Set the auto-rotation interval of a tenant (0 disables auto-rotation)",
+		Long: "Set how old a tenant's current key may get before the server rotates it automatically.\n" +
+			"The interval accepts days (e.g. 90d) or Go durations (e.g. 720h); 0 disables auto-rotation.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tenantID == "" {
+				return fmt.Errorf("--tenant is required")
+			}
+			if interval == "" {
+				return fmt.Errorf("--interval is required")
+			}
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+
+			payload, err := json.Marshal(map[string]string{"rotation_interval": interval})
+			if err != nil {
+				return fmt.Errorf("encoding request: %w", err)
+			}
+			url := fmt.Sprintf("%s/v1/tenants/%s/settings", apiURL, tenantID)
+			body, err := doRequest(http.MethodPut, url, bytes.NewReader(payload), http.StatusOK)
+			if err != nil {
+				return err
+			}
+
+			var result tenantSettingsResult
+			return renderBody(body, &result, func(any) string {
+				if result.RotationInterval == "0s" {
+					return fmt.Sprintf("Disabled auto-rotation for tenant %q", result.TenantID)
+				}
+				return fmt.Sprintf("Set auto-rotation interval for tenant %q to %s", result.TenantID, result.RotationInterval)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "Tenant ID (required)")
+	cmd.Flags().StringVar(&interval, "interval", "", "Auto-rotation interval, e.g. 90d or 720h; 0 disables (required)")
+	if err := cmd.MarkFlagRequired("tenant"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	if err := cmd.MarkFlagRequired("interval"); err != nil {
+		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
+	}
+	return cmd
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// verboseLogHandler は--verbose指定時のログの出力先。既定は標準エラー出力（テストで差し替える）。
+var verboseLogHandler slog.Handler = slog.NewTextHandler(os.Stderr, nil)
+
+// redactedHeaders はログに値を出力しないヘッダー。
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// loggingTransport はAPIリクエストとレスポンスのステータス・ヘッダーをログに出力するRoundTripper。
+// ボディは出力しないため、鍵本体がログに含まれることはない。
+type loggingTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+	timing bool // 所要時間も出力するか（-vv）
+}
+
+// newLoggingTransport は--verboseの指定回数に応じたloggingTransportを返す。
+func newLoggingTransport(base http.RoundTripper, handler slog.Handler, verbosity int) *loggingTransport {
+	return &loggingTransport{
+		base:   base,
+		logger: slog.New(handler),
+		timing: verbosity >= 2,
+	}
+}
+
+// RoundTrip はリクエストを送信し、前後でログを出力する。
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	t.logger.InfoContext(ctx, "request",
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"headers", redactHeaders(req.Header),
+	)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	attrs := []any{"method", req.Method, "url", req.URL.Redacted()}
+	if t.timing {
+		attrs = append(attrs, "duration", time.Since(start))
+	}
+	if err != nil {
+		t.logger.ErrorContext(ctx, "request failed", append(attrs, "error", err)...)
+		return nil, err
+	}
+	t.logger.InfoContext(ctx, "response", append(attrs,
+		"status", resp.StatusCode,
+		"headers", redactHeaders(resp.Header),
+	)...)
+	return resp, nil
+}
+
+// redactHeaders はヘッダーをログ用の文字列に変換する。認証情報を含むヘッダーの値は伏せる。
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		out[name] = strings.Join(values, ", ")
+	}
+	for _, name := range redactedHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = "[REDACTED]"
+		}
+	}
+	return out
+}
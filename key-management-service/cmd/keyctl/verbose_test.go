@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerbose_LogsRequestAndResponse(t *testing.T) {
+	const keyMaterial = "c2VjcmV0LWtleS1tYXRlcmlhbA=="
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		_ = json.NewEncoder(w).Encode(keyResult{TenantID: "tenant-a", Generation: 2, Key: keyMaterial})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantLogs   bool
+		wantTiming bool
+	}{
+		{name: "verboseなし", args: []string{"get", "--tenant", "tenant-a"}},
+		{name: "-v", args: []string{"get", "--tenant", "tenant-a", "-v"}, wantLogs: true},
+		{name: "-vv", args: []string{"get", "--tenant", "tenant-a", "-vv"}, wantLogs: true, wantTiming: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			prevHandler := verboseLogHandler
+			verboseLogHandler = slog.NewJSONHandler(&logs, nil)
+			defer func() { verboseLogHandler = prevHandler }()
+
+			out := executeRoot(t, append([]string{"--api-url", server.URL}, tt.args...)...)
+			if !strings.Contains(out, keyMaterial) {
+				t.Errorf("want key in command output, got %q", out)
+			}
+
+			var records []map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+				if line == "" {
+					continue
+				}
+				var rec map[string]any
+				if err := json.Unmarshal([]byte(line), &rec); err != nil {
+					t.Fatalf("failed to parse log line %q: %v", line, err)
+				}
+				records = append(records, rec)
+			}
+			if !tt.wantLogs {
+				if len(records) != 0 {
+					t.Errorf("want no logs, got %v", records)
+				}
+				return
+			}
+			if len(records) != 2 || records[0]["msg"] != "request" || records[1]["msg"] != "response" {
+				t.Fatalf("want request and response logs, got %v", records)
+			}
+			if records[0]["method"] != http.MethodGet || records[0]["url"] != server.URL+"/v1/tenants/tenant-a/keys/current" {
+				t.Errorf("unexpected request log %v", records[0])
+			}
+			if records[1]["status"] != float64(http.StatusOK) {
+				t.Errorf("want status 200 in response log, got %v", records[1]["status"])
+			}
+			if headers, _ := records[1]["headers"].(map[string]any); headers["Set-Cookie"] != "[REDACTED]" {
+				t.Errorf("want Set-Cookie redacted, got %v", headers)
+			}
+			if _, ok := records[1]["duration"]; ok != tt.wantTiming {
+				t.Errorf("want duration logged: %v, got %v", tt.wantTiming, records[1])
+			}
+			if strings.Contains(logs.String(), keyMaterial) || strings.Contains(logs.String(), "session=secret") {
+				t.Errorf("logs must not contain key material or credentials: %s", logs.String())
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer token")
+	h.Set("X-Api-Key", "api-key")
+	h.Set("Accept", "application/json")
+
+	got := redactHeaders(h)
+	if got["Authorization"] != "[REDACTED]" || got["X-Api-Key"] != "[REDACTED]" {
+		t.Errorf("want credentials redacted, got %v", got)
+	}
+	if got["Accept"] != "application/json" {
+		t.Errorf("want Accept kept, got %v", got)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// waitReadyResult はwait-readyの結果の形式。
+type waitReadyResult struct {
+	Ready     bool  `json:"ready"`
+	Attempts  int   `json:"attempts"`
+	ElapsedMS int64 `json:"elapsed_ms"`
+}
+
+// waitReadyCmd はサーバーの/readyzが200を返すまで待つコマンド。デプロイ・CIのスクリプトでの待ち合わせに使う。
+// --waitは待ち合わせ全体の期限で、グローバルな--timeoutは各試行（/readyzの取得1回）のタイムアウトとして働く。
+func waitReadyCmd() *cobra.Command {
+	var wait, interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "wait-ready",
+		Short: "Poll the server's /readyz until it is ready or the wait elapses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if apiURL == "" {
+				return fmt.Errorf("--api-url is required (or set KEYCTL_API_URL)")
+			}
+			if wait <= 0 {
+				return fmt.Errorf("--wait must be a positive duration")
+			}
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be a positive duration")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			ctx, cancel := context.WithTimeout(ctx, wait)
+			defer cancel()
+
+			start := time.Now()
+			attempts, err := waitReady(ctx, apiURL+"/readyz", interval, cmd.ErrOrStderr())
+			if err != nil {
+				return fmt.Errorf("server not ready after %s (%d attempts): %w", time.Since(start).Round(time.Millisecond), attempts, err)
+			}
+			result := waitReadyResult{Ready: true, Attempts: attempts, ElapsedMS: time.Since(start).Milliseconds()}
+			return render(output, result, func(any) string {
+				return fmt.Sprintf("ready after %d attempts (%s)", result.Attempts, time.Since(start).Round(time.Millisecond))
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&wait, "wait", 60*time.Second, "Give up and exit non-zero if the server is not ready within this duration (--timeout limits each attempt)")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "Delay between attempts")
+	return cmd
+}
+
+// waitReady はctxが終了するまでinterval間隔でurlを取得し、200を返した時点で試行回数を返す。
+// 試行ごとにprogressへ "." を出力し、終了時に改行する。期限切れの場合は最後の失敗理由を返す。
+func waitReady(ctx context.Context, url string, interval time.Duration, progress io.Writer) (int, error) {
+	defer fmt.Fprintln(progress)
+
+	var lastErr error
+	for attempts := 1; ; attempts++ {
+		fmt.Fprint(progress, ".")
+		err := probeReady(ctx, url)
+		if err == nil {
+			return attempts, nil
+		}
+		// 期限切れで中断された試行より、それまでに完了した試行の失敗理由を優先する
+		if ctx.Err() == nil || lastErr == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(lastErr, context.DeadlineExceeded) || errors.Is(lastErr, context.Canceled) {
+				return attempts, ctx.Err()
+			}
+			return attempts, lastErr
+		case <-time.After(interval):
+		}
+	}
+}
+
+// probeReady はurlを1回取得し、200以外の場合はステータスを含むエラーを返す。
+func probeReady(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	// 接続を再利用できるようボディを読み切る
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("/readyz returned %d", resp.StatusCode)
+	}
+	return nil
+}
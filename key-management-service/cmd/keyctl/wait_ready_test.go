@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWaitReadyCmd(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+		// 3回目の試行で受付可能になる
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var progress strings.Builder
+	root := newRootCmd()
+	root.SetErr(&progress)
+	var out strings.Builder
+	prevURL, prevClient, prevOut := apiURL, httpClient, stdout
+	stdout = &out
+	t.Cleanup(func() { apiURL, httpClient, stdout = prevURL, prevClient, prevOut })
+	// --timeoutはグローバルな1回の試行のタイムアウトとして併用できる
+	root.SetArgs([]string{"--api-url", server.URL, "--timeout", "1s", "wait-ready", "--wait", "5s", "--interval", "10ms"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("wait-ready failed: %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("want 3 attempts, got %d", got)
+	}
+	if progress.String() != "...\n" {
+		t.Errorf("want a progress dot per attempt, got %q", progress.String())
+	}
+	if !strings.Contains(out.String(), "ready after 3 attempts") {
+		t.Errorf("want ready message, got %q", out.String())
+	}
+
+	t.Run("期限内に受付可能にならない場合は失敗する", func(t *testing.T) {
+		notReady := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer notReady.Close()
+
+		root := newRootCmd()
+		root.SetErr(&strings.Builder{})
+		root.SetOut(&strings.Builder{})
+		root.SetArgs([]string{"--api-url", notReady.URL, "wait-ready", "--wait", "100ms", "--interval", "10ms"})
+		err := root.Execute()
+		if err == nil || !strings.Contains(err.Error(), "not ready") || !strings.Contains(err.Error(), "503") {
+			t.Errorf("want not-ready error with the last status, got %v", err)
+		}
+	})
+
+	t.Run("待ち合わせの期限は正の値", func(t *testing.T) {
+		root := newRootCmd()
+		root.SetErr(&strings.Builder{})
+		root.SetOut(&strings.Builder{})
+		root.SetArgs([]string{"--api-url", server.URL, "wait-ready", "--wait", "0s"})
+		if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--wait") {
+			t.Errorf("want error mentioning --wait, got %v", err)
+		}
+	})
+}
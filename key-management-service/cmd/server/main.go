@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
 	"time"
 
@@ -15,10 +16,21 @@ import (
 	"key-management-service/config"
 	"key-management-service/internal/handler"
 	"key-management-service/internal/infra"
+	"key-management-service/internal/middleware"
 	"key-management-service/internal/repository"
 	"key-management-service/internal/usecase"
 )
 
+// ビルド情報。ビルド時に-ldflags "-X main.version=... -X main.commit=... -X main.buildTime=..."で埋め込む。
+var (
+	version   = "1.0.0"
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
+// idempotencyCleanupInterval は期限切れの冪等性キーの記録を削除する間隔。
+const idempotencyCleanupInterval = time.Hour
+
 func main() {
 	ctx := context.Background()
 
@@ -28,6 +40,10 @@ func main() {
 
 	// 設定読み込み
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
 
 	// ログレベル設定
 	var logLevel slog.Level
@@ -48,12 +64,12 @@ func main() {
 		slog.Error("failed to init tracer", "error", err)
 		os.Exit(1)
 	}
-	if tp != nil {
-		defer func() {
-			if err := tp.Shutdown(ctx); err != nil {
-				slog.Error("failed to shutdown tracer", "error", err)
-			}
-		}()
+
+	// メータープロバイダー初期化
+	mp, err := infra.InitMeterProvider(ctx, cfg)
+	if err != nil {
+		slog.Error("failed to init meter provider", "error", err)
+		os.Exit(1)
 	}
 
 	// トレース情報付きロガーを設定
@@ -76,42 +92,244 @@ func main() {
 		slog.Error("failed to init KMS client", "error", err)
 		os.Exit(1)
 	}
-	defer func() {
-		if closeErr := kmsClient.Close(); closeErr != nil {
-			slog.Error("failed to close KMS client", "error", closeErr)
+
+	// DI
+	// 鍵の主キーの生成方式（ID_STRATEGY=uuid|ulid）
+	idGenerator, err := repository.NewIDGenerator(cfg.IDStrategy)
+	if err != nil {
+		slog.Error("invalid ID strategy", "error", err)
+		os.Exit(1)
+	}
+	repo := repository.NewKeyRepository(db, repository.WithIDGenerator(idGenerator))
+	// 鍵の最終利用日時（LAST_USED_FLUSH_INTERVAL=0の場合は記録しない）
+	var lastUsed *usecase.LastUsedTracker
+	lastUsedCtx, stopLastUsed := context.WithCancel(ctx)
+	defer stopLastUsed()
+	if cfg.LastUsedFlushInterval > 0 {
+		lastUsed = usecase.NewLastUsedTracker(repo, cfg.LastUsedFlushInterval, cfg.DBTimeout)
+		go lastUsed.Run(lastUsedCtx)
+	}
+	retryingKMS := kmsClient.WithRetry(cfg.KMSRetryAttempts, cfg.KMSRetryBaseDelay)
+	// KMS鍵の移行中は、旧KMS鍵でラップされたままの鍵を移行元のKMS鍵で復号する
+	legacyKMSKeys := make([]usecase.LegacyKMSKey, 0, len(cfg.KMSLegacyKeyNames))
+	for _, name := range cfg.KMSLegacyKeyNames {
+		legacyKMSKeys = append(legacyKMSKeys, usecase.LegacyKMSKey{
+			Name:   name,
+			Client: infra.NewSlowLoggingKMSClient(retryingKMS.WithKeyName(name), cfg.KMSSlowThreshold),
+		})
+	}
+	// テナント専用のKMS鍵（TENANT_KMS_KEY_NAMES）が設定されたテナントの鍵はそのKMS鍵でラップする
+	tenantKMSKeys := make([]usecase.TenantKMSKey, 0, len(cfg.TenantKMSKeyNames))
+	for tenantID, name := range cfg.TenantKMSKeyNames {
+		tenantKMSKeys = append(tenantKMSKeys, usecase.TenantKMSKey{
+			TenantID: tenantID,
+			Name:     name,
+			Client:   infra.NewSlowLoggingKMSClient(retryingKMS.WithKeyName(name), cfg.KMSSlowThreshold),
+		})
+	}
+	tenantSettingsRepo := repository.NewTenantSettingsRepository(db)
+	service := usecase.NewKeyService(repo, infra.NewSlowLoggingKMSClient(retryingKMS, cfg.KMSSlowThreshold),
+		usecase.WithKMSTimeout(cfg.KMSTimeout),
+		usecase.WithKMSMaxConcurrency(cfg.KMSMaxConcurrency),
+		usecase.WithKMSMaxPlaintextBytes(cfg.KMSMaxPlaintextBytes),
+		usecase.WithDBTimeout(cfg.DBTimeout),
+		usecase.WithKeyRetention(cfg.KeyRetention),
+		usecase.WithMaxGeneration(uint(cfg.MaxGeneration)),
+		usecase.WithKMSKeyName(cfg.KMSKeyName),
+		usecase.WithLegacyKMSKeys(legacyKMSKeys...),
+		usecase.WithTenantKMSKeys(tenantKMSKeys...),
+		usecase.WithLastUsedTracker(lastUsed),
+		usecase.WithRotationDue(cfg.RotationDueInterval, tenantSettingsRepo),
+	)
+	// 起動時のセルフテスト（SKIP_STARTUP_SELFTEST=trueの場合は省略）
+	if cfg.SkipStartupSelfTest {
+		slog.Warn("startup self-test skipped", "operation", "startup_self_test")
+	} else {
+		sqlDB, err := db.DB()
+		if err != nil {
+			slog.Error("failed to get underlying sql.DB", "error", err)
+			os.Exit(1)
+		}
+		if err := selfTest(ctx, sqlDB, infra.NewSlowLoggingKMSClient(retryingKMS, cfg.KMSSlowThreshold)); err != nil {
+			slog.Error("startup self-test failed", "operation", "startup_self_test", "error", err)
+			os.Exit(1)
+		}
+	}
+	tenantValidator, err := handler.NewTenantIDValidator(cfg.TenantIDPattern, cfg.TenantIDMaxLen)
+	if err == nil {
+		// TENANT_ALLOWLISTが設定されている場合はリストのテナントのみを受け付ける
+		tenantValidator, err = tenantValidator.WithAllowlist(cfg.TenantAllowlist)
+	}
+	if err != nil {
+		slog.Error("invalid tenant ID validation config", "error", err)
+		os.Exit(1)
+	}
+	if len(cfg.TenantAllowlist) > 0 {
+		slog.Info("tenant allowlist enabled", "operation", "tenant_allowlist", "tenants", len(cfg.TenantAllowlist))
+	}
+	// 監査ログ（AUDIT_LOG_PATH未設定の場合は標準出力）
+	auditLogger := middleware.NewStdoutAuditLogger()
+	if cfg.AuditLogPath != "" {
+		auditLogger, err = middleware.NewFileAuditLogger(cfg.AuditLogPath)
+		if err != nil {
+			slog.Error("failed to open audit log", "error", err)
+			os.Exit(1)
+		}
+	}
+	var audit middleware.AuditLogger = auditLogger
+	// 監査ログのテナントIDのハッシュ化（AUDIT_REDACT_TENANT_ID=true、鍵はAUDIT_TENANT_ID_HASH_KEY）
+	// 監査イベントの検索でテナントIDを絞り込めるよう、データベースに保存するイベントには適用しない
+	if cfg.AuditRedactTenantID {
+		audit = middleware.NewPolicyAuditLogger(audit, middleware.WithTenantIDHashing([]byte(cfg.AuditTenantIDHashKey)))
+	}
+	// 監査イベントの永続化（AUDIT_PERSIST=trueの場合のみ）
+	var auditService *usecase.AuditService
+	if cfg.AuditPersist {
+		auditRepo := repository.NewAuditRepository(db)
+		audit = middleware.NewPersistentAuditLogger(audit, auditRepo)
+		auditService = usecase.NewAuditService(auditRepo, cfg.DBTimeout)
+	}
+	// 成功した読み取り操作の監査ログのサンプリング（AUDIT_SAMPLE_READS、書き込みと失敗は常に記録）
+	if cfg.AuditSampleReads < 1 {
+		audit = middleware.NewPolicyAuditLogger(audit, middleware.WithReadSampling(cfg.AuditSampleReads))
+		slog.Info("audit read sampling enabled", "operation", "audit_sampling", "rate", cfg.AuditSampleReads)
+	}
+	var auditHandler *handler.AuditHandler
+	if auditService != nil {
+		auditHandler = handler.NewAuditHandler(auditService, tenantValidator, audit)
+	}
+	var keyHandlerOpts []handler.KeyHandlerOption
+	// 無効化された鍵を404で返す（DISABLED_KEY_STATUS=not_found、410を扱えないクライアント向け）
+	if cfg.DisabledKeyStatus == config.DisabledKeyStatusNotFound {
+		keyHandlerOpts = append(keyHandlerOpts, handler.WithDisabledKeyStatus(http.StatusNotFound))
+	}
+	h := handler.NewKeyHandler(service, tenantValidator, audit, keyHandlerOpts...)
+	tenantSettingsHandler := handler.NewTenantSettingsHandler(
+		usecase.NewTenantSettingsService(tenantSettingsRepo, cfg.DBTimeout), tenantValidator, audit)
+
+	// 読み取り専用モード（READ_ONLY=trueで有効。SIGUSR1で実行中に切り替える）
+	readOnly := middleware.NewReadOnlyMode(cfg.ReadOnly)
+	if cfg.ReadOnly {
+		slog.Warn("starting in read-only mode", "operation", "read_only")
+	}
+	usr1Ch := make(chan os.Signal, 1)
+	signal.Notify(usr1Ch, syscall.SIGUSR1)
+	defer signal.Stop(usr1Ch)
+	go func() {
+		for range usr1Ch {
+			slog.Warn("read-only mode toggled", "operation", "read_only", "enabled", readOnly.Toggle())
 		}
 	}()
 
-	// DI
-	repo := repository.NewKeyRepository(db)
-	service := usecase.NewKeyService(repo, kmsClient)
-	h := handler.NewKeyHandler(service)
-	router := handler.NewRouter(h, cfg)
+	// テナントごとの間隔に従った自動ローテーション（AUTO_ROTATION_CHECK_INTERVAL=0の場合は無効）
+	// 読み取り専用モードの間は鍵を変更しないよう、ローテーションを行わない
+	autoRotateCtx, stopAutoRotate := context.WithCancel(ctx)
+	defer stopAutoRotate()
+	if cfg.AutoRotationInterval > 0 {
+		rotator := usecase.NewAutoRotator(service, tenantSettingsRepo, audit, cfg.AutoRotationInterval,
+			usecase.WithAutoRotationReadOnly(readOnly))
+		go rotator.Run(autoRotateCtx)
+	}
+	// 冪等性キーの記録はIDEMPOTENCY_KEY_TTLを経過したら定期的に削除する（0の場合は保持し続ける）
+	idempotencyRepo := repository.NewIdempotencyRepository(db, cfg.IdempotencyKeyTTL)
+	idempotencyCtx, stopIdempotencyCleanup := context.WithCancel(ctx)
+	defer stopIdempotencyCleanup()
+	if cfg.IdempotencyKeyTTL > 0 {
+		go idempotencyRepo.RunCleanup(idempotencyCtx, idempotencyCleanupInterval)
+	}
+
+	// DB疎通確認（DB_HEALTH_INTERVAL=0の場合は無効）
+	var readiness handler.ReadinessChecker
+	healthCtx, stopHealth := context.WithCancel(ctx)
+	defer stopHealth()
+	if cfg.DBHealthInterval > 0 {
+		sqlDB, err := db.DB()
+		if err != nil {
+			slog.Error("failed to get underlying sql.DB", "error", err)
+			os.Exit(1)
+		}
+		dbHealth := infra.NewDBHealthMonitor(sqlDB, cfg.DBHealthInterval)
+		go dbHealth.Run(healthCtx)
+		readiness = dbHealth
+	}
+	router := handler.NewRouter(h, cfg,
+		handler.WithIdempotencyStore(idempotencyRepo),
+		handler.WithReadOnlyMode(readOnly),
+		handler.WithAuditHandler(auditHandler),
+		handler.WithTenantSettingsHandler(tenantSettingsHandler),
+		handler.WithReadiness(readiness),
+		handler.WithBuildInfo(handler.BuildInfo{
+			Version:   version,
+			Commit:    commit,
+			BuildTime: buildTime,
+			GoVersion: runtime.Version(),
+		}),
+	)
 
 	// サーバー起動
+	tracker := middleware.NewInFlightTracker()
+	// 0のタイムアウトはnet/httpと同様に無期限を表す
 	server := &http.Server{
-		Addr:    ":" + cfg.Port,
-		Handler: router,
+		Addr:              ":" + cfg.Port,
+		Handler:           tracker.Middleware(router),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	if cfg.TLSEnabled() {
+		server.TLSConfig = newTLSConfig()
 	}
 
-	// Graceful shutdown
+	serverErr := make(chan error, 1)
 	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
-		<-sigCh
-
-		slog.Info("shutting down server...")
-		shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		defer cancel()
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			slog.Error("server shutdown error", "error", err)
+		slog.Info("starting server", "port", cfg.Port, "tls", cfg.TLSEnabled())
+		var err error
+		if cfg.TLSEnabled() {
+			err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != http.ErrServerClosed {
+			serverErr <- err
 		}
 	}()
 
-	slog.Info("starting server", "port", cfg.Port)
-	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	select {
+	case <-sigCh:
+	case err := <-serverErr:
 		slog.Error("server error", "error", err)
 		os.Exit(1)
 	}
+
+	// Graceful shutdown
+	// 処理中のリクエストが完了してからKMSクライアントとトレーサーを解放する
+	slog.Info("shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	closers := []closer{
+		{name: "DB health monitor", close: func(context.Context) error { stopHealth(); return nil }},
+		{name: "auto rotator", close: func(context.Context) error { stopAutoRotate(); return nil }},
+		{name: "idempotency key cleanup", close: func(context.Context) error { stopIdempotencyCleanup(); return nil }},
+	}
+	if lastUsed != nil {
+		// 定期書き込みを止め、残った最終利用日時を保存する
+		closers = append(closers, closer{name: "last used tracker", close: func(ctx context.Context) error {
+			stopLastUsed()
+			return lastUsed.Flush(ctx)
+		}})
+	}
+	closers = append(closers, closer{name: "KMS client", close: func(context.Context) error { return kmsClient.Close() }})
+	closers = append(closers, closer{name: "audit log", close: func(context.Context) error { return auditLogger.Close() }})
+	if mp != nil {
+		closers = append(closers, closer{name: "meter provider", close: mp.Shutdown})
+	}
+	if tp != nil {
+		closers = append(closers, closer{name: "tracer", close: tp.Shutdown})
+	}
+	gracefulShutdown(shutdownCtx, server, tracker, closers...)
 	slog.Info("server stopped")
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"key-management-service/internal/usecase"
+)
+
+// selfTestTimeout は起動時のセルフテスト全体の期限。
+const selfTestTimeout = 30 * time.Second
+
+// selfTestPlaintext はセルフテストでKMSの暗号化・復号に使う定数。鍵ではないため漏れても問題ない。
+var selfTestPlaintext = []byte("key-management-service startup self-test")
+
+// selfTestAAD はセルフテストのKMS呼び出しに付ける追加認証データ。鍵のAADと衝突しない値にする。
+var selfTestAAD = []byte("startup-self-test")
+
+// pinger はデータベースの疎通確認に使うインターフェース（*sql.DBが満たす）。
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// selfTest はリクエストを受け付ける前に、データベースへのpingとKMSでの定数の暗号化・復号を行い、
+// 依存先と実際にやり取りできることを確認する。失敗した場合は原因が分かるエラーを返す。
+func selfTest(ctx context.Context, db pinger, kms usecase.KMSClient) error {
+	ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("startup self-test: database ping failed: %w", err)
+	}
+	dbElapsed := time.Since(start)
+
+	start = time.Now()
+	ciphertext, err := kms.Encrypt(ctx, selfTestPlaintext, selfTestAAD)
+	if err != nil {
+		return fmt.Errorf("startup self-test: KMS encrypt failed: %w", err)
+	}
+	plaintext, err := kms.Decrypt(ctx, ciphertext, selfTestAAD)
+	if err != nil {
+		return fmt.Errorf("startup self-test: KMS decrypt failed: %w", err)
+	}
+	if !bytes.Equal(plaintext, selfTestPlaintext) {
+		return fmt.Errorf("startup self-test: KMS round trip returned a different plaintext")
+	}
+
+	slog.InfoContext(ctx, "startup self-test passed",
+		"operation", "startup_self_test",
+		"db_ping_ms", dbElapsed.Milliseconds(),
+		"kms_round_trip_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type stubPinger struct {
+	err error
+}
+
+func (p *stubPinger) PingContext(ctx context.Context) error {
+	return p.err
+}
+
+// stubKMSClient は平文に接頭辞を付けて暗号化し、復号時に取り除くKMSクライアント。
+type stubKMSClient struct {
+	encryptErr error
+	decryptErr error
+	tamper     bool // 復号結果を書き換える
+}
+
+func (c *stubKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	if c.encryptErr != nil {
+		return nil, c.encryptErr
+	}
+	return append([]byte("sealed:"+string(aad)+":"), plaintext...), nil
+}
+
+func (c *stubKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	if c.decryptErr != nil {
+		return nil, c.decryptErr
+	}
+	plaintext, ok := strings.CutPrefix(string(ciphertext), "sealed:"+string(aad)+":")
+	if !ok {
+		return nil, errors.New("aad mismatch")
+	}
+	if c.tamper {
+		plaintext += "!"
+	}
+	return []byte(plaintext), nil
+}
+
+func TestSelfTest(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	tests := []struct {
+		name    string
+		db      *stubPinger
+		kms     *stubKMSClient
+		wantErr string
+	}{
+		{name: "成功", db: &stubPinger{}, kms: &stubKMSClient{}},
+		{name: "DBに接続できない", db: &stubPinger{err: errUnavailable}, kms: &stubKMSClient{}, wantErr: "database ping failed"},
+		{name: "KMSで暗号化できない", db: &stubPinger{}, kms: &stubKMSClient{encryptErr: errUnavailable}, wantErr: "KMS encrypt failed"},
+		{name: "KMSで復号できない", db: &stubPinger{}, kms: &stubKMSClient{decryptErr: errUnavailable}, wantErr: "KMS decrypt failed"},
+		{name: "復号結果が一致しない", db: &stubPinger{}, kms: &stubKMSClient{tamper: true}, wantErr: "different plaintext"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := selfTest(context.Background(), tt.db, tt.kms)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("want error containing %q, got %v", tt.wantErr, err)
+			}
+			if (tt.db.err != nil || tt.kms.encryptErr != nil || tt.kms.decryptErr != nil) && !errors.Is(err, errUnavailable) {
+				t.Errorf("want the cause to be wrapped, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"key-management-service/internal/middleware"
+)
+
+// closer はシャットダウン時に解放するリソースを表す。
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// gracefulShutdown はサーバーを停止し、処理中のリクエストの完了を待ってからリソースを解放する。
+// リソースはclosersの順に解放する。待機はctxの期限内で行い、期限切れの場合もリソースは解放する。
+func gracefulShutdown(ctx context.Context, server *http.Server, tracker *middleware.InFlightTracker, closers ...closer) {
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("server shutdown error", "error", err)
+	}
+	if err := tracker.Wait(ctx); err != nil {
+		slog.Error("in-flight requests did not drain before deadline", "error", err)
+	}
+	for _, c := range closers {
+		if err := c.close(ctx); err != nil {
+			slog.Error("failed to close "+c.name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"key-management-service/internal/middleware"
+)
+
+func TestGracefulShutdown_WaitsForInFlightRequest(t *testing.T) {
+	started := make(chan struct{})
+	var completed atomic.Bool
+
+	// KMS呼び出し中を模した遅いハンドラ
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		completed.Store(true)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tracker := middleware.NewInFlightTracker()
+	server := &http.Server{Handler: tracker.Middleware(slow)}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		_ = server.Serve(ln)
+	}()
+
+	respCh := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			respCh <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		respCh <- resp.StatusCode
+	}()
+	<-started
+
+	var completedAtClose atomic.Bool
+	closed := false
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	gracefulShutdown(ctx, server, tracker, closer{
+		name: "kms",
+		close: func(context.Context) error {
+			completedAtClose.Store(completed.Load())
+			closed = true
+			return nil
+		},
+	})
+
+	if !closed {
+		t.Fatal("want closer to be called")
+	}
+	if !completedAtClose.Load() {
+		t.Error("want in-flight request to complete before Close is called")
+	}
+	if code := <-respCh; code != http.StatusOK {
+		t.Errorf("want status 200 for in-flight request, got %d", code)
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import "crypto/tls"
+
+// newTLSConfig はHTTPSで待ち受ける際のTLS設定を返す。
+// TLS 1.2未満は受け付けず、TLS 1.2では前方秘匿性のあるAEAD暗号スイートのみを許可する。
+// TLS 1.3の暗号スイートはGoが安全なもののみを使用するため指定しない。
+func newTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewTLSConfig_EnforcesMinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = newTLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(maxVersion uint16) (*http.Response, error) {
+		transport := server.Client().Transport.(*http.Transport).Clone()
+		transport.TLSClientConfig.MinVersion = tls.VersionTLS10
+		transport.TLSClientConfig.MaxVersion = maxVersion
+		defer transport.CloseIdleConnections()
+		return (&http.Client{Transport: transport}).Get(server.URL)
+	}
+
+	// TLS 1.1以下はハンドシェイクで拒否する
+	for _, version := range []uint16{tls.VersionTLS10, tls.VersionTLS11} {
+		if resp, err := get(version); err == nil {
+			resp.Body.Close()
+			t.Errorf("want handshake failure for %s, got status %d", tls.VersionName(version), resp.StatusCode)
+		}
+	}
+
+	// TLS 1.2以上は接続できる
+	for _, version := range []uint16{tls.VersionTLS12, tls.VersionTLS13} {
+		resp, err := get(version)
+		if err != nil {
+			t.Fatalf("want %s to be accepted, got %v", tls.VersionName(version), err)
+		}
+		resp.Body.Close()
+		if resp.TLS.Version != version {
+			t.Errorf("want negotiated %s, got %s", tls.VersionName(version), tls.VersionName(resp.TLS.Version))
+		}
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("want status 204, got %d", resp.StatusCode)
+		}
+	}
+}
@@ -2,36 +2,373 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config はアプリケーション設定を表す。
 type Config struct {
-	Port               string
-	DatabaseURL        string
-	KMSKeyName         string
-	GoogleCloudProject string
-	LogLevel           string
-	OtelEnabled        bool
-	OtelEndpoint       string
-	OtelServiceName    string
-	OtelSamplingRate   float64
+	Port                  string
+	TLSCertFile           string
+	TLSKeyFile            string
+	DatabaseURL           string
+	DBDriver              string
+	IDStrategy            string
+	KMSKeyName            string
+	KMSLegacyKeyNames     []string
+	TenantKMSKeyNames     map[string]string
+	GoogleCloudProject    string
+	LogLevel              string
+	LogFormat             string
+	LogOutput             string
+	OtelEnabled           bool
+	OtelEndpoint          string
+	OtelInsecure          bool
+	OtelCAFile            string
+	OtelServiceName       string
+	OtelSamplingRate      float64
+	TenantIDPattern       string
+	TenantIDMaxLen        int
+	TenantAllowlist       []string
+	MaxRequestBytes       int64
+	MaxConcurrentRequests int
+	AuditLogPath          string
+	AuditPersist          bool
+	AuditSampleReads      float64
+	AuditRedactTenantID   bool
+	AuditTenantIDHashKey  string
+	KMSSlowThreshold      time.Duration
+	KMSTimeout            time.Duration
+	KMSMaxConcurrency     int
+	KMSRetryAttempts      int
+	KMSRetryBaseDelay     time.Duration
+	KMSMaxPlaintextBytes  int
+	DBTimeout             time.Duration
+	DBConnectRetries      int
+	DBConnectBackoff      time.Duration
+	KeyRetention          int
+	MaxGeneration         int
+	RequestTimeout        time.Duration
+	ReadHeaderTimeout     time.Duration
+	ReadTimeout           time.Duration
+	WriteTimeout          time.Duration
+	IdleTimeout           time.Duration
+	DBHealthInterval      time.Duration
+	LastUsedFlushInterval time.Duration
+	IdempotencyKeyTTL     time.Duration
+	ReadOnly              bool
+	SoftDeleteEnabled     bool
+	DisabledKeyStatus     string
+	SkipStartupSelfTest   bool
+	AutoRotationInterval  time.Duration
+	RotationDueInterval   time.Duration
+	CORSAllowedOrigins    []string
+	CORSAllowedMethods    []string
+	CORSAllowedHeaders    []string
+	BasePath              string
+
+	// loadErrs はLoad時に発生した読み込みエラー（_FILEで指定したファイルが読めない等）。Validateで返す。
+	loadErrs []error
 }
 
+const (
+	// DBDriverMySQL はMySQLのデータベースドライバ。
+	DBDriverMySQL = "mysql"
+	// DBDriverPostgres はPostgreSQLのデータベースドライバ。
+	DBDriverPostgres = "postgres"
+	// IDStrategyUUID は鍵の主キーにランダムなUUID（v4）を使用する生成方式。
+	IDStrategyUUID = "uuid"
+	// IDStrategyULID は鍵の主キーに作成時刻順に並ぶULIDを使用する生成方式。
+	IDStrategyULID = "ulid"
+	// LogFormatJSON はJSON形式のログ出力。
+	LogFormatJSON = "json"
+	// LogFormatText はテキスト形式のログ出力（ローカル開発向け）。
+	LogFormatText = "text"
+	// LogOutputStdout はログを標準出力に出力する。
+	LogOutputStdout = "stdout"
+	// LogOutputStderr はログを標準エラー出力に出力する。
+	LogOutputStderr = "stderr"
+	// DisabledKeyStatusGone は無効化された鍵の取得を410 Goneで返す。
+	DisabledKeyStatusGone = "gone"
+	// DisabledKeyStatusNotFound は無効化された鍵の取得を404 Not Foundで返す（410を扱えないクライアント向け）。
+	DisabledKeyStatusNotFound = "not_found"
+	// DefaultTenantIDPattern はテナントIDの既定の許可パターン。
+	DefaultTenantIDPattern = `^[a-zA-Z0-9_-]+$`
+	// MaxTenantIDLen はTENANT_ID_MAX_LENに指定できる上限（tenant_idカラムの長さ）。
+	MaxTenantIDLen = 64
+	// DefaultTenantIDMaxLen はテナントIDの既定の最大長。
+	DefaultTenantIDMaxLen = MaxTenantIDLen
+	// DefaultMaxRequestBytes はリクエストボディの既定の最大サイズ（1MB）。
+	DefaultMaxRequestBytes = 1 << 20
+	// DefaultKMSSlowThreshold はKMS呼び出しを低速として警告する既定のしきい値。
+	DefaultKMSSlowThreshold = 500 * time.Millisecond
+	// DefaultKMSTimeout はKMS呼び出し1回あたりの既定のタイムアウト。
+	DefaultKMSTimeout = 10 * time.Second
+	// DefaultKMSRetryAttempts は一時的なエラーでKMS呼び出しを試行する既定の最大回数（初回を含む）。
+	DefaultKMSRetryAttempts = 3
+	// DefaultKMSRetryBaseDelay はKMS呼び出しを再試行するまでの既定の待ち時間。再試行のたびに倍にする。
+	DefaultKMSRetryBaseDelay = 100 * time.Millisecond
+	// DefaultKMSMaxPlaintextBytes はKMSで暗号化できる平文の既定の最大バイト数（Cloud KMSのソフトウェア鍵の上限64KiB）。
+	DefaultKMSMaxPlaintextBytes = 64 * 1024
+	// DefaultDBTimeout はデータベース呼び出し1回あたりの既定のタイムアウト。
+	DefaultDBTimeout = 5 * time.Second
+	// DefaultDBConnectBackoff は起動時のデータベース接続を再試行するまでの既定の待ち時間。再試行のたびに倍にする。
+	DefaultDBConnectBackoff = time.Second
+	// DefaultRequestTimeout はリクエスト1件あたりの既定の処理期限。
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultReadHeaderTimeout はリクエストヘッダーの読み込みの既定の期限（slowloris対策）。
+	DefaultReadHeaderTimeout = 10 * time.Second
+	// DefaultReadTimeout はリクエストボディを含むリクエスト全体の読み込みの既定の期限。
+	DefaultReadTimeout = 30 * time.Second
+	// DefaultWriteTimeout はレスポンスの書き込みの既定の期限。REQUEST_TIMEOUTの503を返せるよう、それより長くする。
+	DefaultWriteTimeout = 60 * time.Second
+	// DefaultIdleTimeout はkeep-alive接続で次のリクエストを待つ既定の期限。
+	DefaultIdleTimeout = 120 * time.Second
+	// DefaultDBHealthInterval はデータベースの疎通確認の既定の間隔。
+	DefaultDBHealthInterval = 10 * time.Second
+	// DefaultLastUsedFlushInterval は鍵の最終利用日時をデータベースに書き込む既定の間隔。
+	DefaultLastUsedFlushInterval = time.Minute
+	// DefaultIdempotencyKeyTTL は冪等性キーの記録を保持する既定の期間。
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+	// DefaultAutoRotationInterval は自動ローテーションの対象を確認する既定の間隔。
+	DefaultAutoRotationInterval = time.Hour
+	// DefaultRotationDueInterval は鍵のステータスでローテーション期限とみなす既定の経過時間（90日）。
+	DefaultRotationDueInterval = 90 * 24 * time.Hour
+	// MinAuditTenantIDHashKeyLen は監査ログのテナントIDのハッシュ化に使う鍵の最小長（バイト）。
+	MinAuditTenantIDHashKeyLen = 32
+	// DefaultCORSAllowedMethods はCORSで許可する既定のメソッド。
+	DefaultCORSAllowedMethods = "GET,POST,DELETE"
+	// DefaultCORSAllowedHeaders はCORSで許可する既定のリクエストヘッダー。
+	DefaultCORSAllowedHeaders = "Content-Type,Idempotency-Key"
+)
+
 // Load は環境変数から設定を読み込む。
+// DATABASE_URL・KMS_KEY_NAME・AUDIT_TENANT_ID_HASH_KEYは、_FILEを付けた環境変数で指定したファイルからも読み込める（EnvOrFileを参照）。
 func Load() *Config {
-	return &Config{
-		Port:               getEnv("PORT", "8080"),
-		DatabaseURL:        os.Getenv("DATABASE_URL"),
-		KMSKeyName:         os.Getenv("KMS_KEY_NAME"),
-		GoogleCloudProject: os.Getenv("GOOGLE_CLOUD_PROJECT"),
-		LogLevel:           getEnv("LOG_LEVEL", "INFO"),
-		OtelEnabled:        os.Getenv("OTEL_ENABLED") == "true",
-		OtelEndpoint:       os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
-		OtelServiceName:    getEnv("OTEL_SERVICE_NAME", "key-management-service"),
-		OtelSamplingRate:   getEnvFloat("OTEL_SAMPLING_RATE", 1.0),
+	var loadErrs []error
+	databaseURL, err := EnvOrFile("DATABASE_URL")
+	if err != nil {
+		loadErrs = append(loadErrs, err)
+	}
+	kmsKeyName, err := EnvOrFile("KMS_KEY_NAME")
+	if err != nil {
+		loadErrs = append(loadErrs, err)
+	}
+	tenantKMSKeyNames, err := TenantKMSKeyNames()
+	if err != nil {
+		loadErrs = append(loadErrs, err)
 	}
+	auditTenantIDHashKey, err := EnvOrFile("AUDIT_TENANT_ID_HASH_KEY")
+	if err != nil {
+		loadErrs = append(loadErrs, err)
+	}
+	cfg := &Config{
+		Port:                  getEnv("PORT", "8080"),
+		TLSCertFile:           os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:            os.Getenv("TLS_KEY_FILE"),
+		DatabaseURL:           databaseURL,
+		DBDriver:              getEnv("DB_DRIVER", DBDriverMySQL),
+		IDStrategy:            getEnv("ID_STRATEGY", IDStrategyUUID),
+		KMSKeyName:            kmsKeyName,
+		KMSLegacyKeyNames:     getEnvList("KMS_LEGACY_KEY_NAMES", ""),
+		TenantKMSKeyNames:     tenantKMSKeyNames,
+		GoogleCloudProject:    os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		LogLevel:              getEnv("LOG_LEVEL", "INFO"),
+		LogFormat:             getEnv("LOG_FORMAT", LogFormatJSON),
+		LogOutput:             getEnv("LOG_OUTPUT", LogOutputStdout),
+		OtelEnabled:           os.Getenv("OTEL_ENABLED") == "true",
+		OtelEndpoint:          os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		OtelInsecure:          os.Getenv("OTEL_EXPORTER_OTLP_INSECURE") == "true",
+		OtelCAFile:            os.Getenv("OTEL_EXPORTER_OTLP_CA"),
+		OtelServiceName:       getEnv("OTEL_SERVICE_NAME", "key-management-service"),
+		OtelSamplingRate:      getEnvFloat("OTEL_SAMPLING_RATE", 1.0),
+		TenantIDPattern:       getEnv("TENANT_ID_PATTERN", DefaultTenantIDPattern),
+		TenantIDMaxLen:        getEnvInt("TENANT_ID_MAX_LEN", DefaultTenantIDMaxLen, &loadErrs),
+		TenantAllowlist:       getEnvList("TENANT_ALLOWLIST", ""),
+		MaxRequestBytes:       int64(getEnvInt("MAX_REQUEST_BYTES", DefaultMaxRequestBytes, &loadErrs)),
+		MaxConcurrentRequests: getEnvInt("MAX_CONCURRENT_REQUESTS", 0, &loadErrs),
+		AuditLogPath:          os.Getenv("AUDIT_LOG_PATH"),
+		AuditPersist:          os.Getenv("AUDIT_PERSIST") == "true",
+		AuditSampleReads:      getEnvFloat("AUDIT_SAMPLE_READS", 1.0),
+		AuditRedactTenantID:   os.Getenv("AUDIT_REDACT_TENANT_ID") == "true",
+		AuditTenantIDHashKey:  auditTenantIDHashKey,
+		KMSSlowThreshold:      getEnvDuration("KMS_SLOW_THRESHOLD", DefaultKMSSlowThreshold),
+		KMSTimeout:            getEnvDuration("KMS_TIMEOUT", DefaultKMSTimeout),
+		KMSMaxConcurrency:     getEnvInt("KMS_MAX_CONCURRENCY", 0, &loadErrs),
+		KMSRetryAttempts:      getEnvInt("KMS_RETRY_ATTEMPTS", DefaultKMSRetryAttempts, &loadErrs),
+		KMSRetryBaseDelay:     getEnvDuration("KMS_RETRY_BASE_DELAY", DefaultKMSRetryBaseDelay),
+		KMSMaxPlaintextBytes:  getEnvInt("KMS_MAX_PLAINTEXT_BYTES", DefaultKMSMaxPlaintextBytes, &loadErrs),
+		DBTimeout:             getEnvDuration("DB_TIMEOUT", DefaultDBTimeout),
+		DBConnectRetries:      getEnvInt("DB_CONNECT_RETRIES", 0, &loadErrs),
+		DBConnectBackoff:      getEnvDuration("DB_CONNECT_BACKOFF", DefaultDBConnectBackoff),
+		KeyRetention:          getEnvInt("KEY_RETENTION", 0, &loadErrs),
+		MaxGeneration:         getEnvInt("MAX_GENERATION", 0, &loadErrs),
+		RequestTimeout:        getEnvDuration("REQUEST_TIMEOUT", DefaultRequestTimeout),
+		ReadHeaderTimeout:     getEnvDuration("SERVER_READ_HEADER_TIMEOUT", DefaultReadHeaderTimeout),
+		ReadTimeout:           getEnvDuration("SERVER_READ_TIMEOUT", DefaultReadTimeout),
+		WriteTimeout:          getEnvDuration("SERVER_WRITE_TIMEOUT", DefaultWriteTimeout),
+		IdleTimeout:           getEnvDuration("SERVER_IDLE_TIMEOUT", DefaultIdleTimeout),
+		DBHealthInterval:      getEnvDuration("DB_HEALTH_INTERVAL", DefaultDBHealthInterval),
+		LastUsedFlushInterval: getEnvDuration("LAST_USED_FLUSH_INTERVAL", DefaultLastUsedFlushInterval),
+		IdempotencyKeyTTL:     getEnvDuration("IDEMPOTENCY_KEY_TTL", DefaultIdempotencyKeyTTL),
+		ReadOnly:              os.Getenv("READ_ONLY") == "true",
+		SoftDeleteEnabled:     os.Getenv("SOFT_DELETE_ENABLED") == "true",
+		DisabledKeyStatus:     getEnv("DISABLED_KEY_STATUS", DisabledKeyStatusGone),
+		SkipStartupSelfTest:   os.Getenv("SKIP_STARTUP_SELFTEST") == "true",
+		AutoRotationInterval:  getEnvDuration("AUTO_ROTATION_CHECK_INTERVAL", DefaultAutoRotationInterval),
+		RotationDueInterval:   getEnvDuration("ROTATION_DUE_INTERVAL", DefaultRotationDueInterval),
+		CORSAllowedOrigins:    getEnvList("CORS_ALLOWED_ORIGINS", ""),
+		CORSAllowedMethods:    getEnvList("CORS_ALLOWED_METHODS", DefaultCORSAllowedMethods),
+		CORSAllowedHeaders:    getEnvList("CORS_ALLOWED_HEADERS", DefaultCORSAllowedHeaders),
+		BasePath:              getEnv("BASE_PATH", ""),
+	}
+	cfg.loadErrs = loadErrs
+	return cfg
+}
+
+// TLSEnabled はサーバーがHTTPSで待ち受けるか（TLS_CERT_FILEとTLS_KEY_FILEが設定されているか）を返す。
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// Validate は設定値の整合性を検証する。
+// 不正な設定が複数ある場合はすべてのエラーをまとめて返す。
+func (c *Config) Validate() error {
+	errs := append([]error(nil), c.loadErrs...)
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if math.IsNaN(c.OtelSamplingRate) || c.OtelSamplingRate < 0 || c.OtelSamplingRate > 1 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLING_RATE must be a number between 0.0 and 1.0, got %v", c.OtelSamplingRate))
+	}
+	if math.IsNaN(c.AuditSampleReads) || c.AuditSampleReads < 0 || c.AuditSampleReads > 1 {
+		errs = append(errs, fmt.Errorf("AUDIT_SAMPLE_READS must be a number between 0.0 and 1.0, got %v", c.AuditSampleReads))
+	}
+	if c.AuditRedactTenantID && len(c.AuditTenantIDHashKey) < MinAuditTenantIDHashKeyLen {
+		errs = append(errs, fmt.Errorf("AUDIT_TENANT_ID_HASH_KEY must be at least %d bytes when AUDIT_REDACT_TENANT_ID=true", MinAuditTenantIDHashKeyLen))
+	}
+	if c.OtelEnabled && c.OtelEndpoint == "" {
+		errs = append(errs, errors.New("OTEL_EXPORTER_OTLP_ENDPOINT is required when OTEL_ENABLED=true"))
+	}
+	if c.DBDriver != "" && c.DBDriver != DBDriverMySQL && c.DBDriver != DBDriverPostgres {
+		errs = append(errs, fmt.Errorf("DB_DRIVER must be %q or %q, got %q", DBDriverMySQL, DBDriverPostgres, c.DBDriver))
+	}
+	if c.IDStrategy != "" && c.IDStrategy != IDStrategyUUID && c.IDStrategy != IDStrategyULID {
+		errs = append(errs, fmt.Errorf("ID_STRATEGY must be %q or %q, got %q", IDStrategyUUID, IDStrategyULID, c.IDStrategy))
+	}
+	if c.LogFormat != "" && c.LogFormat != LogFormatJSON && c.LogFormat != LogFormatText {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT must be %q or %q, got %q", LogFormatJSON, LogFormatText, c.LogFormat))
+	}
+	if c.LogOutput != "" && c.LogOutput != LogOutputStdout && c.LogOutput != LogOutputStderr {
+		errs = append(errs, fmt.Errorf("LOG_OUTPUT must be %q or %q, got %q", LogOutputStdout, LogOutputStderr, c.LogOutput))
+	}
+	if c.DisabledKeyStatus != "" && c.DisabledKeyStatus != DisabledKeyStatusGone && c.DisabledKeyStatus != DisabledKeyStatusNotFound {
+		errs = append(errs, fmt.Errorf("DISABLED_KEY_STATUS must be %q or %q, got %q", DisabledKeyStatusGone, DisabledKeyStatusNotFound, c.DisabledKeyStatus))
+	}
+	if c.KMSKeyName != "" && slices.Contains(c.KMSLegacyKeyNames, c.KMSKeyName) {
+		errs = append(errs, errors.New("KMS_LEGACY_KEY_NAMES must not include KMS_KEY_NAME"))
+	}
+	if c.KMSSlowThreshold < 0 {
+		errs = append(errs, errors.New("KMS_SLOW_THRESHOLD must be a non-negative duration (e.g. 500ms)"))
+	}
+	if c.KMSTimeout < 0 {
+		errs = append(errs, errors.New("KMS_TIMEOUT must be a non-negative duration (e.g. 10s)"))
+	}
+	if c.TenantIDMaxLen < 1 || c.TenantIDMaxLen > MaxTenantIDLen {
+		errs = append(errs, fmt.Errorf("TENANT_ID_MAX_LEN must be between 1 and %d (tenant_id column size), got %d", MaxTenantIDLen, c.TenantIDMaxLen))
+	}
+	if c.KMSMaxConcurrency < 0 {
+		errs = append(errs, fmt.Errorf("KMS_MAX_CONCURRENCY must be 0 (unlimited) or a positive number, got %d", c.KMSMaxConcurrency))
+	}
+	if c.MaxConcurrentRequests < 0 {
+		errs = append(errs, fmt.Errorf("MAX_CONCURRENT_REQUESTS must be 0 (unlimited) or a positive number, got %d", c.MaxConcurrentRequests))
+	}
+	if c.KMSRetryAttempts < 0 {
+		errs = append(errs, fmt.Errorf("KMS_RETRY_ATTEMPTS must be 0 or 1 (no retry) or a larger number of attempts, got %d", c.KMSRetryAttempts))
+	}
+	if c.KMSRetryBaseDelay < 0 {
+		errs = append(errs, errors.New("KMS_RETRY_BASE_DELAY must be a non-negative duration (e.g. 100ms)"))
+	}
+	if c.KMSMaxPlaintextBytes < 0 {
+		errs = append(errs, fmt.Errorf("KMS_MAX_PLAINTEXT_BYTES must be 0 (unchecked) or a positive number of bytes, got %d", c.KMSMaxPlaintextBytes))
+	}
+	if c.DBTimeout < 0 {
+		errs = append(errs, errors.New("DB_TIMEOUT must be a non-negative duration (e.g. 5s)"))
+	}
+	if c.DBConnectRetries < 0 {
+		errs = append(errs, fmt.Errorf("DB_CONNECT_RETRIES must be 0 (no retry) or a positive number of retries, got %d", c.DBConnectRetries))
+	}
+	if c.DBConnectBackoff < 0 {
+		errs = append(errs, errors.New("DB_CONNECT_BACKOFF must be a non-negative duration (e.g. 1s)"))
+	}
+	if c.RequestTimeout < 0 {
+		errs = append(errs, errors.New("REQUEST_TIMEOUT must be a non-negative duration (e.g. 30s)"))
+	}
+	if c.ReadHeaderTimeout < 0 {
+		errs = append(errs, errors.New("SERVER_READ_HEADER_TIMEOUT must be a non-negative duration (e.g. 10s)"))
+	}
+	if c.ReadTimeout < 0 {
+		errs = append(errs, errors.New("SERVER_READ_TIMEOUT must be a non-negative duration (e.g. 30s)"))
+	}
+	if c.WriteTimeout < 0 {
+		errs = append(errs, errors.New("SERVER_WRITE_TIMEOUT must be a non-negative duration (e.g. 60s)"))
+	}
+	if c.IdleTimeout < 0 {
+		errs = append(errs, errors.New("SERVER_IDLE_TIMEOUT must be a non-negative duration (e.g. 120s)"))
+	}
+	// 書き込み期限がリクエストの処理期限以下だと、REQUEST_TIMEOUTの503を返す前に接続が切断される
+	if c.WriteTimeout > 0 && c.RequestTimeout > 0 && c.WriteTimeout <= c.RequestTimeout {
+		errs = append(errs, fmt.Errorf("SERVER_WRITE_TIMEOUT (%s) must be longer than REQUEST_TIMEOUT (%s)", c.WriteTimeout, c.RequestTimeout))
+	}
+	if c.DBHealthInterval < 0 {
+		errs = append(errs, errors.New("DB_HEALTH_INTERVAL must be a non-negative duration (e.g. 10s)"))
+	}
+	if c.LastUsedFlushInterval < 0 {
+		errs = append(errs, errors.New("LAST_USED_FLUSH_INTERVAL must be a non-negative duration (e.g. 1m)"))
+	}
+	if c.IdempotencyKeyTTL < 0 {
+		errs = append(errs, errors.New("IDEMPOTENCY_KEY_TTL must be a non-negative duration (e.g. 24h)"))
+	}
+	if c.AutoRotationInterval < 0 {
+		errs = append(errs, errors.New("AUTO_ROTATION_CHECK_INTERVAL must be a non-negative duration (e.g. 1h)"))
+	}
+	if c.RotationDueInterval < 0 {
+		errs = append(errs, errors.New("ROTATION_DUE_INTERVAL must be a non-negative duration (e.g. 2160h)"))
+	}
+	if c.KeyRetention < 0 {
+		errs = append(errs, fmt.Errorf("KEY_RETENTION must be 0 (unlimited) or a positive number of generations, got %d", c.KeyRetention))
+	}
+	if c.MaxGeneration < 0 || int64(c.MaxGeneration) > math.MaxUint32 {
+		errs = append(errs, fmt.Errorf("MAX_GENERATION must be between 0 (column maximum) and %d, got %d", uint32(math.MaxUint32), c.MaxGeneration))
+	}
+	if c.BasePath != "" && (!strings.HasPrefix(c.BasePath, "/") || strings.HasSuffix(c.BasePath, "/") || strings.ContainsAny(c.BasePath, "{}*")) {
+		errs = append(errs, fmt.Errorf("BASE_PATH must start with / and must not end with / (e.g. /kms), got %q", c.BasePath))
+	}
+	return errors.Join(errs...)
+}
+
+// EnvOrFile は環境変数keyの値を返す。
+// keyが空でkey+"_FILE"が設定されている場合は、そのファイルの内容を末尾の改行を除いて返す。
+// Kubernetes・Dockerのシークレットをファイルとしてマウントする場合に使用する。
+func EnvOrFile(key string) (string, error) {
+	if val := os.Getenv(key); val != "" {
+		return val, nil
+	}
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%s_FILE: %w", key, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
 }
 
 func getEnv(key, defaultVal string) string {
@@ -41,11 +378,78 @@ func getEnv(key, defaultVal string) string {
 	return defaultVal
 }
 
+// getEnvFloat は環境変数を浮動小数点数として読み込む。
+// 解析できない値はValidateで検出できるようNaNを返す。
 func getEnvFloat(key string, defaultVal float64) float64 {
 	if val := os.Getenv(key); val != "" {
-		if f, err := strconv.ParseFloat(val, 64); err == nil && f >= 0 && f <= 1 {
-			return f
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return f
+	}
+	return defaultVal
+}
+
+// getEnvDuration は環境変数を時間（例: 500ms）として読み込む。
+// 解析できない値はValidateで検出できるよう負の値を返す。
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return -1
+		}
+		return d
+	}
+	return defaultVal
+}
+
+// getEnvList は環境変数をカンマ区切りのリストとして読み込む。空要素は除外する。
+func getEnvList(key, defaultVal string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultVal), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// TenantKMSKeyNames はTENANT_KMS_KEY_NAMES（tenant=KMS鍵名のカンマ区切り）をテナントIDからKMS鍵名へのマップとして返す。
+// keyctlでもサーバーと同じテナント専用のKMS鍵を使用するために公開している。
+func TenantKMSKeyNames() (map[string]string, error) {
+	return getEnvMap("TENANT_KMS_KEY_NAMES")
+}
+
+// getEnvMap はカンマ区切りのkey=value形式の環境変数を解析する。未設定の場合はnilを返す。
+func getEnvMap(key string) (map[string]string, error) {
+	list := getEnvList(key, "")
+	if len(list) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(list))
+	for _, item := range list {
+		k, v, ok := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("%s entries must be key=value, got %q", key, item)
+		}
+		if _, dup := m[k]; dup {
+			return nil, fmt.Errorf("%s has duplicate key %q", key, k)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func getEnvInt(key string, defaultVal int, errs *[]error) int {
+	if val := os.Getenv(key); val != "" {
+		i, err := strconv.Atoi(val)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s must be an integer, got %q", key, val))
+			return defaultVal
 		}
+		return i
 	}
 	return defaultVal
 }
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr []string
+	}{
+		{
+			name: "valid with OTEL disabled",
+			cfg:  Config{OtelSamplingRate: 1.0, TenantIDMaxLen: DefaultTenantIDMaxLen},
+		},
+		{
+			name: "valid with OTEL enabled",
+			cfg:  Config{OtelEnabled: true, OtelEndpoint: "localhost:4317", OtelSamplingRate: 0.1, TenantIDMaxLen: DefaultTenantIDMaxLen},
+		},
+		{
+			name:    "sampling rate below range",
+			cfg:     Config{OtelSamplingRate: -0.1},
+			wantErr: []string{"OTEL_SAMPLING_RATE"},
+		},
+		{
+			name:    "sampling rate above range",
+			cfg:     Config{OtelSamplingRate: 1.5},
+			wantErr: []string{"OTEL_SAMPLING_RATE"},
+		},
+		{
+			name:    "sampling rate not a number",
+			cfg:     Config{OtelSamplingRate: math.NaN()},
+			wantErr: []string{"OTEL_SAMPLING_RATE"},
+		},
+		{
+			name:    "OTEL enabled without endpoint",
+			cfg:     Config{OtelEnabled: true, OtelSamplingRate: 1.0},
+			wantErr: []string{"OTEL_EXPORTER_OTLP_ENDPOINT"},
+		},
+		{
+			name:    "negative KMS slow threshold",
+			cfg:     Config{OtelSamplingRate: 1.0, KMSSlowThreshold: -time.Second},
+			wantErr: []string{"KMS_SLOW_THRESHOLD"},
+		},
+		{
+			name:    "negative timeouts",
+			cfg:     Config{OtelSamplingRate: 1.0, KMSTimeout: -1, DBTimeout: -1, RequestTimeout: -1, DBHealthInterval: -1, LastUsedFlushInterval: -1, IdempotencyKeyTTL: -1, AutoRotationInterval: -1, RotationDueInterval: -1},
+			wantErr: []string{"KMS_TIMEOUT", "DB_TIMEOUT", "REQUEST_TIMEOUT", "DB_HEALTH_INTERVAL", "LAST_USED_FLUSH_INTERVAL", "IDEMPOTENCY_KEY_TTL", "AUTO_ROTATION_CHECK_INTERVAL", "ROTATION_DUE_INTERVAL"},
+		},
+		{
+			name:    "negative server timeouts",
+			cfg:     Config{OtelSamplingRate: 1.0, ReadHeaderTimeout: -1, ReadTimeout: -1, WriteTimeout: -1, IdleTimeout: -1},
+			wantErr: []string{"SERVER_READ_HEADER_TIMEOUT", "SERVER_READ_TIMEOUT", "SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT"},
+		},
+		{
+			name:    "write timeout not longer than request timeout",
+			cfg:     Config{OtelSamplingRate: 1.0, RequestTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second},
+			wantErr: []string{"SERVER_WRITE_TIMEOUT", "REQUEST_TIMEOUT"},
+		},
+		{
+			name:    "negative max concurrent requests",
+			cfg:     Config{OtelSamplingRate: 1.0, MaxConcurrentRequests: -1},
+			wantErr: []string{"MAX_CONCURRENT_REQUESTS"},
+		},
+		{
+			name:    "negative key retention",
+			cfg:     Config{OtelSamplingRate: 1.0, KeyRetention: -1},
+			wantErr: []string{"KEY_RETENTION"},
+		},
+		{
+			name:    "tenant ID max length above column size",
+			cfg:     Config{OtelSamplingRate: 1.0, TenantIDMaxLen: MaxTenantIDLen + 1},
+			wantErr: []string{"TENANT_ID_MAX_LEN"},
+		},
+		{
+			name:    "negative KMS max concurrency",
+			cfg:     Config{OtelSamplingRate: 1.0, KMSMaxConcurrency: -1},
+			wantErr: []string{"KMS_MAX_CONCURRENCY"},
+		},
+		{
+			name:    "invalid KMS retry settings",
+			cfg:     Config{OtelSamplingRate: 1.0, KMSRetryAttempts: -1, KMSRetryBaseDelay: -1},
+			wantErr: []string{"KMS_RETRY_ATTEMPTS", "KMS_RETRY_BASE_DELAY"},
+		},
+		{
+			name:    "audit read sampling out of range",
+			cfg:     Config{OtelSamplingRate: 1.0, AuditSampleReads: 1.5},
+			wantErr: []string{"AUDIT_SAMPLE_READS"},
+		},
+		{
+			name:    "tenant ID redaction without hash key",
+			cfg:     Config{OtelSamplingRate: 1.0, AuditRedactTenantID: true},
+			wantErr: []string{"AUDIT_TENANT_ID_HASH_KEY"},
+		},
+		{
+			name:    "tenant ID redaction with short hash key",
+			cfg:     Config{OtelSamplingRate: 1.0, AuditRedactTenantID: true, AuditTenantIDHashKey: "short"},
+			wantErr: []string{"AUDIT_TENANT_ID_HASH_KEY"},
+		},
+		{
+			name: "tenant ID redaction with hash key",
+			cfg:  Config{OtelSamplingRate: 1.0, TenantIDMaxLen: DefaultTenantIDMaxLen, AuditRedactTenantID: true, AuditTenantIDHashKey: strings.Repeat("k", MinAuditTenantIDHashKeyLen)},
+		},
+		{
+			name:    "TLS certificate without key",
+			cfg:     Config{OtelSamplingRate: 1.0, TLSCertFile: "server.crt"},
+			wantErr: []string{"TLS_CERT_FILE and TLS_KEY_FILE"},
+		},
+		{
+			name:    "invalid DB connect retry settings",
+			cfg:     Config{OtelSamplingRate: 1.0, DBConnectRetries: -1, DBConnectBackoff: -1},
+			wantErr: []string{"DB_CONNECT_RETRIES", "DB_CONNECT_BACKOFF"},
+		},
+		{
+			name:    "negative KMS max plaintext bytes",
+			cfg:     Config{OtelSamplingRate: 1.0, KMSMaxPlaintextBytes: -1},
+			wantErr: []string{"KMS_MAX_PLAINTEXT_BYTES"},
+		},
+		{
+			name:    "legacy KMS keys include the current key",
+			cfg:     Config{OtelSamplingRate: 1.0, KMSKeyName: "key-b", KMSLegacyKeyNames: []string{"key-a", "key-b"}},
+			wantErr: []string{"KMS_LEGACY_KEY_NAMES"},
+		},
+		{
+			name:    "unknown db driver",
+			cfg:     Config{OtelSamplingRate: 1.0, DBDriver: "oracle"},
+			wantErr: []string{"DB_DRIVER"},
+		},
+		{
+			name:    "unknown ID strategy",
+			cfg:     Config{OtelSamplingRate: 1.0, IDStrategy: "snowflake"},
+			wantErr: []string{"ID_STRATEGY"},
+		},
+		{
+			name:    "negative max generation",
+			cfg:     Config{OtelSamplingRate: 1.0, MaxGeneration: -1},
+			wantErr: []string{"MAX_GENERATION"},
+		},
+		{
+			name:    "max generation beyond column range",
+			cfg:     Config{OtelSamplingRate: 1.0, MaxGeneration: math.MaxUint32 + 1},
+			wantErr: []string{"MAX_GENERATION"},
+		},
+		{
+			name:    "unknown log format and output",
+			cfg:     Config{OtelSamplingRate: 1.0, LogFormat: "xml", LogOutput: "file"},
+			wantErr: []string{"LOG_FORMAT", "LOG_OUTPUT"},
+		},
+		{
+			name:    "unknown disabled key status",
+			cfg:     Config{OtelSamplingRate: 1.0, DisabledKeyStatus: "forbidden"},
+			wantErr: []string{"DISABLED_KEY_STATUS"},
+		},
+		{
+			name: "disabled key status not found",
+			cfg:  Config{OtelSamplingRate: 1.0, TenantIDMaxLen: DefaultTenantIDMaxLen, DisabledKeyStatus: DisabledKeyStatusNotFound},
+		},
+		{
+			name: "valid base path",
+			cfg:  Config{OtelSamplingRate: 1.0, TenantIDMaxLen: DefaultTenantIDMaxLen, BasePath: "/kms/api"},
+		},
+		{
+			name:    "base path without leading slash",
+			cfg:     Config{OtelSamplingRate: 1.0, BasePath: "kms"},
+			wantErr: []string{"BASE_PATH"},
+		},
+		{
+			name:    "base path with trailing slash",
+			cfg:     Config{OtelSamplingRate: 1.0, BasePath: "/kms/"},
+			wantErr: []string{"BASE_PATH"},
+		},
+		{
+			name:    "multiple errors",
+			cfg:     Config{OtelEnabled: true, OtelSamplingRate: 2},
+			wantErr: []string{"OTEL_SAMPLING_RATE", "OTEL_EXPORTER_OTLP_ENDPOINT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("want error, got nil")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("want error mentioning %s, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidSamplingRate(t *testing.T) {
+	t.Setenv("OTEL_SAMPLING_RATE", "abc")
+	cfg := Load()
+	if err := cfg.Validate(); err == nil {
+		t.Error("want error for unparsable OTEL_SAMPLING_RATE, got nil")
+	}
+}
+
+func TestLoad_InvalidInteger(t *testing.T) {
+	for _, key := range []string{"TENANT_ID_MAX_LEN", "MAX_GENERATION", "KMS_MAX_CONCURRENCY"} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, "ten")
+			if err := Load().Validate(); err == nil || !strings.Contains(err.Error(), key) {
+				t.Errorf("want error mentioning %s, got %v", key, err)
+			}
+		})
+	}
+}
+
+func TestLoad_KMSSlowThreshold(t *testing.T) {
+	if got := Load().KMSSlowThreshold; got != DefaultKMSSlowThreshold {
+		t.Errorf("want default %v, got %v", DefaultKMSSlowThreshold, got)
+	}
+
+	t.Setenv("KMS_SLOW_THRESHOLD", "2s")
+	if got := Load().KMSSlowThreshold; got != 2*time.Second {
+		t.Errorf("want 2s, got %v", got)
+	}
+
+	t.Setenv("KMS_SLOW_THRESHOLD", "fast")
+	if err := Load().Validate(); err == nil {
+		t.Error("want error for unparsable KMS_SLOW_THRESHOLD, got nil")
+	}
+}
+
+func TestLoad_ServerTimeouts(t *testing.T) {
+	cfg := Load()
+	if cfg.ReadHeaderTimeout != DefaultReadHeaderTimeout || cfg.ReadTimeout != DefaultReadTimeout ||
+		cfg.WriteTimeout != DefaultWriteTimeout || cfg.IdleTimeout != DefaultIdleTimeout {
+		t.Errorf("want defaults, got read_header=%v read=%v write=%v idle=%v",
+			cfg.ReadHeaderTimeout, cfg.ReadTimeout, cfg.WriteTimeout, cfg.IdleTimeout)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("defaults must be valid: %v", err)
+	}
+
+	t.Setenv("SERVER_READ_HEADER_TIMEOUT", "5s")
+	t.Setenv("SERVER_READ_TIMEOUT", "15s")
+	t.Setenv("SERVER_WRITE_TIMEOUT", "45s")
+	t.Setenv("SERVER_IDLE_TIMEOUT", "0")
+	cfg = Load()
+	if cfg.ReadHeaderTimeout != 5*time.Second || cfg.ReadTimeout != 15*time.Second ||
+		cfg.WriteTimeout != 45*time.Second || cfg.IdleTimeout != 0 {
+		t.Errorf("want 5s/15s/45s/0, got read_header=%v read=%v write=%v idle=%v",
+			cfg.ReadHeaderTimeout, cfg.ReadTimeout, cfg.WriteTimeout, cfg.IdleTimeout)
+	}
+
+	t.Setenv("SERVER_READ_HEADER_TIMEOUT", "soon")
+	if err := Load().Validate(); err == nil || !strings.Contains(err.Error(), "SERVER_READ_HEADER_TIMEOUT") {
+		t.Errorf("want error for unparsable SERVER_READ_HEADER_TIMEOUT, got %v", err)
+	}
+}
+
+func TestLoad_TenantKMSKeyNames(t *testing.T) {
+	if got := Load().TenantKMSKeyNames; got != nil {
+		t.Errorf("want nil by default, got %v", got)
+	}
+
+	t.Setenv("TENANT_KMS_KEY_NAMES", "tenant-a=projects/p/locations/l/keyRings/r/cryptoKeys/a, tenant-b=projects/p/locations/l/keyRings/r/cryptoKeys/b")
+	cfg := Load()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("want valid config, got %v", err)
+	}
+	if got := cfg.TenantKMSKeyNames["tenant-b"]; got != "projects/p/locations/l/keyRings/r/cryptoKeys/b" || len(cfg.TenantKMSKeyNames) != 2 {
+		t.Errorf("unexpected mapping: %v", cfg.TenantKMSKeyNames)
+	}
+
+	for _, v := range []string{"tenant-a", "tenant-a=", "=key", "tenant-a=x,tenant-a=y"} {
+		t.Setenv("TENANT_KMS_KEY_NAMES", v)
+		if err := Load().Validate(); err == nil || !strings.Contains(err.Error(), "TENANT_KMS_KEY_NAMES") {
+			t.Errorf("%q: want TENANT_KMS_KEY_NAMES error, got %v", v, err)
+		}
+	}
+}
+
+func TestLoad_CORS(t *testing.T) {
+	cfg := Load()
+	if len(cfg.CORSAllowedOrigins) != 0 {
+		t.Errorf("want CORS disabled by default, got origins %v", cfg.CORSAllowedOrigins)
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com,")
+	cfg = Load()
+	if len(cfg.CORSAllowedOrigins) != 2 || cfg.CORSAllowedOrigins[1] != "https://b.example.com" {
+		t.Errorf("unexpected origins: %v", cfg.CORSAllowedOrigins)
+	}
+	if len(cfg.CORSAllowedMethods) != 3 {
+		t.Errorf("want default methods, got %v", cfg.CORSAllowedMethods)
+	}
+}
+
+func TestLoad_OtlpTransport(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	t.Setenv("OTEL_EXPORTER_OTLP_CA", "/etc/otel/ca.pem")
+	cfg := Load()
+	if !cfg.OtelInsecure {
+		t.Error("want OtelInsecure true")
+	}
+	if cfg.OtelCAFile != "/etc/otel/ca.pem" {
+		t.Errorf("want OtelCAFile /etc/otel/ca.pem, got %s", cfg.OtelCAFile)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+	if Load().OtelInsecure {
+		t.Error("want OtelInsecure false by default")
+	}
+}
+
+func TestLoad_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	dsnFile := filepath.Join(dir, "database_url")
+	if err := os.WriteFile(dsnFile, []byte("user:pass@tcp(db:3306)/keys\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	kmsFile := filepath.Join(dir, "kms_key_name")
+	if err := os.WriteFile(kmsFile, []byte("projects/p/locations/l/keyRings/r/cryptoKeys/k\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("read from file", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "")
+		t.Setenv("DATABASE_URL_FILE", dsnFile)
+		t.Setenv("KMS_KEY_NAME", "")
+		t.Setenv("KMS_KEY_NAME_FILE", kmsFile)
+		cfg := Load()
+		if cfg.DatabaseURL != "user:pass@tcp(db:3306)/keys" {
+			t.Errorf("want DatabaseURL from file without trailing newline, got %q", cfg.DatabaseURL)
+		}
+		if cfg.KMSKeyName != "projects/p/locations/l/keyRings/r/cryptoKeys/k" {
+			t.Errorf("want KMSKeyName from file without trailing newline, got %q", cfg.KMSKeyName)
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("direct value takes precedence", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "direct-dsn")
+		t.Setenv("DATABASE_URL_FILE", dsnFile)
+		if got := Load().DatabaseURL; got != "direct-dsn" {
+			t.Errorf("want direct-dsn, got %q", got)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "")
+		t.Setenv("DATABASE_URL_FILE", filepath.Join(dir, "missing"))
+		err := Load().Validate()
+		if err == nil || !strings.Contains(err.Error(), "DATABASE_URL_FILE") {
+			t.Errorf("want error mentioning DATABASE_URL_FILE, got %v", err)
+		}
+	})
+}
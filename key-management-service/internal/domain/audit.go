@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// AuditEvent は永続化された監査イベントを表す。
+type AuditEvent struct {
+	ID         uint64
+	TenantID   string
+	Operation  string
+	Generation uint
+	Result     string
+	RequestID  string
+	CreatedAt  time.Time
+}
+
+// AuditFilter は監査イベントの絞り込み条件を表す。時刻の条件は境界値を含む。
+type AuditFilter struct {
+	Since     *time.Time // 指定時刻以降のイベント
+	Until     *time.Time // 指定時刻以前のイベント
+	Operation string     // 空の場合は全操作
+	Limit     int
+	Offset    int
+}
+
+// Validate は絞り込み条件の整合性を検証する。
+func (f AuditFilter) Validate() error {
+	if f.Since != nil && f.Until != nil && f.Since.After(*f.Until) {
+		return ErrInvalidAuditFilter
+	}
+	return nil
+}
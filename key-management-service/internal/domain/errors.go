@@ -12,15 +12,87 @@ var (
 	// ErrKeyDisabled は指定された鍵が無効化されている場合のエラー。
 	ErrKeyDisabled = errors.New("key is disabled")
 
+	// ErrKeyExpired は現在の鍵の有効期限が切れている場合のエラー。
+	ErrKeyExpired = errors.New("key has expired")
+
 	// ErrKeyAlreadyDisabled は指定された鍵が既に無効化されている場合のエラー。
 	ErrKeyAlreadyDisabled = errors.New("key is already disabled")
 
+	// ErrKeyAlreadyDeprecated は指定された鍵が既に非推奨の場合のエラー。
+	ErrKeyAlreadyDeprecated = errors.New("key is already deprecated")
+
 	// ErrInvalidTenantID はテナントIDの形式が不正な場合のエラー。
 	ErrInvalidTenantID = errors.New("invalid tenant ID")
 
+	// ErrTenantNotAllowed はテナントが許可リストに含まれていない場合のエラー。
+	ErrTenantNotAllowed = errors.New("tenant not allowed")
+
 	// ErrInvalidGeneration は世代番号が不正な場合のエラー。
 	ErrInvalidGeneration = errors.New("invalid generation")
 
+	// ErrGenerationAlreadyExists は指定された世代の鍵が既に存在する場合のエラー。
+	ErrGenerationAlreadyExists = errors.New("generation already exists")
+
+	// ErrMaxGenerationReached はローテーション後の世代番号が上限を超える場合のエラー。
+	ErrMaxGenerationReached = errors.New("max generation reached")
+
+	// ErrInvalidKeyStatus は鍵のステータスが不正な場合のエラー。
+	ErrInvalidKeyStatus = errors.New("invalid key status")
+
+	// ErrInvalidKeySize は鍵長が許可されていない値の場合のエラー。
+	ErrInvalidKeySize = errors.New("invalid key size")
+
+	// ErrInvalidKeyType は鍵種別が不正な場合のエラー。
+	ErrInvalidKeyType = errors.New("invalid key type")
+
+	// ErrPayloadTooLarge はKMSに渡すデータがKMSのサイズ上限を超える場合のエラー。
+	ErrPayloadTooLarge = errors.New("payload too large for KMS")
+
+	// ErrInvalidExpiry は鍵の有効期限が現在より後でない場合のエラー。
+	ErrInvalidExpiry = errors.New("invalid key expiry")
+
+	// ErrInvalidKeyFilter は鍵一覧の絞り込み条件が不正な場合のエラー。
+	ErrInvalidKeyFilter = errors.New("invalid key filter")
+
+	// ErrInvalidAuditFilter は監査イベントの絞り込み条件が不正な場合のエラー。
+	ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+	// ErrInvalidDisableReason は無効化理由が長すぎる場合のエラー。
+	ErrInvalidDisableReason = errors.New("invalid disable reason")
+
+	// ErrInvalidBatchSize は一括取得の世代数が0件または上限を超える場合のエラー。
+	ErrInvalidBatchSize = errors.New("invalid batch size")
+
+	// ErrBatchPayloadTooLarge は一括暗号化の平文の合計バイト数が上限を超える場合のエラー。
+	ErrBatchPayloadTooLarge = errors.New("batch payload too large")
+
+	// ErrDecryptionFailed は暗号文を鍵で復号できない（認証に失敗した）場合のエラー。
+	ErrDecryptionFailed = errors.New("ciphertext could not be decrypted")
+
+	// ErrCorruptKey はKMSで復号した鍵素材の長さが記録された鍵長と一致しない場合のエラー（保存された鍵の破損など）。
+	ErrCorruptKey = errors.New("decrypted key material has unexpected length")
+
+	// ErrUnsupportedKeyType は鍵種別が要求された操作に対応していない場合のエラー（HMAC鍵での暗号化など）。
+	ErrUnsupportedKeyType = errors.New("unsupported key type for this operation")
+
+	// ErrUpstreamTimeout はKMSまたはデータベースの呼び出しが期限内に完了しなかった場合のエラー。
+	ErrUpstreamTimeout = errors.New("upstream timeout")
+
+	// ErrKMSPermission はKMSの呼び出しが権限不足で拒否された場合のエラー（IAM設定の誤りなど）。
+	ErrKMSPermission = errors.New("KMS permission denied")
+
+	// ErrKMSKeyUnavailable はKMSの暗号鍵が存在しない、または無効化されている場合のエラー。
+	ErrKMSKeyUnavailable = errors.New("KMS key unavailable")
+
+	// ErrKMSUnavailable はKMSが一時的に利用できない場合のエラー。再試行で回復する可能性がある。
+	ErrKMSUnavailable = errors.New("KMS temporarily unavailable")
+
+	// ErrInvalidRewrapSource は再ラップ元のKMS鍵名が未指定、または現在のKMS鍵と同じ場合のエラー。
+	ErrInvalidRewrapSource = errors.New("invalid rewrap source KMS key")
+
+	// ErrInvalidRotationInterval は自動ローテーション間隔の形式が不正、または下限未満の場合のエラー。
+	ErrInvalidRotationInterval = errors.New("invalid rotation interval")
+
 	// ErrMigrationFailed はマイグレーション実行時のエラー。
 	ErrMigrationFailed = errors.New("migration failed")
 
@@ -29,4 +101,7 @@ var (
 
 	// ErrInvalidMigrationFile はマイグレーションファイルのフォーマットが不正な場合のエラー。
 	ErrInvalidMigrationFile = errors.New("invalid migration file")
+
+	// ErrDuplicateMigrationVersion はmigrationsディレクトリに同じバージョンのファイルが複数ある場合のエラー。
+	ErrDuplicateMigrationVersion = errors.New("duplicate migration version")
 )
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// MaxIdempotencyKeyLen は冪等性キーの最大長。
+const MaxIdempotencyKeyLen = 255
+
+// IdempotencyRecord は冪等性キーに対応する初回リクエストの結果を表す。
+// 初回リクエストの処理中はStatusCodeが0の予約として記録される。
+type IdempotencyRecord struct {
+	Key         string    // クライアントが指定した冪等性キー
+	RequestPath string    // メソッドとパス（例: "POST /v1/tenants/t1/keys/rotate"）
+	RequestHash string    // クエリ文字列とボディのSHA-256（16進数）。異なる内容での再利用の検出に使う
+	StatusCode  int       // 初回レスポンスのステータスコード（処理中は0）
+	Body        []byte    // 初回レスポンスのボディ
+	CreatedAt   time.Time // 記録日時
+}
+
+// Pending は初回リクエストが処理中（結果が未記録）かどうかを返す。
+func (r *IdempotencyRecord) Pending() bool {
+	return r.StatusCode == 0
+}
@@ -1,7 +1,11 @@
 // Package domain はドメインモデルとビジネスルールを定義する。
 package domain
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // KeyStatus は暗号鍵のステータスを表す。
 type KeyStatus string
@@ -9,32 +13,324 @@ type KeyStatus string
 const (
 	// KeyStatusActive は有効な鍵を表す。
 	KeyStatusActive KeyStatus = "active"
+	// KeyStatusDeprecated は非推奨の鍵を表す。既存データの復号には使えるが、現在の鍵（新規の暗号化に使う鍵）には選ばれない。
+	KeyStatusDeprecated KeyStatus = "deprecated"
 	// KeyStatusDisabled は無効化された鍵を表す。
 	KeyStatusDisabled KeyStatus = "disabled"
 )
 
+// IsValid はステータスが定義済みの値かどうかを返す。
+func (s KeyStatus) IsValid() bool {
+	switch s {
+	case KeyStatusActive, KeyStatusDeprecated, KeyStatusDisabled:
+		return true
+	default:
+		return false
+	}
+}
+
+// KeyType は鍵の種別を表す。
+type KeyType string
+
+const (
+	// KeyTypeAES はAES暗号鍵を表す。
+	KeyTypeAES KeyType = "aes"
+	// KeyTypeHMAC はHMAC署名用のシークレットを表す。
+	KeyTypeHMAC KeyType = "hmac"
+)
+
+// DefaultKeyType は種別が指定されなかった場合の鍵種別。
+const DefaultKeyType = KeyTypeAES
+
+// DefaultKeyBits はAES鍵で鍵長が指定されなかった場合の鍵長（ビット）。
+const DefaultKeyBits = 256
+
+// IsValid は種別が定義済みの値かどうかを返す。
+func (t KeyType) IsValid() bool {
+	switch t {
+	case KeyTypeAES, KeyTypeHMAC:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultBits は種別ごとの既定の鍵長（ビット）を返す。
+// HMACはHMAC-SHA512のブロック長に合わせて512ビットとする。
+func (t KeyType) DefaultBits() int {
+	if t == KeyTypeHMAC {
+		return 512
+	}
+	return DefaultKeyBits
+}
+
+// IsValidBits は種別に対して鍵長が許可された値かどうかを返す。
+func (t KeyType) IsValidBits(bits int) bool {
+	switch t {
+	case KeyTypeAES:
+		return bits == 128 || bits == 192 || bits == 256
+	case KeyTypeHMAC:
+		return bits == 256 || bits == 384 || bits == 512
+	default:
+		return false
+	}
+}
+
+// MaxDisableReasonLen は無効化理由の最大長（文字数）。
+const MaxDisableReasonLen = 255
+
+// MaxKMSKeyNameLen は鍵ごとに記録するKMS鍵名の最大長。
+const MaxKMSKeyNameLen = 512
+
+// DisableReasonRetention は保持世代数を超えて自動的に無効化された鍵に記録する理由。
+const DisableReasonRetention = "exceeded key retention window"
+
+// KeySpec は鍵生成時の指定を表す。
+type KeySpec struct {
+	Type      KeyType    // 空の場合はDefaultKeyType
+	Bits      int        // 0の場合は種別ごとの既定値
+	ExpiresAt *time.Time // 有効期限（nilの場合は期限なし）
+}
+
 // EncryptionKey は暗号鍵エンティティを表す。
 type EncryptionKey struct {
-	ID           string
-	TenantID     string
-	Generation   uint
-	EncryptedKey []byte
-	Status       KeyStatus
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID             string
+	TenantID       string
+	Generation     uint
+	KeyType        KeyType
+	Bits           int
+	EncryptedKey   []byte
+	KMSKeyName     string // ラップに使用したKMS鍵名（記録前に作成された鍵は空）
+	TenantAAD      bool   // テナントIDを追加認証データ（AAD）としてラップしたか（導入前に作成・インポートされた鍵はfalse）
+	Status         KeyStatus
+	ExpiresAt      *time.Time // 有効期限（期限なしの場合はnil）。期限切れの鍵は現在の鍵として返さないが、過去データの復号には引き続き使用できる
+	LastUsedAt     *time.Time // 最後に取得された日時（未使用の場合はnil）
+	DisabledAt     *time.Time // 無効化日時（有効な鍵の場合はnil）
+	DisabledReason string     // 無効化の理由
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// AAD は鍵素材のKMSでの復号に指定する追加認証データを返す。テナントIDに紐づけずにラップした鍵はnilを返す。
+func (k *EncryptionKey) AAD() []byte {
+	if !k.TenantAAD {
+		return nil
+	}
+	return TenantAAD(k.TenantID)
+}
+
+// Expired は鍵の有効期限がnow時点で切れているかを返す。期限が設定されていない鍵はfalse。
+func (k *EncryptionKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(now)
+}
+
+// KeySize は復号後の鍵素材の長さ（バイト）を返す。鍵長が記録されていない場合は種別ごとの既定値とみなす。
+func (k *EncryptionKey) KeySize() int {
+	bits := k.Bits
+	if bits == 0 {
+		bits = k.KeyType.DefaultBits()
+	}
+	return bits / 8
+}
+
+// TenantAAD はテナントIDをKMSの追加認証データにする。
+// 暗号文をテナントに紐づけ、データベース上で別テナントの行に移し替えられた暗号文の復号を失敗させる。
+func TenantAAD(tenantID string) []byte {
+	return []byte(tenantID)
+}
+
+// KeyFilter は鍵一覧の絞り込み条件を表す。いずれの条件も境界値を含む。
+type KeyFilter struct {
+	CreatedAfter  *time.Time // 指定時刻以降に作成された鍵
+	MinGeneration uint       // 0の場合は下限なし
+	MaxGeneration uint       // 0の場合は上限なし
+}
+
+// IsZero は絞り込み条件が指定されていないかを返す。
+func (f KeyFilter) IsZero() bool {
+	return f.CreatedAfter == nil && f.MinGeneration == 0 && f.MaxGeneration == 0
+}
+
+// Validate は絞り込み条件の整合性を検証する。
+func (f KeyFilter) Validate() error {
+	if f.MinGeneration > 0 && f.MaxGeneration > 0 && f.MinGeneration > f.MaxGeneration {
+		return ErrInvalidKeyFilter
+	}
+	return nil
 }
 
 // KeyMetadata は暗号鍵のメタデータを表す（平文鍵を含まない）。
 type KeyMetadata struct {
+	TenantID       string
+	Generation     uint
+	KeyType        KeyType
+	Bits           int
+	Status         KeyStatus
+	KMSKeyName     string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ExpiresAt      *time.Time
+	LastUsedAt     *time.Time
+	DisabledAt     *time.Time
+	DisabledReason string
+	IsCurrent      bool // 現在の鍵（GetCurrentKeyが返す最新の有効な鍵）か
+}
+
+// CurrentKeyStatus はテナントの現在の鍵の経過時間とローテーション期限を表す。
+type CurrentKeyStatus struct {
 	TenantID   string
 	Generation uint
-	Status     KeyStatus
 	CreatedAt  time.Time
+	Age        time.Duration
+	// RotationInterval は期限の判定に使用した間隔（テナントの自動ローテーション間隔、未設定の場合は既定値）。0の場合は期限なし。
+	RotationInterval time.Duration
+	RotationDue      bool
 }
 
 // Key は復号済みの暗号鍵を表す。
 type Key struct {
 	TenantID   string
 	Generation uint
+	KeyType    KeyType
+	Bits       int
 	Key        []byte // 平文の鍵（Base64エンコード前）
+	Status     KeyStatus
+	CreatedAt  time.Time
+	UpdatedAt  time.Time // 鍵レコードの最終更新日時（ETagの算出に使用）
+}
+
+// Zero は平文の鍵を0で上書きする。レスポンスへの書き込み後など、不要になった時点で呼び出す。
+// Goではコピーやエンコード済みの文字列までは消去できないため、メモリ上に残る時間を短くする目的で使う。
+func (k *Key) Zero() {
+	if k == nil {
+		return
+	}
+	clear(k.Key)
+}
+
+// MaxGenerationLimit は世代番号として扱える上限（generation列の符号なし32ビット整数の最大値）。
+const MaxGenerationLimit = math.MaxUint32
+
+// MaxBatchGetGenerations は一括取得で一度に指定できる世代数の上限。
+const MaxBatchGetGenerations = 100
+
+// BatchKeyStatus は一括取得における世代ごとの取得結果を表す。
+type BatchKeyStatus string
+
+const (
+	// BatchKeyStatusOK は鍵を取得できたことを表す。
+	BatchKeyStatusOK BatchKeyStatus = "ok"
+	// BatchKeyStatusDisabled は鍵が無効化されているため返さなかったことを表す。
+	BatchKeyStatusDisabled BatchKeyStatus = "disabled"
+	// BatchKeyStatusNotFound は鍵が存在しないことを表す。
+	BatchKeyStatusNotFound BatchKeyStatus = "not_found"
+)
+
+// BatchKeyResult は一括取得における1世代分の結果を表す。
+type BatchKeyResult struct {
+	Generation uint
+	Status     BatchKeyStatus
+	Key        *Key // StatusがBatchKeyStatusOKの場合のみ設定
+}
+
+// MaxBatchDisableGenerations は一括無効化で一度に指定できる世代数の上限。
+const MaxBatchDisableGenerations = 100
+
+// BatchDisableStatus は一括無効化における世代ごとの結果を表す。
+type BatchDisableStatus string
+
+const (
+	// BatchDisableStatusDisabled は鍵を無効化したことを表す。
+	BatchDisableStatusDisabled BatchDisableStatus = "disabled"
+	// BatchDisableStatusAlreadyDisabled は鍵が既に無効化されていたことを表す。
+	BatchDisableStatusAlreadyDisabled BatchDisableStatus = "already_disabled"
+	// BatchDisableStatusNotFound は鍵が存在しないことを表す。
+	BatchDisableStatusNotFound BatchDisableStatus = "not_found"
+)
+
+// BatchDisableResult は一括無効化における1世代分の結果を表す。
+type BatchDisableResult struct {
+	Generation uint
+	Status     BatchDisableStatus
+}
+
+// MaxBatchEncryptItems は一括暗号化で一度に指定できる平文の件数の上限。
+const MaxBatchEncryptItems = 1000
+
+// MaxBatchEncryptBytes は一括暗号化で一度に指定できる平文の合計バイト数の上限。
+const MaxBatchEncryptBytes = 1 << 20
+
+// BatchEncryptResult は一括暗号化の結果を表す。
+// Ciphertextsは指定順で、各要素は nonce(12バイト) | AES-GCMの暗号文（認証タグを含む）。
+type BatchEncryptResult struct {
+	TenantID    string
+	Generation  uint
+	Ciphertexts [][]byte
+}
+
+// MinCiphertextSize は BatchEncryptResult 形式の暗号文の最小バイト数（nonceと認証タグ）。
+// これより短い値は暗号文として不正であり、復号を試みるまでもなく拒否できる。
+const MinCiphertextSize = 12 + 16
+
+// MaxAutoDecryptAttempts は世代を指定しない復号で試行する世代数の上限（新しい世代から順に試す）。
+const MaxAutoDecryptAttempts = 10
+
+// DecryptResult は復号の結果を表す。Generationは復号に使用した世代。
+type DecryptResult struct {
+	TenantID   string
+	Generation uint
+	Plaintext  []byte
+}
+
+// RewrapResult はKMS鍵の切り替えに伴う再ラップの結果を表す。
+type RewrapResult struct {
+	FromKMSKeyName string
+	ToKMSKeyName   string
+	Rewrapped      int // 再ラップして保存した鍵の数
+	Failed         int // 復号・暗号化・保存のいずれかに失敗し、元のKMS鍵のまま残った鍵の数
+}
+
+// StatusBackfillResult はステータスが空の鍵へのステータスの補完結果を表す。
+type StatusBackfillResult struct {
+	Activated int  // activeにした（dry-runの場合はする）鍵の数
+	Disabled  int  // 無効化日時が記録されているためdisabledにした（する）鍵の数
+	DryRun    bool // 件数のみを数え、更新しなかったか
+}
+
+// Total は補完の対象となった鍵の数を返す。
+func (r StatusBackfillResult) Total() int {
+	return r.Activated + r.Disabled
+}
+
+// GenerationRange は連続する世代の範囲（From以上To以下）を表す。
+type GenerationRange struct {
+	From uint
+	To   uint
+}
+
+// String は範囲を表示用の文字列（例: 3、5-7）にする。
+func (r GenerationRange) String() string {
+	if r.From == r.To {
+		return fmt.Sprintf("%d", r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// IntegrityReport はテナントの鍵の世代の整合性の確認結果を表す。
+// 手動のDB操作などで生じた不整合を報告するためのもので、修復は行わない。
+type IntegrityReport struct {
+	TenantID      string
+	KeyCount      int
+	MaxGeneration uint
+	// MissingGenerations は1から最大世代までの間で欠けている世代の範囲。
+	MissingGenerations []GenerationRange
+	// DuplicateGenerations は複数の行が存在する世代。
+	DuplicateGenerations []uint
+	// DuplicateActiveGenerations は有効な行が複数存在し、鍵が一意に決まらない世代。
+	DuplicateActiveGenerations []uint
+}
+
+// OK は不整合が見つからなかったかを返す。
+func (r *IntegrityReport) OK() bool {
+	return len(r.MissingGenerations) == 0 && len(r.DuplicateGenerations) == 0 && len(r.DuplicateActiveGenerations) == 0
 }
@@ -0,0 +1,23 @@
+package domain
+
+import "testing"
+
+func TestKey_Zero(t *testing.T) {
+	material := []byte("plain-key-material")
+	key := &Key{TenantID: "tenant-001", Generation: 1, Key: material}
+
+	key.Zero()
+
+	for i, b := range material {
+		if b != 0 {
+			t.Fatalf("byte %d not cleared: %v", i, material)
+		}
+	}
+	if len(key.Key) != len("plain-key-material") {
+		t.Errorf("want length preserved, got %d", len(key.Key))
+	}
+
+	// nilでもpanicしない
+	var nilKey *Key
+	nilKey.Zero()
+}
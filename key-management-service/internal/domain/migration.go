@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // MigrationStatus はマイグレーションの適用状態を表す
 type MigrationStatus string
@@ -17,4 +20,40 @@ type Migration struct {
 	AppliedAt *time.Time      // 適用日時（未適用の場合はnil）
 	FilePath  string          // マイグレーションファイルのパス
 	Status    MigrationStatus // 適用状態
+	SQL       string          // マイグレーションSQL（実行計画の作成時のみ設定）
+}
+
+// MigrationError はマイグレーションの一括適用が途中で失敗したことを表すエラー。
+// 失敗したバージョンと、失敗前に適用済みとなった件数を保持する。
+type MigrationError struct {
+	Version string // 失敗したマイグレーションのバージョン
+	Applied int    // 失敗前に適用できた件数
+	Err     error  // 失敗の原因
+}
+
+// Error はエラーメッセージを返す。
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("%v: version %s (applied %d before failure): %v", ErrMigrationFailed, e.Version, e.Applied, e.Err)
+}
+
+// Unwrap はErrMigrationFailedと原因のエラーを返す。
+func (e *MigrationError) Unwrap() []error {
+	return []error{ErrMigrationFailed, e.Err}
+}
+
+// DuplicateMigrationVersionError は同じバージョンのマイグレーションファイルが複数あることを表すエラー。
+// 適用順と適用済みの判定が曖昧になるため、どちらのファイルも適用しない。
+type DuplicateMigrationVersionError struct {
+	Version string    // 重複したバージョン
+	Files   [2]string // 同じバージョンのファイルのパス（スキャン順）
+}
+
+// Error はエラーメッセージを返す。
+func (e *DuplicateMigrationVersionError) Error() string {
+	return fmt.Sprintf("%v: version %s is used by both %s and %s", ErrDuplicateMigrationVersion, e.Version, e.Files[0], e.Files[1])
+}
+
+// Unwrap はErrDuplicateMigrationVersionを返す。
+func (e *DuplicateMigrationVersionError) Unwrap() error {
+	return ErrDuplicateMigrationVersion
 }
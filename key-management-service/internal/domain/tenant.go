@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinRotationInterval は自動ローテーション間隔の下限。短すぎる間隔で世代が増え続けることを防ぐ。
+const MinRotationInterval = time.Hour
+
+// TenantSummary はテナントの概要を表す。
+type TenantSummary struct {
+	TenantID string
+	KeyCount int
+}
+
+// TenantSettings はテナントごとの設定を表す。
+type TenantSettings struct {
+	TenantID string
+	// RotationInterval は現在の鍵を自動でローテーションするまでの期間。0の場合は自動ローテーションしない。
+	RotationInterval time.Duration
+	UpdatedAt        time.Time
+}
+
+// RotationDue は現在の鍵の作成日時からローテーション間隔が経過しているかを返す。
+// 間隔が0（自動ローテーションなし）の場合は常にfalseを返す。
+func (s TenantSettings) RotationDue(currentKeyCreatedAt, now time.Time) bool {
+	if s.RotationInterval <= 0 {
+		return false
+	}
+	return !now.Before(currentKeyCreatedAt.Add(s.RotationInterval))
+}
+
+// ParseRotationInterval はローテーション間隔を解析する。
+// time.ParseDurationの形式（例: 720h）に加えて日数（例: 90d）を受け付ける。
+// "0"は自動ローテーションの無効化、それ以外はMinRotationInterval以上でなければならない。
+func ParseRotationInterval(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 || n > 3650 {
+			return 0, ErrInvalidRotationInterval
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, ErrInvalidRotationInterval
+		}
+	}
+	if d != 0 && d < MinRotationInterval {
+		return 0, ErrInvalidRotationInterval
+	}
+	return d, nil
+}
+
+// FormatRotationInterval はローテーション間隔を表示用の文字列にする。日単位で割り切れる場合は日数（例: 90d）とする。
+func FormatRotationInterval(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
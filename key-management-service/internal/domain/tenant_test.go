@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRotationInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "90d", want: 90 * 24 * time.Hour},
+		{in: "720h", want: 720 * time.Hour},
+		{in: "0", want: 0},
+		{in: "0d", want: 0},
+		{in: "30m", wantErr: true},
+		{in: "-1d", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "d", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRotationInterval(tt.in)
+		if tt.wantErr {
+			if err != ErrInvalidRotationInterval {
+				t.Errorf("%q: want ErrInvalidRotationInterval, got %v (%v)", tt.in, err, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: want %v, got %v (err: %v)", tt.in, tt.want, got, err)
+		}
+	}
+
+	if got := FormatRotationInterval(90 * 24 * time.Hour); got != "90d" {
+		t.Errorf("want 90d, got %s", got)
+	}
+	if got := FormatRotationInterval(36 * time.Hour); got != "36h0m0s" {
+		t.Errorf("want 36h0m0s, got %s", got)
+	}
+}
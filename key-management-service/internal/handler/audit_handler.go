@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"key-management-service/internal/domain"
+	"key-management-service/internal/middleware"
+	"key-management-service/internal/usecase"
+	"key-management-service/pkg/httputil"
+)
+
+// maxAuditOperationLen は絞り込みに指定できる操作名の最大長。
+const maxAuditOperationLen = 64
+
+// AuditHandler は永続化された監査イベントの検索を提供する。
+type AuditHandler struct {
+	service         *usecase.AuditService
+	tenantValidator *TenantIDValidator
+	audit           middleware.AuditLogger
+}
+
+// NewAuditHandler は新しいAuditHandlerを生成する。
+func NewAuditHandler(service *usecase.AuditService, tenantValidator *TenantIDValidator, audit middleware.AuditLogger) *AuditHandler {
+	return &AuditHandler{
+		service:         service,
+		tenantValidator: tenantValidator,
+		audit:           audit,
+	}
+}
+
+// AuditEventResponse は監査イベントのレスポンス形式。
+// generationは鍵の世代に紐づかない操作（テナント一覧など）では0のため省略する。
+type AuditEventResponse struct {
+	Operation  string `json:"operation"`
+	TenantID   string `json:"tenant_id"`
+	Generation uint   `json:"generation,omitempty"`
+	Result     string `json:"result"`
+	RequestID  string `json:"request_id,omitempty"`
+	Timestamp  string `json:"timestamp"`
+}
+
+// AuditEventListResponse は監査イベント一覧のレスポンス形式。
+type AuditEventListResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+// parseAuditFilter はクエリパラメータsince/until/operation/limit/offsetから絞り込み条件を解析する。
+func parseAuditFilter(r *http.Request) (domain.AuditFilter, error) {
+	var filter domain.AuditFilter
+	q := r.URL.Query()
+	for name, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, domain.ErrInvalidAuditFilter
+			}
+			*dst = &t
+		}
+	}
+	filter.Operation = q.Get("operation")
+	if len(filter.Operation) > maxAuditOperationLen {
+		return filter, domain.ErrInvalidAuditFilter
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		return filter, domain.ErrInvalidAuditFilter
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+	return filter, filter.Validate()
+}
+
+// ListAuditEvents はテナントの監査イベントを古い順に取得する。
+func (h *AuditHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_FILTER", "invalid since, until, operation, limit or offset")
+		return
+	}
+
+	events, err := h.service.ListEvents(r.Context(), tenantID, filter)
+	if err != nil {
+		h.audit.Write(r.Context(), "LIST_AUDIT_EVENTS", tenantID, 0, "FAILED")
+		if errors.Is(err, domain.ErrInvalidAuditFilter) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_FILTER", "invalid since, until, operation, limit or offset")
+			return
+		}
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "LIST_AUDIT_EVENTS", tenantID, 0, "SUCCESS")
+	response := AuditEventListResponse{
+		Events: make([]AuditEventResponse, len(events)),
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	for i, e := range events {
+		response.Events[i] = AuditEventResponse{
+			Operation:  e.Operation,
+			TenantID:   e.TenantID,
+			Generation: e.Generation,
+			Result:     e.Result,
+			RequestID:  e.RequestID,
+			Timestamp:  e.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+	httputil.JSON(w, http.StatusOK, response)
+}
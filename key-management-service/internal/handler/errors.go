@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"key-management-service/internal/domain"
+	"key-management-service/internal/middleware"
+	"key-management-service/pkg/httputil"
+)
+
+// errorWithContext はリクエストIDとトレースIDを付与したエラーレスポンスを返す。
+// クライアントから報告されたエラーをログと突き合わせるために使用する。
+// ミドルウェアが返すエラーと形式を揃えるため、middleware.WriteErrorに委譲する。
+func errorWithContext(w http.ResponseWriter, r *http.Request, status int, code string, message string) {
+	middleware.WriteError(w, r, status, code, message)
+}
+
+// validationErrorWithContext はフィールド単位の検証エラーをdetailsに列挙した400レスポンスを返す。
+func validationErrorWithContext(w http.ResponseWriter, r *http.Request, code string, message string, verr *httputil.ValidationError) {
+	requestID, traceID := middleware.RequestIDs(r)
+	httputil.ErrorWithDetails(w, http.StatusBadRequest, code, message, requestID, traceID, verr.Fields)
+}
+
+// writeServiceError はサービス層の想定外のエラーを返す。
+// KMS・データベースの呼び出しが期限切れとなった場合は504とする。
+// KMSの権限不足・鍵の利用不可は運用者が設定を確認できるよう専用のコードで502、
+// KMSの一時的な障害は再試行可能な503、KMSのサイズ上限を超えるデータは413、
+// 復号した鍵素材の長さが合わない（保存された鍵が破損している）場合は専用のコードで500とし、それ以外は500とする。
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUpstreamTimeout):
+		errorWithContext(w, r, http.StatusGatewayTimeout, "UPSTREAM_TIMEOUT", "upstream service timed out")
+	case errors.Is(err, domain.ErrKMSPermission):
+		errorWithContext(w, r, http.StatusBadGateway, "KMS_PERMISSION_DENIED", "KMS denied access to the key encryption key")
+	case errors.Is(err, domain.ErrKMSKeyUnavailable):
+		errorWithContext(w, r, http.StatusBadGateway, "KMS_KEY_UNAVAILABLE", "key encryption key is not available in KMS")
+	case errors.Is(err, domain.ErrPayloadTooLarge):
+		errorWithContext(w, r, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "payload exceeds the KMS size limit")
+	case errors.Is(err, domain.ErrKMSUnavailable):
+		w.Header().Set("Retry-After", "1")
+		errorWithContext(w, r, http.StatusServiceUnavailable, "KMS_UNAVAILABLE", "KMS is temporarily unavailable")
+	case errors.Is(err, domain.ErrCorruptKey):
+		errorWithContext(w, r, http.StatusInternalServerError, "CORRUPT_KEY", "stored key material is corrupt")
+	default:
+		errorWithContext(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+	}
+}
+
+// writeTenantIDError はTenantIDValidator.Validateのエラーに応じたエラーレスポンスを返す。
+// 許可リストに含まれないテナントは403、形式が不正なテナントIDは400とする。
+func writeTenantIDError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrTenantNotAllowed) {
+		errorWithContext(w, r, http.StatusForbidden, "TENANT_NOT_ALLOWED", "tenant is not allowed")
+		return
+	}
+	errorWithContext(w, r, http.StatusBadRequest, "INVALID_TENANT_ID", "invalid tenant ID format")
+}
+
+// writeDecodeError はhttputil.DecodeJSONのエラーに応じたエラーレスポンスを返す。
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, httputil.ErrBodyTooLarge) {
+		errorWithContext(w, r, http.StatusRequestEntityTooLarge, "INVALID_BODY", "request body too large")
+		return
+	}
+	errorWithContext(w, r, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"key-management-service/pkg/httputil"
+)
+
+// ReadinessChecker はリクエストを受け付けられる状態かを返すインターフェース。
+// 呼び出しごとに依存先へ問い合わせず、直近の確認結果を返すことを想定する。
+type ReadinessChecker interface {
+	Healthy() bool
+}
+
+// ReadyResponse はreadinessのレスポンス形式。
+type ReadyResponse struct {
+	Status string `json:"status"`
+}
+
+// healthz はliveness（プロセスが応答できること）を返す。依存先の状態は確認しない。
+func healthz(w http.ResponseWriter, r *http.Request) {
+	httputil.JSON(w, http.StatusOK, ReadyResponse{Status: "ok"})
+}
+
+// readyz はreadinessを返すハンドラーを生成する。checkerがnilの場合は常に受付可能とする。
+func readyz(checker ReadinessChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if checker != nil && !checker.Healthy() {
+			errorWithContext(w, r, http.StatusServiceUnavailable, "NOT_READY", "database is unavailable")
+			return
+		}
+		httputil.JSON(w, http.StatusOK, ReadyResponse{Status: "ready"})
+	}
+}
@@ -3,9 +3,10 @@ package handler
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
-	"regexp"
 	"strconv"
 	"time"
 
@@ -17,52 +18,373 @@ import (
 	"key-management-service/pkg/httputil"
 )
 
-var tenantIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-
 // KeyHandler はHTTPハンドラを提供する。
 type KeyHandler struct {
-	service *usecase.KeyService
+	service           *usecase.KeyService
+	tenantValidator   *TenantIDValidator
+	audit             middleware.AuditLogger
+	disabledKeyStatus int
 }
 
-// NewKeyHandler は新しいKeyHandlerを生成する。
-func NewKeyHandler(service *usecase.KeyService) *KeyHandler {
-	return &KeyHandler{service: service}
+// KeyHandlerOption はKeyHandlerの設定を変更する。
+type KeyHandlerOption func(*KeyHandler)
+
+// WithDisabledKeyStatus は無効化された鍵を取得・復号に使おうとした場合のステータスコードを設定する。
+// 既定は410 Gone。410を扱えず404のみを「再試行しない」と判断するクライアント向けに404を指定できる。
+func WithDisabledKeyStatus(status int) KeyHandlerOption {
+	return func(h *KeyHandler) {
+		h.disabledKeyStatus = status
+	}
 }
 
-func validateTenantID(tenantID string) error {
-	if tenantID == "" {
-		return domain.ErrInvalidTenantID
+// NewKeyHandler は新しいKeyHandlerを生成する。
+func NewKeyHandler(service *usecase.KeyService, tenantValidator *TenantIDValidator, audit middleware.AuditLogger, opts ...KeyHandlerOption) *KeyHandler {
+	h := &KeyHandler{
+		service:           service,
+		tenantValidator:   tenantValidator,
+		audit:             audit,
+		disabledKeyStatus: http.StatusGone,
 	}
-	if len(tenantID) > 64 {
-		return domain.ErrInvalidTenantID
+	for _, opt := range opts {
+		opt(h)
 	}
-	if !tenantIDRegex.MatchString(tenantID) {
-		return domain.ErrInvalidTenantID
+	return h
+}
+
+// writeKeyDisabled は無効化された鍵へのアクセスを設定されたステータスコード（既定は410）で返す。
+// ステータスコードによらずエラーコードはKEY_DISABLEDとし、鍵が存在しない場合と区別できるようにする。
+func (h *KeyHandler) writeKeyDisabled(w http.ResponseWriter, r *http.Request) {
+	errorWithContext(w, r, h.disabledKeyStatus, "KEY_DISABLED", "key has been disabled")
+}
+
+// generationLatest は世代の代わりに指定でき、現在の鍵を表す。
+const generationLatest = "latest"
+
+// latestGeneration はvalidateGenerationがgenerationLatestに対して返す世代。実在する世代は1以上のため区別できる。
+const latestGeneration uint = 0
+
+// validateGeneration は世代の取得パスの値を検証する。generationLatestの場合はlatestGenerationを返し、
+// それ以外はvalidateNumericGenerationと同じく検証する。
+func validateGeneration(genStr string, maxGen uint) (uint, error) {
+	if genStr == generationLatest {
+		return latestGeneration, nil
 	}
-	return nil
+	return validateNumericGeneration(genStr, maxGen)
 }
 
-func validateGeneration(genStr string) (uint, error) {
+// validateNumericGeneration は世代番号を解析し、1以上maxGen以下であることを検証する。
+func validateNumericGeneration(genStr string, maxGen uint) (uint, error) {
 	gen, err := strconv.ParseUint(genStr, 10, 32)
-	if err != nil || gen < 1 {
+	if err != nil || gen < 1 || gen > uint64(maxGen) {
 		return 0, domain.ErrInvalidGeneration
 	}
 	return uint(gen), nil
 }
 
+// keyETagParts はETagの算出に使う世代・ステータス・更新日時を文字列にする。
+func keyETagParts(generation uint, status domain.KeyStatus, updatedAt time.Time) []string {
+	return []string{
+		strconv.FormatUint(uint64(generation), 10),
+		string(status),
+		updatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// keyETag は鍵のレスポンスのETagを算出する。表現ごとに異なる値とするため、JSON以外ではメディアタイプを含める。
+func keyETag(format string, generation uint, status domain.KeyStatus, updatedAt time.Time) string {
+	parts := keyETagParts(generation, status, updatedAt)
+	if format != mediaTypeJSON {
+		parts = append(parts, format)
+	}
+	return httputil.ETag(parts...)
+}
+
+// keyListETag は鍵一覧のETagを算出する。いずれかの鍵の追加・無効化・最終利用日時の更新と、現在の鍵の変更で値が変わる。
+func keyListETag(keys []*domain.KeyMetadata) string {
+	parts := make([]string, 0, len(keys)*5)
+	for _, k := range keys {
+		parts = append(parts, keyETagParts(k.Generation, k.Status, k.UpdatedAt)...)
+		parts = append(parts, formatOptionalTime(k.LastUsedAt), strconv.FormatBool(k.IsCurrent))
+	}
+	return httputil.ETag(parts...)
+}
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+
+	defaultRecentKeys = 5
+	maxRecentKeys     = 100
+)
+
+// parsePagination はクエリパラメータlimit/offsetを解析する。
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	limit = defaultPageLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return 0, 0, errors.New("invalid limit")
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+	}
+	return limit, offset, nil
+}
+
+// parseKeySpec はクエリパラメータkey_type/key_bitsから鍵生成指定を解析する。
+// 種別と鍵長の組み合わせの検証はサービス層で行う。
+func parseKeySpec(r *http.Request) (domain.KeySpec, error) {
+	spec := domain.KeySpec{
+		Type: domain.KeyType(r.URL.Query().Get("key_type")),
+	}
+	if v := r.URL.Query().Get("key_bits"); v != "" {
+		bits, err := strconv.Atoi(v)
+		if err != nil {
+			return spec, domain.ErrInvalidKeySize
+		}
+		spec.Bits = bits
+	}
+	return spec, nil
+}
+
+// writeKeySpecError は鍵生成指定の検証エラーをレスポンスに書き込む。
+// 鍵生成指定のエラーでない場合はfalseを返す。
+func writeKeySpecError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrInvalidKeyType):
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_KEY_TYPE", "key_type must be aes or hmac")
+	case errors.Is(err, domain.ErrInvalidKeySize):
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_KEY_SIZE", "key_bits is not allowed for this key_type")
+	case errors.Is(err, domain.ErrInvalidExpiry):
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_EXPIRY", "expires_at must be in the future")
+	default:
+		return false
+	}
+	return true
+}
+
+// applyCreateKeyRequest は鍵生成のリクエストボディをクエリパラメータから解析した指定に反映する。
+// ボディで指定した項目はクエリパラメータより優先し、省略した項目は既定値のままとする。
+// 不正な項目はすべてフィールド単位の検証エラーとして返す。
+func applyCreateKeyRequest(spec domain.KeySpec, req CreateKeyRequest, now time.Time) (domain.KeySpec, *httputil.ValidationError) {
+	verr := &httputil.ValidationError{}
+
+	if req.KeyType != "" {
+		spec.Type = domain.KeyType(req.KeyType)
+	}
+	keyType := spec.Type
+	if keyType == "" {
+		keyType = domain.DefaultKeyType
+	}
+	if !keyType.IsValid() {
+		verr.Add("key_type", "must be aes or hmac")
+	}
+
+	if req.KeyBits != 0 {
+		spec.Bits = req.KeyBits
+		if keyType.IsValid() && !keyType.IsValidBits(req.KeyBits) {
+			verr.Add("key_bits", "is not allowed for this key_type")
+		}
+	}
+
+	if req.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		switch {
+		case err != nil:
+			verr.Add("expires_at", "must be RFC3339")
+		case !expiresAt.After(now):
+			verr.Add("expires_at", "must be in the future")
+		default:
+			spec.ExpiresAt = &expiresAt
+		}
+	}
+	return spec, verr
+}
+
+// parseKeyFilter はクエリパラメータcreated_after/min_generation/max_generationから絞り込み条件を解析する。
+func parseKeyFilter(r *http.Request, maxGen uint) (domain.KeyFilter, error) {
+	var filter domain.KeyFilter
+	q := r.URL.Query()
+	if v := q.Get("created_after"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, domain.ErrInvalidKeyFilter
+		}
+		filter.CreatedAfter = &t
+	}
+	if v := q.Get("min_generation"); v != "" {
+		gen, err := validateNumericGeneration(v, maxGen)
+		if err != nil {
+			return filter, domain.ErrInvalidKeyFilter
+		}
+		filter.MinGeneration = gen
+	}
+	if v := q.Get("max_generation"); v != "" {
+		gen, err := validateNumericGeneration(v, maxGen)
+		if err != nil {
+			return filter, domain.ErrInvalidKeyFilter
+		}
+		filter.MaxGeneration = gen
+	}
+	return filter, filter.Validate()
+}
+
+// レスポンスのゼロ値の扱い:
+//   - 鍵を表すレスポンスのgenerationは常に出力し、omitemptyを付けない。
+//   - 任意の日時（expires_atなど）は値がない場合に省略する。作成日時などの必須の日時は常に出力する。
+//   - 監査イベントのgenerationは世代に紐づかない操作（0）の場合のみ省略する。世代は1から始まる。
+
 // KeyMetadataResponse は鍵メタデータのレスポンス形式。
 type KeyMetadataResponse struct {
-	TenantID   string `json:"tenant_id"`
-	Generation uint   `json:"generation"`
-	Status     string `json:"status"`
-	CreatedAt  string `json:"created_at"`
+	TenantID       string `json:"tenant_id"`
+	Generation     uint   `json:"generation"`
+	KeyType        string `json:"key_type"`
+	KeyBits        int    `json:"key_bits"`
+	Status         string `json:"status"`
+	KMSKeyName     string `json:"kms_key_name,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
+	LastUsedAt     string `json:"last_used_at,omitempty"`
+	DisabledAt     string `json:"disabled_at,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+	IsCurrent      bool   `json:"is_current"`
+}
+
+// keyMetadataResponse は鍵メタデータをレスポンス形式に変換する。
+func keyMetadataResponse(k *domain.KeyMetadata) KeyMetadataResponse {
+	return KeyMetadataResponse{
+		TenantID:       k.TenantID,
+		Generation:     k.Generation,
+		KeyType:        string(k.KeyType),
+		KeyBits:        k.Bits,
+		Status:         string(k.Status),
+		KMSKeyName:     k.KMSKeyName,
+		CreatedAt:      k.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:      formatOptionalTime(k.ExpiresAt),
+		LastUsedAt:     formatOptionalTime(k.LastUsedAt),
+		DisabledAt:     formatOptionalTime(k.DisabledAt),
+		DisabledReason: k.DisabledReason,
+		IsCurrent:      k.IsCurrent,
+	}
 }
 
 // KeyResponse は鍵のレスポンス形式。
 type KeyResponse struct {
 	TenantID   string `json:"tenant_id"`
 	Generation uint   `json:"generation"`
+	KeyType    string `json:"key_type"`
+	KeyBits    int    `json:"key_bits"`
 	Key        string `json:"key"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// JWKResponse は鍵をJWK（RFC 7517）のoct鍵として返すレスポンス形式。
+type JWKResponse struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Key       string `json:"k"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+}
+
+const (
+	// mediaTypeJSON は鍵をKeyResponseのJSONで返すメディアタイプ（既定）。
+	mediaTypeJSON = "application/json"
+	// mediaTypeOctetStream は鍵を生のバイト列で返すメディアタイプ。世代はX-Key-Generationヘッダーで返す。
+	mediaTypeOctetStream = "application/octet-stream"
+	// mediaTypeJWK は鍵をJWKResponseで返すメディアタイプ。
+	mediaTypeJWK = "application/jwk+json"
+)
+
+// keyFormatParams はformatクエリパラメータの値と対応するメディアタイプ。
+var keyFormatParams = map[string]string{
+	"json": mediaTypeJSON,
+	"raw":  mediaTypeOctetStream,
+	"jwk":  mediaTypeJWK,
+}
+
+// negotiateKeyFormat はformatクエリパラメータまたはAcceptヘッダーから鍵のレスポンス形式を決定する。
+// formatクエリパラメータはAcceptヘッダーより優先する。不正な値の場合は400を、
+// 対応していない形式のみを受け入れる場合は406を返してfalseを返す。
+func negotiateKeyFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if param := r.URL.Query().Get("format"); param != "" {
+		format, ok := keyFormatParams[param]
+		if !ok {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_FORMAT", "format must be json, raw or jwk")
+			return "", false
+		}
+		return format, true
+	}
+	format := httputil.Negotiate(r, mediaTypeJSON, mediaTypeOctetStream, mediaTypeJWK)
+	if format == "" {
+		errorWithContext(w, r, http.StatusNotAcceptable, "NOT_ACCEPTABLE",
+			"supported media types are "+mediaTypeJSON+", "+mediaTypeOctetStream+" and "+mediaTypeJWK)
+		return "", false
+	}
+	return format, true
+}
+
+// writeKey は鍵をformatの形式で返す。
+func writeKey(w http.ResponseWriter, format string, key *domain.Key) {
+	w.Header().Set("Vary", "Accept")
+	switch format {
+	case mediaTypeOctetStream:
+		w.Header().Set("Content-Type", mediaTypeOctetStream)
+		w.Header().Set("Content-Length", strconv.Itoa(len(key.Key)))
+		w.Header().Set("X-Key-Generation", strconv.FormatUint(uint64(key.Generation), 10))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(key.Key)
+	case mediaTypeJWK:
+		alg, use := jwkAlgorithm(key.KeyType, key.Bits)
+		w.Header().Set("Content-Type", mediaTypeJWK)
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(JWKResponse{
+			KeyType:   "oct",
+			KeyID:     jwkKeyID(key.TenantID, key.Generation),
+			Key:       base64.RawURLEncoding.EncodeToString(key.Key),
+			Algorithm: alg,
+			Use:       use,
+		})
+	default:
+		httputil.JSON(w, http.StatusOK, KeyResponse{
+			TenantID:   key.TenantID,
+			Generation: key.Generation,
+			KeyType:    string(key.KeyType),
+			KeyBits:    key.Bits,
+			Key:        base64.StdEncoding.EncodeToString(key.Key),
+			Status:     string(key.Status),
+			CreatedAt:  key.CreatedAt.Format(time.RFC3339),
+		})
+	}
+}
+
+// jwkKeyID はJWKのkidを "テナントID:世代" の形式で返す。
+func jwkKeyID(tenantID string, generation uint) string {
+	return tenantID + ":" + strconv.FormatUint(uint64(generation), 10)
+}
+
+// jwkAlgorithm は鍵種別と鍵長に対応するJWKのalgとuseを返す。対応するアルゴリズムがない場合は空文字列を返す。
+func jwkAlgorithm(keyType domain.KeyType, bits int) (alg, use string) {
+	switch keyType {
+	case domain.KeyTypeAES:
+		switch bits {
+		case 128, 192, 256:
+			return fmt.Sprintf("A%dGCM", bits), "enc"
+		}
+		return "", "enc"
+	case domain.KeyTypeHMAC:
+		switch bits {
+		case 256, 384, 512:
+			return fmt.Sprintf("HS%d", bits), "sig"
+		}
+		return "", "sig"
+	}
+	return "", ""
 }
 
 // KeyListResponse は鍵一覧のレスポンス形式。
@@ -70,157 +392,602 @@ type KeyListResponse struct {
 	Keys []KeyMetadataResponse `json:"keys"`
 }
 
+// KeyCountResponse は鍵数のレスポンス形式。
+type KeyCountResponse struct {
+	TenantID   string `json:"tenant_id"`
+	Active     int    `json:"active"`
+	Deprecated int    `json:"deprecated"`
+	Disabled   int    `json:"disabled"`
+	Total      int    `json:"total"`
+}
+
+// TenantResponse はテナント概要のレスポンス形式。
+type TenantResponse struct {
+	TenantID string `json:"tenant_id"`
+	KeyCount int    `json:"key_count"`
+}
+
+// TenantListResponse はテナント一覧のレスポンス形式。
+type TenantListResponse struct {
+	Tenants []TenantResponse `json:"tenants"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+}
+
+// ImportKeyEntry はインポート対象の鍵1件の形式。
+type ImportKeyEntry struct {
+	Generation uint   `json:"generation"`
+	KeyType    string `json:"key_type"`
+	KeyBits    int    `json:"key_bits"`
+	WrappedKey string `json:"wrapped_key"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
+	// KMSKeyName は鍵をラップしたKMS鍵名。バックアップに記録がない場合は省略し、空のまま取り込む
+	KMSKeyName string `json:"kms_key_name"`
+	// TenantAAD はテナントIDを追加認証データとしてラップした鍵か（バックアップのtenant_aad列）。
+	// 省略した場合でも、復号時にAADなしで失敗すればテナントIDを指定して再試行する
+	TenantAAD bool `json:"tenant_aad"`
+}
+
+// DisableKeyRequest は鍵無効化のリクエスト形式。ボディは省略可能。
+type DisableKeyRequest struct {
+	Reason string `json:"reason"`
+}
+
+// BatchGetKeysRequest は鍵一括取得のリクエスト形式。
+type BatchGetKeysRequest struct {
+	Generations []uint `json:"generations"`
+}
+
+// BatchKeyEntry は鍵一括取得における1世代分のレスポンス形式。
+// 鍵はstatusがokの場合のみ含まれる。
+type BatchKeyEntry struct {
+	Status    string `json:"status"`
+	KeyType   string `json:"key_type,omitempty"`
+	KeyBits   int    `json:"key_bits,omitempty"`
+	Key       string `json:"key,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// BatchGetKeysResponse は鍵一括取得のレスポンス形式。キーは世代番号。
+type BatchGetKeysResponse struct {
+	TenantID string                   `json:"tenant_id"`
+	Keys     map[string]BatchKeyEntry `json:"keys"`
+}
+
+// EncryptBatchRequest は一括暗号化のリクエスト形式。平文はbase64で指定する。
+type EncryptBatchRequest struct {
+	Plaintexts []string `json:"plaintexts"`
+}
+
+// EncryptBatchResponse は一括暗号化のレスポンス形式。暗号文（base64）はリクエストの平文と同じ順。
+type EncryptBatchResponse struct {
+	TenantID    string   `json:"tenant_id"`
+	Generation  uint     `json:"generation"`
+	Ciphertexts []string `json:"ciphertexts"`
+}
+
+// DecryptRequest は復号のリクエスト形式。暗号文はencrypt-batchが返した形式（base64）で指定する。
+// 世代はgenerationで指定するか、分からない場合はauto_generationをtrueにして新しい世代から順に試させる。
+type DecryptRequest struct {
+	Ciphertext     string `json:"ciphertext"`
+	Generation     uint   `json:"generation,omitempty"`
+	AutoGeneration bool   `json:"auto_generation,omitempty"`
+}
+
+// DecryptResponse は復号のレスポンス形式。Generationは復号に使用した世代。
+type DecryptResponse struct {
+	TenantID   string `json:"tenant_id"`
+	Generation uint   `json:"generation"`
+	Plaintext  string `json:"plaintext"`
+}
+
+// DisableKeysRequest は鍵一括無効化のリクエスト形式。
+// 世代はgenerationsで列挙するか、fromとtoで範囲（両端を含む）を指定する。
+type DisableKeysRequest struct {
+	Generations []uint `json:"generations"`
+	From        uint   `json:"from"`
+	To          uint   `json:"to"`
+	Reason      string `json:"reason"`
+}
+
+// DisableKeysResult は鍵一括無効化における1世代分のレスポンス形式。
+type DisableKeysResult struct {
+	Generation uint   `json:"generation"`
+	Status     string `json:"status"`
+}
+
+// DisableKeysResponse は鍵一括無効化のレスポンス形式。結果は指定順。
+type DisableKeysResponse struct {
+	TenantID string              `json:"tenant_id"`
+	Results  []DisableKeysResult `json:"results"`
+}
+
+// ImportKeysRequest は鍵インポートのリクエスト形式。
+type ImportKeysRequest struct {
+	Keys []ImportKeyEntry `json:"keys"`
+}
+
+// formatOptionalTime は日時をRFC3339形式にする。nilの場合は空文字を返す。
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// CreateKeyRequest は鍵生成のリクエスト形式。ボディは省略可能で、省略した項目は既定値とする。
+type CreateKeyRequest struct {
+	KeyType   string `json:"key_type"`
+	KeyBits   int    `json:"key_bits"`
+	ExpiresAt string `json:"expires_at"` // RFC3339形式
+}
+
 // CreateKey は新しい暗号鍵を生成する。
 func (h *KeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenant_id")
-	if err := validateTenantID(tenantID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_TENANT_ID", "invalid tenant ID format")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	spec, err := parseKeySpec(r)
+	if err != nil {
+		writeKeySpecError(w, r, err)
+		return
+	}
+	var req CreateKeyRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil && !errors.Is(err, httputil.ErrEmptyBody) {
+		writeDecodeError(w, r, err)
+		return
+	}
+	spec, verr := applyCreateKeyRequest(spec, req, time.Now())
+	if verr.HasErrors() {
+		validationErrorWithContext(w, r, "INVALID_BODY", "request body has invalid fields", verr)
 		return
 	}
 
-	metadata, err := h.service.CreateKey(r.Context(), tenantID)
+	metadata, err := h.service.CreateKey(r.Context(), tenantID, spec)
 	if err != nil {
+		if writeKeySpecError(w, r, err) {
+			h.audit.Write(r.Context(), "CREATE_KEY", tenantID, 0, "FAILED")
+			return
+		}
 		if errors.Is(err, domain.ErrKeyAlreadyExists) {
-			middleware.WriteAuditLog(r.Context(), "CREATE_KEY", tenantID, 0, "FAILED")
-			httputil.Error(w, http.StatusConflict, "KEY_ALREADY_EXISTS", "key already exists for this tenant")
+			h.audit.Write(r.Context(), "CREATE_KEY", tenantID, 0, "FAILED")
+			errorWithContext(w, r, http.StatusConflict, "KEY_ALREADY_EXISTS", "key already exists for this tenant")
 			return
 		}
-		middleware.WriteAuditLog(r.Context(), "CREATE_KEY", tenantID, 0, "FAILED")
-		httputil.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		h.audit.Write(r.Context(), "CREATE_KEY", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
 		return
 	}
 
-	middleware.WriteAuditLog(r.Context(), "CREATE_KEY", tenantID, metadata.Generation, "SUCCESS")
+	h.audit.Write(r.Context(), "CREATE_KEY", tenantID, metadata.Generation, "SUCCESS")
 	httputil.JSON(w, http.StatusCreated, KeyMetadataResponse{
 		TenantID:   metadata.TenantID,
 		Generation: metadata.Generation,
+		KeyType:    string(metadata.KeyType),
+		KeyBits:    metadata.Bits,
 		Status:     string(metadata.Status),
+		KMSKeyName: metadata.KMSKeyName,
 		CreatedAt:  metadata.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:  formatOptionalTime(metadata.ExpiresAt),
+		IsCurrent:  metadata.IsCurrent,
 	})
 }
 
 // GetCurrentKey は現在有効な鍵を取得する。
 func (h *KeyHandler) GetCurrentKey(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenant_id")
-	if err := validateTenantID(tenantID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_TENANT_ID", "invalid tenant ID format")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+	format, ok := negotiateKeyFormat(w, r)
+	if !ok {
 		return
 	}
 
 	key, err := h.service.GetCurrentKey(r.Context(), tenantID)
 	if err != nil {
 		if errors.Is(err, domain.ErrKeyNotFound) {
-			middleware.WriteAuditLog(r.Context(), "GET_CURRENT_KEY", tenantID, 0, "FAILED")
-			httputil.Error(w, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant")
+			h.audit.Write(r.Context(), "GET_CURRENT_KEY", tenantID, 0, "FAILED")
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant")
+			return
+		}
+		if errors.Is(err, domain.ErrKeyExpired) {
+			h.audit.Write(r.Context(), "GET_CURRENT_KEY", tenantID, 0, "FAILED")
+			errorWithContext(w, r, http.StatusConflict, "KEY_EXPIRED", "current key has expired; rotate the key")
+			return
+		}
+		h.audit.Write(r.Context(), "GET_CURRENT_KEY", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	// レスポンスの書き込み後に平文の鍵を消去する
+	defer key.Zero()
+
+	h.audit.Write(r.Context(), "GET_CURRENT_KEY", tenantID, key.Generation, "SUCCESS")
+	writeKey(w, format, key)
+}
+
+// CurrentKeyStatusResponse は現在の鍵のステータスのレスポンス形式。
+type CurrentKeyStatusResponse struct {
+	TenantID   string `json:"tenant_id"`
+	Generation uint   `json:"generation"`
+	CreatedAt  string `json:"created_at"`
+	AgeSeconds int64  `json:"age_seconds"`
+	// RotationInterval は期限の判定に使用した間隔（例: 90d）。"0s"は期限なし。
+	RotationInterval string `json:"rotation_interval"`
+	RotationDue      bool   `json:"rotation_due"`
+}
+
+// GetCurrentKeyStatus は現在の鍵の経過時間とローテーション期限を取得する。鍵本体は返さない。
+func (h *KeyHandler) GetCurrentKeyStatus(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	status, err := h.service.GetCurrentKeyStatus(r.Context(), tenantID)
+	if err != nil {
+		h.audit.Write(r.Context(), "GET_CURRENT_KEY_STATUS", tenantID, 0, "FAILED")
+		if errors.Is(err, domain.ErrKeyNotFound) {
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant")
 			return
 		}
-		middleware.WriteAuditLog(r.Context(), "GET_CURRENT_KEY", tenantID, 0, "FAILED")
-		httputil.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		writeServiceError(w, r, err)
 		return
 	}
 
-	middleware.WriteAuditLog(r.Context(), "GET_CURRENT_KEY", tenantID, key.Generation, "SUCCESS")
-	httputil.JSON(w, http.StatusOK, KeyResponse{
-		TenantID:   key.TenantID,
-		Generation: key.Generation,
-		Key:        base64.StdEncoding.EncodeToString(key.Key),
+	h.audit.Write(r.Context(), "GET_CURRENT_KEY_STATUS", tenantID, status.Generation, "SUCCESS")
+	httputil.JSON(w, http.StatusOK, CurrentKeyStatusResponse{
+		TenantID:         status.TenantID,
+		Generation:       status.Generation,
+		CreatedAt:        status.CreatedAt.UTC().Format(time.RFC3339),
+		AgeSeconds:       int64(status.Age / time.Second),
+		RotationInterval: domain.FormatRotationInterval(status.RotationInterval),
+		RotationDue:      status.RotationDue,
 	})
 }
 
-// GetKeyByGeneration は指定された世代の鍵を取得する。
+// GetKeyByGeneration は指定された世代の鍵を取得する。世代にlatestを指定した場合は現在の鍵を返す。
 func (h *KeyHandler) GetKeyByGeneration(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenant_id")
-	if err := validateTenantID(tenantID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_TENANT_ID", "invalid tenant ID format")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
 		return
 	}
 
 	genStr := chi.URLParam(r, "generation")
-	generation, err := validateGeneration(genStr)
+	generation, err := validateGeneration(genStr, h.service.MaxGeneration())
 	if err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+		return
+	}
+	// latestは現在の鍵の取得と同じ結果を返す
+	if generation == latestGeneration {
+		h.GetCurrentKey(w, r)
+		return
+	}
+	format, ok := negotiateKeyFormat(w, r)
+	if !ok {
 		return
 	}
 
+	// 条件付きGET: 鍵が変更されていなければKMSで復号せずに304を返す
+	// メタデータの取得に失敗した場合は通常の取得でエラーを返す
+	if r.Header.Get("If-None-Match") != "" {
+		metadata, err := h.service.GetKeyMetadata(r.Context(), tenantID, generation)
+		if err == nil && metadata.Status != domain.KeyStatusDisabled {
+			etag := keyETag(format, metadata.Generation, metadata.Status, metadata.UpdatedAt)
+			if httputil.IfNoneMatch(r, etag) {
+				w.Header().Set("Vary", "Accept")
+				h.audit.Write(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "SUCCESS")
+				httputil.NotModified(w, etag)
+				return
+			}
+		}
+	}
+
 	key, err := h.service.GetKeyByGeneration(r.Context(), tenantID, generation)
 	if err != nil {
 		if errors.Is(err, domain.ErrKeyNotFound) {
-			middleware.WriteAuditLog(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "FAILED")
-			httputil.Error(w, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant and generation")
+			h.audit.Write(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "FAILED")
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant and generation")
 			return
 		}
 		if errors.Is(err, domain.ErrKeyDisabled) {
-			middleware.WriteAuditLog(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "FAILED")
-			httputil.Error(w, http.StatusGone, "KEY_DISABLED", "key has been disabled")
+			h.audit.Write(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "FAILED")
+			h.writeKeyDisabled(w, r)
 			return
 		}
-		middleware.WriteAuditLog(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "FAILED")
-		httputil.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		h.audit.Write(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "FAILED")
+		writeServiceError(w, r, err)
 		return
 	}
 
-	middleware.WriteAuditLog(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "SUCCESS")
-	httputil.JSON(w, http.StatusOK, KeyResponse{
-		TenantID:   key.TenantID,
-		Generation: key.Generation,
-		Key:        base64.StdEncoding.EncodeToString(key.Key),
-	})
+	// レスポンスの書き込み後に平文の鍵を消去する
+	defer key.Zero()
+
+	h.audit.Write(r.Context(), "GET_KEY_BY_GENERATION", tenantID, generation, "SUCCESS")
+	w.Header().Set("ETag", keyETag(format, key.Generation, key.Status, key.UpdatedAt))
+	writeKey(w, format, key)
+}
+
+// BatchGetKeys は複数世代の鍵を一括で取得する。
+func (h *KeyHandler) BatchGetKeys(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	var req BatchGetKeysRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+	maxGen := h.service.MaxGeneration()
+	verr := &httputil.ValidationError{}
+	for i, gen := range req.Generations {
+		if gen < 1 || gen > maxGen {
+			verr.Addf(fmt.Sprintf("generations[%d]", i), "must be between 1 and %d", maxGen)
+		}
+	}
+	if verr.HasErrors() {
+		validationErrorWithContext(w, r, "INVALID_GENERATION", "invalid generation number", verr)
+		return
+	}
+
+	results, err := h.service.BatchGetKeys(r.Context(), tenantID, req.Generations)
+	if err != nil {
+		h.audit.Write(r.Context(), "BATCH_GET_KEYS", tenantID, 0, "FAILED")
+		if errors.Is(err, domain.ErrInvalidBatchSize) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_BATCH_SIZE",
+				fmt.Sprintf("generations must contain 1 to %d entries", domain.MaxBatchGetGenerations))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidGeneration) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+			return
+		}
+		writeServiceError(w, r, err)
+		return
+	}
+
+	// レスポンスの書き込み後に平文の鍵を消去する
+	defer func() {
+		for _, res := range results {
+			res.Key.Zero()
+		}
+	}()
+
+	h.audit.Write(r.Context(), "BATCH_GET_KEYS", tenantID, 0, "SUCCESS")
+	response := BatchGetKeysResponse{
+		TenantID: tenantID,
+		Keys:     make(map[string]BatchKeyEntry, len(results)),
+	}
+	for _, res := range results {
+		entry := BatchKeyEntry{Status: string(res.Status)}
+		if res.Key != nil {
+			entry.KeyType = string(res.Key.KeyType)
+			entry.KeyBits = res.Key.Bits
+			entry.Key = base64.StdEncoding.EncodeToString(res.Key.Key)
+			entry.CreatedAt = res.Key.CreatedAt.Format(time.RFC3339)
+		}
+		response.Keys[strconv.FormatUint(uint64(res.Generation), 10)] = entry
+	}
+	httputil.JSON(w, http.StatusOK, response)
 }
 
 // RotateKey は鍵をローテーションする。
 func (h *KeyHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenant_id")
-	if err := validateTenantID(tenantID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_TENANT_ID", "invalid tenant ID format")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	spec, err := parseKeySpec(r)
+	if err != nil {
+		writeKeySpecError(w, r, err)
 		return
 	}
 
-	metadata, err := h.service.RotateKey(r.Context(), tenantID)
+	metadata, err := h.service.RotateKey(r.Context(), tenantID, spec)
 	if err != nil {
+		if writeKeySpecError(w, r, err) {
+			h.audit.Write(r.Context(), "ROTATE_KEY", tenantID, 0, "FAILED")
+			return
+		}
 		if errors.Is(err, domain.ErrKeyNotFound) {
-			middleware.WriteAuditLog(r.Context(), "ROTATE_KEY", tenantID, 0, "FAILED")
-			httputil.Error(w, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant")
+			h.audit.Write(r.Context(), "ROTATE_KEY", tenantID, 0, "FAILED")
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant")
 			return
 		}
-		middleware.WriteAuditLog(r.Context(), "ROTATE_KEY", tenantID, 0, "FAILED")
-		httputil.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		if errors.Is(err, domain.ErrMaxGenerationReached) {
+			h.audit.Write(r.Context(), "ROTATE_KEY", tenantID, 0, "FAILED")
+			errorWithContext(w, r, http.StatusConflict, "MAX_GENERATION_REACHED", "maximum generation reached for this tenant")
+			return
+		}
+		h.audit.Write(r.Context(), "ROTATE_KEY", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
 		return
 	}
 
-	middleware.WriteAuditLog(r.Context(), "ROTATE_KEY", tenantID, metadata.Generation, "SUCCESS")
+	h.audit.Write(r.Context(), "ROTATE_KEY", tenantID, metadata.Generation, "SUCCESS")
 	httputil.JSON(w, http.StatusCreated, KeyMetadataResponse{
 		TenantID:   metadata.TenantID,
 		Generation: metadata.Generation,
+		KeyType:    string(metadata.KeyType),
+		KeyBits:    metadata.Bits,
 		Status:     string(metadata.Status),
+		KMSKeyName: metadata.KMSKeyName,
 		CreatedAt:  metadata.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:  formatOptionalTime(metadata.ExpiresAt),
+		IsCurrent:  metadata.IsCurrent,
 	})
 }
 
 // ListKeys は鍵一覧を取得する。
 func (h *KeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenant_id")
-	if err := validateTenantID(tenantID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_TENANT_ID", "invalid tenant ID format")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
 		return
 	}
 
-	keys, err := h.service.ListKeys(r.Context(), tenantID)
+	filter, err := parseKeyFilter(r, h.service.MaxGeneration())
 	if err != nil {
-		middleware.WriteAuditLog(r.Context(), "LIST_KEYS", tenantID, 0, "FAILED")
-		httputil.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_FILTER", "invalid created_after, min_generation or max_generation")
 		return
 	}
 
-	middleware.WriteAuditLog(r.Context(), "LIST_KEYS", tenantID, 0, "SUCCESS")
+	keys, err := h.service.ListKeys(r.Context(), tenantID, filter)
+	if err != nil {
+		h.audit.Write(r.Context(), "LIST_KEYS", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "LIST_KEYS", tenantID, 0, "SUCCESS")
+	etag := keyListETag(keys)
+	if httputil.IfNoneMatch(r, etag) {
+		httputil.NotModified(w, etag)
+		return
+	}
+	w.Header().Set("ETag", etag)
 	response := KeyListResponse{
 		Keys: make([]KeyMetadataResponse, len(keys)),
 	}
 	for i, k := range keys {
-		response.Keys[i] = KeyMetadataResponse{
-			TenantID:   k.TenantID,
-			Generation: k.Generation,
-			Status:     string(k.Status),
-			CreatedAt:  k.CreatedAt.Format(time.RFC3339),
+		response.Keys[i] = keyMetadataResponse(k)
+	}
+	httputil.JSON(w, http.StatusOK, response)
+}
+
+// RecentKeys は新しい鍵n件（クエリパラメータn、省略時は5件）のメタデータを世代の降順で取得する。
+func (h *KeyHandler) RecentKeys(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	n := defaultRecentKeys
+	if v := r.URL.Query().Get("n"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxRecentKeys {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_FILTER", fmt.Sprintf("n must be an integer between 1 and %d", maxRecentKeys))
+			return
+		}
+		n = parsed
+	}
+
+	keys, err := h.service.ListRecentKeys(r.Context(), tenantID, n)
+	if err != nil {
+		h.audit.Write(r.Context(), "LIST_RECENT_KEYS", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "LIST_RECENT_KEYS", tenantID, 0, "SUCCESS")
+	etag := keyListETag(keys)
+	if httputil.IfNoneMatch(r, etag) {
+		httputil.NotModified(w, etag)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	response := KeyListResponse{
+		Keys: make([]KeyMetadataResponse, len(keys)),
+	}
+	for i, k := range keys {
+		response.Keys[i] = keyMetadataResponse(k)
+	}
+	httputil.JSON(w, http.StatusOK, response)
+}
+
+// CountKeys はステータスごとの鍵数を取得する。
+func (h *KeyHandler) CountKeys(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	counts, err := h.service.CountKeys(r.Context(), tenantID)
+	if err != nil {
+		h.audit.Write(r.Context(), "COUNT_KEYS", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "COUNT_KEYS", tenantID, 0, "SUCCESS")
+	response := KeyCountResponse{
+		TenantID:   tenantID,
+		Active:     counts[domain.KeyStatusActive],
+		Deprecated: counts[domain.KeyStatusDeprecated],
+		Disabled:   counts[domain.KeyStatusDisabled],
+	}
+	for _, c := range counts {
+		response.Total += c
+	}
+	httputil.JSON(w, http.StatusOK, response)
+}
+
+// KeyExists はテナントに鍵が存在するかをステータスコードのみで返す（HEAD）。
+// 鍵が存在する場合は200、存在しない場合は404を返し、いずれもボディは返さない。
+func (h *KeyHandler) KeyExists(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	exists, err := h.service.TenantExists(r.Context(), tenantID)
+	if err != nil {
+		h.audit.Write(r.Context(), "KEY_EXISTS", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "KEY_EXISTS", tenantID, 0, "SUCCESS")
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ListTenants はテナント一覧を取得する。
+func (h *KeyHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_PAGINATION", "invalid limit or offset")
+		return
+	}
+
+	tenants, err := h.service.ListTenants(r.Context(), limit, offset)
+	if err != nil {
+		h.audit.Write(r.Context(), "LIST_TENANTS", "", 0, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "LIST_TENANTS", "", 0, "SUCCESS")
+	response := TenantListResponse{
+		Tenants: make([]TenantResponse, len(tenants)),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for i, t := range tenants {
+		response.Tenants[i] = TenantResponse{
+			TenantID: t.TenantID,
+			KeyCount: t.KeyCount,
 		}
 	}
 	httputil.JSON(w, http.StatusOK, response)
@@ -229,35 +996,488 @@ func (h *KeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
 // DisableKey は鍵を無効化する。
 func (h *KeyHandler) DisableKey(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenant_id")
-	if err := validateTenantID(tenantID); err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_TENANT_ID", "invalid tenant ID format")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
 		return
 	}
 
 	genStr := chi.URLParam(r, "generation")
-	generation, err := validateGeneration(genStr)
+	generation, err := validateNumericGeneration(genStr, h.service.MaxGeneration())
 	if err != nil {
-		httputil.Error(w, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+		return
+	}
+
+	var req DisableKeyRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil && !errors.Is(err, httputil.ErrEmptyBody) {
+		writeDecodeError(w, r, err)
 		return
 	}
 
-	err = h.service.DisableKey(r.Context(), tenantID, generation)
+	metadata, err := h.service.DisableKey(r.Context(), tenantID, generation, req.Reason)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDisableReason) {
+			h.audit.Write(r.Context(), "DISABLE_KEY", tenantID, generation, "FAILED")
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_REASON", "reason must be at most 255 characters")
+			return
+		}
 		if errors.Is(err, domain.ErrKeyNotFound) {
-			middleware.WriteAuditLog(r.Context(), "DISABLE_KEY", tenantID, generation, "FAILED")
-			httputil.Error(w, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant and generation")
+			h.audit.Write(r.Context(), "DISABLE_KEY", tenantID, generation, "FAILED")
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant and generation")
 			return
 		}
 		if errors.Is(err, domain.ErrKeyAlreadyDisabled) {
-			middleware.WriteAuditLog(r.Context(), "DISABLE_KEY", tenantID, generation, "FAILED")
-			httputil.Error(w, http.StatusConflict, "KEY_ALREADY_DISABLED", "key is already disabled")
+			h.audit.Write(r.Context(), "DISABLE_KEY", tenantID, generation, "FAILED")
+			errorWithContext(w, r, http.StatusConflict, "KEY_ALREADY_DISABLED", "key is already disabled")
+			return
+		}
+		h.audit.Write(r.Context(), "DISABLE_KEY", tenantID, generation, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "DISABLE_KEY", tenantID, generation, "SUCCESS")
+	httputil.JSON(w, http.StatusAccepted, keyMetadataResponse(metadata))
+}
+
+// DeprecateKey は指定された世代の鍵を非推奨にする。
+// 非推奨の鍵は世代を指定すれば取得できるが、現在の鍵には選ばれない。
+func (h *KeyHandler) DeprecateKey(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	generation, err := validateNumericGeneration(chi.URLParam(r, "generation"), h.service.MaxGeneration())
+	if err != nil {
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+		return
+	}
+
+	metadata, err := h.service.DeprecateKey(r.Context(), tenantID, generation)
+	if err != nil {
+		h.audit.Write(r.Context(), "DEPRECATE_KEY", tenantID, generation, "FAILED")
+		switch {
+		case errors.Is(err, domain.ErrKeyNotFound):
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant and generation")
+		case errors.Is(err, domain.ErrKeyDisabled):
+			errorWithContext(w, r, http.StatusConflict, "KEY_DISABLED", "key has been disabled")
+		case errors.Is(err, domain.ErrKeyAlreadyDeprecated):
+			errorWithContext(w, r, http.StatusConflict, "KEY_ALREADY_DEPRECATED", "key is already deprecated")
+		default:
+			writeServiceError(w, r, err)
+		}
+		return
+	}
+
+	h.audit.Write(r.Context(), "DEPRECATE_KEY", tenantID, generation, "SUCCESS")
+	httputil.JSON(w, http.StatusOK, keyMetadataResponse(metadata))
+}
+
+// DisableKeys は複数世代の鍵を一括で無効化する。
+func (h *KeyHandler) DisableKeys(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	var req DisableKeysRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+	generations, verr := disableKeysGenerations(req, h.service.MaxGeneration())
+	if verr.HasErrors() {
+		validationErrorWithContext(w, r, "INVALID_GENERATION", "invalid generation number", verr)
+		return
+	}
+
+	results, err := h.service.DisableKeys(r.Context(), tenantID, generations, req.Reason)
+	if err != nil {
+		h.audit.Write(r.Context(), "DISABLE_KEYS", tenantID, 0, "FAILED")
+		if errors.Is(err, domain.ErrInvalidDisableReason) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_REASON", "reason must be at most 255 characters")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidBatchSize) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_BATCH_SIZE",
+				fmt.Sprintf("generations must contain 1 to %d entries", domain.MaxBatchDisableGenerations))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidGeneration) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+			return
+		}
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "DISABLE_KEYS", tenantID, 0, "SUCCESS")
+	response := DisableKeysResponse{
+		TenantID: tenantID,
+		Results:  make([]DisableKeysResult, len(results)),
+	}
+	for i, res := range results {
+		response.Results[i] = DisableKeysResult{Generation: res.Generation, Status: string(res.Status)}
+	}
+	httputil.JSON(w, http.StatusOK, response)
+}
+
+// EncryptBatch はテナントの現在の鍵で複数の平文をまとめて暗号化する。
+func (h *KeyHandler) EncryptBatch(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	var req EncryptBatchRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+	verr := &httputil.ValidationError{}
+	plaintexts := make([][]byte, len(req.Plaintexts))
+	for i, p := range req.Plaintexts {
+		decoded, err := httputil.DecodeBase64(p, 0)
+		if err != nil {
+			verr.Add(fmt.Sprintf("plaintexts[%d]", i), "must be base64")
+			continue
+		}
+		plaintexts[i] = decoded
+	}
+	if verr.HasErrors() {
+		validationErrorWithContext(w, r, "INVALID_BODY", "request body has invalid fields", verr)
+		return
+	}
+
+	result, err := h.service.EncryptBatch(r.Context(), tenantID, plaintexts)
+	if err != nil {
+		h.audit.Write(r.Context(), "ENCRYPT_BATCH", tenantID, 0, "FAILED")
+		switch {
+		case errors.Is(err, domain.ErrInvalidBatchSize):
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_BATCH_SIZE",
+				fmt.Sprintf("plaintexts must contain 1 to %d entries", domain.MaxBatchEncryptItems))
+		case errors.Is(err, domain.ErrBatchPayloadTooLarge):
+			errorWithContext(w, r, http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE",
+				fmt.Sprintf("plaintexts must total at most %d bytes", domain.MaxBatchEncryptBytes))
+		case errors.Is(err, domain.ErrKeyNotFound):
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "no active key found for this tenant")
+		case errors.Is(err, domain.ErrKeyExpired):
+			errorWithContext(w, r, http.StatusConflict, "KEY_EXPIRED", "current key has expired; rotate the key")
+		case errors.Is(err, domain.ErrUnsupportedKeyType):
+			errorWithContext(w, r, http.StatusConflict, "UNSUPPORTED_KEY_TYPE", "current key is not an AES key")
+		default:
+			writeServiceError(w, r, err)
+		}
+		return
+	}
+
+	h.audit.Write(r.Context(), "ENCRYPT_BATCH", tenantID, result.Generation, "SUCCESS")
+	response := EncryptBatchResponse{
+		TenantID:    result.TenantID,
+		Generation:  result.Generation,
+		Ciphertexts: make([]string, len(result.Ciphertexts)),
+	}
+	for i, c := range result.Ciphertexts {
+		response.Ciphertexts[i] = base64.StdEncoding.EncodeToString(c)
+	}
+	httputil.JSON(w, http.StatusOK, response)
+}
+
+// Decrypt はencrypt-batchで暗号化した暗号文を復号する。
+// auto_generationを指定した場合は世代を特定できるまで新しい世代から順に試し、復号できた世代を返す。
+func (h *KeyHandler) Decrypt(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	var req DecryptRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+	maxGen := h.service.MaxGeneration()
+	verr := &httputil.ValidationError{}
+	switch {
+	case req.AutoGeneration && req.Generation != 0:
+		verr.Add("generation", "cannot be combined with auto_generation")
+	case !req.AutoGeneration && (req.Generation < 1 || req.Generation > maxGen):
+		verr.Addf("generation", "must be between 1 and %d unless auto_generation is true", maxGen)
+	}
+	if verr.HasErrors() {
+		validationErrorWithContext(w, r, "INVALID_BODY", "request body has invalid fields", verr)
+		return
+	}
+	// 暗号文はログにもエラーレスポンスにも含めない
+	ciphertext, err := httputil.DecodeBase64(req.Ciphertext, domain.MinCiphertextSize)
+	if err != nil {
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_CIPHERTEXT",
+			fmt.Sprintf("ciphertext must be base64 of at least %d bytes", domain.MinCiphertextSize))
+		return
+	}
+
+	var result *domain.DecryptResult
+	if req.AutoGeneration {
+		result, err = h.service.DecryptAuto(r.Context(), tenantID, ciphertext)
+	} else {
+		result, err = h.service.Decrypt(r.Context(), tenantID, req.Generation, ciphertext)
+	}
+	if err != nil {
+		h.audit.Write(r.Context(), "DECRYPT", tenantID, req.Generation, "FAILED")
+		switch {
+		case errors.Is(err, domain.ErrDecryptionFailed):
+			errorWithContext(w, r, http.StatusUnprocessableEntity, "DECRYPTION_FAILED", "ciphertext could not be decrypted with the key")
+		case errors.Is(err, domain.ErrKeyNotFound):
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant and generation")
+		case errors.Is(err, domain.ErrKeyDisabled):
+			h.writeKeyDisabled(w, r)
+		case errors.Is(err, domain.ErrUnsupportedKeyType):
+			errorWithContext(w, r, http.StatusConflict, "UNSUPPORTED_KEY_TYPE", "key is not an AES key")
+		default:
+			writeServiceError(w, r, err)
+		}
+		return
+	}
+
+	h.audit.Write(r.Context(), "DECRYPT", tenantID, result.Generation, "SUCCESS")
+	httputil.JSON(w, http.StatusOK, DecryptResponse{
+		TenantID:   result.TenantID,
+		Generation: result.Generation,
+		Plaintext:  base64.StdEncoding.EncodeToString(result.Plaintext),
+	})
+}
+
+// SoftDeleteKey は指定された世代の鍵を論理削除する。SOFT_DELETE_ENABLED=trueの場合のみルーティングされる。
+func (h *KeyHandler) SoftDeleteKey(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	generation, err := validateNumericGeneration(chi.URLParam(r, "generation"), h.service.MaxGeneration())
+	if err != nil {
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+		return
+	}
+
+	if err := h.service.SoftDeleteKey(r.Context(), tenantID, generation); err != nil {
+		h.audit.Write(r.Context(), "SOFT_DELETE_KEY", tenantID, generation, "FAILED")
+		if errors.Is(err, domain.ErrKeyNotFound) {
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "key not found for this tenant and generation")
+			return
+		}
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "SOFT_DELETE_KEY", tenantID, generation, "SUCCESS")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreKey は論理削除した鍵を元に戻す。SOFT_DELETE_ENABLED=trueの場合のみルーティングされる。
+func (h *KeyHandler) RestoreKey(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	generation, err := validateNumericGeneration(chi.URLParam(r, "generation"), h.service.MaxGeneration())
+	if err != nil {
+		errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid generation number")
+		return
+	}
+
+	metadata, err := h.service.RestoreKey(r.Context(), tenantID, generation)
+	if err != nil {
+		h.audit.Write(r.Context(), "RESTORE_KEY", tenantID, generation, "FAILED")
+		if errors.Is(err, domain.ErrKeyNotFound) {
+			errorWithContext(w, r, http.StatusNotFound, "KEY_NOT_FOUND", "no soft deleted key for this tenant and generation")
+			return
+		}
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "RESTORE_KEY", tenantID, generation, "SUCCESS")
+	httputil.JSON(w, http.StatusOK, keyMetadataResponse(metadata))
+}
+
+// disableKeysGenerations は一括無効化のリクエストから対象の世代を求める。
+// 範囲指定は上限を超える場合に展開せず検証エラーとする。
+func disableKeysGenerations(req DisableKeysRequest, maxGen uint) ([]uint, *httputil.ValidationError) {
+	verr := &httputil.ValidationError{}
+	useRange := req.From != 0 || req.To != 0
+	switch {
+	case useRange && len(req.Generations) > 0:
+		verr.Add("generations", "must not be combined with from and to")
+		return nil, verr
+	case !useRange:
+		for i, gen := range req.Generations {
+			if gen < 1 || gen > maxGen {
+				verr.Addf(fmt.Sprintf("generations[%d]", i), "must be between 1 and %d", maxGen)
+			}
+		}
+		return req.Generations, verr
+	}
+
+	if req.From < 1 || req.From > maxGen {
+		verr.Addf("from", "must be between 1 and %d", maxGen)
+	}
+	if req.To < 1 || req.To > maxGen {
+		verr.Addf("to", "must be between 1 and %d", maxGen)
+	}
+	if verr.HasErrors() {
+		return nil, verr
+	}
+	if req.From > req.To {
+		verr.Add("to", "must be greater than or equal to from")
+		return nil, verr
+	}
+	if req.To-req.From >= domain.MaxBatchDisableGenerations {
+		verr.Addf("to", "range must contain at most %d generations", domain.MaxBatchDisableGenerations)
+		return nil, verr
+	}
+	generations := make([]uint, 0, req.To-req.From+1)
+	for gen := req.From; gen <= req.To; gen++ {
+		generations = append(generations, gen)
+	}
+	return generations, verr
+}
+
+// parseImportKeys はインポートのリクエストを検証してドメインの鍵に変換する。
+// 不正なフィールドは最初の1件で止めずにすべて検証エラーとして返す。
+func parseImportKeys(req ImportKeysRequest, maxGen uint) ([]*domain.EncryptionKey, *httputil.ValidationError) {
+	verr := &httputil.ValidationError{}
+	if len(req.Keys) == 0 {
+		verr.Add("keys", "must not be empty")
+		return nil, verr
+	}
+
+	keys := make([]*domain.EncryptionKey, len(req.Keys))
+	seen := make(map[uint]struct{}, len(req.Keys))
+	for i, entry := range req.Keys {
+		field := func(name string) string { return fmt.Sprintf("keys[%d].%s", i, name) }
+
+		if entry.Generation < 1 || entry.Generation > maxGen {
+			verr.Addf(field("generation"), "must be between 1 and %d", maxGen)
+		} else if _, dup := seen[entry.Generation]; dup {
+			verr.Addf(field("generation"), "duplicate generation %d in bundle", entry.Generation)
+		}
+		seen[entry.Generation] = struct{}{}
+
+		keyType := domain.KeyType(entry.KeyType)
+		if keyType == "" {
+			keyType = domain.DefaultKeyType
+		}
+		if !keyType.IsValid() {
+			verr.Add(field("key_type"), "must be aes or hmac")
+		} else if entry.KeyBits != 0 && !keyType.IsValidBits(entry.KeyBits) {
+			verr.Add(field("key_bits"), "is not allowed for this key_type")
+		}
+
+		wrapped, err := httputil.DecodeBase64(entry.WrappedKey, 1)
+		if err != nil {
+			verr.Add(field("wrapped_key"), "must be non-empty base64")
+		}
+
+		status := domain.KeyStatus(entry.Status)
+		if entry.Status == "" {
+			status = domain.KeyStatusActive
+		}
+		if !status.IsValid() {
+			verr.Add(field("status"), "must be active, deprecated or disabled")
+		}
+
+		var createdAt time.Time
+		if entry.CreatedAt != "" {
+			createdAt, err = time.Parse(time.RFC3339, entry.CreatedAt)
+			if err != nil {
+				verr.Add(field("created_at"), "must be RFC3339")
+			}
+		}
+
+		if len(entry.KMSKeyName) > domain.MaxKMSKeyNameLen {
+			verr.Addf(field("kms_key_name"), "must be at most %d characters", domain.MaxKMSKeyNameLen)
+		}
+
+		keys[i] = &domain.EncryptionKey{
+			Generation:   entry.Generation,
+			KeyType:      domain.KeyType(entry.KeyType),
+			Bits:         entry.KeyBits,
+			EncryptedKey: wrapped,
+			KMSKeyName:   entry.KMSKeyName,
+			TenantAAD:    entry.TenantAAD,
+			Status:       status,
+			CreatedAt:    createdAt,
+		}
+	}
+	return keys, verr
+}
+
+// ImportKeys はバックアップされたラップ済み鍵を取り込む。
+func (h *KeyHandler) ImportKeys(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	var req ImportKeysRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+	keys, verr := parseImportKeys(req, h.service.MaxGeneration())
+	if verr.HasErrors() {
+		validationErrorWithContext(w, r, "INVALID_BODY", "request body has invalid fields", verr)
+		return
+	}
+
+	imported, err := h.service.ImportKeys(r.Context(), tenantID, keys)
+	if err != nil {
+		h.audit.Write(r.Context(), "IMPORT_KEYS", tenantID, 0, "FAILED")
+		if errors.Is(err, domain.ErrGenerationAlreadyExists) {
+			errorWithContext(w, r, http.StatusConflict, "GENERATION_ALREADY_EXISTS", "generation already exists for this tenant")
 			return
 		}
-		middleware.WriteAuditLog(r.Context(), "DISABLE_KEY", tenantID, generation, "FAILED")
-		httputil.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		if errors.Is(err, domain.ErrInvalidGeneration) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_GENERATION", "invalid or duplicate generation number")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidKeyStatus) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_STATUS", "status must be active, deprecated or disabled")
+			return
+		}
+		if writeKeySpecError(w, r, err) {
+			return
+		}
+		writeServiceError(w, r, err)
 		return
 	}
 
-	middleware.WriteAuditLog(r.Context(), "DISABLE_KEY", tenantID, generation, "SUCCESS")
-	w.WriteHeader(http.StatusAccepted)
+	h.audit.Write(r.Context(), "IMPORT_KEYS", tenantID, 0, "SUCCESS")
+	response := KeyListResponse{
+		Keys: make([]KeyMetadataResponse, len(imported)),
+	}
+	for i, k := range imported {
+		response.Keys[i] = KeyMetadataResponse{
+			TenantID:   k.TenantID,
+			Generation: k.Generation,
+			KeyType:    string(k.KeyType),
+			KeyBits:    k.Bits,
+			Status:     string(k.Status),
+			KMSKeyName: k.KMSKeyName,
+			CreatedAt:  k.CreatedAt.Format(time.RFC3339),
+			ExpiresAt:  formatOptionalTime(k.ExpiresAt),
+			IsCurrent:  k.IsCurrent,
+		}
+	}
+	httputil.JSON(w, http.StatusCreated, response)
 }
@@ -1,17 +1,28 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"key-management-service/config"
 	"key-management-service/internal/domain"
+	"key-management-service/internal/middleware"
 	"key-management-service/internal/usecase"
+	"key-management-service/pkg/httputil"
 )
 
 // mockKeyRepository はテスト用のモックリポジトリ。
@@ -25,16 +36,34 @@ type mockKeyRepository struct {
 	findLatestErr    error
 	findAllResult    []*domain.EncryptionKey
 	findAllErr       error
+	lastRecentN      int
+	lastFilter       domain.KeyFilter
+	countResult      map[domain.KeyStatus]int
+	countErr         error
+	tenantIDsResult  []string
+	tenantIDsErr     error
+	tenantCounts     map[string]int
+	tenantCountsErr  error
 	maxGenResult     uint
 	maxGenErr        error
-	updateStatusErr  error
+	disableErr       error
+	disabledAt       time.Time
+	disabledReason   string
+	updatedStatus    domain.KeyStatus
 	createdKeys      []*domain.EncryptionKey
+	findByGensResult []*domain.EncryptionKey
+	softDeleteResult bool
+	restoreResult    bool
 }
 
 func (m *mockKeyRepository) ExistsByTenantID(ctx context.Context, tenantID string) (bool, error) {
 	return m.existsResult, m.existsErr
 }
 
+func (m *mockKeyRepository) CreateWithRetention(ctx context.Context, key *domain.EncryptionKey, retention int, disabledAt time.Time, reason string) ([]uint, error) {
+	return nil, m.Create(ctx, key)
+}
+
 func (m *mockKeyRepository) Create(ctx context.Context, key *domain.EncryptionKey) error {
 	if m.createErr != nil {
 		return m.createErr
@@ -44,6 +73,17 @@ func (m *mockKeyRepository) Create(ctx context.Context, key *domain.EncryptionKe
 	return nil
 }
 
+func (m *mockKeyRepository) CreateWithGeneration(ctx context.Context, key *domain.EncryptionKey) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	m.createdKeys = append(m.createdKeys, key)
+	return nil
+}
+
 func (m *mockKeyRepository) FindByTenantIDAndGeneration(ctx context.Context, tenantID string, generation uint) (*domain.EncryptionKey, error) {
 	return m.findByGenResult, m.findByGenErr
 }
@@ -56,12 +96,113 @@ func (m *mockKeyRepository) FindAllByTenantID(ctx context.Context, tenantID stri
 	return m.findAllResult, m.findAllErr
 }
 
+func (m *mockKeyRepository) FindByTenantIDWithFilter(ctx context.Context, tenantID string, filter domain.KeyFilter) ([]*domain.EncryptionKey, error) {
+	m.lastFilter = filter
+	return m.findAllResult, m.findAllErr
+}
+
+func (m *mockKeyRepository) FindRecentByTenantID(ctx context.Context, tenantID string, n int) ([]*domain.EncryptionKey, error) {
+	m.lastRecentN = n
+	if m.findAllErr != nil {
+		return nil, m.findAllErr
+	}
+	// findAllResultは世代の昇順のため、末尾から新しい順に取り出す
+	recent := slices.Clone(m.findAllResult)
+	slices.Reverse(recent)
+	return recent[:min(n, len(recent))], nil
+}
+
+func (m *mockKeyRepository) FindRecentUsableByTenantID(ctx context.Context, tenantID string, keyType domain.KeyType, n int) ([]*domain.EncryptionKey, error) {
+	if m.findAllErr != nil {
+		return nil, m.findAllErr
+	}
+	var recent []*domain.EncryptionKey
+	for _, k := range slices.Backward(m.findAllResult) {
+		if k.Status != domain.KeyStatusDisabled && k.KeyType == keyType && len(recent) < n {
+			recent = append(recent, k)
+		}
+	}
+	return recent, nil
+}
+
+func (m *mockKeyRepository) CountByTenantIDGroupedByStatus(ctx context.Context, tenantID string) (map[domain.KeyStatus]int, error) {
+	return m.countResult, m.countErr
+}
+
+func (m *mockKeyRepository) ListTenantIDs(ctx context.Context, limit, offset int) ([]string, error) {
+	return m.tenantIDsResult, m.tenantIDsErr
+}
+
+func (m *mockKeyRepository) CountByTenantIDs(ctx context.Context, tenantIDs []string) (map[string]int, error) {
+	return m.tenantCounts, m.tenantCountsErr
+}
+
+func (m *mockKeyRepository) FindByTenantIDAndGenerations(ctx context.Context, tenantID string, generations []uint) ([]*domain.EncryptionKey, error) {
+	return m.findByGensResult, m.findByGenErr
+}
+
 func (m *mockKeyRepository) GetMaxGeneration(ctx context.Context, tenantID string) (uint, error) {
 	return m.maxGenResult, m.maxGenErr
 }
 
+func (m *mockKeyRepository) Disable(ctx context.Context, id string, disabledAt time.Time, reason string) error {
+	m.disabledAt = disabledAt
+	m.disabledReason = reason
+	return m.disableErr
+}
+
+// UpdateStatusBatch はfindAllResultのうち指定された世代の有効な鍵を無効化し、更新前のステータスを返す。
+func (m *mockKeyRepository) UpdateStatusBatch(ctx context.Context, tenantID string, generations []uint, disabledAt time.Time, reason string) (map[uint]domain.KeyStatus, error) {
+	if m.disableErr != nil {
+		return nil, m.disableErr
+	}
+	previous := make(map[uint]domain.KeyStatus)
+	for _, k := range m.findAllResult {
+		for _, gen := range generations {
+			if k.Generation != gen {
+				continue
+			}
+			previous[gen] = k.Status
+			if k.Status == domain.KeyStatusActive {
+				k.Status = domain.KeyStatusDisabled
+				k.DisabledAt = &disabledAt
+				k.DisabledReason = reason
+			}
+		}
+	}
+	return previous, nil
+}
+
+func (m *mockKeyRepository) FindByKMSKeyName(ctx context.Context, kmsKeyName string, limit, offset int) ([]*domain.EncryptionKey, error) {
+	return nil, nil
+}
+
+func (m *mockKeyRepository) UpdateWrappedKey(ctx context.Context, id, fromKMSKeyName string, encryptedKey []byte, kmsKeyName string, tenantAAD bool) (bool, error) {
+	return false, nil
+}
+
+func (m *mockKeyRepository) BackfillStatus(ctx context.Context, dryRun bool) (map[domain.KeyStatus]int, error) {
+	return nil, nil
+}
+
 func (m *mockKeyRepository) UpdateStatus(ctx context.Context, id string, status domain.KeyStatus) error {
-	return m.updateStatusErr
+	m.updatedStatus = status
+	if m.findByGenResult != nil && m.findByGenResult.ID == id {
+		m.findByGenResult.Status = status
+	}
+	return nil
+}
+
+func (m *mockKeyRepository) SoftDelete(ctx context.Context, tenantID string, generation uint) (bool, error) {
+	return m.softDeleteResult, nil
+}
+
+func (m *mockKeyRepository) Restore(ctx context.Context, tenantID string, generation uint) (bool, error) {
+	return m.restoreResult, nil
+}
+
+func (m *mockKeyRepository) WithTx(ctx context.Context, fn func(txRepo usecase.KeyRepository) error) error {
+	return fn(m)
 }
 
 // mockKMSClient はテスト用のモックKMSクライアント。
@@ -69,28 +210,37 @@ type mockKMSClient struct {
 	encryptErr    error
 	decryptResult []byte
 	decryptErr    error
+	block         bool
 }
 
-func (m *mockKMSClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+func (m *mockKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
 	if m.encryptErr != nil {
 		return nil, m.encryptErr
 	}
 	return append([]byte("encrypted:"), plaintext...), nil
 }
 
-func (m *mockKMSClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+func (m *mockKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	if m.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	if m.decryptErr != nil {
 		return nil, m.decryptErr
 	}
 	if m.decryptResult != nil {
 		return m.decryptResult, nil
 	}
-	return []byte("decrypted-key"), nil
+	return []byte("decrypted-key-0123456789abcdefgh"), nil
 }
 
 func setupHandler(repo *mockKeyRepository, kms *mockKMSClient) *KeyHandler {
 	service := usecase.NewKeyService(repo, kms)
-	return NewKeyHandler(service)
+	validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		panic(err)
+	}
+	return NewKeyHandler(service, validator, middleware.NewJSONAuditLogger(io.Discard))
 }
 
 func TestCreateKey_Success(t *testing.T) {
@@ -164,7 +314,7 @@ func TestGetCurrentKey_Success(t *testing.T) {
 			Status:       domain.KeyStatusActive,
 		},
 	}
-	kms := &mockKMSClient{decryptResult: []byte("plain-key")}
+	kms := &mockKMSClient{decryptResult: []byte("plain-key-0123456789abcdefghijkl")}
 	h := setupHandler(repo, kms)
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil)
@@ -198,6 +348,364 @@ func TestGetCurrentKey_NotFound(t *testing.T) {
 	}
 }
 
+func TestGetCurrentKey_Expired(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Hour)
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{
+			TenantID:     "tenant-001",
+			Generation:   3,
+			EncryptedKey: []byte("encrypted"),
+			Status:       domain.KeyStatusActive,
+			ExpiresAt:    &expiresAt,
+		},
+	}
+	kms := &mockKMSClient{decryptResult: []byte("plain-key-0123456789abcdefghijkl")}
+	h := setupHandler(repo, kms)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.GetCurrentKey(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("want status 409, got %d", rec.Code)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Code != "KEY_EXPIRED" {
+		t.Errorf("want code KEY_EXPIRED, got %s", resp.Code)
+	}
+}
+
+func TestDisabledKeyStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []KeyHandlerOption
+		wantStatus int
+	}{
+		{name: "既定は410", wantStatus: http.StatusGone},
+		{name: "404を設定", opts: []KeyHandlerOption{WithDisabledKeyStatus(http.StatusNotFound)}, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{
+				findByGenResult: &domain.EncryptionKey{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusDisabled},
+			}
+			validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := NewKeyHandler(usecase.NewKeyService(repo, &mockKMSClient{}), validator, middleware.NewJSONAuditLogger(io.Discard), tt.opts...)
+			router := NewRouter(h, &config.Config{})
+
+			requests := map[string]*http.Request{
+				"get":     httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/2", nil),
+				"decrypt": httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/decrypt", strings.NewReader(`{"ciphertext":"`+base64.StdEncoding.EncodeToString(make([]byte, domain.MinCiphertextSize))+`","generation":2}`)),
+			}
+			for name, req := range requests {
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				if rec.Code != tt.wantStatus {
+					t.Errorf("%s: want status %d, got %d", name, tt.wantStatus, rec.Code)
+				}
+				// ステータスコードによらず、鍵が存在しない場合とはエラーコードで区別できる
+				if !strings.Contains(rec.Body.String(), "KEY_DISABLED") {
+					t.Errorf("%s: want code KEY_DISABLED, got %s", name, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGetKeyByGeneration_Latest(t *testing.T) {
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{TenantID: "tenant-001", Generation: 3, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive},
+		findByGenResult:  &domain.EncryptionKey{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive},
+	}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+	get := func(path string) (int, KeyResponse) {
+		t.Helper()
+		kms.decryptResult = []byte("plain-key-0123456789abcdefghijkl")
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var resp KeyResponse
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	currentStatus, current := get("/v1/tenants/tenant-001/keys/current")
+	latestStatus, latest := get("/v1/tenants/tenant-001/keys/latest")
+	if currentStatus != http.StatusOK || latestStatus != http.StatusOK {
+		t.Fatalf("want status 200, got current=%d latest=%d", currentStatus, latestStatus)
+	}
+	if latest != current {
+		t.Errorf("want latest to equal current, got %+v and %+v", latest, current)
+	}
+	if latest.Generation != 3 {
+		t.Errorf("want generation 3, got %d", latest.Generation)
+	}
+
+	// 数値の世代は従来どおり
+	status, byGen := get("/v1/tenants/tenant-001/keys/2")
+	if status != http.StatusOK || byGen.Generation != 2 {
+		t.Errorf("want generation 2 with status 200, got %d %+v", status, byGen)
+	}
+
+	// 現在の鍵がない場合はcurrentと同じく404
+	repo.findLatestResult = nil
+	if status, _ := get("/v1/tenants/tenant-001/keys/latest"); status != http.StatusNotFound {
+		t.Errorf("want status 404, got %d", status)
+	}
+
+	// 更新系のエンドポイントではlatestを受け付けない
+	req := httptest.NewRequest(http.MethodDelete, "/v1/tenants/tenant-001/keys/latest", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("want status 400 for DELETE latest, got %d", rec.Code)
+	}
+}
+
+func TestGetCurrentKeyStatus(t *testing.T) {
+	validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const day = 24 * time.Hour
+	tests := []struct {
+		name         string
+		age          time.Duration
+		expiresIn    time.Duration
+		tenantPolicy time.Duration
+		wantDue      bool
+		wantInterval string
+	}{
+		{name: "fresh key", age: 10 * day, wantDue: false, wantInterval: "90d"},
+		{name: "stale key", age: 120 * day, wantDue: true, wantInterval: "90d"},
+		{name: "tenant interval overrides default", age: 10 * day, tenantPolicy: 7 * day, wantDue: true, wantInterval: "7d"},
+		{name: "expired key", age: 10 * day, expiresIn: -time.Hour, wantDue: true, wantInterval: "90d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &domain.EncryptionKey{
+				TenantID:   "tenant-001",
+				Generation: 4,
+				Status:     domain.KeyStatusActive,
+				CreatedAt:  time.Now().Add(-tt.age),
+			}
+			if tt.expiresIn != 0 {
+				expiresAt := time.Now().Add(tt.expiresIn)
+				key.ExpiresAt = &expiresAt
+			}
+			repo := &mockKeyRepository{findLatestResult: key}
+			settings := &memoryTenantSettingsRepository{settings: map[string]*domain.TenantSettings{
+				"tenant-001": {TenantID: "tenant-001", RotationInterval: tt.tenantPolicy},
+			}}
+			// 鍵を復号しないため、KMSが呼ばれるとテストが失敗する
+			service := usecase.NewKeyService(repo, &mockKMSClient{decryptErr: errors.New("kms must not be called")},
+				usecase.WithRotationDue(90*day, settings))
+			h := NewKeyHandler(service, validator, middleware.NewJSONAuditLogger(io.Discard))
+			router := NewRouter(h, &config.Config{})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current/status", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var resp CurrentKeyStatusResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Generation != 4 || resp.RotationDue != tt.wantDue || resp.RotationInterval != tt.wantInterval {
+				t.Errorf("want generation 4, rotation_due %v, interval %s; got %+v", tt.wantDue, tt.wantInterval, resp)
+			}
+			if wantAge := int64(tt.age / time.Second); resp.AgeSeconds < wantAge || resp.AgeSeconds > wantAge+5 {
+				t.Errorf("want age_seconds about %d, got %d", wantAge, resp.AgeSeconds)
+			}
+		})
+	}
+}
+
+func TestGetCurrentKeyStatus_NotFound(t *testing.T) {
+	router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current/status", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("want status 404, got %d", rec.Code)
+	}
+}
+
+func TestEncryptBatch(t *testing.T) {
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{
+			TenantID:   "tenant-001",
+			Generation: 2,
+			KeyType:    domain.KeyTypeAES,
+			Status:     domain.KeyStatusActive,
+		},
+	}
+	router := NewRouter(setupHandler(repo, &mockKMSClient{decryptResult: bytes.Repeat([]byte{1}, 32)}), &config.Config{})
+
+	body := `{"plaintexts":["` + base64.StdEncoding.EncodeToString([]byte("a")) + `","` + base64.StdEncoding.EncodeToString([]byte("bb")) + `"]}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/encrypt-batch", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp EncryptBatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Generation != 2 || len(resp.Ciphertexts) != 2 {
+		t.Errorf("want 2 ciphertexts under generation 2, got %+v", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/encrypt-batch", strings.NewReader(`{"plaintexts":["not base64!"]}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("want status 400 for invalid base64, got %d", rec.Code)
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	key := &domain.EncryptionKey{
+		TenantID:   "tenant-001",
+		Generation: 2,
+		KeyType:    domain.KeyTypeAES,
+		Status:     domain.KeyStatusActive,
+	}
+	repo := &mockKeyRepository{
+		findLatestResult: key,
+		findByGenResult:  key,
+		findAllResult:    []*domain.EncryptionKey{key},
+	}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+	post := func(path, body string) *httptest.ResponseRecorder {
+		// 鍵の平文は使用後に消去されるため、リクエストごとに用意する
+		kms.decryptResult = bytes.Repeat([]byte{1}, 32)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post("/v1/tenants/tenant-001/encrypt-batch", `{"plaintexts":["`+base64.StdEncoding.EncodeToString([]byte("secret"))+`"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200 from encrypt-batch, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var encrypted EncryptBatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&encrypted); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	ciphertext := encrypted.Ciphertexts[0]
+
+	for _, body := range []string{
+		`{"ciphertext":"` + ciphertext + `","generation":2}`,
+		`{"ciphertext":"` + ciphertext + `","auto_generation":true}`,
+	} {
+		rec := post("/v1/tenants/tenant-001/decrypt", body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: want status 200, got %d: %s", body, rec.Code, rec.Body.String())
+		}
+		var resp DecryptResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Generation != 2 || resp.Plaintext != base64.StdEncoding.EncodeToString([]byte("secret")) {
+			t.Errorf("%s: unexpected response %+v", body, resp)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "base64として不正", body: `{"ciphertext":"not base64!","generation":2}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_CIPHERTEXT"},
+		{name: "base64として正しいが短すぎる", body: `{"ciphertext":"AAECAw==","generation":2}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_CIPHERTEXT"},
+		{name: "認証に失敗", body: `{"ciphertext":"` + base64.StdEncoding.EncodeToString(make([]byte, domain.MinCiphertextSize)) + `","auto_generation":true}`, wantStatus: http.StatusUnprocessableEntity, wantCode: "DECRYPTION_FAILED"},
+		{name: "世代の指定なし", body: `{"ciphertext":"` + ciphertext + `"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_BODY"},
+		{name: "世代とautoの併用", body: `{"ciphertext":"` + ciphertext + `","generation":2,"auto_generation":true}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_BODY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := post("/v1/tenants/tenant-001/decrypt", tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("want status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			var errResp httputil.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if errResp.Code != tt.wantCode {
+				t.Errorf("want code %s, got %s", tt.wantCode, errResp.Code)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteAndRestoreKey(t *testing.T) {
+	repo := &mockKeyRepository{
+		softDeleteResult: true,
+		restoreResult:    true,
+		findByGenResult: &domain.EncryptionKey{
+			TenantID:   "tenant-001",
+			Generation: 2,
+			Status:     domain.KeyStatusActive,
+		},
+	}
+	h := setupHandler(repo, &mockKMSClient{})
+
+	// 無効な場合はルーティングされない
+	rec := httptest.NewRecorder()
+	NewRouter(h, &config.Config{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/2/soft-delete", nil))
+	if rec.Code == http.StatusNoContent {
+		t.Fatalf("want soft delete to be unavailable when disabled, got %d", rec.Code)
+	}
+
+	router := NewRouter(h, &config.Config{SoftDeleteEnabled: true})
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/2/soft-delete", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("want status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/2/restore", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp KeyMetadataResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Generation != 2 || resp.Status != string(domain.KeyStatusActive) {
+		t.Errorf("want restored generation 2, got %+v", resp)
+	}
+
+	// 論理削除されていない世代の復元は404
+	repo.restoreResult = false
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/2/restore", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("want status 404, got %d", rec.Code)
+	}
+}
+
 func TestDisableKey_Success(t *testing.T) {
 	repo := &mockKeyRepository{
 		findByGenResult: &domain.EncryptionKey{
@@ -220,7 +728,46 @@ func TestDisableKey_Success(t *testing.T) {
 	h.DisableKey(rec, req)
 
 	if rec.Code != http.StatusAccepted {
-		t.Errorf("want status 202, got %d", rec.Code)
+		t.Fatalf("want status 202, got %d", rec.Code)
+	}
+	var resp KeyMetadataResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Status != "disabled" || resp.Generation != 1 {
+		t.Errorf("want disabled generation 1, got %+v", resp)
+	}
+	if resp.DisabledAt == "" {
+		t.Error("want disabled_at in response")
+	}
+}
+
+func TestDisableKey_WithReason(t *testing.T) {
+	repo := &mockKeyRepository{
+		findByGenResult: &domain.EncryptionKey{
+			ID:         "key-id",
+			TenantID:   "tenant-001",
+			Generation: 1,
+			Status:     domain.KeyStatusActive,
+		},
+	}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/tenants/tenant-001/keys/1", strings.NewReader(`{"reason":"rotated out"}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	rctx.URLParams.Add("generation", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.DisableKey(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("want status 202, got %d", rec.Code)
+	}
+	if repo.disabledReason != "rotated out" {
+		t.Errorf("want reason %q, got %q", "rotated out", repo.disabledReason)
 	}
 }
 
@@ -249,3 +796,1604 @@ func TestDisableKey_AlreadyDisabled(t *testing.T) {
 		t.Errorf("want status 409, got %d", rec.Code)
 	}
 }
+
+func TestImportKeys_Success(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	body := `{"keys":[
+		{"generation":1,"wrapped_key":"d3JhcHBlZC0x","status":"disabled","created_at":"2025-01-01T00:00:00Z"},
+		{"generation":2,"wrapped_key":"d3JhcHBlZC0y","status":"active","created_at":"2025-02-01T00:00:00Z","kms_key_name":"projects/p/locations/l/keyRings/r/cryptoKeys/old","tenant_aad":true}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/import", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.ImportKeys(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("want status 201, got %d", rec.Code)
+	}
+
+	var resp KeyListResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Keys) != 2 {
+		t.Fatalf("want 2 keys, got %d", len(resp.Keys))
+	}
+	if resp.Keys[0].CreatedAt != "2025-01-01T00:00:00Z" {
+		t.Errorf("want created_at preserved, got %s", resp.Keys[0].CreatedAt)
+	}
+	if string(repo.createdKeys[1].EncryptedKey) != "wrapped-2" {
+		t.Errorf("want wrapped key decoded, got %q", repo.createdKeys[1].EncryptedKey)
+	}
+	// KMS鍵名は指定されたものを保持し、省略された場合は現在のKMS鍵で補完しない
+	if repo.createdKeys[0].KMSKeyName != "" {
+		t.Errorf("want empty kms_key_name when omitted, got %q", repo.createdKeys[0].KMSKeyName)
+	}
+	if got := repo.createdKeys[1].KMSKeyName; got != "projects/p/locations/l/keyRings/r/cryptoKeys/old" {
+		t.Errorf("want kms_key_name preserved, got %q", got)
+	}
+	if repo.createdKeys[0].TenantAAD || !repo.createdKeys[1].TenantAAD {
+		t.Errorf("want tenant_aad passed through, got %v and %v", repo.createdKeys[0].TenantAAD, repo.createdKeys[1].TenantAAD)
+	}
+}
+
+func TestImportKeys_GenerationConflict(t *testing.T) {
+	repo := &mockKeyRepository{
+		findByGenResult: &domain.EncryptionKey{
+			TenantID:   "tenant-001",
+			Generation: 1,
+			Status:     domain.KeyStatusActive,
+		},
+	}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	body := `{"keys":[{"generation":1,"wrapped_key":"d3JhcHBlZC0x","status":"active"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/import", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.ImportKeys(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("want status 409, got %d", rec.Code)
+	}
+}
+
+func TestImportKeys_InvalidWrappedKey(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	body := `{"keys":[{"generation":1,"wrapped_key":"not base64!","status":"active"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/import", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.ImportKeys(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("want status 400, got %d", rec.Code)
+	}
+}
+
+func TestImportKeys_WrappedKeyTooLarge(t *testing.T) {
+	repo := &mockKeyRepository{}
+	validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service := usecase.NewKeyService(repo, &mockKMSClient{}, usecase.WithKMSMaxPlaintextBytes(8))
+	h := NewKeyHandler(service, validator, middleware.NewJSONAuditLogger(io.Discard))
+
+	wrapped := base64.StdEncoding.EncodeToString(make([]byte, 4096))
+	body := `{"keys":[{"generation":1,"wrapped_key":"` + wrapped + `","status":"active"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/import", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.ImportKeys(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("want status 413, got %d", rec.Code)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Code != "PAYLOAD_TOO_LARGE" {
+		t.Errorf("want code PAYLOAD_TOO_LARGE, got %s", resp.Code)
+	}
+}
+
+func TestCreateKey_HMAC(t *testing.T) {
+	repo := &mockKeyRepository{existsResult: false}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys?key_type=hmac", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.CreateKey(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("want status 201, got %d", rec.Code)
+	}
+
+	var resp KeyMetadataResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.KeyType != "hmac" || resp.KeyBits != 512 {
+		t.Errorf("want key_type hmac key_bits 512, got %+v", resp)
+	}
+}
+
+func TestCreateKey_InvalidKeyType(t *testing.T) {
+	repo := &mockKeyRepository{existsResult: false}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys?key_type=rsa", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.CreateKey(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("want status 400, got %d", rec.Code)
+	}
+}
+
+func TestCreateKey_RequestBody(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		query       string
+		body        string
+		wantStatus  int
+		wantType    string
+		wantBits    int
+		wantExpires string
+		wantFields  []string
+	}{
+		{
+			name:        "options body",
+			body:        `{"key_type":"hmac","key_bits":384,"expires_at":"` + expiresAt.Format(time.RFC3339) + `"}`,
+			wantStatus:  http.StatusCreated,
+			wantType:    "hmac",
+			wantBits:    384,
+			wantExpires: expiresAt.Format(time.RFC3339),
+		},
+		{
+			// 後方互換のため、ボディを省略した場合は既定値で生成する
+			name:       "empty body",
+			wantStatus: http.StatusCreated,
+			wantType:   "aes",
+			wantBits:   256,
+		},
+		{
+			name:       "body overrides query",
+			query:      "?key_type=hmac",
+			body:       `{"key_type":"aes","key_bits":128}`,
+			wantStatus: http.StatusCreated,
+			wantType:   "aes",
+			wantBits:   128,
+		},
+		{
+			name:       "invalid bits and past expiry",
+			body:       `{"key_type":"aes","key_bits":512,"expires_at":"2000-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"key_bits", "expires_at"},
+		},
+		{
+			name:       "unknown field",
+			body:       `{"key_size":256}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{existsResult: false}
+			h := setupHandler(repo, &mockKMSClient{})
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys"+tt.query, strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tenant_id", "tenant-001")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rec := httptest.NewRecorder()
+			h.CreateKey(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("want status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				if len(repo.createdKeys) != 0 {
+					t.Errorf("want no key created, got %d", len(repo.createdKeys))
+				}
+				var resp httputil.ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != "INVALID_BODY" {
+					t.Errorf("want code INVALID_BODY, got %s", resp.Code)
+				}
+				var fields []string
+				for _, d := range resp.Details {
+					fields = append(fields, d.Field)
+				}
+				if tt.wantFields != nil && fmt.Sprint(fields) != fmt.Sprint(tt.wantFields) {
+					t.Errorf("want invalid fields %v, got %v", tt.wantFields, fields)
+				}
+				return
+			}
+
+			var resp KeyMetadataResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.KeyType != tt.wantType || resp.KeyBits != tt.wantBits || resp.ExpiresAt != tt.wantExpires {
+				t.Errorf("want key_type %s key_bits %d expires_at %q, got %+v", tt.wantType, tt.wantBits, tt.wantExpires, resp)
+			}
+			if tt.wantExpires != "" && (repo.createdKeys[0].ExpiresAt == nil || !repo.createdKeys[0].ExpiresAt.Equal(expiresAt)) {
+				t.Errorf("want stored expiry %v, got %v", expiresAt, repo.createdKeys[0].ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestListKeys_ShowsKeyType(t *testing.T) {
+	repo := &mockKeyRepository{
+		findAllResult: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive},
+			{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeHMAC, Bits: 512, Status: domain.KeyStatusActive},
+		},
+	}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.ListKeys(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+
+	var resp KeyListResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Keys) != 2 {
+		t.Fatalf("want 2 keys, got %d", len(resp.Keys))
+	}
+	if resp.Keys[0].KeyType != "aes" || resp.Keys[1].KeyType != "hmac" {
+		t.Errorf("want key types [aes hmac], got [%s %s]", resp.Keys[0].KeyType, resp.Keys[1].KeyType)
+	}
+}
+
+func TestListKeys_ShowsLastUsedAt(t *testing.T) {
+	lastUsed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	repo := &mockKeyRepository{
+		findAllResult: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive, LastUsedAt: &lastUsed},
+			{TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusActive},
+		},
+	}
+	router := NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+	etag := rec.Header().Get("ETag")
+
+	var resp KeyListResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Keys[0].LastUsedAt != lastUsed.Format(time.RFC3339) {
+		t.Errorf("generation 1: want last_used_at %s, got %q", lastUsed.Format(time.RFC3339), resp.Keys[0].LastUsedAt)
+	}
+	if resp.Keys[1].LastUsedAt != "" {
+		t.Errorf("generation 2: want last_used_at omitted, got %q", resp.Keys[1].LastUsedAt)
+	}
+
+	// 最終利用日時が更新されると一覧のETagも変わる
+	newer := lastUsed.Add(time.Minute)
+	repo.findAllResult[1].LastUsedAt = &newer
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status 200 after last used time changed, got %d", rec.Code)
+	}
+}
+
+func TestRecentKeys(t *testing.T) {
+	keys := make([]*domain.EncryptionKey, 8)
+	for i := range keys {
+		keys[i] = &domain.EncryptionKey{TenantID: "tenant-001", Generation: uint(i + 1), KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive}
+	}
+	keys[7].Status = domain.KeyStatusDisabled // 世代8は無効化済みのため、現在の鍵は世代7
+	repo := &mockKeyRepository{findAllResult: keys}
+	router := NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{})
+
+	tests := []struct {
+		name     string
+		query    string
+		wantN    int
+		wantGens []uint
+	}{
+		{name: "省略時は5件", query: "", wantN: 5, wantGens: []uint{8, 7, 6, 5, 4}},
+		{name: "件数を指定", query: "?n=2", wantN: 2, wantGens: []uint{8, 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/recent"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if repo.lastRecentN != tt.wantN {
+				t.Errorf("want n=%d passed to repository, got %d", tt.wantN, repo.lastRecentN)
+			}
+
+			var resp KeyListResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			gens := make([]uint, len(resp.Keys))
+			for i, k := range resp.Keys {
+				gens[i] = k.Generation
+				if want := k.Generation == 7; k.IsCurrent != want {
+					t.Errorf("generation %d: want is_current=%v, got %v", k.Generation, want, k.IsCurrent)
+				}
+			}
+			if !slices.Equal(gens, tt.wantGens) {
+				t.Errorf("want generations %v, got %v", tt.wantGens, gens)
+			}
+		})
+	}
+
+	for _, n := range []string{"0", "-1", "101", "abc"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/recent?n="+n, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("n=%s: want status 400, got %d", n, rec.Code)
+		}
+	}
+}
+
+func TestCountKeys_Success(t *testing.T) {
+	repo := &mockKeyRepository{
+		countResult: map[domain.KeyStatus]int{
+			domain.KeyStatusActive:   2,
+			domain.KeyStatusDisabled: 1,
+		},
+	}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/count", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	h.CountKeys(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+
+	var resp KeyCountResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Active != 2 || resp.Disabled != 1 || resp.Total != 3 {
+		t.Errorf("want active=2 disabled=1 total=3, got %+v", resp)
+	}
+}
+
+func TestListTenants_Success(t *testing.T) {
+	repo := &mockKeyRepository{
+		tenantIDsResult: []string{"tenant-001", "tenant-002"},
+		tenantCounts:    map[string]int{"tenant-001": 3, "tenant-002": 1},
+	}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants?limit=2", nil)
+	rec := httptest.NewRecorder()
+	h.ListTenants(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+
+	var resp TenantListResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Tenants) != 2 || resp.Limit != 2 || resp.Offset != 0 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Tenants[0].TenantID != "tenant-001" || resp.Tenants[0].KeyCount != 3 {
+		t.Errorf("unexpected first tenant: %+v", resp.Tenants[0])
+	}
+}
+
+func TestListTenants_InvalidPagination(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	h := setupHandler(repo, kms)
+
+	for _, query := range []string{"limit=0", "limit=1001", "limit=abc", "offset=-1"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/tenants?"+query, nil)
+		rec := httptest.NewRecorder()
+		h.ListTenants(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: want status 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestImportKeys_BodyTooLarge(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{MaxRequestBytes: 64})
+
+	body := `{"keys":[{"generation":1,"wrapped_key":"` + strings.Repeat("A", 128) + `"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/import", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("want status 413, got %d", rec.Code)
+	}
+	if len(repo.createdKeys) != 0 {
+		t.Errorf("want no created keys, got %d", len(repo.createdKeys))
+	}
+}
+
+func TestImportKeys_UnknownField(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{MaxRequestBytes: config.DefaultMaxRequestBytes})
+
+	body := `{"keys":[{"generation":1,"wrapped_key":"d3JhcHBlZA=="}],"unknown":true}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/import", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("want status 400, got %d", rec.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["code"] != "INVALID_BODY" {
+		t.Errorf("want code INVALID_BODY, got %v", resp["code"])
+	}
+}
+
+func TestErrorResponse_IncludesRequestID(t *testing.T) {
+	repo := &mockKeyRepository{findLatestResult: nil}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil)
+	req.Header.Set("X-Request-Id", "req-12345")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("want status 404, got %d", rec.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["request_id"] != "req-12345" {
+		t.Errorf("want request_id req-12345, got %v", resp["request_id"])
+	}
+	if _, ok := resp["trace_id"]; ok {
+		t.Errorf("want no trace_id without an active span, got %v", resp["trace_id"])
+	}
+}
+
+func TestGetCurrentKey_KMSTimeout(t *testing.T) {
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{
+			TenantID:     "tenant-001",
+			Generation:   1,
+			EncryptedKey: []byte("encrypted"),
+			Status:       domain.KeyStatusActive,
+		},
+	}
+	kms := &mockKMSClient{block: true}
+	service := usecase.NewKeyService(repo, kms, usecase.WithKMSTimeout(20*time.Millisecond))
+	validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewKeyHandler(service, validator, middleware.NewJSONAuditLogger(io.Discard))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tenant_id", "tenant-001")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	h.GetCurrentKey(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("want status 504, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "UPSTREAM_TIMEOUT") {
+		t.Errorf("want UPSTREAM_TIMEOUT code, got %s", rec.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("want request to end shortly after timeout, took %v", elapsed)
+	}
+}
+
+func TestBatchGetKeys_MixedResults(t *testing.T) {
+	repo := &mockKeyRepository{
+		findByGensResult: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, KeyType: domain.KeyTypeAES, Bits: 256, EncryptedKey: []byte("enc-1"), Status: domain.KeyStatusActive},
+			{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeAES, Bits: 256, EncryptedKey: []byte("enc-2"), Status: domain.KeyStatusDisabled},
+		},
+	}
+	kms := &mockKMSClient{decryptResult: []byte("plain-key-0123456789abcdefghijkl")}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/batch-get", strings.NewReader(`{"generations":[1,2,3]}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp BatchGetKeysResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Keys) != 3 {
+		t.Fatalf("want 3 entries, got %d", len(resp.Keys))
+	}
+	if got := resp.Keys["1"]; got.Status != "ok" || got.Key != base64.StdEncoding.EncodeToString([]byte("plain-key-0123456789abcdefghijkl")) {
+		t.Errorf("generation 1: want ok with key, got %+v", got)
+	}
+	if got := resp.Keys["2"]; got.Status != "disabled" || got.Key != "" {
+		t.Errorf("generation 2: want disabled without key, got %+v", got)
+	}
+	if got := resp.Keys["3"]; got.Status != "not_found" || got.Key != "" {
+		t.Errorf("generation 3: want not_found without key, got %+v", got)
+	}
+}
+
+func TestBatchGetKeys_TooMany(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+	gens := make([]string, domain.MaxBatchGetGenerations+1)
+	for i := range gens {
+		gens[i] = strconv.Itoa(i + 1)
+	}
+	body := `{"generations":[` + strings.Join(gens, ",") + `]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/batch-get", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("want status 400, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "INVALID_BATCH_SIZE") {
+		t.Errorf("want INVALID_BATCH_SIZE, got %s", rec.Body.String())
+	}
+}
+
+// stubReadiness は固定の状態を返すテスト用ReadinessChecker。
+type stubReadiness struct {
+	healthy bool
+}
+
+func (s stubReadiness) Healthy() bool { return s.healthy }
+
+func TestHealthz(t *testing.T) {
+	router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{}, WithReadiness(stubReadiness{healthy: false}))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// データベースに接続できなくてもlivenessは正常とする
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status 200, got %d", rec.Code)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{})
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		wantAllow string
+	}{
+		// /currentは/{generation}のパターンにも一致するため、DELETEも許可メソッドに含まれる
+		{name: "current key", method: http.MethodPut, path: "/v1/tenants/tenant-001/keys/current", wantAllow: "GET, DELETE"},
+		{name: "generation", method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/1", wantAllow: "GET, DELETE"},
+		{name: "key collection", method: http.MethodPatch, path: "/v1/tenants/tenant-001/keys", wantAllow: "GET, HEAD, POST"},
+		{name: "healthz", method: http.MethodPost, path: "/healthz", wantAllow: "GET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("want status 405, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("want Allow %q, got %q", tt.wantAllow, got)
+			}
+			var resp httputil.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Code != "METHOD_NOT_ALLOWED" {
+				t.Errorf("want code METHOD_NOT_ALLOWED, got %s", resp.Code)
+			}
+		})
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{})
+
+	for _, path := range []string{"/unknown", "/v1/tenants/tenant-001/unknown", "/v1/tenants/tenant-001/keys/1/unknown"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: want status 404, got %d", path, rec.Code)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("%s: want JSON response, got Content-Type %q", path, ct)
+		}
+		var resp httputil.ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", path, err)
+		}
+		if resp.Code != "NOT_FOUND" {
+			t.Errorf("%s: want code NOT_FOUND, got %s", path, resp.Code)
+		}
+	}
+}
+
+func TestTenantAllowlist(t *testing.T) {
+	repo := &mockKeyRepository{
+		findAllResult: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
+		},
+	}
+	validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validator, err = validator.WithAllowlist([]string{"tenant-001"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h := NewKeyHandler(usecase.NewKeyService(repo, &mockKMSClient{}), validator, middleware.NewJSONAuditLogger(io.Discard))
+	router := NewRouter(h, &config.Config{})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "allowed tenant", path: "/v1/tenants/tenant-001/keys", wantStatus: http.StatusOK},
+		{name: "disallowed tenant", path: "/v1/tenants/tenant-002/keys", wantStatus: http.StatusForbidden, wantCode: "TENANT_NOT_ALLOWED"},
+		{name: "invalid tenant ID", path: "/v1/tenants/bad@tenant/keys", wantStatus: http.StatusBadRequest, wantCode: "INVALID_TENANT_ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("want status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var resp httputil.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("want code %s, got %s", tt.wantCode, resp.Code)
+			}
+		})
+	}
+
+	// 許可リストが空の場合は従来どおり全テナントを受け付ける
+	rec := httptest.NewRecorder()
+	NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-002/keys", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("without allowlist: want status 200, got %d", rec.Code)
+	}
+}
+
+func TestRouter_BasePath(t *testing.T) {
+	repo := &mockKeyRepository{
+		findAllResult: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
+		},
+	}
+	tests := []struct {
+		name       string
+		basePath   string
+		path       string
+		method     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "default base path", path: "/v1/tenants/tenant-001/keys", method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "prefixed route", basePath: "/kms", path: "/kms/v1/tenants/tenant-001/keys", method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "unprefixed route under base path", basePath: "/kms", path: "/v1/tenants/tenant-001/keys", method: http.MethodGet, wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND"},
+		{name: "unknown path under base path", basePath: "/kms", path: "/kms/unknown", method: http.MethodGet, wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND"},
+		{name: "method not allowed under base path", basePath: "/kms", path: "/kms/v1/tenants/tenant-001/keys", method: http.MethodPatch, wantStatus: http.StatusMethodNotAllowed, wantCode: "METHOD_NOT_ALLOWED"},
+		// ヘルスチェックはルート直下とBASE_PATHの配下の両方で応答する
+		{name: "healthz at root", basePath: "/kms", path: "/healthz", method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "healthz under base path", basePath: "/kms", path: "/kms/healthz", method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "version under base path", basePath: "/kms", path: "/kms/version", method: http.MethodGet, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{BasePath: tt.basePath})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("want status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var resp httputil.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("want code %s, got %s", tt.wantCode, resp.Code)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != "GET, HEAD, POST" {
+				t.Errorf("want Allow %q, got %q", "GET, HEAD, POST", rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestDisableKeys(t *testing.T) {
+	newRepo := func() *mockKeyRepository {
+		return &mockKeyRepository{
+			findAllResult: []*domain.EncryptionKey{
+				{ID: "id-1", TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
+				{ID: "id-2", TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusDisabled},
+				{ID: "id-3", TenantID: "tenant-001", Generation: 3, Status: domain.KeyStatusActive},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+		want       []DisableKeysResult
+	}{
+		{
+			name:       "range with mixed results",
+			body:       `{"from":1,"to":4,"reason":"compromised"}`,
+			wantStatus: http.StatusOK,
+			want: []DisableKeysResult{
+				{Generation: 1, Status: "disabled"},
+				{Generation: 2, Status: "already_disabled"},
+				{Generation: 3, Status: "disabled"},
+				{Generation: 4, Status: "not_found"},
+			},
+		},
+		{
+			name:       "list",
+			body:       `{"generations":[2,3]}`,
+			wantStatus: http.StatusOK,
+			want: []DisableKeysResult{
+				{Generation: 2, Status: "already_disabled"},
+				{Generation: 3, Status: "disabled"},
+			},
+		},
+		{name: "list and range", body: `{"generations":[1],"from":1,"to":2}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_GENERATION"},
+		{name: "reversed range", body: `{"from":5,"to":1}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_GENERATION"},
+		{name: "range too large", body: `{"from":1,"to":1000}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_GENERATION"},
+		{name: "empty", body: `{}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_BATCH_SIZE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(setupHandler(newRepo(), &mockKMSClient{}), &config.Config{})
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/disable-batch", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("want status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp httputil.ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("want code %s, got %s", tt.wantCode, resp.Code)
+				}
+				return
+			}
+			var resp DisableKeysResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Results) != len(tt.want) {
+				t.Fatalf("want %d results, got %+v", len(tt.want), resp.Results)
+			}
+			for i, w := range tt.want {
+				if resp.Results[i] != w {
+					t.Errorf("results[%d]: want %+v, got %+v", i, w, resp.Results[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	repo := &mockKeyRepository{
+		existsResult: true,
+		findLatestResult: &domain.EncryptionKey{
+			TenantID:     "tenant-001",
+			Generation:   1,
+			EncryptedKey: []byte("encrypted"),
+			Status:       domain.KeyStatusActive,
+		},
+		findByGensResult: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, EncryptedKey: []byte("encrypted"), Status: domain.KeyStatusActive},
+		},
+	}
+	kms := &mockKMSClient{decryptResult: []byte("plain-key-0123456789abcdefghijkl")}
+	mode := middleware.NewReadOnlyMode(true)
+	router := NewRouter(setupHandler(repo, kms), &config.Config{}, WithReadOnlyMode(mode))
+
+	writes := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodPost, path: "/v1/tenants/tenant-001/keys"},
+		{method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/rotate"},
+		{method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/import", body: `{"keys":[]}`},
+		{method: http.MethodDelete, path: "/v1/tenants/tenant-001/keys/1"},
+		{method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/disable-batch", body: `{"from":1,"to":2}`},
+	}
+	for _, tt := range writes {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s: want status 503, got %d", tt.method, tt.path, rec.Code)
+			continue
+		}
+		var resp httputil.ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s %s: failed to decode response: %v", tt.method, tt.path, err)
+		}
+		if resp.Code != "SERVICE_READ_ONLY" {
+			t.Errorf("%s %s: want code SERVICE_READ_ONLY, got %s", tt.method, tt.path, resp.Code)
+		}
+	}
+	if len(repo.createdKeys) != 0 || !repo.disabledAt.IsZero() {
+		t.Error("want no writes to the repository in read-only mode")
+	}
+
+	reads := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodGet, path: "/v1/tenants/tenant-001/keys/current"},
+		{method: http.MethodHead, path: "/v1/tenants/tenant-001/keys"},
+		{method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/batch-get", body: `{"generations":[1]}`},
+	}
+	for _, tt := range reads {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s %s: want status 200, got %d: %s", tt.method, tt.path, rec.Code, rec.Body.String())
+		}
+	}
+
+	// 実行中に無効化すると書き込みを再び受け付ける
+	mode.Set(false)
+	repo.existsResult = false
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys", nil))
+	if rec.Code != http.StatusCreated {
+		t.Errorf("want status 201 after leaving read-only mode, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		readiness  ReadinessChecker
+		wantStatus int
+	}{
+		{name: "no checker", readiness: nil, wantStatus: http.StatusOK},
+		{name: "healthy", readiness: stubReadiness{healthy: true}, wantStatus: http.StatusOK},
+		{name: "unhealthy", readiness: stubReadiness{healthy: false}, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{}, WithReadiness(tt.readiness))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("want status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestGetCurrentKey_KMSErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "permission denied", err: domain.ErrKMSPermission, wantStatus: http.StatusBadGateway, wantCode: "KMS_PERMISSION_DENIED"},
+		{name: "key unavailable", err: domain.ErrKMSKeyUnavailable, wantStatus: http.StatusBadGateway, wantCode: "KMS_KEY_UNAVAILABLE"},
+		{name: "transient", err: domain.ErrKMSUnavailable, wantStatus: http.StatusServiceUnavailable, wantCode: "KMS_UNAVAILABLE"},
+		{name: "corrupt key", err: domain.ErrCorruptKey, wantStatus: http.StatusInternalServerError, wantCode: "CORRUPT_KEY"},
+		{name: "unknown", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCode: "INTERNAL_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{
+				findLatestResult: &domain.EncryptionKey{
+					TenantID:     "tenant-001",
+					Generation:   1,
+					EncryptedKey: []byte("encrypted"),
+					Status:       domain.KeyStatusActive,
+				},
+			}
+			kms := &mockKMSClient{decryptErr: fmt.Errorf("decrypting: %w", tt.err)}
+			router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("want status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("want code %s, got %s", tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	info := BuildInfo{Version: "1.2.3", Commit: "abc1234", BuildTime: "2026-01-02T03:04:05Z", GoVersion: "go1.25.0"}
+	router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{}, WithBuildInfo(info))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+	var got BuildInfo
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got != info {
+		t.Errorf("want %+v, got %+v", info, got)
+	}
+}
+
+func TestGetCurrentKey_ZeroesPlaintextAfterResponse(t *testing.T) {
+	plain := []byte("plain-key-0123456789abcdefghijkl")
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{
+			TenantID:     "tenant-001",
+			Generation:   1,
+			EncryptedKey: []byte("encrypted"),
+			Status:       domain.KeyStatusActive,
+		},
+	}
+	kms := &mockKMSClient{decryptResult: plain}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+	var resp KeyResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Key != base64.StdEncoding.EncodeToString([]byte("plain-key-0123456789abcdefghijkl")) {
+		t.Errorf("want response to carry the key before zeroing, got %s", resp.Key)
+	}
+	for i, b := range plain {
+		if b != 0 {
+			t.Fatalf("want plaintext zeroed after response, byte %d is %d", i, b)
+		}
+	}
+}
+
+func TestMaxGeneration(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		maxGen     uint
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "rotate below cap", method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/rotate", maxGen: 2, wantStatus: http.StatusCreated},
+		{name: "rotate at cap", method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/rotate", maxGen: 3, wantStatus: http.StatusConflict, wantCode: "MAX_GENERATION_REACHED"},
+		{name: "rotate beyond cap", method: http.MethodPost, path: "/v1/tenants/tenant-001/keys/rotate", maxGen: 5, wantStatus: http.StatusConflict, wantCode: "MAX_GENERATION_REACHED"},
+		{name: "generation beyond cap", method: http.MethodGet, path: "/v1/tenants/tenant-001/keys/4", wantStatus: http.StatusBadRequest, wantCode: "INVALID_GENERATION"},
+		{name: "filter beyond cap", method: http.MethodGet, path: "/v1/tenants/tenant-001/keys?max_generation=4", wantStatus: http.StatusBadRequest, wantCode: "INVALID_FILTER"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{maxGenResult: tt.maxGen}
+			service := usecase.NewKeyService(repo, &mockKMSClient{}, usecase.WithMaxGeneration(3))
+			validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := NewKeyHandler(service, validator, middleware.NewJSONAuditLogger(io.Discard))
+			router := NewRouter(h, &config.Config{})
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), tt.wantCode) {
+				t.Errorf("want %s, got %s", tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestKeyExists(t *testing.T) {
+	tests := []struct {
+		name       string
+		exists     bool
+		wantStatus int
+	}{
+		{name: "tenant has keys", exists: true, wantStatus: http.StatusOK},
+		{name: "tenant has no keys", exists: false, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{existsResult: tt.exists}
+			kms := &mockKMSClient{}
+			router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+			req := httptest.NewRequest(http.MethodHead, "/v1/tenants/tenant-001/keys", nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("want status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("want empty body, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetKeyByGeneration_ConditionalGet(t *testing.T) {
+	updatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockKeyRepository{
+		findByGenResult: &domain.EncryptionKey{
+			ID:         "key-id",
+			TenantID:   "tenant-001",
+			Generation: 1,
+			KeyType:    domain.KeyTypeAES,
+			Bits:       256,
+			Status:     domain.KeyStatusActive,
+			UpdatedAt:  updatedAt,
+		},
+	}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/1", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("want 200 with ETag, got %d (ETag %q)", first.Code, etag)
+	}
+
+	// 一致する場合はKMSで復号せずに304を返す
+	kms.decryptErr = errors.New("decrypt must not be called")
+	rec := get(etag)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("want status 304, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("want empty body, got %q", rec.Body.String())
+	}
+	if got := rec.Header().Get("ETag"); got != etag {
+		t.Errorf("want ETag %q, got %q", etag, got)
+	}
+
+	// 鍵が更新された後の古いETagでは200と新しいETagを返す
+	kms.decryptErr = nil
+	repo.findByGenResult.UpdatedAt = updatedAt.Add(time.Second)
+	rec = get(etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got == "" || got == etag {
+		t.Errorf("want new ETag, got %q", got)
+	}
+}
+
+func TestListKeys_ConditionalGet(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockKeyRepository{
+		findAllResult: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive, CreatedAt: createdAt, UpdatedAt: createdAt},
+			{TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusActive, CreatedAt: createdAt, UpdatedAt: createdAt},
+		},
+	}
+	router := NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{})
+
+	list := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	etag := list("").Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("want ETag on list response")
+	}
+
+	rec := list(`"stale", ` + etag)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("want status 304, got %d", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("want empty body, got %q", rec.Body.String())
+	}
+
+	// 世代が無効化されると一覧のETagが変わる
+	repo.findAllResult[0].Status = domain.KeyStatusDisabled
+	rec = list(etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got == "" || got == etag {
+		t.Errorf("want new ETag, got %q", got)
+	}
+}
+
+func TestImportKeys_ReportsAllFieldErrors(t *testing.T) {
+	repo := &mockKeyRepository{}
+	router := NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{})
+
+	body := `{"keys":[
+		{"generation":1,"wrapped_key":"not base64!","status":"revoked"},
+		{"generation":1,"key_type":"des","wrapped_key":"d3JhcHBlZA==","created_at":"yesterday"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/import", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want status 400, got %d", rec.Code)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Code != "INVALID_BODY" {
+		t.Errorf("want code INVALID_BODY, got %s", resp.Code)
+	}
+	got := make(map[string]bool, len(resp.Details))
+	for _, d := range resp.Details {
+		got[d.Field] = true
+		if d.Field == "keys[0].status" && d.Message != "must be active, deprecated or disabled" {
+			t.Errorf("want status message listing every status, got %q", d.Message)
+		}
+	}
+	for _, field := range []string{
+		"keys[0].wrapped_key",
+		"keys[0].status",
+		"keys[1].generation",
+		"keys[1].key_type",
+		"keys[1].created_at",
+	} {
+		if !got[field] {
+			t.Errorf("want error for %s, got %+v", field, resp.Details)
+		}
+	}
+	if len(resp.Details) != 5 {
+		t.Errorf("want 5 field errors, got %d: %+v", len(resp.Details), resp.Details)
+	}
+	if len(repo.createdKeys) != 0 {
+		t.Errorf("want no keys imported, got %d", len(repo.createdKeys))
+	}
+}
+
+func TestBatchGetKeys_ReportsAllInvalidGenerations(t *testing.T) {
+	router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{})
+
+	body := `{"generations":[0,1,0]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/batch-get", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want status 400, got %d", rec.Code)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Details) != 2 || resp.Details[0].Field != "generations[0]" || resp.Details[1].Field != "generations[2]" {
+		t.Errorf("want errors for generations[0] and generations[2], got %+v", resp.Details)
+	}
+}
+
+func TestGetKey_ContentNegotiation(t *testing.T) {
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{TenantID: "tenant-001", Generation: 3, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive},
+		findByGenResult:  &domain.EncryptionKey{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive},
+	}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		// 平文はレスポンス後に消去されるため、リクエストごとに用意する
+		kms.decryptResult = []byte("plain-key-0123456789abcdefghijkl")
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, tt := range []struct {
+		path           string
+		wantGeneration string
+	}{
+		{path: "/v1/tenants/tenant-001/keys/current", wantGeneration: "3"},
+		{path: "/v1/tenants/tenant-001/keys/2", wantGeneration: "2"},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			// Acceptなし・application/jsonは従来のJSON
+			for _, accept := range []string{"", "application/json", "*/*", "application/octet-stream;q=0.5, application/json"} {
+				rec := get(tt.path, accept)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("Accept %q: want status 200, got %d: %s", accept, rec.Code, rec.Body.String())
+				}
+				if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Accept %q: want application/json, got %q", accept, ct)
+				}
+				var resp KeyResponse
+				if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+					t.Fatalf("Accept %q: failed to decode response: %v", accept, err)
+				}
+				if resp.Key != base64.StdEncoding.EncodeToString([]byte("plain-key-0123456789abcdefghijkl")) {
+					t.Errorf("Accept %q: unexpected key %q", accept, resp.Key)
+				}
+			}
+
+			// application/octet-streamは生のバイト列と世代ヘッダー
+			rec := get(tt.path, "application/octet-stream")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/octet-stream" {
+				t.Errorf("want application/octet-stream, got %q", ct)
+			}
+			if got := rec.Body.String(); got != "plain-key-0123456789abcdefghijkl" {
+				t.Errorf("want raw key bytes, got %q", got)
+			}
+			if got := rec.Header().Get("X-Key-Generation"); got != tt.wantGeneration {
+				t.Errorf("want X-Key-Generation %s, got %q", tt.wantGeneration, got)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("want Vary: Accept, got %q", got)
+			}
+
+			// 対応していない形式のみの場合は406
+			for _, accept := range []string{"text/plain", "application/json;q=0, text/*"} {
+				rec := get(tt.path, accept)
+				if rec.Code != http.StatusNotAcceptable {
+					t.Fatalf("Accept %q: want status 406, got %d", accept, rec.Code)
+				}
+				var errResp httputil.ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil {
+					t.Fatalf("failed to decode error response: %v", err)
+				}
+				if errResp.Code != "NOT_ACCEPTABLE" {
+					t.Errorf("want code NOT_ACCEPTABLE, got %s", errResp.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestGetKey_JWK(t *testing.T) {
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{TenantID: "tenant-001", Generation: 3, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive},
+		findByGenResult:  &domain.EncryptionKey{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeHMAC, Bits: 384, Status: domain.KeyStatusActive},
+	}
+	kms := &mockKMSClient{}
+	router := NewRouter(setupHandler(repo, kms), &config.Config{})
+	// base64urlで "-" と "_" になるバイトを含める
+	pattern := []byte{0xfb, 0xff, 0x00, 0x3e}
+
+	tests := []struct {
+		name    string
+		path    string
+		accept  string
+		keySize int
+		wantKid string
+		wantAlg string
+	}{
+		{name: "Acceptヘッダー", path: "/v1/tenants/tenant-001/keys/current", accept: "application/jwk+json", keySize: 32, wantKid: "tenant-001:3", wantAlg: "A256GCM"},
+		{name: "formatパラメータ", path: "/v1/tenants/tenant-001/keys/current?format=jwk", keySize: 32, wantKid: "tenant-001:3", wantAlg: "A256GCM"},
+		{name: "formatパラメータはAcceptより優先", path: "/v1/tenants/tenant-001/keys/2?format=jwk", accept: "application/octet-stream", keySize: 48, wantKid: "tenant-001:2", wantAlg: "HS384"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plainKey := bytes.Repeat(pattern, tt.keySize/len(pattern))
+			kms.decryptResult = bytes.Clone(plainKey)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/jwk+json" {
+				t.Errorf("want application/jwk+json, got %q", ct)
+			}
+			// RFC 7517/7518に従ってoct鍵として解析する
+			var jwk map[string]string
+			if err := json.NewDecoder(rec.Body).Decode(&jwk); err != nil {
+				t.Fatalf("failed to decode JWK: %v", err)
+			}
+			if jwk["kty"] != "oct" {
+				t.Errorf("want kty oct, got %q", jwk["kty"])
+			}
+			if jwk["kid"] != tt.wantKid {
+				t.Errorf("want kid %q, got %q", tt.wantKid, jwk["kid"])
+			}
+			if jwk["alg"] != tt.wantAlg {
+				t.Errorf("want alg %q, got %q", tt.wantAlg, jwk["alg"])
+			}
+			got, err := base64.RawURLEncoding.Strict().DecodeString(jwk["k"])
+			if err != nil {
+				t.Fatalf("k is not unpadded base64url: %v", err)
+			}
+			if !bytes.Equal(got, plainKey) {
+				t.Errorf("want key %x, got %x", plainKey, got)
+			}
+		})
+	}
+
+	t.Run("不正なformatパラメータ", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current?format=pem", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("want status 400, got %d", rec.Code)
+		}
+		var errResp httputil.ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil {
+			t.Fatalf("failed to decode error response: %v", err)
+		}
+		if errResp.Code != "INVALID_FORMAT" {
+			t.Errorf("want code INVALID_FORMAT, got %s", errResp.Code)
+		}
+	})
+}
+
+func TestGetKeyByGeneration_ETagPerRepresentation(t *testing.T) {
+	repo := &mockKeyRepository{
+		findByGenResult: &domain.EncryptionKey{TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
+	}
+	router := NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{})
+
+	etags := map[string]string{}
+	for _, accept := range []string{"application/json", "application/octet-stream", "application/jwk+json"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/1", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		etags[accept] = rec.Header().Get("ETag")
+	}
+	if etags["application/json"] == "" || etags["application/json"] == etags["application/octet-stream"] ||
+		etags["application/jwk+json"] == etags["application/json"] || etags["application/jwk+json"] == etags["application/octet-stream"] {
+		t.Errorf("want distinct ETags per representation, got %v", etags)
+	}
+}
+
+func TestGetKey_IncludesMetadata(t *testing.T) {
+	createdAt := time.Date(2025, 1, 28, 10, 30, 0, 0, time.UTC)
+	key := func(gen uint) *domain.EncryptionKey {
+		return &domain.EncryptionKey{TenantID: "tenant-001", Generation: gen, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive, CreatedAt: createdAt}
+	}
+	repo := &mockKeyRepository{findLatestResult: key(3), findByGenResult: key(2)}
+	router := NewRouter(setupHandler(repo, &mockKMSClient{}), &config.Config{})
+
+	for _, path := range []string{"/v1/tenants/tenant-001/keys/current", "/v1/tenants/tenant-001/keys/2"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: want status 200, got %d", path, rec.Code)
+		}
+		var resp KeyResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", path, err)
+		}
+		if resp.CreatedAt != "2025-01-28T10:30:00Z" {
+			t.Errorf("%s: want created_at 2025-01-28T10:30:00Z, got %q", path, resp.CreatedAt)
+		}
+		if resp.Status != "active" {
+			t.Errorf("%s: want status active, got %q", path, resp.Status)
+		}
+	}
+}
+
+// jsonFields はvをJSONに変換し、出力されたフィールドを返す。
+func jsonFields(t *testing.T, v any) map[string]any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %v", v, err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", b, err)
+	}
+	return fields
+}
+
+func TestResponseShapes_ZeroValues(t *testing.T) {
+	created := time.Date(2025, 1, 28, 10, 30, 0, 0, time.UTC)
+	later := created.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		value   any
+		present []string
+		absent  []string
+	}{
+		{
+			name:    "key metadata with zero generation and no optional fields",
+			value:   keyMetadataResponse(&domain.KeyMetadata{TenantID: "tenant-001", Status: domain.KeyStatusActive, CreatedAt: created}),
+			present: []string{"tenant_id", "generation", "key_type", "key_bits", "status", "created_at", "is_current"},
+			absent:  []string{"kms_key_name", "expires_at", "last_used_at", "disabled_at", "disabled_reason"},
+		},
+		{
+			name: "key metadata with all optional fields",
+			value: keyMetadataResponse(&domain.KeyMetadata{
+				TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusDisabled, KMSKeyName: "kms-key", CreatedAt: created,
+				ExpiresAt: &later, LastUsedAt: &later, DisabledAt: &later, DisabledReason: "compromised",
+			}),
+			present: []string{"generation", "kms_key_name", "expires_at", "last_used_at", "disabled_at", "disabled_reason"},
+		},
+		{
+			name:    "key with zero generation",
+			value:   KeyResponse{TenantID: "tenant-001"},
+			present: []string{"tenant_id", "generation", "key_type", "key_bits", "key", "status", "created_at"},
+		},
+		{
+			name:    "audit event without generation",
+			value:   AuditEventResponse{Operation: "LIST_TENANTS", Result: "SUCCESS"},
+			present: []string{"operation", "tenant_id", "result", "timestamp"},
+			absent:  []string{"generation", "request_id"},
+		},
+		{
+			name:    "audit event with generation",
+			value:   AuditEventResponse{Operation: "ROTATE_KEY", Generation: 3, Result: "SUCCESS", RequestID: "req-1"},
+			present: []string{"generation", "request_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := jsonFields(t, tt.value)
+			for _, name := range tt.present {
+				if _, ok := fields[name]; !ok {
+					t.Errorf("want %s in %v", name, fields)
+				}
+			}
+			for _, name := range tt.absent {
+				if _, ok := fields[name]; ok {
+					t.Errorf("want no %s in %v", name, fields)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
@@ -11,28 +12,182 @@ import (
 )
 
 // NewRouter はルーターを生成する。
-func NewRouter(h *KeyHandler, cfg *config.Config) http.Handler {
+func NewRouter(h *KeyHandler, cfg *config.Config, opts ...RouterOption) http.Handler {
+	var o routerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	r := chi.NewRouter()
 
 	// ミドルウェア
-	r.Use(chimiddleware.Logger)
-	r.Use(chimiddleware.Recoverer)
 	r.Use(chimiddleware.RequestID)
-
 	// トレーシングミドルウェア（OTEL_ENABLED=trueの場合のみ）
+	// アクセスログとpanicのログにトレース情報を含めるため、それらより外側に配置する
 	if cfg.OtelEnabled {
 		r.Use(middleware.Tracing(cfg.OtelServiceName))
 	}
+	r.Use(middleware.AccessLog)
+	r.Use(middleware.Recover)
+	// CORS（CORS_ALLOWED_ORIGINSが設定されている場合のみ）
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		r.Use(middleware.CORS(middleware.CORSConfig{
+			AllowedOrigins: cfg.CORSAllowedOrigins,
+			AllowedMethods: cfg.CORSAllowedMethods,
+			AllowedHeaders: cfg.CORSAllowedHeaders,
+		}))
+	}
+	if cfg.MaxRequestBytes > 0 {
+		r.Use(middleware.MaxBytes(cfg.MaxRequestBytes))
+	}
+	// リクエスト単位の処理期限（0で無効）
+	if cfg.RequestTimeout > 0 {
+		r.Use(middleware.Timeout(cfg.RequestTimeout))
+	}
+
+	// 鍵を生成する操作は再送で世代が重複しないよう冪等にする
+	idempotent := func(next http.Handler) http.Handler { return next }
+	if o.idempotencyStore != nil {
+		idempotent = middleware.Idempotency(o.idempotencyStore)
+	}
+	// 読み取り専用モードでは状態を変更する操作を拒否する（batch-getはPOSTだが参照のため対象外）
+	writable := func(next http.Handler) http.Handler { return next }
+	if o.readOnly != nil {
+		writable = middleware.ReadOnly(o.readOnly)
+	}
 
 	// ルート定義
-	r.Route("/v1/tenants/{tenant_id}/keys", func(r chi.Router) {
-		r.Post("/", h.CreateKey)
-		r.Get("/", h.ListKeys)
-		r.Get("/current", h.GetCurrentKey)
-		r.Get("/{generation}", h.GetKeyByGeneration)
-		r.Delete("/{generation}", h.DisableKey)
-		r.Post("/rotate", h.RotateKey)
-	})
+	// ヘルスチェックとバージョンはプローブの設定がBASE_PATHに依存しないよう常にルート直下に置く。
+	// BASE_PATHを設定した場合は、プレフィックス付きのURLで接続するkeyctlなどのためにその配下にも置く
+	probes := func(r chi.Router) {
+		r.Get("/healthz", healthz)
+		r.Get("/readyz", readyz(o.readiness))
+		r.Get("/version", version(o.buildInfo))
+	}
+	api := func(r chi.Router) {
+		// サーバー全体の同時処理数の上限（0で無制限）。プローブは過負荷時も応答できるよう対象外とする
+		if cfg.MaxConcurrentRequests > 0 {
+			r = r.With(middleware.ConcurrencyLimit(cfg.MaxConcurrentRequests))
+		}
+		r.Get("/v1/tenants", h.ListTenants)
+		if o.audit != nil {
+			r.Get("/v1/tenants/{tenant_id}/audit", o.audit.ListAuditEvents)
+		}
+		if o.tenantSettings != nil {
+			r.Get("/v1/tenants/{tenant_id}/settings", o.tenantSettings.GetSettings)
+			r.With(writable).Put("/v1/tenants/{tenant_id}/settings", o.tenantSettings.UpdateSettings)
+		}
+		r.Post("/v1/tenants/{tenant_id}/encrypt-batch", h.EncryptBatch)
+		r.Post("/v1/tenants/{tenant_id}/decrypt", h.Decrypt)
+		r.Route("/v1/tenants/{tenant_id}/keys", func(r chi.Router) {
+			r.With(writable, idempotent).Post("/", h.CreateKey)
+			r.Get("/", h.ListKeys)
+			r.Head("/", h.KeyExists)
+			r.Get("/current", h.GetCurrentKey)
+			r.Get("/current/status", h.GetCurrentKeyStatus)
+			r.Get("/count", h.CountKeys)
+			r.Get("/recent", h.RecentKeys)
+			r.Get("/{generation}", h.GetKeyByGeneration)
+			r.With(writable).Delete("/{generation}", h.DisableKey)
+			r.With(writable).Post("/{generation}/deprecate", h.DeprecateKey)
+			r.With(writable, idempotent).Post("/rotate", h.RotateKey)
+			r.With(writable).Post("/import", h.ImportKeys)
+			r.With(writable).Post("/disable-batch", h.DisableKeys)
+			r.Post("/batch-get", h.BatchGetKeys)
+			// 論理削除と復元（SOFT_DELETE_ENABLED=trueの場合のみ）
+			if cfg.SoftDeleteEnabled {
+				r.With(writable).Post("/{generation}/soft-delete", h.SoftDeleteKey)
+				r.With(writable).Post("/{generation}/restore", h.RestoreKey)
+			}
+		})
+	}
+	// APIはBASE_PATH（例: /kms）の配下に置く。未設定の場合はルート直下
+	probes(r)
+	if cfg.BasePath != "" {
+		r.Route(cfg.BasePath, func(r chi.Router) {
+			probes(r)
+			api(r)
+		})
+	} else {
+		api(r)
+	}
+
+	// 未定義のパス・メソッドもAPIと同じJSON形式のエラーで返す
+	r.NotFound(notFound)
+	r.MethodNotAllowed(methodNotAllowed(routeMethods(r)))
 
 	return r
 }
+
+// notFound は未定義のパスに対して404を返す。
+func notFound(w http.ResponseWriter, r *http.Request) {
+	errorWithContext(w, r, http.StatusNotFound, "NOT_FOUND", "resource not found")
+}
+
+// routeMethods はルート定義からパスパターンごとの許可メソッドを収集する。
+// chiはカスタムの405ハンドラーに許可メソッドを渡さないため、Allowヘッダーの算出に使用する。
+func routeMethods(routes chi.Routes) map[string][]string {
+	methods := make(map[string][]string)
+	_ = chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(route, "/")
+		methods[route] = append(methods[route], method)
+		return nil
+	})
+	return methods
+}
+
+// allowCandidates はAllowヘッダーに列挙するメソッドとその順序。
+var allowCandidates = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// methodNotAllowed はパスは定義済みだがメソッドが未対応の場合に405を返すハンドラーを生成する。
+// Allowヘッダーにはリクエストのパスに一致するすべてのパターンの許可メソッドを列挙する。
+func methodNotAllowed(routeMethods map[string][]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		allowed := make(map[string]bool)
+		for pattern, methods := range routeMethods {
+			if matchRoute(pattern, path) {
+				for _, m := range methods {
+					allowed[m] = true
+				}
+			}
+		}
+		var allow []string
+		for _, m := range allowCandidates {
+			if allowed[m] {
+				allow = append(allow, m)
+			}
+		}
+		w.Header().Set("Allow", strings.Join(allow, ", "))
+		errorWithContext(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+	}
+}
+
+// matchRoute はパスがchiのルートパターンに一致するかを返す。{param}は空でない1セグメントに一致する。
+func matchRoute(pattern, path string) bool {
+	ps := strings.Split(pattern, "/")
+	ss := strings.Split(path, "/")
+	if len(ps) != len(ss) {
+		return false
+	}
+	for i, p := range ps {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			if ss[i] == "" {
+				return false
+			}
+			continue
+		}
+		if p != ss[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package handler
+
+import "key-management-service/internal/middleware"
+
+// routerOptions はNewRouterの任意設定。
+type routerOptions struct {
+	idempotencyStore middleware.IdempotencyStore
+	readiness        ReadinessChecker
+	buildInfo        BuildInfo
+	audit            *AuditHandler
+	readOnly         *middleware.ReadOnlyMode
+	tenantSettings   *TenantSettingsHandler
+}
+
+// RouterOption はNewRouterの任意設定を行う。
+type RouterOption func(*routerOptions)
+
+// WithIdempotencyStore は作成・ローテーションのIdempotency-Keyヘッダーの記録先を設定する。
+// 設定しない場合、Idempotency-Keyヘッダーは無視される。
+func WithIdempotencyStore(store middleware.IdempotencyStore) RouterOption {
+	return func(o *routerOptions) { o.idempotencyStore = store }
+}
+
+// WithReadiness は/readyzで参照する状態を設定する。設定しない場合は常に受付可能を返す。
+func WithReadiness(checker ReadinessChecker) RouterOption {
+	return func(o *routerOptions) { o.readiness = checker }
+}
+
+// WithBuildInfo は/versionで返すビルド情報を設定する。
+func WithBuildInfo(info BuildInfo) RouterOption {
+	return func(o *routerOptions) { o.buildInfo = info }
+}
+
+// WithAuditHandler は監査イベントの検索エンドポイントを有効にする。
+// 設定しない場合、/v1/tenants/{tenant_id}/auditは登録されない。
+func WithAuditHandler(h *AuditHandler) RouterOption {
+	return func(o *routerOptions) { o.audit = h }
+}
+
+// WithReadOnlyMode は読み取り専用モードの状態を設定する。
+// 有効な間は鍵の作成・ローテーション・インポート・無効化を503で拒否し、参照系のAPIのみ受け付ける。
+func WithReadOnlyMode(mode *middleware.ReadOnlyMode) RouterOption {
+	return func(o *routerOptions) { o.readOnly = mode }
+}
+
+// WithTenantSettingsHandler はテナント設定（自動ローテーション間隔など）のエンドポイントを有効にする。
+// 設定しない場合、/v1/tenants/{tenant_id}/settingsは登録されない。
+func WithTenantSettingsHandler(h *TenantSettingsHandler) RouterOption {
+	return func(o *routerOptions) { o.tenantSettings = h }
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"key-management-service/internal/domain"
+	"key-management-service/internal/middleware"
+	"key-management-service/internal/usecase"
+	"key-management-service/pkg/httputil"
+)
+
+// TenantSettingsHandler はテナントごとの設定の参照と変更を提供する。
+type TenantSettingsHandler struct {
+	service         *usecase.TenantSettingsService
+	tenantValidator *TenantIDValidator
+	audit           middleware.AuditLogger
+}
+
+// NewTenantSettingsHandler は新しいTenantSettingsHandlerを生成する。
+func NewTenantSettingsHandler(service *usecase.TenantSettingsService, tenantValidator *TenantIDValidator, audit middleware.AuditLogger) *TenantSettingsHandler {
+	return &TenantSettingsHandler{
+		service:         service,
+		tenantValidator: tenantValidator,
+		audit:           audit,
+	}
+}
+
+// TenantSettingsResponse はテナント設定のレスポンス形式。
+type TenantSettingsResponse struct {
+	TenantID string `json:"tenant_id"`
+	// RotationInterval は自動ローテーション間隔（例: 90d）。"0s"は自動ローテーションなし。
+	RotationInterval string `json:"rotation_interval"`
+	UpdatedAt        string `json:"updated_at,omitempty"`
+}
+
+// UpdateTenantSettingsRequest はテナント設定の変更のリクエスト形式。
+type UpdateTenantSettingsRequest struct {
+	RotationInterval *string `json:"rotation_interval"`
+}
+
+func tenantSettingsResponse(s *domain.TenantSettings) TenantSettingsResponse {
+	resp := TenantSettingsResponse{
+		TenantID:         s.TenantID,
+		RotationInterval: domain.FormatRotationInterval(s.RotationInterval),
+	}
+	if !s.UpdatedAt.IsZero() {
+		resp.UpdatedAt = s.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
+
+// GetSettings はテナントの設定を取得する。設定していないテナントは既定値を返す。
+func (h *TenantSettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	settings, err := h.service.GetSettings(r.Context(), tenantID)
+	if err != nil {
+		h.audit.Write(r.Context(), "GET_TENANT_SETTINGS", tenantID, 0, "FAILED")
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "GET_TENANT_SETTINGS", tenantID, 0, "SUCCESS")
+	httputil.JSON(w, http.StatusOK, tenantSettingsResponse(settings))
+}
+
+// UpdateSettings はテナントの設定を変更する。
+func (h *TenantSettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenant_id")
+	if err := h.tenantValidator.Validate(tenantID); err != nil {
+		writeTenantIDError(w, r, err)
+		return
+	}
+
+	var req UpdateTenantSettingsRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+	verr := &httputil.ValidationError{}
+	var interval time.Duration
+	if req.RotationInterval == nil {
+		verr.Add("rotation_interval", "is required")
+	} else {
+		var err error
+		if interval, err = domain.ParseRotationInterval(*req.RotationInterval); err != nil {
+			verr.Addf("rotation_interval", "must be 0 or a duration of at least %s (e.g. 90d, 720h)", domain.MinRotationInterval)
+		}
+	}
+	if verr.HasErrors() {
+		validationErrorWithContext(w, r, "INVALID_ROTATION_INTERVAL", "invalid rotation interval", verr)
+		return
+	}
+
+	settings, err := h.service.SetRotationInterval(r.Context(), tenantID, interval)
+	if err != nil {
+		h.audit.Write(r.Context(), "UPDATE_TENANT_SETTINGS", tenantID, 0, "FAILED")
+		if errors.Is(err, domain.ErrInvalidRotationInterval) {
+			errorWithContext(w, r, http.StatusBadRequest, "INVALID_ROTATION_INTERVAL", "invalid rotation interval")
+			return
+		}
+		writeServiceError(w, r, err)
+		return
+	}
+
+	h.audit.Write(r.Context(), "UPDATE_TENANT_SETTINGS", tenantID, 0, "SUCCESS")
+	httputil.JSON(w, http.StatusOK, tenantSettingsResponse(settings))
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"key-management-service/config"
+	"key-management-service/internal/domain"
+	"key-management-service/internal/middleware"
+	"key-management-service/internal/usecase"
+	"key-management-service/pkg/httputil"
+)
+
+// memoryTenantSettingsRepository はメモリ上にテナント設定を保持するテスト用リポジトリ。
+type memoryTenantSettingsRepository struct {
+	settings map[string]*domain.TenantSettings
+}
+
+func (m *memoryTenantSettingsRepository) Find(ctx context.Context, tenantID string) (*domain.TenantSettings, error) {
+	return m.settings[tenantID], nil
+}
+
+func (m *memoryTenantSettingsRepository) Save(ctx context.Context, settings *domain.TenantSettings) error {
+	m.settings[settings.TenantID] = settings
+	return nil
+}
+
+func (m *memoryTenantSettingsRepository) FindWithRotationInterval(ctx context.Context) ([]*domain.TenantSettings, error) {
+	return nil, nil
+}
+
+func TestTenantSettings(t *testing.T) {
+	validator, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &memoryTenantSettingsRepository{settings: make(map[string]*domain.TenantSettings)}
+	settingsHandler := NewTenantSettingsHandler(usecase.NewTenantSettingsService(repo, 0), validator, middleware.NewJSONAuditLogger(io.Discard))
+	router := NewRouter(setupHandler(&mockKeyRepository{}, &mockKMSClient{}), &config.Config{}, WithTenantSettingsHandler(settingsHandler))
+
+	get := func() TenantSettingsResponse {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/settings", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", rec.Code)
+		}
+		var resp TenantSettingsResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	if got := get().RotationInterval; got != "0s" {
+		t.Errorf("want no rotation interval by default, got %s", got)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v1/tenants/tenant-001/settings", strings.NewReader(`{"rotation_interval":"90d"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := get().RotationInterval; got != "90d" {
+		t.Errorf("want 90d, got %s", got)
+	}
+
+	for _, body := range []string{`{"rotation_interval":"10m"}`, `{"rotation_interval":"soon"}`, `{}`} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v1/tenants/tenant-001/settings", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: want status 400, got %d", body, rec.Code)
+			continue
+		}
+		var resp httputil.ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Code != "INVALID_ROTATION_INTERVAL" {
+			t.Errorf("%s: want code INVALID_ROTATION_INTERVAL, got %s", body, resp.Code)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+
+	"key-management-service/internal/domain"
+)
+
+// TenantIDValidator はテナントIDの形式を検証する。
+// 許可リストを設定した場合は、リストに含まれるテナントのみを受け付ける。
+type TenantIDValidator struct {
+	pattern   *regexp.Regexp
+	maxLen    int
+	allowlist map[string]struct{}
+}
+
+// NewTenantIDValidator は許可パターンと最大長からTenantIDValidatorを生成する。
+// パターンが不正な場合は起動時に検出できるようエラーを返す。
+func NewTenantIDValidator(pattern string, maxLen int) (*TenantIDValidator, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling tenant ID pattern: %w", err)
+	}
+	if maxLen < 1 {
+		return nil, fmt.Errorf("tenant ID max length must be positive: %d", maxLen)
+	}
+	return &TenantIDValidator{pattern: re, maxLen: maxLen}, nil
+}
+
+// WithAllowlist は許可リストを設定したTenantIDValidatorを返す。空の場合はすべてのテナントを許可する。
+// 許可リストに形式が不正なテナントIDが含まれる場合は起動時に検出できるようエラーを返す。
+func (v *TenantIDValidator) WithAllowlist(tenantIDs []string) (*TenantIDValidator, error) {
+	clone := *v
+	clone.allowlist = nil
+	if len(tenantIDs) == 0 {
+		return &clone, nil
+	}
+	clone.allowlist = make(map[string]struct{}, len(tenantIDs))
+	for _, id := range tenantIDs {
+		if err := v.Validate(id); err != nil {
+			return nil, fmt.Errorf("tenant allowlist entry %q: %w", id, err)
+		}
+		clone.allowlist[id] = struct{}{}
+	}
+	return &clone, nil
+}
+
+// Validate はテナントIDが空でなく、最大長以内でパターンに一致するか検証する。
+// 形式が正しくても許可リストに含まれない場合はdomain.ErrTenantNotAllowedを返す。
+func (v *TenantIDValidator) Validate(tenantID string) error {
+	if tenantID == "" {
+		return domain.ErrInvalidTenantID
+	}
+	if len(tenantID) > v.maxLen {
+		return domain.ErrInvalidTenantID
+	}
+	if !v.pattern.MatchString(tenantID) {
+		return domain.ErrInvalidTenantID
+	}
+	if v.allowlist != nil {
+		if _, ok := v.allowlist[tenantID]; !ok {
+			return domain.ErrTenantNotAllowed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"key-management-service/config"
+	"key-management-service/internal/domain"
+)
+
+func TestTenantIDValidator_DefaultRules(t *testing.T) {
+	v, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tenantID string
+		wantErr  bool
+	}{
+		{"alphanumeric", "tenant-001", false},
+		{"underscore", "tenant_001", false},
+		{"max length", strings.Repeat("a", 64), false},
+		{"empty", "", true},
+		{"too long", strings.Repeat("a", 65), true},
+		{"invalid char", "invalid@tenant", true},
+		{"colon", "tenant:001", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.tenantID)
+			if tt.wantErr && !errors.Is(err, domain.ErrInvalidTenantID) {
+				t.Errorf("want ErrInvalidTenantID, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTenantIDValidator_CustomPattern(t *testing.T) {
+	v, err := NewTenantIDValidator(`^[a-zA-Z0-9:_-]+$`, 128)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := v.Validate("org:3f2504e0-4f89-11d3-9a0c-0305e82c3301"); err != nil {
+		t.Errorf("want colon-separated UUID to be accepted, got %v", err)
+	}
+	if err := v.Validate("invalid@tenant"); !errors.Is(err, domain.ErrInvalidTenantID) {
+		t.Errorf("want ErrInvalidTenantID, got %v", err)
+	}
+}
+
+func TestNewTenantIDValidator_InvalidConfig(t *testing.T) {
+	if _, err := NewTenantIDValidator(`^[a-z`, 64); err == nil {
+		t.Error("want error for invalid pattern, got nil")
+	}
+	if _, err := NewTenantIDValidator(config.DefaultTenantIDPattern, 0); err == nil {
+		t.Error("want error for non-positive max length, got nil")
+	}
+}
+
+func TestTenantIDValidator_Allowlist(t *testing.T) {
+	base, err := NewTenantIDValidator(config.DefaultTenantIDPattern, config.DefaultTenantIDMaxLen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, err := base.WithAllowlist([]string{"tenant-001", "tenant-002"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		validator *TenantIDValidator
+		tenantID  string
+		wantErr   error
+	}{
+		{"allowed tenant", v, "tenant-001", nil},
+		{"disallowed tenant", v, "tenant-003", domain.ErrTenantNotAllowed},
+		// 形式の検証は許可リストより優先する
+		{"invalid format", v, "invalid@tenant", domain.ErrInvalidTenantID},
+		{"no allowlist", base, "tenant-003", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.validator.Validate(tt.tenantID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// 空の許可リストは全テナントを許可する
+	empty, err := v.WithAllowlist(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := empty.Validate("tenant-003"); err != nil {
+		t.Errorf("want empty allowlist to pass through, got %v", err)
+	}
+	if _, err := base.WithAllowlist([]string{"tenant-001", "bad@tenant"}); !errors.Is(err, domain.ErrInvalidTenantID) {
+		t.Errorf("want ErrInvalidTenantID for malformed allowlist entry, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+
+	"key-management-service/pkg/httputil"
+)
+
+// BuildInfo は稼働中のビルドの情報。ビルド時に-ldflagsで埋め込んだ値を設定する。
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// version はビルド情報を返すハンドラーを生成する。
+func version(info BuildInfo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httputil.JSON(w, http.StatusOK, info)
+	}
+}
@@ -2,10 +2,12 @@
 package infra
 
 import (
+	"fmt"
 	"log/slog"
 	"time"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"gorm.io/plugin/opentelemetry/tracing"
@@ -13,11 +15,51 @@ import (
 	"key-management-service/config"
 )
 
+// newDialector はDB_DRIVERに対応するgormのダイアレクタを返す。未指定の場合はMySQLとする。
+func newDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "", config.DBDriverMySQL:
+		return mysql.Open(dsn), nil
+	case config.DBDriverPostgres:
+		return postgres.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", driver)
+	}
+}
+
 // NewDB はgormによるデータベース接続を初期化する。
+// ドライバはcfg.DBDriverで選択し、接続プール設定はドライバによらず共通とする。
 func NewDB(dsn string, cfg *config.Config) (*gorm.DB, error) {
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
-	})
+	dialector, err := newDialector(cfg.DBDriver, dsn)
+	if err != nil {
+		slog.Error("failed to select database driver",
+			"operation", "db_init",
+			"driver", cfg.DBDriver,
+			"error", err,
+		)
+		return nil, err
+	}
+	return openDB(dialector, cfg)
+}
+
+// maxConnectBackoff は起動時のデータベース接続を再試行するまでの待ち時間の上限。
+const maxConnectBackoff = 30 * time.Second
+
+// openDB は指定されたダイアレクタで接続を開き、トレーシングと接続プールを設定する。
+// 接続（gorm.Openによる疎通確認を含む）に失敗した場合はcfg.DBConnectRetries回まで再試行する。
+func openDB(dialector gorm.Dialector, cfg *config.Config) (*gorm.DB, error) {
+	db, err := connectWithRetry(func() (*gorm.DB, error) {
+		db, err := gorm.Open(dialector, &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if err != nil && db != nil {
+			// 疎通確認に失敗した接続を閉じ、再試行のたびに接続プールが残らないようにする
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				_ = sqlDB.Close()
+			}
+		}
+		return db, err
+	}, cfg.DBConnectRetries, cfg.DBConnectBackoff)
 	if err != nil {
 		slog.Error("failed to open database connection",
 			"operation", "db_init",
@@ -53,3 +95,34 @@ func NewDB(dsn string, cfg *config.Config) (*gorm.DB, error) {
 
 	return db, nil
 }
+
+// connectWithRetry はopenが成功するまで最大retries回まで再試行する。
+// 待ち時間はbackoffから再試行のたびに倍にし、maxConnectBackoffを上限とする。
+// データベースのコンテナがアプリケーションより遅れて起動する場合に備えるためのもので、起動時にのみ使用する。
+func connectWithRetry(open func() (*gorm.DB, error), retries int, backoff time.Duration) (*gorm.DB, error) {
+	delay := backoff
+	for attempt := 1; ; attempt++ {
+		db, err := open()
+		if err == nil {
+			if attempt > 1 {
+				slog.Info("database connection established",
+					"operation", "db_init",
+					"attempt", attempt,
+				)
+			}
+			return db, nil
+		}
+		if attempt > retries {
+			return nil, fmt.Errorf("connecting to database failed after %d attempt(s): %w", attempt, err)
+		}
+		slog.Warn("database connection failed, retrying",
+			"operation", "db_init",
+			"attempt", attempt,
+			"max_attempts", retries+1,
+			"retry_in", delay,
+			"error", err,
+		)
+		time.Sleep(delay)
+		delay = min(delay*2, maxConnectBackoff)
+	}
+}
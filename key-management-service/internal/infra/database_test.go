@@ -0,0 +1,100 @@
+package infra
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"key-management-service/config"
+)
+
+func TestNewDialector(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   string
+	}{
+		{driver: "", want: "mysql"},
+		{driver: config.DBDriverMySQL, want: "mysql"},
+		{driver: config.DBDriverPostgres, want: "postgres"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			dialector, err := newDialector(tt.driver, "dsn")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := dialector.Name(); got != tt.want {
+				t.Errorf("want dialector %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewDB_UnsupportedDriver(t *testing.T) {
+	if _, err := NewDB("dsn", &config.Config{DBDriver: "oracle"}); err == nil {
+		t.Error("want error for unsupported driver")
+	}
+}
+
+func TestOpenDB_SharedPoolSettings(t *testing.T) {
+	// 実際のMySQL/PostgreSQLの代わりにSQLiteで接続プール設定が適用されることを確認する
+	db, err := openDB(sqlite.Open(":memory:"), &config.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+
+	if got := sqlDB.Stats().MaxOpenConnections; got != 10 {
+		t.Errorf("want max open connections 10, got %d", got)
+	}
+}
+
+func TestConnectWithRetry(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	// failuresの回数だけ失敗した後にSQLiteで接続する（起動が遅れたデータベースの代わり）
+	flakyOpen := func(failures int, calls *int) func() (*gorm.DB, error) {
+		return func() (*gorm.DB, error) {
+			*calls++
+			if *calls <= failures {
+				return nil, errRefused
+			}
+			return gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		}
+	}
+
+	var calls int
+	db, err := connectWithRetry(flakyOpen(2, &calls), 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("want connection after 2 failures, got %v", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	if calls != 3 {
+		t.Errorf("want 3 attempts, got %d", calls)
+	}
+
+	// 再試行の回数を使い切った場合は最後のエラーを返す
+	calls = 0
+	_, err = connectWithRetry(flakyOpen(5, &calls), 2, time.Millisecond)
+	if !errors.Is(err, errRefused) || !strings.Contains(err.Error(), "after 3 attempt(s)") {
+		t.Errorf("want error after 3 attempts wrapping the last error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("want 3 attempts, got %d", calls)
+	}
+
+	// 再試行しない設定では1回で失敗する
+	calls = 0
+	if _, err := connectWithRetry(flakyOpen(1, &calls), 0, time.Millisecond); err == nil || calls != 1 {
+		t.Errorf("want a single failed attempt without retries, got %d attempts (%v)", calls, err)
+	}
+}
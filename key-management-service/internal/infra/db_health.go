@@ -0,0 +1,74 @@
+package infra
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Pinger はデータベースへの疎通確認を行うインターフェース。*sql.DBが満たす。
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DBHealthMonitor はデータベースへの疎通をバックグラウンドで定期的に確認する。
+// MySQLの再起動などでプールに切断済みの接続が残った場合に、readinessで検知できるようにする。
+type DBHealthMonitor struct {
+	pinger   Pinger
+	interval time.Duration
+	healthy  atomic.Bool
+}
+
+// NewDBHealthMonitor は新しいDBHealthMonitorを生成する。
+// 最初の確認が行われるまでは正常とみなす。
+func NewDBHealthMonitor(pinger Pinger, interval time.Duration) *DBHealthMonitor {
+	m := &DBHealthMonitor{pinger: pinger, interval: interval}
+	m.healthy.Store(true)
+	return m
+}
+
+// Healthy は直近の確認結果を返す。データベースへの問い合わせは行わない。
+func (m *DBHealthMonitor) Healthy() bool {
+	return m.healthy.Load()
+}
+
+// Run はctxが終了するまでintervalごとに疎通を確認する。
+func (m *DBHealthMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check は疎通を1回確認し、状態が変化した場合にログを出力する。
+func (m *DBHealthMonitor) Check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	err := m.pinger.PingContext(pingCtx)
+	if ctx.Err() != nil {
+		// 停止中の確認失敗は状態に反映しない
+		return
+	}
+	healthy := err == nil
+	if m.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		slog.InfoContext(ctx, "database became healthy",
+			"operation", "db_health",
+		)
+		return
+	}
+	slog.ErrorContext(ctx, "database became unhealthy",
+		"operation", "db_health",
+		"error", err,
+	)
+}
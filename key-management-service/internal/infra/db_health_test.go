@@ -0,0 +1,92 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// togglePinger は疎通確認の結果を切り替えられるテスト用Pinger。
+type togglePinger struct {
+	down  atomic.Bool
+	calls atomic.Int32
+}
+
+func (p *togglePinger) PingContext(ctx context.Context) error {
+	p.calls.Add(1)
+	if p.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestDBHealthMonitor_Transitions(t *testing.T) {
+	buf := captureLogs(t)
+	ctx := context.Background()
+	pinger := &togglePinger{}
+	monitor := NewDBHealthMonitor(pinger, time.Second)
+
+	monitor.Check(ctx)
+	if !monitor.Healthy() {
+		t.Fatal("want healthy while ping succeeds")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("want no log without a transition, got %s", buf.String())
+	}
+
+	pinger.down.Store(true)
+	monitor.Check(ctx)
+	monitor.Check(ctx)
+	if monitor.Healthy() {
+		t.Fatal("want unhealthy while ping fails")
+	}
+	if got := strings.Count(buf.String(), "database became unhealthy"); got != 1 {
+		t.Errorf("want one unhealthy transition logged, got %d: %s", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), `"operation":"db_health"`) || !strings.Contains(buf.String(), "connection refused") {
+		t.Errorf("unexpected log output: %s", buf.String())
+	}
+
+	pinger.down.Store(false)
+	monitor.Check(ctx)
+	if !monitor.Healthy() {
+		t.Fatal("want healthy after recovery")
+	}
+	if !strings.Contains(buf.String(), "database became healthy") {
+		t.Errorf("want recovery logged, got %s", buf.String())
+	}
+
+	// Healthyは疎通確認を行わない
+	before := pinger.calls.Load()
+	_ = monitor.Healthy()
+	if pinger.calls.Load() != before {
+		t.Error("want Healthy not to ping the database")
+	}
+}
+
+func TestDBHealthMonitor_Run(t *testing.T) {
+	captureLogs(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	pinger := &togglePinger{}
+	pinger.down.Store(true)
+	monitor := NewDBHealthMonitor(pinger, 5*time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		monitor.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for monitor.Healthy() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if monitor.Healthy() {
+		t.Error("want Run to detect the unhealthy database")
+	}
+	cancel()
+	<-done
+}
@@ -5,15 +5,31 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	kms "cloud.google.com/go/kms/apiv1"
 	kmspb "cloud.google.com/go/kms/apiv1/kmspb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"key-management-service/config"
+	"key-management-service/internal/domain"
 )
 
+// maxRetryDelay は再試行までの待ち時間の上限。
+const maxRetryDelay = 5 * time.Second
+
 // KMSClient はCloud KMSクライアントをラップする。
 type KMSClient struct {
-	client  *kms.KeyManagementClient
-	keyName string
+	client         *kms.KeyManagementClient
+	keyName        string
+	duration       metric.Float64Histogram
+	retryAttempts  int
+	retryBaseDelay time.Duration
 }
 
 // NewKMSClient は環境変数KMS_KEY_NAMEからキー名を取得してKMSClientを生成する。
@@ -27,49 +43,182 @@ func NewKMSClient(ctx context.Context) (*KMSClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("creating KMS client: %w", err)
 	}
+	return newKMSClient(client, keyName), nil
+}
+
+// newKMSClient は生成済みのCloud KMSクライアントからKMSClientを生成する。
+// 計器の生成に失敗した場合は起動を妨げないよう、レイテンシを記録しない計器にフォールバックする。
+func newKMSClient(client *kms.KeyManagementClient, keyName string) *KMSClient {
+	duration, err := otel.Meter("key-management-service").Float64Histogram("kms.request.duration",
+		metric.WithDescription("Cloud KMS request latency"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		slog.Warn("failed to create KMS latency histogram", "error", err)
+		duration = noop.Float64Histogram{}
+	}
+
+	// 再試行はKMSClientで行うため、クライアントライブラリの既定の再試行は無効化する
+	client.CallOptions.Encrypt = nil
+	client.CallOptions.Decrypt = nil
 
 	return &KMSClient{
-		client:  client,
-		keyName: keyName,
-	}, nil
+		client:         client,
+		keyName:        keyName,
+		duration:       duration,
+		retryAttempts:  config.DefaultKMSRetryAttempts,
+		retryBaseDelay: config.DefaultKMSRetryBaseDelay,
+	}
+}
+
+// isRetryableKMSError は一時的な障害として再試行できるエラーかを返す。
+// 権限不足や鍵の利用不可など、再試行しても結果が変わらないエラーは対象外とする。
+func isRetryableKMSError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay はattempt回目の試行に失敗した後の待ち時間を返す（指数バックオフ）。
+func (c *KMSClient) retryDelay(attempt int) time.Duration {
+	delay := c.retryBaseDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// withRetry は一時的なエラーの間、最大retryAttempts回までfnを試行する。
+// 待機中にctxが終了した場合は、最後のエラーを返して打ち切る。
+func (c *KMSClient) withRetry(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= c.retryAttempts || !isRetryableKMSError(err) {
+			return err
+		}
+		delay := c.retryDelay(attempt)
+		slog.WarnContext(ctx, "retrying KMS request",
+			"operation", operation,
+			"key_name", c.keyName,
+			"attempt", attempt,
+			"delay_ms", delay.Milliseconds(),
+			"grpc_code", status.Code(err).String(),
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
 }
 
-// Encrypt は平文をCloud KMSで暗号化する。
-func (c *KMSClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+// classifyKMSError はCloud KMSのgRPCステータスコードに応じてドメインエラーでラップする。
+// 権限不足・鍵の利用不可は設定の誤りとして、一時的な障害は再試行可能として区別する。
+func classifyKMSError(err error) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return fmt.Errorf("%w: %w", domain.ErrKMSPermission, err)
+	case codes.FailedPrecondition, codes.NotFound:
+		return fmt.Errorf("%w: %w", domain.ErrKMSKeyUnavailable, err)
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return fmt.Errorf("%w: %w", domain.ErrKMSUnavailable, err)
+	default:
+		return err
+	}
+}
+
+// recordDuration はKMS呼び出しのレイテンシを記録する。
+func (c *KMSClient) recordDuration(ctx context.Context, operation string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	c.duration.Record(ctx, time.Since(start).Seconds(),
+		metric.WithAttributes(
+			attribute.String("operation", operation),
+			attribute.String("result", result),
+		),
+	)
+}
+
+// Encrypt は平文をCloud KMSで暗号化する。一時的なエラーは指数バックオフで再試行する。
+// aadは追加認証データで、復号時に同じ値を指定する必要がある（nilの場合は指定しない）。
+func (c *KMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
 	req := &kmspb.EncryptRequest{
-		Name:      c.keyName,
-		Plaintext: plaintext,
+		Name:                        c.keyName,
+		Plaintext:                   plaintext,
+		AdditionalAuthenticatedData: aad,
 	}
-	resp, err := c.client.Encrypt(ctx, req)
+	var resp *kmspb.EncryptResponse
+	err := c.withRetry(ctx, "kms_encrypt", func(ctx context.Context) error {
+		start := time.Now()
+		var err error
+		resp, err = c.client.Encrypt(ctx, req)
+		c.recordDuration(ctx, "encrypt", start, err)
+		return err
+	})
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to encrypt with KMS",
 			"operation", "kms_encrypt",
 			"key_name", c.keyName,
+			"grpc_code", status.Code(err).String(),
 			"error", err,
 		)
-		return nil, fmt.Errorf("encrypting: %w", err)
+		return nil, fmt.Errorf("encrypting: %w", classifyKMSError(err))
 	}
 	return resp.Ciphertext, nil
 }
 
-// Decrypt は暗号文をCloud KMSで復号する。
-func (c *KMSClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+// Decrypt は暗号文をCloud KMSで復号する。一時的なエラーは指数バックオフで再試行する。
+// aadには暗号化時と同じ追加認証データを指定する。異なる場合は復号に失敗する。
+func (c *KMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
 	req := &kmspb.DecryptRequest{
-		Name:       c.keyName,
-		Ciphertext: ciphertext,
+		Name:                        c.keyName,
+		Ciphertext:                  ciphertext,
+		AdditionalAuthenticatedData: aad,
 	}
-	resp, err := c.client.Decrypt(ctx, req)
+	var resp *kmspb.DecryptResponse
+	err := c.withRetry(ctx, "kms_decrypt", func(ctx context.Context) error {
+		start := time.Now()
+		var err error
+		resp, err = c.client.Decrypt(ctx, req)
+		c.recordDuration(ctx, "decrypt", start, err)
+		return err
+	})
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to decrypt with KMS",
 			"operation", "kms_decrypt",
 			"key_name", c.keyName,
+			"grpc_code", status.Code(err).String(),
 			"error", err,
 		)
-		return nil, fmt.Errorf("decrypting: %w", err)
+		return nil, fmt.Errorf("decrypting: %w", classifyKMSError(err))
 	}
 	return resp.Plaintext, nil
 }
 
+// WithKeyName は接続を共有したまま、別のKMS鍵名で暗号化・復号するKMSClientを返す。
+// 再ラップで旧KMS鍵による復号に使用する。Closeは元のKMSClientでのみ行う。
+func (c *KMSClient) WithKeyName(keyName string) *KMSClient {
+	clone := *c
+	clone.keyName = keyName
+	return &clone
+}
+
+// WithRetry は接続を共有したまま、再試行の設定を変更したKMSClientを返す。
+// attemptsは初回を含む最大試行回数（1で再試行しない）、baseDelayは最初の再試行までの待ち時間。
+func (c *KMSClient) WithRetry(attempts int, baseDelay time.Duration) *KMSClient {
+	clone := *c
+	clone.retryAttempts = max(attempts, 1)
+	clone.retryBaseDelay = baseDelay
+	return &clone
+}
+
 // Close はKMSクライアントを閉じる。
 func (c *KMSClient) Close() error {
 	return c.client.Close()
@@ -0,0 +1,53 @@
+package infra
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// kmsOperations は暗号化・復号を行うKMSクライアントのインターフェース。
+type kmsOperations interface {
+	Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error)
+}
+
+// SlowLoggingKMSClient はしきい値を超えたKMS呼び出しを警告ログに出力するラッパー。
+// リージョンのレイテンシ悪化を検知するために使用する。しきい値以内の呼び出しでは何も出力しない。
+type SlowLoggingKMSClient struct {
+	next      kmsOperations
+	threshold time.Duration
+}
+
+// NewSlowLoggingKMSClient は新しいSlowLoggingKMSClientを生成する。
+// thresholdが0の場合は警告を出力しない。
+func NewSlowLoggingKMSClient(next kmsOperations, threshold time.Duration) *SlowLoggingKMSClient {
+	return &SlowLoggingKMSClient{
+		next:      next,
+		threshold: threshold,
+	}
+}
+
+// Encrypt は平文を暗号化し、しきい値を超えた場合に警告を出力する。
+func (c *SlowLoggingKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	defer c.warnIfSlow(ctx, "kms_encrypt", time.Now())
+	return c.next.Encrypt(ctx, plaintext, aad)
+}
+
+// Decrypt は暗号文を復号し、しきい値を超えた場合に警告を出力する。
+func (c *SlowLoggingKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	defer c.warnIfSlow(ctx, "kms_decrypt", time.Now())
+	return c.next.Decrypt(ctx, ciphertext, aad)
+}
+
+func (c *SlowLoggingKMSClient) warnIfSlow(ctx context.Context, operation string, start time.Time) {
+	elapsed := time.Since(start)
+	if c.threshold <= 0 || elapsed <= c.threshold {
+		return
+	}
+	slog.WarnContext(ctx, "slow KMS operation",
+		"operation", operation,
+		"duration_ms", elapsed.Milliseconds(),
+		"threshold_ms", c.threshold.Milliseconds(),
+	)
+}
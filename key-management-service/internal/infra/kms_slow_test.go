@@ -0,0 +1,70 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sleepingKMS は指定時間待機してから入力をそのまま返すテスト用KMSクライアント。
+type sleepingKMS struct {
+	delay time.Duration
+}
+
+func (k *sleepingKMS) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	time.Sleep(k.delay)
+	return plaintext, nil
+}
+
+func (k *sleepingKMS) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	time.Sleep(k.delay)
+	return ciphertext, nil
+}
+
+// captureLogs はテスト中のデフォルトロガーの出力をバッファに切り替える。
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestSlowLoggingKMSClient_WarnsPastThreshold(t *testing.T) {
+	buf := captureLogs(t)
+	client := NewSlowLoggingKMSClient(&sleepingKMS{delay: 30 * time.Millisecond}, 10*time.Millisecond)
+
+	if _, err := client.Encrypt(context.Background(), []byte("data"), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Decrypt(context.Background(), []byte("data"), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{`"level":"WARN"`, `"operation":"kms_encrypt"`, `"operation":"kms_decrypt"`, `"duration_ms"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want log to contain %s, got %s", want, out)
+		}
+	}
+}
+
+func TestSlowLoggingKMSClient_SilentWithinThreshold(t *testing.T) {
+	buf := captureLogs(t)
+	client := NewSlowLoggingKMSClient(&sleepingKMS{}, time.Second)
+
+	if _, err := client.Encrypt(context.Background(), []byte("data"), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Decrypt(context.Background(), []byte("data"), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("want no log output, got %s", buf.String())
+	}
+}
@@ -0,0 +1,248 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	kmspb "cloud.google.com/go/kms/apiv1/kmspb"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"key-management-service/config"
+	"key-management-service/internal/domain"
+)
+
+// fakeKMSServer は指定したgRPCステータスを返すテスト用KMSサーバー。
+// failuresが0より大きい場合は、最初のfailures回の呼び出しだけerrを返す。
+type fakeKMSServer struct {
+	kmspb.UnimplementedKeyManagementServiceServer
+	err      error
+	failures int32
+	calls    atomic.Int32
+	aads     sync.Map // 暗号文ごとの暗号化時の追加認証データ
+}
+
+func (s *fakeKMSServer) fail() error {
+	n := s.calls.Add(1)
+	if s.err != nil && (s.failures == 0 || n <= s.failures) {
+		return s.err
+	}
+	return nil
+}
+
+func (s *fakeKMSServer) Encrypt(ctx context.Context, req *kmspb.EncryptRequest) (*kmspb.EncryptResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	s.aads.Store(string(req.Plaintext), req.AdditionalAuthenticatedData)
+	return &kmspb.EncryptResponse{Ciphertext: req.Plaintext}, nil
+}
+
+func (s *fakeKMSServer) Decrypt(ctx context.Context, req *kmspb.DecryptRequest) (*kmspb.DecryptResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	// Cloud KMSと同様に、暗号化時と異なる追加認証データでは復号に失敗する
+	if aad, ok := s.aads.Load(string(req.Ciphertext)); ok && !bytes.Equal(aad.([]byte), req.AdditionalAuthenticatedData) {
+		return nil, status.Error(codes.InvalidArgument, "decryption failed")
+	}
+	return &kmspb.DecryptResponse{Plaintext: req.Ciphertext}, nil
+}
+
+// newFakeKMSClient はfakeKMSServerに接続したKMSClientを生成する。
+func newFakeKMSClient(t *testing.T, srv *fakeKMSServer) *KMSClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	kmspb.RegisterKeyManagementServiceServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial fake KMS: %v", err)
+	}
+	client, err := kms.NewKeyManagementClient(context.Background(), option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("failed to create KMS client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	c := newKMSClient(client, "projects/p/locations/l/keyRings/r/cryptoKeys/k")
+	// テストを待たせないよう再試行の間隔だけ短くする
+	return c.WithRetry(config.DefaultKMSRetryAttempts, time.Millisecond)
+}
+
+func TestKMSClient_ClassifiesGRPCStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		code codes.Code
+		want error
+	}{
+		{name: "permission denied", code: codes.PermissionDenied, want: domain.ErrKMSPermission},
+		{name: "key disabled", code: codes.FailedPrecondition, want: domain.ErrKMSKeyUnavailable},
+		{name: "key not found", code: codes.NotFound, want: domain.ErrKMSKeyUnavailable},
+		{name: "transient", code: codes.Unavailable, want: domain.ErrKMSUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureLogs(t)
+			client := newFakeKMSClient(t, &fakeKMSServer{err: status.Error(tt.code, tt.name)})
+
+			_, err := client.Encrypt(context.Background(), []byte("data"), nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Encrypt: want %v, got %v", tt.want, err)
+			}
+			_, err = client.Decrypt(context.Background(), []byte("data"), nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Decrypt: want %v, got %v", tt.want, err)
+			}
+			if status.Code(err) != tt.code {
+				t.Errorf("want gRPC status %v preserved, got %v", tt.code, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestKMSClient_UnclassifiedError(t *testing.T) {
+	captureLogs(t)
+	client := newFakeKMSClient(t, &fakeKMSServer{err: status.Error(codes.InvalidArgument, "bad ciphertext")})
+
+	_, err := client.Decrypt(context.Background(), []byte("data"), nil)
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	for _, target := range []error{domain.ErrKMSPermission, domain.ErrKMSKeyUnavailable, domain.ErrKMSUnavailable} {
+		if errors.Is(err, target) {
+			t.Errorf("want unclassified error, got %v", err)
+		}
+	}
+}
+
+func TestKMSClient_Success(t *testing.T) {
+	client := newFakeKMSClient(t, &fakeKMSServer{})
+
+	got, err := client.Encrypt(context.Background(), []byte("data"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != "data" {
+		t.Errorf("unexpected ciphertext: %q", got)
+	}
+}
+
+func TestKMSClient_RetriesTransientErrors(t *testing.T) {
+	for _, code := range []codes.Code{codes.Unavailable, codes.ResourceExhausted} {
+		t.Run(code.String(), func(t *testing.T) {
+			captureLogs(t)
+			srv := &fakeKMSServer{err: status.Error(code, "transient"), failures: 2}
+			client := newFakeKMSClient(t, srv)
+
+			got, err := client.Encrypt(context.Background(), []byte("data"), nil)
+			if err != nil {
+				t.Fatalf("want success after retries, got %v", err)
+			}
+			if string(got) != "data" {
+				t.Errorf("unexpected ciphertext: %q", got)
+			}
+			if n := srv.calls.Load(); n != 3 {
+				t.Errorf("want 3 calls, got %d", n)
+			}
+		})
+	}
+}
+
+func TestKMSClient_GivesUpAfterMaxAttempts(t *testing.T) {
+	captureLogs(t)
+	srv := &fakeKMSServer{err: status.Error(codes.Unavailable, "down")}
+	client := newFakeKMSClient(t, srv)
+
+	_, err := client.Decrypt(context.Background(), []byte("data"), nil)
+	if !errors.Is(err, domain.ErrKMSUnavailable) {
+		t.Errorf("want ErrKMSUnavailable, got %v", err)
+	}
+	if n := srv.calls.Load(); n != config.DefaultKMSRetryAttempts {
+		t.Errorf("want %d calls, got %d", config.DefaultKMSRetryAttempts, n)
+	}
+}
+
+func TestKMSClient_PermanentErrorFailsFast(t *testing.T) {
+	captureLogs(t)
+	srv := &fakeKMSServer{err: status.Error(codes.PermissionDenied, "denied")}
+	client := newFakeKMSClient(t, srv)
+
+	_, err := client.Encrypt(context.Background(), []byte("data"), nil)
+	if !errors.Is(err, domain.ErrKMSPermission) {
+		t.Errorf("want ErrKMSPermission, got %v", err)
+	}
+	if n := srv.calls.Load(); n != 1 {
+		t.Errorf("want 1 call, got %d", n)
+	}
+}
+
+func TestKMSClient_RetryStopsOnContextCancel(t *testing.T) {
+	captureLogs(t)
+	srv := &fakeKMSServer{err: status.Error(codes.Unavailable, "down")}
+	client := newFakeKMSClient(t, srv).WithRetry(5, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.Encrypt(ctx, []byte("data"), nil)
+	if !errors.Is(err, domain.ErrKMSUnavailable) {
+		t.Errorf("want ErrKMSUnavailable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("want retry to stop on context cancel, took %v", elapsed)
+	}
+	if n := srv.calls.Load(); n != 1 {
+		t.Errorf("want 1 call, got %d", n)
+	}
+}
+
+func TestKMSClient_RetryDelay(t *testing.T) {
+	c := (&KMSClient{}).WithRetry(10, 100*time.Millisecond)
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		9: maxRetryDelay,
+	} {
+		if got := c.retryDelay(attempt); got != want {
+			t.Errorf("attempt %d: want %v, got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestKMSClient_AdditionalAuthenticatedData(t *testing.T) {
+	captureLogs(t)
+	client := newFakeKMSClient(t, &fakeKMSServer{})
+	ctx := context.Background()
+
+	ciphertext, err := client.Encrypt(ctx, []byte("data"), []byte("tenant-a"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := client.Decrypt(ctx, ciphertext, []byte("tenant-a")); err != nil {
+		t.Errorf("want decryption with the same AAD to succeed, got %v", err)
+	}
+	for _, aad := range [][]byte{[]byte("tenant-b"), nil} {
+		if _, err := client.Decrypt(ctx, ciphertext, aad); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("AAD %q: want InvalidArgument, got %v", aad, err)
+		}
+	}
+}
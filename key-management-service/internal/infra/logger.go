@@ -2,6 +2,7 @@ package infra
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"os"
 
@@ -81,8 +82,33 @@ func (h *TraceHandler) WithGroup(name string) slog.Handler {
 }
 
 // SetupLogger はトレース情報付きのグローバルロガーを設定する。
+// 出力形式はLOG_FORMAT、出力先はLOG_OUTPUTに従う。
 func SetupLogger(cfg *config.Config, level slog.Level) {
-	jsonHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
-	traceHandler := NewTraceHandler(jsonHandler, cfg)
-	slog.SetDefault(slog.New(traceHandler))
+	slog.SetDefault(slog.New(NewLogHandler(cfg, level)))
+}
+
+// NewLogHandler は設定に応じた出力形式・出力先のslogハンドラをTraceHandlerで包んで返す。
+func NewLogHandler(cfg *config.Config, level slog.Level) *TraceHandler {
+	return newLogHandler(logWriter(cfg.LogOutput), cfg, level)
+}
+
+// newLogHandler はwに出力するslogハンドラを生成する。
+// DEBUGレベルではログ呼び出し箇所（source: ファイル名・行番号）も出力する。
+func newLogHandler(w io.Writer, cfg *config.Config, level slog.Level) *TraceHandler {
+	opts := &slog.HandlerOptions{Level: level, AddSource: level <= slog.LevelDebug}
+	var handler slog.Handler
+	if cfg.LogFormat == config.LogFormatText {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return NewTraceHandler(handler, cfg)
+}
+
+// logWriter はLOG_OUTPUTの値に対応する出力先を返す。
+func logWriter(output string) io.Writer {
+	if output == config.LogOutputStderr {
+		return os.Stderr
+	}
+	return os.Stdout
 }
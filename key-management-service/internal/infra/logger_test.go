@@ -0,0 +1,105 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/config"
+)
+
+// spanContext はテスト用の有効なスパンを持つコンテキストを返す。
+func spanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestNewLogHandler(t *testing.T) {
+	tests := []struct {
+		format     string
+		output     string
+		wantText   bool
+		wantWriter *os.File
+	}{
+		{format: config.LogFormatJSON, output: config.LogOutputStdout, wantWriter: os.Stdout},
+		{format: config.LogFormatJSON, output: config.LogOutputStderr, wantWriter: os.Stderr},
+		{format: config.LogFormatText, output: config.LogOutputStdout, wantText: true, wantWriter: os.Stdout},
+		{format: config.LogFormatText, output: config.LogOutputStderr, wantText: true, wantWriter: os.Stderr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.output, func(t *testing.T) {
+			cfg := &config.Config{LogFormat: tt.format, LogOutput: tt.output, OtelEnabled: true, GoogleCloudProject: "my-project"}
+
+			h := NewLogHandler(cfg, slog.LevelInfo)
+			switch h.handler.(type) {
+			case *slog.TextHandler:
+				if !tt.wantText {
+					t.Error("want JSON handler, got text handler")
+				}
+			case *slog.JSONHandler:
+				if tt.wantText {
+					t.Error("want text handler, got JSON handler")
+				}
+			default:
+				t.Errorf("unexpected handler type %T", h.handler)
+			}
+			if got := logWriter(tt.output); got != tt.wantWriter {
+				t.Errorf("want writer %s, got %v", tt.wantWriter.Name(), got)
+			}
+
+			// トレース情報はどちらの形式でも付与される
+			var buf bytes.Buffer
+			slog.New(newLogHandler(&buf, cfg, slog.LevelInfo)).InfoContext(spanContext(t), "hello")
+			for _, want := range []string{"0123456789abcdef0123456789abcdef", "projects/my-project/traces/"} {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("want log containing %q, got %s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNewLogHandler_DebugAddsSource(t *testing.T) {
+	cfg := &config.Config{LogFormat: config.LogFormatJSON, OtelEnabled: true, GoogleCloudProject: "my-project"}
+
+	// TraceHandlerはレコードをそのまま渡すため、呼び出し箇所とトレース情報が両方出力される
+	var buf bytes.Buffer
+	slog.New(newLogHandler(&buf, cfg, slog.LevelDebug)).DebugContext(spanContext(t), "hello")
+	var entry struct {
+		Source *struct {
+			File string `json:"file"`
+			Line int    `json:"line"`
+		} `json:"source"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log: %v: %s", err, buf.String())
+	}
+	if entry.Source == nil || !strings.HasSuffix(entry.Source.File, "logger_test.go") || entry.Source.Line == 0 {
+		t.Errorf("want source pointing at logger_test.go, got %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "0123456789abcdef0123456789abcdef") {
+		t.Errorf("want trace fields alongside source, got %s", buf.String())
+	}
+
+	// INFOレベルでは呼び出し箇所を出力しない
+	buf.Reset()
+	slog.New(newLogHandler(&buf, cfg, slog.LevelInfo)).InfoContext(spanContext(t), "hello")
+	if strings.Contains(buf.String(), `"source"`) {
+		t.Errorf("want no source at info level, got %s", buf.String())
+	}
+}
@@ -0,0 +1,61 @@
+package infra
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"key-management-service/config"
+)
+
+// metricExportInterval はメトリクスのエクスポート間隔。
+const metricExportInterval = 60 * time.Second
+
+// InitMeterProvider はメータープロバイダーを初期化する。
+// OTEL_ENABLED=false の場合は nil を返す（メトリクス無効）。
+// エクスポート先とリソース属性はトレーサーと共通の設定を使用する。
+func InitMeterProvider(ctx context.Context, cfg *config.Config) (*sdkmetric.MeterProvider, error) {
+	if !cfg.OtelEnabled {
+		return nil, nil
+	}
+
+	sec, err := otlpTransportSecurity(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dialOpts, err := otlpDialOptions(ctx, sec)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithDialOption(dialOpts...)}
+	if sec.insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(sec.tls))
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter,
+			sdkmetric.WithInterval(metricExportInterval),
+		)),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetMeterProvider(mp)
+
+	return mp, nil
+}
@@ -0,0 +1,80 @@
+package infra
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"key-management-service/config"
+)
+
+// setupFakeCredentials はテスト用のサービスアカウント鍵を作成し、ADCとして参照させる。
+// トークンは実際のエクスポート時まで取得されないため、ネットワーク接続は不要。
+func setupFakeCredentials(t *testing.T) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	creds, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "test-project",
+		"private_key_id": "test-key-id",
+		"private_key":    string(keyPEM),
+		"client_email":   "test@test-project.iam.gserviceaccount.com",
+		"client_id":      "123456789",
+		"token_uri":      "https://oauth2.googleapis.com/token",
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal credentials: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, creds, 0o600); err != nil {
+		t.Fatalf("failed to write credentials: %v", err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", path)
+}
+
+func TestInitMeterProvider_Disabled(t *testing.T) {
+	mp, err := InitMeterProvider(context.Background(), &config.Config{OtelEnabled: false})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mp != nil {
+		t.Error("want nil meter provider when OTEL is disabled")
+	}
+}
+
+func TestInitMeterProvider_Enabled(t *testing.T) {
+	setupFakeCredentials(t)
+
+	ctx := context.Background()
+	mp, err := InitMeterProvider(ctx, &config.Config{
+		OtelEnabled:     true,
+		OtelEndpoint:    "localhost:4317",
+		OtelServiceName: "test-service",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mp == nil {
+		t.Fatal("want non-nil meter provider when OTEL is enabled")
+	}
+	// エクスポート先が存在しないため終了時のエクスポートは待たずに打ち切る
+	shutdownCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_ = mp.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,48 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
+
+	"key-management-service/config"
+)
+
+// otlpSecurity はOTLPエクスポーターの接続方式を表す。
+type otlpSecurity struct {
+	insecure bool
+	tls      credentials.TransportCredentials
+}
+
+// otlpTransportSecurity は設定からOTLPエクスポーターの接続方式を決定する。
+// OTEL_EXPORTER_OTLP_INSECURE=true の場合は平文で接続し、
+// それ以外はOTEL_EXPORTER_OTLP_CA（未指定時はシステムのルート証明書）でTLS接続する。
+func otlpTransportSecurity(cfg *config.Config) (otlpSecurity, error) {
+	if cfg.OtelInsecure {
+		return otlpSecurity{insecure: true}, nil
+	}
+	if cfg.OtelCAFile == "" {
+		return otlpSecurity{tls: credentials.NewClientTLSFromCert(nil, "")}, nil
+	}
+	creds, err := credentials.NewClientTLSFromFile(cfg.OtelCAFile, "")
+	if err != nil {
+		return otlpSecurity{}, fmt.Errorf("loading OTLP CA certificate: %w", err)
+	}
+	return otlpSecurity{tls: creds}, nil
+}
+
+// otlpDialOptions はTLS接続時に付与するgRPCダイアルオプションを返す。
+// Google Cloudの認証情報はトランスポートの暗号化が前提のため、平文接続時は付与しない。
+func otlpDialOptions(ctx context.Context, sec otlpSecurity) ([]grpc.DialOption, error) {
+	if sec.insecure {
+		return nil, nil
+	}
+	creds, err := oauth.NewApplicationDefault(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading application default credentials: %w", err)
+	}
+	return []grpc.DialOption{grpc.WithPerRPCCredentials(creds)}, nil
+}
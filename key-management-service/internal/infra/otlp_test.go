@@ -0,0 +1,87 @@
+package infra
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"key-management-service/config"
+)
+
+// writeTestCA はテスト用の自己署名CA証明書をファイルに書き出す。
+func writeTestCA(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	return path
+}
+
+func TestOtlpTransportSecurity(t *testing.T) {
+	t.Run("insecure", func(t *testing.T) {
+		sec, err := otlpTransportSecurity(&config.Config{OtelInsecure: true, OtelCAFile: "ignored.pem"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !sec.insecure || sec.tls != nil {
+			t.Errorf("want insecure without TLS credentials, got %+v", sec)
+		}
+	})
+
+	t.Run("system roots", func(t *testing.T) {
+		sec, err := otlpTransportSecurity(&config.Config{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sec.insecure || sec.tls == nil {
+			t.Fatalf("want TLS credentials, got %+v", sec)
+		}
+		if proto := sec.tls.Info().SecurityProtocol; proto != "tls" {
+			t.Errorf("want security protocol tls, got %s", proto)
+		}
+	})
+
+	t.Run("custom CA", func(t *testing.T) {
+		sec, err := otlpTransportSecurity(&config.Config{OtelCAFile: writeTestCA(t)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sec.insecure || sec.tls == nil {
+			t.Errorf("want TLS credentials, got %+v", sec)
+		}
+	})
+
+	t.Run("missing CA file", func(t *testing.T) {
+		if _, err := otlpTransportSecurity(&config.Config{OtelCAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+			t.Error("want error for missing CA file, got nil")
+		}
+	})
+}
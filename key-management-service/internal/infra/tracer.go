@@ -13,9 +13,6 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 
 	"key-management-service/config"
-
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/oauth"
 )
 
 // InitTracer はトレーサープロバイダーを初期化する。
@@ -25,24 +22,28 @@ func InitTracer(ctx context.Context, cfg *config.Config) (*sdktrace.TracerProvid
 		return nil, nil
 	}
 
-	creds, err := oauth.NewApplicationDefault(ctx)
+	sec, err := otlpTransportSecurity(cfg)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	dialOpts, err := otlpDialOptions(ctx, sec)
+	if err != nil {
+		return nil, err
 	}
 
-	exporter, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithDialOption(grpc.WithPerRPCCredentials(creds)),
-	)
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithDialOption(dialOpts...)}
+	if sec.insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(sec.tls))
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(cfg.OtelServiceName),
-			attribute.String("gcp.project_id", cfg.GoogleCloudProject),
-		),
-	)
+	res, err := newResource(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -66,3 +67,13 @@ func InitTracer(ctx context.Context, cfg *config.Config) (*sdktrace.TracerProvid
 
 	return tp, nil
 }
+
+// newResource はトレース・メトリクス共通のリソース属性を生成する。
+func newResource(ctx context.Context, cfg *config.Config) (*resource.Resource, error) {
+	return resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.OtelServiceName),
+			attribute.String("gcp.project_id", cfg.GoogleCloudProject),
+		),
+	)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// AccessLog はリクエストごとにメソッド・パス・ステータス・処理時間・レスポンスサイズを1件の構造化ログとして出力するミドルウェア。
+// ログはリクエストのコンテキスト付きで出力するため、TraceHandlerによりトレース情報も付与される。
+// リクエストIDとトレースを参照し、回復したpanicも500として記録できるよう、RequestID・Tracingの内側かつRecoverの外側に配置する。
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			// 何も書き込まれなかった場合はnet/httpが200を返す
+			status = http.StatusOK
+		}
+		attrs := []slog.Attr{
+			slog.String("operation", "http_request"),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.String("request_id", chimiddleware.GetReqID(r.Context())),
+		}
+		// ルーティングで解決したパスパラメーターはルーティング後に参照できる
+		if tenantID := chi.URLParam(r, "tenant_id"); tenantID != "" {
+			attrs = append(attrs, slog.String("tenant_id", tenantID))
+		}
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(r.Context(), level, "request completed", attrs...)
+	})
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/config"
+	"key-management-service/internal/infra"
+)
+
+// captureAccessLog はTraceHandler経由のJSONログを記録するようslogの既定のロガーを差し替える。
+func captureAccessLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(infra.NewTraceHandler(slog.NewJSONHandler(&buf, nil), &config.Config{OtelEnabled: true})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestAccessLog(t *testing.T) {
+	buf := captureAccessLog(t)
+
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID, AccessLog)
+	r.Get("/v1/tenants/{tenant_id}/keys/current", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	})
+
+	traceID, _ := trace.TraceIDFromHex("0123456789abcdef0123456789abcdef")
+	spanID, _ := trace.SpanIDFromHex("0123456789abcdef")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil).WithContext(ctx)
+	req.Header.Set(chimiddleware.RequestIDHeader, "req-123")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("want a single JSON log entry, got %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"level":      "INFO",
+		"operation":  "http_request",
+		"method":     http.MethodGet,
+		"path":       "/v1/tenants/tenant-001/keys/current",
+		"status":     float64(http.StatusCreated),
+		"bytes":      float64(len(`{"ok":true}`)),
+		"request_id": "req-123",
+		"tenant_id":  "tenant-001",
+		"trace":      traceID.String(),
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s: want %v, got %v", k, v, entry[k])
+		}
+	}
+	if _, ok := entry["duration_ms"].(float64); !ok {
+		t.Errorf("want duration_ms, got %v", entry["duration_ms"])
+	}
+}
+
+func TestAccessLog_ErrorsAndDefaults(t *testing.T) {
+	buf := captureAccessLog(t)
+
+	r := chi.NewRouter()
+	r.Use(AccessLog, Recover)
+	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+
+	// 何も書き込まないハンドラーは200、テナントIDのないルートはtenant_idを出力しない
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+	}
+	if entry["status"] != float64(http.StatusOK) {
+		t.Errorf("want status 200, got %v", entry["status"])
+	}
+	if _, ok := entry["tenant_id"]; ok {
+		t.Errorf("want no tenant_id, got %v", entry["tenant_id"])
+	}
+
+	// Recoverで回復したpanicは500としてエラーレベルで記録する
+	buf.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	var last map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		if err := dec.Decode(&last); err != nil {
+			t.Fatalf("failed to decode log entry: %v", err)
+		}
+	}
+	if last["operation"] != "http_request" || last["status"] != float64(http.StatusInternalServerError) || last["level"] != "ERROR" {
+		t.Errorf("want an ERROR access log with status 500, got %v", last)
+	}
+}
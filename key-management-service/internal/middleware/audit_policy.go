@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand/v2"
+)
+
+// auditReadOperations は状態を変更しない（サンプリングの対象になる）監査ログの操作。
+// ここにない操作は書き込みとして扱い、常に記録する。
+var auditReadOperations = map[string]bool{
+	"GET_CURRENT_KEY":        true,
+	"GET_CURRENT_KEY_STATUS": true,
+	"GET_KEY_BY_GENERATION":  true,
+	"BATCH_GET_KEYS":         true,
+	"LIST_KEYS":              true,
+	"LIST_RECENT_KEYS":       true,
+	"COUNT_KEYS":             true,
+	"KEY_EXISTS":             true,
+	"LIST_TENANTS":           true,
+	"LIST_AUDIT_EVENTS":      true,
+	"GET_TENANT_SETTINGS":    true,
+	"ENCRYPT_BATCH":          true,
+	"DECRYPT":                true,
+}
+
+// PolicyAuditLogger は記録の方針（読み取りのサンプリング・テナントIDのハッシュ化）を適用してnextに書き込む。
+type PolicyAuditLogger struct {
+	next        AuditLogger
+	readRate    float64
+	tenantIDKey []byte
+	random      func() float64
+}
+
+// AuditPolicyOption はPolicyAuditLoggerの設定を変更する。
+type AuditPolicyOption func(*PolicyAuditLogger)
+
+// WithReadSampling は成功した読み取り操作をrate（0.0〜1.0）の割合で記録する。
+// 書き込み操作と失敗した操作はrateによらず常に記録する。
+func WithReadSampling(rate float64) AuditPolicyOption {
+	return func(l *PolicyAuditLogger) {
+		l.readRate = rate
+	}
+}
+
+// WithTenantIDHashing はテナントIDをkeyによるHMAC-SHA-256（"hmac-sha256:"に続く16進数）に置き換えて記録する。
+// 同じテナントの記録は同じ値になるため、テナントIDを残さずに突き合わせられる。
+// 鍵を知らなければテナントIDの候補から値を再計算できないため、推測しやすいテナントIDでも元の値を特定できない。
+func WithTenantIDHashing(key []byte) AuditPolicyOption {
+	return func(l *PolicyAuditLogger) {
+		l.tenantIDKey = key
+	}
+}
+
+// withRandom はサンプリングに使う乱数を差し替える（テスト用）。
+func withRandom(random func() float64) AuditPolicyOption {
+	return func(l *PolicyAuditLogger) {
+		l.random = random
+	}
+}
+
+// NewPolicyAuditLogger はoptsの方針を適用してnextに書き込むPolicyAuditLoggerを生成する。
+// オプションを指定しない場合はすべてをそのまま記録する。
+func NewPolicyAuditLogger(next AuditLogger, opts ...AuditPolicyOption) *PolicyAuditLogger {
+	l := &PolicyAuditLogger{
+		next:     next,
+		readRate: 1.0,
+		random:   rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Write は方針に従って監査ログを書き込む。サンプリングで除外した場合は何もしない。
+func (l *PolicyAuditLogger) Write(ctx context.Context, operation string, tenantID string, generation uint, result string) {
+	if !l.sampled(operation, result) {
+		return
+	}
+	if l.tenantIDKey != nil && tenantID != "" {
+		tenantID = hashTenantID(l.tenantIDKey, tenantID)
+	}
+	l.next.Write(ctx, operation, tenantID, generation, result)
+}
+
+// sampled は監査ログを記録するかを返す。
+func (l *PolicyAuditLogger) sampled(operation, result string) bool {
+	if l.readRate >= 1 || result != "SUCCESS" || !auditReadOperations[operation] {
+		return true
+	}
+	return l.random() < l.readRate
+}
+
+// hashTenantID はkeyによるテナントIDのHMAC-SHA-256を返す。
+func hashTenantID(key []byte, tenantID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(tenantID))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+}
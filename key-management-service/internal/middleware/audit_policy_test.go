@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// recordingAuditLogger は書き込まれた監査ログを記録する。
+type recordingAuditLogger struct {
+	entries []AuditLog
+}
+
+func (l *recordingAuditLogger) Write(ctx context.Context, operation string, tenantID string, generation uint, result string) {
+	l.entries = append(l.entries, AuditLog{Operation: operation, TenantID: tenantID, Generation: generation, Result: result})
+}
+
+func TestPolicyAuditLogger_Sampling(t *testing.T) {
+	const n = 10000
+	ctx := context.Background()
+
+	t.Run("書き込みと失敗は常に記録する", func(t *testing.T) {
+		next := &recordingAuditLogger{}
+		logger := NewPolicyAuditLogger(next, WithReadSampling(0))
+		for range n {
+			logger.Write(ctx, "ROTATE_KEY", "tenant-001", 2, "SUCCESS")
+			logger.Write(ctx, "GET_CURRENT_KEY", "tenant-001", 2, "FAILED")
+		}
+		if len(next.entries) != 2*n {
+			t.Errorf("want %d entries, got %d", 2*n, len(next.entries))
+		}
+	})
+
+	for _, rate := range []float64{0, 0.1, 0.5, 1} {
+		next := &recordingAuditLogger{}
+		rng := rand.New(rand.NewPCG(1, 2))
+		logger := NewPolicyAuditLogger(next, WithReadSampling(rate), withRandom(rng.Float64))
+		writes := 0
+		for i := range n {
+			logger.Write(ctx, "GET_CURRENT_KEY", "tenant-001", 1, "SUCCESS")
+			if i%10 == 0 {
+				logger.Write(ctx, "DISABLE_KEY", "tenant-001", 1, "SUCCESS")
+				writes++
+			}
+		}
+
+		reads := 0
+		for _, e := range next.entries {
+			if e.Operation == "GET_CURRENT_KEY" {
+				reads++
+			}
+		}
+		if got := len(next.entries) - reads; got != writes {
+			t.Errorf("rate %v: want all %d writes, got %d", rate, writes, got)
+		}
+		// 期待値から標準偏差の5倍以内（n=10000では最大250件）
+		want := rate * n
+		if diff := float64(reads) - want; diff > 250 || diff < -250 {
+			t.Errorf("rate %v: want about %.0f reads, got %d", rate, want, reads)
+		}
+	}
+}
+
+func TestPolicyAuditLogger_TenantIDHashing(t *testing.T) {
+	next := &recordingAuditLogger{}
+	key := []byte("0123456789abcdef0123456789abcdef")
+	logger := NewPolicyAuditLogger(next, WithTenantIDHashing(key))
+	ctx := context.Background()
+
+	logger.Write(ctx, "CREATE_KEY", "tenant-001", 1, "SUCCESS")
+	logger.Write(ctx, "ROTATE_KEY", "tenant-001", 2, "SUCCESS")
+	logger.Write(ctx, "ROTATE_KEY", "tenant-002", 2, "SUCCESS")
+
+	first, second, other := next.entries[0].TenantID, next.entries[1].TenantID, next.entries[2].TenantID
+	if strings.Contains(first, "tenant-001") || !strings.HasPrefix(first, "hmac-sha256:") || len(first) != len("hmac-sha256:")+64 {
+		t.Errorf("want an HMAC-SHA-256 instead of the tenant ID, got %q", first)
+	}
+	if first != second {
+		t.Errorf("want the same hash for the same tenant, got %q and %q", first, second)
+	}
+	if first == other {
+		t.Errorf("want different hashes for different tenants, got %q", other)
+	}
+
+	// 鍵が異なれば同じテナントでも別の値になる（鍵なしでは再計算できない）
+	rekeyed := &recordingAuditLogger{}
+	NewPolicyAuditLogger(rekeyed, WithTenantIDHashing([]byte("another-key-another-key-another-k"))).Write(ctx, "CREATE_KEY", "tenant-001", 1, "SUCCESS")
+	if rekeyed.entries[0].TenantID == first {
+		t.Errorf("want a different value for a different key, got %q", first)
+	}
+
+	// 既定ではそのまま記録する
+	plain := &recordingAuditLogger{}
+	NewPolicyAuditLogger(plain).Write(ctx, "CREATE_KEY", "tenant-001", 1, "SUCCESS")
+	if plain.entries[0].TenantID != "tenant-001" {
+		t.Errorf("want raw tenant ID by default, got %q", plain.entries[0].TenantID)
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import "net/http"
+
+// MaxBytes はリクエストボディのサイズを制限するミドルウェアを返す。
+// 上限を超えた読み込みはhttp.MaxBytesErrorとなる。
+func MaxBytes(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
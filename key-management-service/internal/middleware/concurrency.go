@@ -0,0 +1,24 @@
+package middleware
+
+import "net/http"
+
+// ConcurrencyLimit は同時に処理するリクエスト数をnまでに制限するミドルウェアを返す。
+// 上限に達している場合は待たせずに503（SERVER_BUSY）とRetry-Afterを返し、
+// 処理しきれないリクエストがKMS・DBの待ちに積み上がることを防ぐ。テナント単位ではなくサーバー全体の上限。
+func ConcurrencyLimit(n int) func(http.Handler) http.Handler {
+	slots := make(chan struct{}, n)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case slots <- struct{}{}:
+			default:
+				// 過負荷時に大量に出力されないよう、拒否はアクセスログの503でのみ記録する
+				w.Header().Set("Retry-After", "1")
+				WriteError(w, r, http.StatusServiceUnavailable, "SERVER_BUSY", "server is busy, retry later")
+				return
+			}
+			defer func() { <-slots }()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"key-management-service/pkg/httputil"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	const limit = 2
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := ConcurrencyLimit(limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// 上限まで処理中のリクエストで埋める
+	var wg sync.WaitGroup
+	codes := make([]int, limit)
+	for i := range limit {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil))
+			codes[i] = rec.Code
+		}()
+		<-started
+	}
+
+	// 上限を超えたリクエストは待たずに503になる
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("want status 503 past the limit, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("want Retry-After 1, got %q", got)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("want JSON error body, got %q: %v", rec.Body.String(), err)
+	}
+	if resp.Code != "SERVER_BUSY" {
+		t.Errorf("want code SERVER_BUSY, got %s", resp.Code)
+	}
+
+	close(release)
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d: want status 200, got %d", i, code)
+		}
+	}
+
+	// 枠が空けば再び受け付ける
+	go func() { <-started }()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status 200 after slots are released, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CORSConfig はCORSミドルウェアの設定。
+type CORSConfig struct {
+	AllowedOrigins []string // 許可するオリジン。"*"はすべてのオリジンを許可する
+	AllowedMethods []string // プリフライトで許可するメソッド
+	AllowedHeaders []string // プリフライトで許可するリクエストヘッダー
+}
+
+// CORS はブラウザからのクロスオリジンリクエストを許可するミドルウェアを返す。
+// 許可されたオリジンの場合のみ、そのオリジンをAccess-Control-Allow-Originに返す。
+// プリフライト（OPTIONS）リクエストはルーティングせずにここで応答する。
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	allowAll := slices.Contains(cfg.AllowedOrigins, "*")
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			allowed := allowAll || slices.Contains(cfg.AllowedOrigins, origin)
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				if !allowed {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Expose-Headers", IdempotencyReplayedHeader)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
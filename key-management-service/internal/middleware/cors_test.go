@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newCORSHandler() http.Handler {
+	cfg := CORSConfig{
+		AllowedOrigins: []string{"https://console.example.com"},
+		AllowedMethods: []string{"GET", "POST", "DELETE"},
+		AllowedHeaders: []string{"Content-Type", "Idempotency-Key"},
+	}
+	return CORS(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestCORS_AllowedOrigin(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants", nil)
+	req.Header.Set("Origin", "https://console.example.com")
+	rec := httptest.NewRecorder()
+	newCORSHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://console.example.com" {
+		t.Errorf("want origin echoed, got %q", got)
+	}
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+	newCORSHandler().ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("want no Access-Control-Allow-Origin, got %q", got)
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/v1/tenants/tenant-001/keys", nil)
+	req.Header.Set("Origin", "https://console.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "Idempotency-Key")
+	rec := httptest.NewRecorder()
+	newCORSHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("want status 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://console.example.com" {
+		t.Errorf("want origin echoed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST, DELETE" {
+		t.Errorf("unexpected Access-Control-Allow-Methods: %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, Idempotency-Key" {
+		t.Errorf("unexpected Access-Control-Allow-Headers: %q", got)
+	}
+}
+
+func TestCORS_PreflightDisallowedOrigin(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/v1/tenants/tenant-001/keys", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	newCORSHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("want status 403, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("want no Access-Control-Allow-Origin, got %q", got)
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/pkg/httputil"
+)
+
+// WriteError はリクエストIDとトレースIDを付与したエラーレスポンスを返す。
+// ハンドラーとミドルウェアで同じ形式のエラーを返すため、両方から使用する。
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code string, message string) {
+	requestID, traceID := RequestIDs(r)
+	httputil.ErrorWithIDs(w, status, code, message, requestID, traceID)
+}
+
+// RequestIDs はエラーレスポンスに付与するリクエストIDとトレースIDを返す。
+// トレースが記録されていない場合、トレースIDは空文字列になる。
+func RequestIDs(r *http.Request) (requestID, traceID string) {
+	ctx := r.Context()
+	if sc := trace.SpanFromContext(ctx).SpanContext(); sc.HasTraceID() {
+		traceID = sc.TraceID().String()
+	}
+	return chimiddleware.GetReqID(ctx), traceID
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/pkg/httputil"
+)
+
+func TestWriteError(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("0123456789abcdef0123456789abcdef")
+	spanID, _ := trace.SpanIDFromHex("0123456789abcdef")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	ctx = context.WithValue(ctx, chimiddleware.RequestIDKey, "req-123")
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants", nil).WithContext(ctx)
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, req, http.StatusConflict, "CONFLICT", "conflict")
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("want status 409, got %d", rec.Code)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.RequestID != "req-123" || resp.TraceID != traceID.String() {
+		t.Errorf("want request_id req-123 and trace_id %s, got %q and %q", traceID, resp.RequestID, resp.TraceID)
+	}
+
+	// トレースがない場合はトレースIDを返さない
+	requestID, gotTraceID := RequestIDs(httptest.NewRequest(http.MethodGet, "/v1/tenants", nil))
+	if requestID != "" || gotTraceID != "" {
+		t.Errorf("want empty IDs without a request ID and span, got %q and %q", requestID, gotTraceID)
+	}
+}
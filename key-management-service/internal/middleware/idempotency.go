@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"key-management-service/internal/domain"
+)
+
+// IdempotencyKeyHeader は冪等性キーを指定するリクエストヘッダー。
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyReplayedHeader は記録済みの結果を返したことを示すレスポンスヘッダー。
+const IdempotencyReplayedHeader = "Idempotency-Replayed"
+
+// IdempotencyStore は冪等性キーの記録を保存・参照するインターフェース。
+type IdempotencyStore interface {
+	// Reserve はrecordを処理中として記録する。同じパス・キーの記録が既にある場合は記録せず、既存の記録を返す。
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+	// Complete は処理中の記録にレスポンスを記録する。
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error
+	// Release は処理中の記録を削除し、同じキーで再試行できるようにする。
+	Release(ctx context.Context, requestPath, key string) error
+}
+
+// Idempotency はIdempotency-Keyヘッダー付きのリクエストを冪等にするミドルウェアを返す。
+// ハンドラーの実行前にキーを処理中として予約するため、同じキーの同時リクエストは409で拒否される。
+// 成功済みのリクエストが再送された場合は、ハンドラーを実行せず初回の結果を返す。
+// キーはクエリ文字列・ボディのハッシュと紐付け、異なる内容で再利用された場合は422を返す。
+// 失敗したレスポンスは記録せず予約を解除するため、同じキーで再試行できる。
+func Idempotency(store IdempotencyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > domain.MaxIdempotencyKeyLen {
+				WriteError(w, r, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY", "idempotency key is too long")
+				return
+			}
+
+			ctx := r.Context()
+			requestPath := r.Method + " " + r.URL.Path
+			hash, err := requestHash(r)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					WriteError(w, r, http.StatusRequestEntityTooLarge, "INVALID_BODY", "request body too large")
+					return
+				}
+				WriteError(w, r, http.StatusBadRequest, "INVALID_BODY", "invalid request body")
+				return
+			}
+
+			record, err := store.Reserve(ctx, &domain.IdempotencyRecord{
+				Key:         key,
+				RequestPath: requestPath,
+				RequestHash: hash,
+			})
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to reserve idempotency key",
+					"operation", "idempotency",
+					"request_path", requestPath,
+					"error", err,
+				)
+				WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+				return
+			}
+			if record != nil {
+				// 変更前に記録されたハッシュのない記録は内容を比較しない
+				if record.RequestHash != "" && record.RequestHash != hash {
+					WriteError(w, r, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_MISMATCH", "idempotency key was already used with a different request")
+					return
+				}
+				if record.Pending() {
+					WriteError(w, r, http.StatusConflict, "IDEMPOTENCY_KEY_IN_PROGRESS", "a request with this idempotency key is in progress")
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(IdempotencyReplayedHeader, "true")
+				w.WriteHeader(record.StatusCode)
+				_, _ = w.Write(record.Body)
+				return
+			}
+
+			// レスポンスの送信後やリクエストの期限切れ後も記録できるよう、キャンセルされないコンテキストを使う
+			storeCtx := context.WithoutCancel(ctx)
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				// 失敗やpanicの場合は予約を解除する
+				if completed {
+					return
+				}
+				if err := store.Release(storeCtx, requestPath, key); err != nil {
+					slog.ErrorContext(ctx, "failed to release idempotency key",
+						"operation", "idempotency",
+						"request_path", requestPath,
+						"error", err,
+					)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+
+			if rec.status < 200 || rec.status >= 300 {
+				return
+			}
+			completed = true
+			if err := store.Complete(storeCtx, &domain.IdempotencyRecord{
+				Key:         key,
+				RequestPath: requestPath,
+				RequestHash: hash,
+				StatusCode:  rec.status,
+				Body:        rec.body.Bytes(),
+			}); err != nil {
+				// レスポンスは送信済みのため、記録の失敗はログのみ
+				slog.ErrorContext(ctx, "failed to save idempotency key",
+					"operation", "idempotency",
+					"request_path", requestPath,
+					"error", err,
+				)
+			}
+		})
+	}
+}
+
+// requestHash はクエリ文字列とボディのSHA-256を16進数で返す。
+// ボディは読み込んだ内容でハンドラーから再度読めるよう差し替える。
+func requestHash(r *http.Request) (string, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	h := sha256.New()
+	h.Write([]byte(r.URL.RawQuery))
+	// 区切りを入れて ("a=1","") と ("","a=1") を区別する
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// responseRecorder はレスポンスを書き込みつつステータスとボディを記録する。
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
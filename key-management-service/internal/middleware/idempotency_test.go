@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"key-management-service/internal/domain"
+)
+
+// memoryIdempotencyStore はテスト用のインメモリストア。
+type memoryIdempotencyStore struct {
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: map[string]*domain.IdempotencyRecord{}}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	if existing, ok := s.records[record.RequestPath+"|"+record.Key]; ok {
+		return existing, nil
+	}
+	s.records[record.RequestPath+"|"+record.Key] = &domain.IdempotencyRecord{
+		Key:         record.Key,
+		RequestPath: record.RequestPath,
+		RequestHash: record.RequestHash,
+	}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	s.records[record.RequestPath+"|"+record.Key] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, requestPath, key string) error {
+	if r, ok := s.records[requestPath+"|"+key]; ok && r.Pending() {
+		delete(s.records, requestPath+"|"+key)
+	}
+	return nil
+}
+
+// generationHandler は呼び出しごとに世代番号を増やして201を返すハンドラー。
+func generationHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"generation":%d}`, *calls)
+	})
+}
+
+func TestIdempotency_ReplayReturnsCachedResponse(t *testing.T) {
+	var calls int
+	h := Idempotency(newMemoryIdempotencyStore())(generationHandler(&calls))
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/rotate", nil)
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send()
+	second := send()
+
+	if calls != 1 {
+		t.Errorf("want handler called once, got %d", calls)
+	}
+	if second.Code != http.StatusCreated {
+		t.Errorf("want status 201, got %d", second.Code)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("want replayed body %s, got %s", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Error("want Idempotency-Replayed header on replay")
+	}
+	if first.Header().Get(IdempotencyReplayedHeader) != "" {
+		t.Error("want no Idempotency-Replayed header on first response")
+	}
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	var calls int
+	h := Idempotency(newMemoryIdempotencyStore())(generationHandler(&calls))
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/rotate", nil)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if calls != 2 {
+		t.Errorf("want handler called twice, got %d", calls)
+	}
+}
+
+func TestIdempotency_FailureIsNotCached(t *testing.T) {
+	var calls int
+	h := Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys", nil)
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if calls != 2 {
+		t.Errorf("want failed request to be retried, got %d calls", calls)
+	}
+}
+
+func TestIdempotency_KeyTooLong(t *testing.T) {
+	var calls int
+	h := Idempotency(newMemoryIdempotencyStore())(generationHandler(&calls))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys", nil)
+	req.Header.Set(IdempotencyKeyHeader, strings.Repeat("a", domain.MaxIdempotencyKeyLen+1))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("want status 400, got %d", rec.Code)
+	}
+	if calls != 0 {
+		t.Errorf("want handler not called, got %d", calls)
+	}
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	var calls int
+	var inner *httptest.ResponseRecorder
+	var h http.Handler
+	// ハンドラーの実行中に同じキーで再送する
+	h = Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/rotate", nil)
+			req.Header.Set(IdempotencyKeyHeader, "retry-1")
+			inner = httptest.NewRecorder()
+			h.ServeHTTP(inner, req)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys/rotate", nil)
+	req.Header.Set(IdempotencyKeyHeader, "retry-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if calls != 1 {
+		t.Errorf("want handler called once, got %d", calls)
+	}
+	if inner.Code != http.StatusConflict {
+		t.Errorf("want status 409 while in progress, got %d", inner.Code)
+	}
+	if !strings.Contains(inner.Body.String(), "IDEMPOTENCY_KEY_IN_PROGRESS") {
+		t.Errorf("want IDEMPOTENCY_KEY_IN_PROGRESS, got %s", inner.Body.String())
+	}
+}
+
+func TestIdempotency_DifferentPayload(t *testing.T) {
+	var calls int
+	var bodies []string
+	h := Idempotency(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	send := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	send("/v1/tenants/tenant-001/keys", `{"key_type":"AES"}`)
+	if len(bodies) != 1 || bodies[0] != `{"key_type":"AES"}` {
+		t.Errorf("want handler to read the original body, got %q", bodies)
+	}
+	for _, tc := range []struct{ target, body string }{
+		{"/v1/tenants/tenant-001/keys", `{"key_type":"HMAC"}`},
+		{"/v1/tenants/tenant-001/keys?force=true", `{"key_type":"AES"}`},
+	} {
+		rec := send(tc.target, tc.body)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s %s: want status 422, got %d", tc.target, tc.body, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "IDEMPOTENCY_KEY_MISMATCH") {
+			t.Errorf("want IDEMPOTENCY_KEY_MISMATCH, got %s", rec.Body.String())
+		}
+	}
+	if rec := send("/v1/tenants/tenant-001/keys", `{"key_type":"AES"}`); rec.Code != http.StatusCreated {
+		t.Errorf("want replay with the same payload, got %d", rec.Code)
+	}
+	if calls != 1 {
+		t.Errorf("want handler called once, got %d", calls)
+	}
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	h := Idempotency(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys", nil)
+	req.Header.Set(IdempotencyKeyHeader, "retry-1")
+	func() {
+		defer func() { _ = recover() }()
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	if len(store.records) != 0 {
+		t.Errorf("want reservation released after panic, got %+v", store.records)
+	}
+}
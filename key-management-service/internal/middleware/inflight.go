@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// InFlightTracker は処理中のリクエストを追跡する。
+// シャットダウン時に処理中のKMS呼び出しが完了するまで依存リソースの解放を待つために使用する。
+type InFlightTracker struct {
+	wg sync.WaitGroup
+}
+
+// NewInFlightTracker は新しいInFlightTrackerを生成する。
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{}
+}
+
+// Middleware はリクエストの開始から完了までを追跡するミドルウェアを返す。
+func (t *InFlightTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.wg.Add(1)
+		defer t.wg.Done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Wait は処理中のリクエストが全て完了するまで待機する。
+// ctxが先に終了した場合はctxのエラーを返す。
+func (t *InFlightTracker) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -3,8 +3,17 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"sync"
 	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"key-management-service/internal/domain"
 )
 
 // AuditLog は監査ログの構造体。
@@ -13,16 +22,106 @@ type AuditLog struct {
 	TenantID   string `json:"tenant_id"`
 	Generation uint   `json:"generation,omitempty"`
 	Result     string `json:"result"`
+	RequestID  string `json:"request_id,omitempty"`
 	Timestamp  string `json:"timestamp"`
 }
 
-// WriteAuditLog は監査ログを出力する。
-func WriteAuditLog(ctx context.Context, operation string, tenantID string, generation uint, result string) {
-	slog.InfoContext(ctx, "key operation completed",
-		"operation", operation,
-		"tenant_id", tenantID,
-		"generation", generation,
-		"result", result,
-		"timestamp", time.Now().UTC().Format(time.RFC3339),
-	)
+// AuditLogger は監査ログの出力先を抽象化する。
+// アプリケーションログとは別の出力先に書き込むことで、改ざん耐性のあるストアへ転送できるようにする。
+type AuditLogger interface {
+	Write(ctx context.Context, operation string, tenantID string, generation uint, result string)
+}
+
+// JSONAuditLogger は監査ログを1行1件のJSONとして書き込む。
+type JSONAuditLogger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONAuditLogger は指定されたWriterに書き込むJSONAuditLoggerを生成する。
+func NewJSONAuditLogger(w io.Writer) *JSONAuditLogger {
+	return &JSONAuditLogger{w: w}
+}
+
+// NewStdoutAuditLogger は標準出力に書き込むJSONAuditLoggerを生成する。
+func NewStdoutAuditLogger() *JSONAuditLogger {
+	return NewJSONAuditLogger(os.Stdout)
+}
+
+// NewFileAuditLogger は指定されたファイルに追記するJSONAuditLoggerを生成する。
+// ファイルが存在しない場合は作成する。
+func NewFileAuditLogger(path string) (*JSONAuditLogger, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log file: %w", err)
+	}
+	return &JSONAuditLogger{w: f, closer: f}, nil
+}
+
+// Write は監査ログを1件書き込む。書き込みに失敗した場合はアプリケーションログにエラーを出力する。
+func (l *JSONAuditLogger) Write(ctx context.Context, operation string, tenantID string, generation uint, result string) {
+	entry := AuditLog{
+		Operation:  operation,
+		TenantID:   tenantID,
+		Generation: generation,
+		Result:     result,
+		RequestID:  chimiddleware.GetReqID(ctx),
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to marshal audit log", "operation", operation, "error", err)
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(line); err != nil {
+		slog.ErrorContext(ctx, "failed to write audit log", "operation", operation, "error", err)
+	}
+}
+
+// Close は出力先のファイルを閉じる。ファイル以外の出力先の場合は何もしない。
+func (l *JSONAuditLogger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// AuditEventStore は監査イベントの保存先のインターフェース。
+type AuditEventStore interface {
+	Save(ctx context.Context, event *domain.AuditEvent) error
+}
+
+// PersistentAuditLogger は監査ログを出力したうえで、検索できるよう保存先にも記録する。
+type PersistentAuditLogger struct {
+	next  AuditLogger
+	store AuditEventStore
+}
+
+// NewPersistentAuditLogger はnextに書き込み、storeにも記録するPersistentAuditLoggerを生成する。
+func NewPersistentAuditLogger(next AuditLogger, store AuditEventStore) *PersistentAuditLogger {
+	return &PersistentAuditLogger{next: next, store: store}
+}
+
+// Write は監査ログを書き込み、監査イベントを保存する。
+// 保存に失敗してもリクエストは失敗させず、アプリケーションログにエラーを出力する。
+func (l *PersistentAuditLogger) Write(ctx context.Context, operation string, tenantID string, generation uint, result string) {
+	l.next.Write(ctx, operation, tenantID, generation, result)
+
+	event := &domain.AuditEvent{
+		TenantID:   tenantID,
+		Operation:  operation,
+		Generation: generation,
+		Result:     result,
+		RequestID:  chimiddleware.GetReqID(ctx),
+		CreatedAt:  time.Now().UTC(),
+	}
+	// リクエストがキャンセルされても記録は残す
+	if err := l.store.Save(context.WithoutCancel(ctx), event); err != nil {
+		slog.ErrorContext(ctx, "failed to persist audit event", "operation", operation, "error", err)
+	}
 }
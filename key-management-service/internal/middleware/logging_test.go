@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"key-management-service/internal/domain"
+)
+
+func TestFileAuditLogger_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	// 既存の内容が保持されることを確認するため2回開く
+	for i, op := range []string{"CREATE_KEY", "ROTATE_KEY"} {
+		logger, err := NewFileAuditLogger(path)
+		if err != nil {
+			t.Fatalf("NewFileAuditLogger failed: %v", err)
+		}
+		ctx := context.WithValue(context.Background(), chimiddleware.RequestIDKey, "req-"+op)
+		logger.Write(ctx, op, "tenant-001", uint(i+1), "SUCCESS")
+		if err := logger.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []AuditLog
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditLog
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to parse audit log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Operation != "CREATE_KEY" || entries[0].RequestID != "req-CREATE_KEY" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Operation != "ROTATE_KEY" || entries[1].RequestID != "req-ROTATE_KEY" || entries[1].Generation != 2 {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+// memoryAuditStore は保存された監査イベントを保持するテスト用ストア。
+type memoryAuditStore struct {
+	events []*domain.AuditEvent
+	err    error
+}
+
+func (s *memoryAuditStore) Save(ctx context.Context, event *domain.AuditEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestPersistentAuditLogger_WritesAndPersists(t *testing.T) {
+	var buf bytes.Buffer
+	store := &memoryAuditStore{}
+	logger := NewPersistentAuditLogger(NewJSONAuditLogger(&buf), store)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), chimiddleware.RequestIDKey, "req-1"))
+	cancel()
+	logger.Write(ctx, "ROTATE_KEY", "tenant-001", 2, "SUCCESS")
+
+	if buf.Len() == 0 {
+		t.Error("expected audit log line to be written")
+	}
+	if len(store.events) != 1 {
+		t.Fatalf("expected 1 persisted event even after cancellation, got %d", len(store.events))
+	}
+	e := store.events[0]
+	if e.Operation != "ROTATE_KEY" || e.TenantID != "tenant-001" || e.Generation != 2 || e.RequestID != "req-1" || e.CreatedAt.IsZero() {
+		t.Errorf("unexpected persisted event: %+v", e)
+	}
+
+	// 保存に失敗しても監査ログの出力は継続する
+	store.err = errors.New("db down")
+	logger.Write(context.Background(), "GET_KEY", "tenant-001", 2, "SUCCESS")
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 2 {
+		t.Errorf("expected 2 audit log lines, got %d", got)
+	}
+}
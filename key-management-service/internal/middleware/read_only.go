@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+)
+
+// ReadOnlyMode はメンテナンス用の読み取り専用モードの状態を保持する。
+// 実行中に切り替えられるよう、状態はatomicに管理する。
+type ReadOnlyMode struct {
+	enabled atomic.Bool
+}
+
+// NewReadOnlyMode は初期状態を指定してReadOnlyModeを生成する。
+func NewReadOnlyMode(enabled bool) *ReadOnlyMode {
+	m := &ReadOnlyMode{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled は読み取り専用モードが有効かを返す。
+func (m *ReadOnlyMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set は読み取り専用モードの有効・無効を設定する。
+func (m *ReadOnlyMode) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Toggle は読み取り専用モードを反転し、切り替え後の状態を返す。
+func (m *ReadOnlyMode) Toggle() bool {
+	for {
+		old := m.enabled.Load()
+		if m.enabled.CompareAndSwap(old, !old) {
+			return !old
+		}
+	}
+}
+
+// ReadOnly は読み取り専用モードが有効な間、リクエストを503（SERVICE_READ_ONLY）で拒否するミドルウェアを返す。
+// 鍵の作成・ローテーション・無効化など、状態を変更するルートにのみ適用する。
+func ReadOnly(mode *ReadOnlyMode) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if mode.Enabled() {
+				slog.InfoContext(r.Context(), "rejected write in read-only mode",
+					"operation", "read_only",
+					"method", r.Method,
+					"path", r.URL.Path,
+				)
+				WriteError(w, r, http.StatusServiceUnavailable, "SERVICE_READ_ONLY", "service is in read-only mode")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"key-management-service/pkg/httputil"
+)
+
+func TestReadOnly(t *testing.T) {
+	mode := NewReadOnlyMode(false)
+	called := 0
+	handler := ReadOnly(mode)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys", nil))
+	if rec.Code != http.StatusCreated || called != 1 {
+		t.Fatalf("want request to pass when disabled, got status %d", rec.Code)
+	}
+
+	// SIGUSR1と同様に実行中に切り替える
+	if !mode.Toggle() {
+		t.Fatal("want Toggle to enable read-only mode")
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("want status 503, got %d", rec.Code)
+	}
+	if called != 1 {
+		t.Error("want handler not to be called in read-only mode")
+	}
+	var resp httputil.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("want JSON error body, got %q: %v", rec.Body.String(), err)
+	}
+	if resp.Code != "SERVICE_READ_ONLY" {
+		t.Errorf("want code SERVICE_READ_ONLY, got %s", resp.Code)
+	}
+
+	if mode.Toggle() {
+		t.Error("want Toggle to disable read-only mode")
+	}
+}
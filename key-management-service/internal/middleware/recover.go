@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Recover はハンドラーのpanicを回復し、500（INTERNAL_ERROR）をJSONのエラーレスポンスで返すミドルウェア。
+// chiのRecovererと異なり、panicの内容とスタックトレースをリクエストIDとトレース情報付きでslogに出力する。
+// リクエストIDとトレースのコンテキストを参照するため、RequestIDとTracingの内側に配置する。
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			// net/httpが接続の中断に使用するpanicはそのまま伝播させる
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			slog.ErrorContext(r.Context(), "panic recovered",
+				"operation", "recover",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"request_id", chimiddleware.GetReqID(r.Context()),
+				"panic", fmt.Sprint(p),
+				"stack", string(debug.Stack()),
+			)
+			// WebSocketなどアップグレード済みの接続には書き込まない
+			if r.Header.Get("Connection") == "Upgrade" {
+				return
+			}
+			WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
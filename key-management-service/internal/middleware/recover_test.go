@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"key-management-service/pkg/httputil"
+)
+
+func TestRecover_PanicReturnsJSON500(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	handler := chimiddleware.RequestID(Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants/tenant-001/keys/current", nil)
+	req.Header.Set(chimiddleware.RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("want status 500, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("want Content-Type application/json, got %q", ct)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("want JSON error body, got %q: %v", rec.Body.String(), err)
+	}
+	if resp.Code != "INTERNAL_ERROR" {
+		t.Errorf("want code INTERNAL_ERROR, got %s", resp.Code)
+	}
+	if resp.RequestID != "req-123" {
+		t.Errorf("want request_id req-123, got %q", resp.RequestID)
+	}
+
+	// panicの内容とスタックトレースがリクエストID付きでログに出力される
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("want a JSON log entry, got %q: %v", buf.String(), err)
+	}
+	if entry["panic"] != "boom" || entry["request_id"] != "req-123" || entry["operation"] != "recover" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+	if stack, _ := entry["stack"].(string); !strings.Contains(stack, "recover_test.go") {
+		t.Errorf("want stack trace of the panicking handler, got %q", stack)
+	}
+}
+
+func TestRecover_PassesThroughWithoutPanic(t *testing.T) {
+	handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("want status 204, got %d", rec.Code)
+	}
+}
+
+func TestRecover_RepanicsAbortHandler(t *testing.T) {
+	handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("want http.ErrAbortHandler to propagate, got %v", p)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errRequestTimedOut はタイムアウト後にハンドラーが書き込もうとした場合のエラー。
+var errRequestTimedOut = errors.New("request timed out")
+
+// Timeout はリクエストごとに処理期限を設けるミドルウェアを返す。
+// リクエストのコンテキストに期限を設定するため、サービス層・KMS・DBの呼び出しも期限切れで中断される。
+// 期限までにハンドラーが完了しない場合は503（REQUEST_TIMEOUT）を返し、以降のハンドラーの書き込みは破棄する。
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{header: make(http.Header), status: http.StatusOK}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				// 外側のRecoverで処理できるよう呼び出し元のゴルーチンで再度panicする
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				dst := w.Header()
+				for k, v := range tw.header {
+					dst[k] = v
+				}
+				w.WriteHeader(tw.status)
+				_, _ = w.Write(tw.body.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				slog.WarnContext(ctx, "request timed out",
+					"operation", "request_timeout",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"timeout_ms", d.Milliseconds(),
+				)
+				WriteError(w, r, http.StatusServiceUnavailable, "REQUEST_TIMEOUT", "request timed out")
+			}
+		})
+	}
+}
+
+// timeoutWriter はハンドラーのレスポンスをバッファし、期限内に完了した場合のみ送信する。
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.status = status
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, errRequestTimedOut
+	}
+	tw.wroteHeader = true
+	return tw.body.Write(b)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"key-management-service/pkg/httputil"
+)
+
+func TestTimeout_SlowHandler(t *testing.T) {
+	cancelled := make(chan struct{})
+	handler := Timeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 下位層と同様にコンテキストの終了を待つ
+		<-r.Context().Done()
+		close(cancelled)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tenants", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("want status 503, got %d", rec.Code)
+	}
+	var resp httputil.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("want JSON error body, got %q: %v", rec.Body.String(), err)
+	}
+	if resp.Code != "REQUEST_TIMEOUT" {
+		t.Errorf("want code REQUEST_TIMEOUT, got %s", resp.Code)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("want handler context to be cancelled")
+	}
+}
+
+func TestTimeout_FastHandler(t *testing.T) {
+	handler := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tenants/tenant-001/keys", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("want status 201, got %d", rec.Code)
+	}
+	if rec.Body.String() != `{"ok":true}` {
+		t.Errorf("unexpected body: %q", rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("want Content-Type application/json, got %q", got)
+	}
+}
+
+func TestTimeout_Panic(t *testing.T) {
+	handler := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("want panic propagated, got %v", p)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
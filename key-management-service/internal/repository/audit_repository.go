@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"key-management-service/internal/domain"
+)
+
+// AuditEventModel はaudit_eventsテーブルのモデル。
+type AuditEventModel struct {
+	ID         uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	TenantID   string    `gorm:"column:tenant_id;type:varchar(255);not null"`
+	Operation  string    `gorm:"column:operation;type:varchar(64);not null"`
+	Generation uint      `gorm:"column:generation;not null;default:0"`
+	Result     string    `gorm:"column:result;type:varchar(16);not null"`
+	RequestID  string    `gorm:"column:request_id;type:varchar(255);not null;default:''"`
+	CreatedAt  time.Time `gorm:"column:created_at;precision:6;not null"`
+}
+
+// TableName はテーブル名を返す。
+func (AuditEventModel) TableName() string {
+	return "audit_events"
+}
+
+// AuditRepository は監査イベントの保存と検索を提供する。
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository は新しいAuditRepositoryを生成する。
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Save は監査イベントを1件保存する。
+func (r *AuditRepository) Save(ctx context.Context, event *domain.AuditEvent) error {
+	model := AuditEventModel{
+		TenantID:   event.TenantID,
+		Operation:  event.Operation,
+		Generation: event.Generation,
+		Result:     event.Result,
+		RequestID:  event.RequestID,
+		CreatedAt:  event.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to save audit event",
+			"operation", "save_audit_event",
+			"tenant_id", event.TenantID,
+			"error", err,
+		)
+		return err
+	}
+	event.ID = model.ID
+	return nil
+}
+
+// FindByTenantID は条件に一致するテナントの監査イベントを古い順に取得する。
+func (r *AuditRepository) FindByTenantID(ctx context.Context, tenantID string, filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at <= ?", *filter.Until)
+	}
+	if filter.Operation != "" {
+		query = query.Where("operation = ?", filter.Operation)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var models []AuditEventModel
+	if err := query.Order("created_at ASC, id ASC").Find(&models).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to find audit events",
+			"operation", "find_audit_events",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	events := make([]*domain.AuditEvent, len(models))
+	for i, m := range models {
+		events[i] = &domain.AuditEvent{
+			ID:         m.ID,
+			TenantID:   m.TenantID,
+			Operation:  m.Operation,
+			Generation: m.Generation,
+			Result:     m.Result,
+			RequestID:  m.RequestID,
+			CreatedAt:  m.CreatedAt,
+		}
+	}
+	return events, nil
+}
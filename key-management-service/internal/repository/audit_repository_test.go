@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"key-management-service/internal/domain"
+)
+
+// setupAuditTable はaudit_eventsテーブルを作成し、テスト用のイベントを保存する。
+func setupAuditTable(t *testing.T, db *gorm.DB, base time.Time) *AuditRepository {
+	t.Helper()
+	if err := db.AutoMigrate(&AuditEventModel{}); err != nil {
+		t.Fatalf("failed to migrate audit_events table: %v", err)
+	}
+	repo := NewAuditRepository(db)
+
+	events := []*domain.AuditEvent{
+		{TenantID: "tenant-1", Operation: "CREATE_KEY", Generation: 1, Result: "SUCCESS", CreatedAt: base},
+		{TenantID: "tenant-1", Operation: "GET_KEY", Generation: 1, Result: "SUCCESS", CreatedAt: base.Add(time.Hour)},
+		{TenantID: "tenant-1", Operation: "ROTATE_KEY", Generation: 2, Result: "SUCCESS", CreatedAt: base.Add(2 * time.Hour)},
+		{TenantID: "tenant-1", Operation: "GET_KEY", Generation: 2, Result: "FAILED", CreatedAt: base.Add(3 * time.Hour)},
+		{TenantID: "tenant-2", Operation: "GET_KEY", Generation: 1, Result: "SUCCESS", CreatedAt: base.Add(time.Hour)},
+	}
+	for _, e := range events {
+		if err := repo.Save(context.Background(), e); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		if e.ID == 0 {
+			t.Fatal("expected ID to be assigned")
+		}
+	}
+	return repo
+}
+
+func TestAuditRepository_FindByTenantID(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := setupAuditTable(t, setupTestDB(t), base)
+
+	since := base.Add(time.Hour)
+	until := base.Add(2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  domain.AuditFilter
+		wantOps []string
+	}{
+		{
+			name:    "all events for tenant",
+			filter:  domain.AuditFilter{},
+			wantOps: []string{"CREATE_KEY", "GET_KEY", "ROTATE_KEY", "GET_KEY"},
+		},
+		{
+			name:    "by operation",
+			filter:  domain.AuditFilter{Operation: "GET_KEY"},
+			wantOps: []string{"GET_KEY", "GET_KEY"},
+		},
+		{
+			name:    "by time range (inclusive)",
+			filter:  domain.AuditFilter{Since: &since, Until: &until},
+			wantOps: []string{"GET_KEY", "ROTATE_KEY"},
+		},
+		{
+			name:    "by operation and since",
+			filter:  domain.AuditFilter{Since: &until, Operation: "GET_KEY"},
+			wantOps: []string{"GET_KEY"},
+		},
+		{
+			name:    "paginated",
+			filter:  domain.AuditFilter{Limit: 2, Offset: 1},
+			wantOps: []string{"GET_KEY", "ROTATE_KEY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.FindByTenantID(ctx, "tenant-1", tt.filter)
+			if err != nil {
+				t.Fatalf("FindByTenantID failed: %v", err)
+			}
+			if len(events) != len(tt.wantOps) {
+				t.Fatalf("expected %d events, got %d", len(tt.wantOps), len(events))
+			}
+			for i, e := range events {
+				if e.Operation != tt.wantOps[i] {
+					t.Errorf("event %d: expected %s, got %s", i, tt.wantOps[i], e.Operation)
+				}
+				if e.TenantID != "tenant-1" {
+					t.Errorf("event %d: unexpected tenant %s", i, e.TenantID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"key-management-service/config"
+)
+
+// IDGenerator は鍵レコードの主キーを生成する。
+type IDGenerator interface {
+	NewID() string
+}
+
+// NewIDGenerator は生成方式（uuid / ulid）に対応するIDGeneratorを返す。空の場合はUUIDとする。
+func NewIDGenerator(strategy string) (IDGenerator, error) {
+	switch strategy {
+	case "", config.IDStrategyUUID:
+		return UUIDGenerator{}, nil
+	case config.IDStrategyULID:
+		return NewULIDGenerator(), nil
+	default:
+		return nil, fmt.Errorf("unknown ID strategy %q", strategy)
+	}
+}
+
+// UUIDGenerator はランダムなUUID（v4）を生成する。既定の生成方式。
+type UUIDGenerator struct{}
+
+// NewID は新しいUUIDを返す。
+func (UUIDGenerator) NewID() string {
+	return uuid.New().String()
+}
+
+// crockfordBase32 はULIDの文字列表現に使用するCrockfordのBase32のアルファベット。
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator はULID（ミリ秒単位の時刻48ビットと乱数80ビットの26文字）を生成する。
+// 先頭が作成時刻のため、文字列として比較すると作成順に並ぶ。
+type ULIDGenerator struct {
+	now  func() time.Time
+	rand io.Reader
+}
+
+// NewULIDGenerator は現在時刻とcrypto/randを使用するULIDGeneratorを生成する。
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{now: time.Now, rand: rand.Reader}
+}
+
+// NewID は新しいULIDを返す。
+func (g *ULIDGenerator) NewID() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(g.now().UnixMilli())<<16)
+	if _, err := io.ReadFull(g.rand, b[6:]); err != nil {
+		// crypto/randは失敗しない。失敗した場合に重複したIDを返さないよう停止する
+		panic(fmt.Sprintf("reading random bytes for ULID: %v", err))
+	}
+	return encodeULID(b)
+}
+
+// encodeULID は128ビットの値を上位ビットから5ビットずつCrockfordのBase32で26文字に符号化する。
+func encodeULID(b [16]byte) string {
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	out := make([]byte, 26)
+	for i := range out {
+		shift := uint(5 * (len(out) - 1 - i))
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift+5 > 64:
+			v = lo>>shift | hi<<(64-shift)
+		default:
+			v = lo >> shift
+		}
+		out[i] = crockfordBase32[v&31]
+	}
+	return string(out)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"key-management-service/config"
+)
+
+func TestNewIDGenerator(t *testing.T) {
+	tests := []struct {
+		strategy string
+		pattern  string
+	}{
+		{strategy: "", pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{strategy: config.IDStrategyUUID, pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{strategy: config.IDStrategyULID, pattern: `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`},
+	}
+	for _, tt := range tests {
+		gen, err := NewIDGenerator(tt.strategy)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.strategy, err)
+		}
+		first, second := gen.NewID(), gen.NewID()
+		if !regexp.MustCompile(tt.pattern).MatchString(first) {
+			t.Errorf("%q: unexpected ID form %q", tt.strategy, first)
+		}
+		if first == second {
+			t.Errorf("%q: want distinct IDs, got %q twice", tt.strategy, first)
+		}
+	}
+
+	if _, err := NewIDGenerator("snowflake"); err == nil {
+		t.Error("want error for unknown strategy, got nil")
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	now := time.UnixMilli(1469918176385)
+	gen := &ULIDGenerator{
+		now:  func() time.Time { return now },
+		rand: bytes.NewReader(append(make([]byte, 10), bytes.Repeat([]byte{0xff}, 10)...)),
+	}
+
+	// ULIDの仕様の例と同じ時刻部分になる
+	first := gen.NewID()
+	if first != "01ARYZ6S410000000000000000" {
+		t.Errorf("want 01ARYZ6S410000000000000000, got %s", first)
+	}
+	second := gen.NewID()
+	if second != "01ARYZ6S41ZZZZZZZZZZZZZZZZ" {
+		t.Errorf("want 01ARYZ6S41ZZZZZZZZZZZZZZZZ, got %s", second)
+	}
+
+	// 作成時刻が後のIDは文字列として後に並ぶ
+	now = now.Add(time.Millisecond)
+	gen.rand = bytes.NewReader(make([]byte, 10))
+	if third := gen.NewID(); strings.Compare(second, third) >= 0 {
+		t.Errorf("want %s to sort after %s", third, second)
+	}
+}
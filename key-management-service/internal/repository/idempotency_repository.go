@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"key-management-service/internal/domain"
+)
+
+// IdempotencyKeyModel はidempotency_keysテーブルのモデル。
+type IdempotencyKeyModel struct {
+	IdempotencyKey string    `gorm:"column:idempotency_key;type:varchar(255);primaryKey"`
+	RequestPath    string    `gorm:"column:request_path;type:varchar(255);primaryKey"`
+	RequestHash    string    `gorm:"column:request_hash;type:char(64);not null;default:''"`
+	StatusCode     int       `gorm:"column:status_code;not null"`
+	ResponseBody   []byte    `gorm:"column:response_body;type:bytes;not null"`
+	CreatedAt      time.Time `gorm:"column:created_at;precision:6;not null;autoCreateTime;index"`
+}
+
+// TableName はテーブル名を返す。
+func (IdempotencyKeyModel) TableName() string {
+	return "idempotency_keys"
+}
+
+// IdempotencyRepository は冪等性キーの保存と参照を提供する。
+// ttlが正の場合、記録からttlを経過したキーは存在しないものとして扱う。
+type IdempotencyRepository struct {
+	db  *gorm.DB
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewIdempotencyRepository は新しいIdempotencyRepositoryを生成する。ttlが0の場合、記録は期限切れにならない。
+func NewIdempotencyRepository(db *gorm.DB, ttl time.Duration) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db, ttl: ttl, now: time.Now}
+}
+
+// Find は指定されたリクエストパス・冪等性キーの記録を取得する。存在しない場合はnilを返す。
+func (r *IdempotencyRepository) Find(ctx context.Context, requestPath, key string) (*domain.IdempotencyRecord, error) {
+	var model IdempotencyKeyModel
+	err := r.db.WithContext(ctx).
+		Where("request_path = ? AND idempotency_key = ?", requestPath, key).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		slog.ErrorContext(ctx, "failed to find idempotency key",
+			"operation", "find_idempotency_key",
+			"request_path", requestPath,
+			"error", err,
+		)
+		return nil, err
+	}
+	return &domain.IdempotencyRecord{
+		Key:         model.IdempotencyKey,
+		RequestPath: model.RequestPath,
+		RequestHash: model.RequestHash,
+		StatusCode:  model.StatusCode,
+		Body:        model.ResponseBody,
+		CreatedAt:   model.CreatedAt,
+	}, nil
+}
+
+// Reserve は冪等性キーを処理中（status_code=0）として記録する。
+// 主キーの一意性をロックとして使い、同じキーが既に記録されている場合は記録せずに既存の記録を返す。
+// 期限切れの記録は削除してから予約する。
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	now := r.now()
+	if r.ttl > 0 {
+		err := r.db.WithContext(ctx).
+			Where("request_path = ? AND idempotency_key = ? AND created_at < ?", record.RequestPath, record.Key, now.Add(-r.ttl)).
+			Delete(&IdempotencyKeyModel{}).Error
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to delete expired idempotency key",
+				"operation", "reserve_idempotency_key",
+				"request_path", record.RequestPath,
+				"error", err,
+			)
+			return nil, err
+		}
+	}
+
+	model := IdempotencyKeyModel{
+		IdempotencyKey: record.Key,
+		RequestPath:    record.RequestPath,
+		RequestHash:    record.RequestHash,
+		ResponseBody:   []byte{},
+		CreatedAt:      now,
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to reserve idempotency key",
+			"operation", "reserve_idempotency_key",
+			"request_path", record.RequestPath,
+			"error", result.Error,
+		)
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil, nil
+	}
+
+	existing, err := r.Find(ctx, record.RequestPath, record.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		// 競合した予約が直後に解除された場合は処理中として扱い、クライアントに再試行させる
+		return &domain.IdempotencyRecord{
+			Key:         record.Key,
+			RequestPath: record.RequestPath,
+			RequestHash: record.RequestHash,
+		}, nil
+	}
+	return existing, nil
+}
+
+// Complete は処理中の記録に初回レスポンスを記録する。
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	err := r.db.WithContext(ctx).
+		Model(&IdempotencyKeyModel{}).
+		Where("request_path = ? AND idempotency_key = ? AND status_code = 0", record.RequestPath, record.Key).
+		Updates(map[string]any{
+			"status_code":   record.StatusCode,
+			"response_body": record.Body,
+		}).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to save idempotency key",
+			"operation", "save_idempotency_key",
+			"request_path", record.RequestPath,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// Release は処理中の記録を削除する。結果が記録済みのキーは削除しない。
+func (r *IdempotencyRepository) Release(ctx context.Context, requestPath, key string) error {
+	err := r.db.WithContext(ctx).
+		Where("request_path = ? AND idempotency_key = ? AND status_code = 0", requestPath, key).
+		Delete(&IdempotencyKeyModel{}).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to release idempotency key",
+			"operation", "release_idempotency_key",
+			"request_path", requestPath,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired は記録からttlを経過した記録を削除し、削除した件数を返す。ttlが0の場合は何もしない。
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	if r.ttl <= 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("created_at < ?", r.now().Add(-r.ttl)).
+		Delete(&IdempotencyKeyModel{})
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to delete expired idempotency keys",
+			"operation", "delete_expired_idempotency_keys",
+			"error", result.Error,
+		)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// RunCleanup はctxが終了するまでintervalごとに期限切れの記録を削除する。
+func (r *IdempotencyRepository) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := r.DeleteExpired(ctx); err == nil && n > 0 {
+				slog.InfoContext(ctx, "deleted expired idempotency keys",
+					"operation", "delete_expired_idempotency_keys",
+					"count", n,
+				)
+			}
+		}
+	}
+}
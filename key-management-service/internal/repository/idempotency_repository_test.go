@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"key-management-service/internal/domain"
+)
+
+func TestIdempotencyRepository_ReserveAndComplete(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&IdempotencyKeyModel{}); err != nil {
+		t.Fatalf("failed to migrate idempotency_keys table: %v", err)
+	}
+	repo := NewIdempotencyRepository(db, 0)
+	const path = "POST /v1/tenants/tenant-1/keys"
+
+	// 未記録の場合は予約してnilを返す
+	existing, err := repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key-1", RequestPath: path, RequestHash: "hash-1"})
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if existing != nil {
+		t.Fatalf("expected nil record, got %+v", existing)
+	}
+
+	// 処理中のキーは予約できず、処理中の記録を返す
+	existing, err = repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key-1", RequestPath: path, RequestHash: "hash-2"})
+	if err != nil {
+		t.Fatalf("second Reserve failed: %v", err)
+	}
+	if existing == nil || !existing.Pending() || existing.RequestHash != "hash-1" {
+		t.Fatalf("expected pending record with the first hash, got %+v", existing)
+	}
+
+	if err := repo.Complete(ctx, &domain.IdempotencyRecord{
+		Key:         "key-1",
+		RequestPath: path,
+		StatusCode:  201,
+		Body:        []byte(`{"generation":1}`),
+	}); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	// 結果が記録済みのキーは解除されない
+	if err := repo.Release(ctx, path, "key-1"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	record, err := repo.Find(ctx, path, "key-1")
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if record == nil || record.Pending() {
+		t.Fatalf("expected completed record, got %+v", record)
+	}
+	if record.StatusCode != 201 {
+		t.Errorf("expected status 201, got %d", record.StatusCode)
+	}
+	if string(record.Body) != `{"generation":1}` {
+		t.Errorf("expected original body, got %s", record.Body)
+	}
+
+	// パスが異なれば別のキーとして扱う
+	existing, err = repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key-1", RequestPath: path + "/rotate", RequestHash: "hash-1"})
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if existing != nil {
+		t.Errorf("expected nil record for different path, got %+v", existing)
+	}
+}
+
+func TestIdempotencyRepository_Release(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&IdempotencyKeyModel{}); err != nil {
+		t.Fatalf("failed to migrate idempotency_keys table: %v", err)
+	}
+	repo := NewIdempotencyRepository(db, 0)
+	const path = "POST /v1/tenants/tenant-1/keys"
+
+	if _, err := repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key-1", RequestPath: path}); err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if err := repo.Release(ctx, path, "key-1"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	// 解除後は再び予約できる
+	existing, err := repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key-1", RequestPath: path})
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if existing != nil {
+		t.Errorf("expected key to be reservable after release, got %+v", existing)
+	}
+}
+
+func TestIdempotencyRepository_TTL(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&IdempotencyKeyModel{}); err != nil {
+		t.Fatalf("failed to migrate idempotency_keys table: %v", err)
+	}
+	repo := NewIdempotencyRepository(db, time.Hour)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+	const path = "POST /v1/tenants/tenant-1/keys"
+
+	for _, key := range []string{"key-1", "key-2"} {
+		if _, err := repo.Reserve(ctx, &domain.IdempotencyRecord{Key: key, RequestPath: path, RequestHash: "old"}); err != nil {
+			t.Fatalf("Reserve failed: %v", err)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+
+	// 期限切れの記録は存在しないものとして予約し直す
+	existing, err := repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key-1", RequestPath: path, RequestHash: "new"})
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if existing != nil {
+		t.Errorf("expected expired record to be replaced, got %+v", existing)
+	}
+
+	deleted, err := repo.DeleteExpired(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpired failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 expired record deleted, got %d", deleted)
+	}
+	if record, _ := repo.Find(ctx, path, "key-1"); record == nil || record.RequestHash != "new" {
+		t.Errorf("expected the new reservation to remain, got %+v", record)
+	}
+}
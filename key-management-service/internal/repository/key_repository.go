@@ -4,24 +4,37 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"key-management-service/internal/domain"
+	"key-management-service/internal/usecase"
 )
 
 // EncryptionKeyModel はgorm用のモデル定義。
 type EncryptionKeyModel struct {
-	ID           string    `gorm:"type:char(36);primaryKey"`
-	TenantID     string    `gorm:"type:varchar(64);not null;uniqueIndex:uk_tenant_generation;index:idx_tenant_id;index:idx_tenant_status"`
-	Generation   uint      `gorm:"not null;uniqueIndex:uk_tenant_generation"`
-	EncryptedKey []byte    `gorm:"type:blob;not null"`
-	Status       string    `gorm:"type:enum('active','disabled');not null;default:'active';index:idx_tenant_status"`
-	CreatedAt    time.Time `gorm:"type:datetime(6);not null;autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"type:datetime(6);not null;autoUpdateTime"`
+	ID             string     `gorm:"type:char(36);primaryKey"`
+	TenantID       string     `gorm:"type:varchar(64);not null;uniqueIndex:uk_tenant_generation;index:idx_tenant_id;index:idx_tenant_status"`
+	Generation     uint       `gorm:"not null;uniqueIndex:uk_tenant_generation"`
+	KeyType        string     `gorm:"type:varchar(16);not null;default:'aes'"`
+	Bits           int        `gorm:"not null;default:256"`
+	EncryptedKey   []byte     `gorm:"type:bytes;not null"`
+	KMSKeyName     string     `gorm:"column:kms_key_name;type:varchar(512);not null;default:'';index:idx_kms_key_name"`
+	TenantAAD      bool       `gorm:"column:tenant_aad;not null;default:false"`
+	Status         string     `gorm:"type:varchar(16);not null;default:'active';index:idx_tenant_status"`
+	ExpiresAt      *time.Time `gorm:"precision:6"`
+	LastUsedAt     *time.Time `gorm:"precision:6"`
+	DisabledAt     *time.Time `gorm:"precision:6"`
+	DisabledReason string     `gorm:"type:varchar(255);not null;default:''"`
+	CreatedAt      time.Time  `gorm:"precision:6;not null;autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"precision:6;not null;autoUpdateTime"`
+	// DeletedAt は論理削除の日時。設定された行はUnscopedを指定しない限りすべての検索から除外される。
+	DeletedAt gorm.DeletedAt `gorm:"precision:6;index:idx_deleted_at"`
 }
 
 // TableName はテーブル名を返す。
@@ -29,8 +42,13 @@ func (EncryptionKeyModel) TableName() string {
 	return "encryption_keys"
 }
 
-// BeforeCreate はレコード作成前にUUIDを生成する。
+// BeforeCreate はレコード作成前にステータスを検証し、IDが未設定の場合はUUIDを生成する。
+// KeyRepositoryを通した作成ではIDGeneratorでIDを設定済みのため、ここでの生成はモデルを直接保存した場合のみ。
+// status列はデータベースによらず共通のvarcharのため、許可する値はアプリケーション側で検証する。
 func (e *EncryptionKeyModel) BeforeCreate(tx *gorm.DB) error {
+	if e.Status != "" && !domain.KeyStatus(e.Status).IsValid() {
+		return domain.ErrInvalidKeyStatus
+	}
 	if e.ID == "" {
 		e.ID = uuid.New().String()
 	}
@@ -40,30 +58,85 @@ func (e *EncryptionKeyModel) BeforeCreate(tx *gorm.DB) error {
 // toDomain はモデルをドメインエンティティに変換する。
 func (e *EncryptionKeyModel) toDomain() *domain.EncryptionKey {
 	return &domain.EncryptionKey{
-		ID:           e.ID,
-		TenantID:     e.TenantID,
-		Generation:   e.Generation,
-		EncryptedKey: e.EncryptedKey,
-		Status:       domain.KeyStatus(e.Status),
-		CreatedAt:    e.CreatedAt,
-		UpdatedAt:    e.UpdatedAt,
+		ID:             e.ID,
+		TenantID:       e.TenantID,
+		Generation:     e.Generation,
+		KeyType:        domain.KeyType(e.KeyType),
+		Bits:           e.Bits,
+		EncryptedKey:   e.EncryptedKey,
+		KMSKeyName:     e.KMSKeyName,
+		TenantAAD:      e.TenantAAD,
+		Status:         domain.KeyStatus(e.Status),
+		ExpiresAt:      e.ExpiresAt,
+		LastUsedAt:     e.LastUsedAt,
+		DisabledAt:     e.DisabledAt,
+		DisabledReason: e.DisabledReason,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
 	}
 }
 
 // KeyRepository はデータアクセスを提供する。
 type KeyRepository struct {
-	db *gorm.DB
+	db  *gorm.DB
+	ids IDGenerator
+}
+
+// KeyRepositoryOption はKeyRepositoryの設定を変更するオプション。
+type KeyRepositoryOption func(*KeyRepository)
+
+// WithIDGenerator は鍵の主キーの生成方式を設定する。未設定の場合はUUIDを使用する。
+func WithIDGenerator(ids IDGenerator) KeyRepositoryOption {
+	return func(r *KeyRepository) {
+		r.ids = ids
+	}
 }
 
 // NewKeyRepository は新しいKeyRepositoryを生成する。
-func NewKeyRepository(db *gorm.DB) *KeyRepository {
-	return &KeyRepository{db: db}
+func NewKeyRepository(db *gorm.DB, opts ...KeyRepositoryOption) *KeyRepository {
+	r := &KeyRepository{db: db, ids: UUIDGenerator{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// WithTx はfnを1つのトランザクションで実行する。
+// fnに渡すリポジトリはトランザクションに紐づいており、その操作はfnがエラーを返した場合（panicを含む）にすべてロールバックされる。
+// 自身でトランザクションを開始する操作（CreateWithRetentionなど）はセーブポイントとして入れ子になる。
+func (r *KeyRepository) WithTx(ctx context.Context, fn func(txRepo usecase.KeyRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&KeyRepository{db: tx, ids: r.ids})
+	})
+}
+
+// isDuplicateKeyError はerrが一意制約（uk_tenant_generationなど）の違反かどうかを返す。
+// 同じテナント・世代の鍵を同時に保存した場合に、呼び出し元がdomain.ErrGenerationAlreadyExistsとして扱えるようにする。
+// gorm.ConfigのTranslateErrorによらず判定できるよう、ダイアレクトのエラー変換で確認する。
+func (r *KeyRepository) isDuplicateKeyError(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	if t, ok := r.db.Dialector.(gorm.ErrorTranslator); ok {
+		return errors.Is(t.Translate(err), gorm.ErrDuplicatedKey)
+	}
+	return false
+}
+
+// keyID は保存する鍵の主キーを返す。呼び出し元がIDを指定していない場合はIDGeneratorで生成する。
+func (r *KeyRepository) keyID(key *domain.EncryptionKey) string {
+	if key.ID != "" {
+		return key.ID
+	}
+	return r.ids.NewID()
 }
 
 // ExistsByTenantID は指定されたテナントに鍵が存在するか確認する。
+// 論理削除した鍵も世代番号を占有しているため、存在するものとして数える。
 func (r *KeyRepository) ExistsByTenantID(ctx context.Context, tenantID string) (bool, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
+		Unscoped().
 		Model(&EncryptionKeyModel{}).
 		Where("tenant_id = ?", tenantID).
 		Count(&count).Error
@@ -81,13 +154,21 @@ func (r *KeyRepository) ExistsByTenantID(ctx context.Context, tenantID string) (
 // Create は新しい暗号鍵を保存する。
 func (r *KeyRepository) Create(ctx context.Context, key *domain.EncryptionKey) error {
 	model := &EncryptionKeyModel{
-		ID:           key.ID,
+		ID:           r.keyID(key),
 		TenantID:     key.TenantID,
 		Generation:   key.Generation,
+		KeyType:      string(key.KeyType),
+		Bits:         key.Bits,
 		EncryptedKey: key.EncryptedKey,
+		KMSKeyName:   key.KMSKeyName,
+		TenantAAD:    key.TenantAAD,
 		Status:       string(key.Status),
+		ExpiresAt:    key.ExpiresAt,
 	}
 	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		if r.isDuplicateKeyError(err) {
+			return fmt.Errorf("%w: generation %d", domain.ErrGenerationAlreadyExists, key.Generation)
+		}
 		slog.ErrorContext(ctx, "failed to create key",
 			"operation", "create",
 			"tenant_id", key.TenantID,
@@ -103,6 +184,102 @@ func (r *KeyRepository) Create(ctx context.Context, key *domain.EncryptionKey) e
 	return nil
 }
 
+// CreateWithRetention は新しい暗号鍵を保存し、同じトランザクション内で
+// 新しい順にretention件を超える古い有効鍵を無効化する。無効化した世代番号を返す。
+func (r *KeyRepository) CreateWithRetention(ctx context.Context, key *domain.EncryptionKey, retention int, disabledAt time.Time, reason string) ([]uint, error) {
+	model := &EncryptionKeyModel{
+		ID:           r.keyID(key),
+		TenantID:     key.TenantID,
+		Generation:   key.Generation,
+		KeyType:      string(key.KeyType),
+		Bits:         key.Bits,
+		EncryptedKey: key.EncryptedKey,
+		KMSKeyName:   key.KMSKeyName,
+		TenantAAD:    key.TenantAAD,
+		Status:       string(key.Status),
+		ExpiresAt:    key.ExpiresAt,
+	}
+	var disabled []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		var generations []uint
+		if err := tx.Model(&EncryptionKeyModel{}).
+			Where("tenant_id = ? AND status = ?", key.TenantID, string(domain.KeyStatusActive)).
+			Order("generation DESC").
+			Pluck("generation", &generations).Error; err != nil {
+			return err
+		}
+		if len(generations) <= retention {
+			return nil
+		}
+
+		disabled = generations[retention:]
+		return tx.Model(&EncryptionKeyModel{}).
+			Where("tenant_id = ? AND generation IN ?", key.TenantID, disabled).
+			Updates(map[string]any{
+				"status":          string(domain.KeyStatusDisabled),
+				"disabled_at":     disabledAt,
+				"disabled_reason": reason,
+			}).Error
+	})
+	if err != nil {
+		if r.isDuplicateKeyError(err) {
+			return nil, fmt.Errorf("%w: generation %d", domain.ErrGenerationAlreadyExists, key.Generation)
+		}
+		slog.ErrorContext(ctx, "failed to create key with retention",
+			"operation", "create_with_retention",
+			"tenant_id", key.TenantID,
+			"generation", key.Generation,
+			"retention", retention,
+			"error", err,
+		)
+		return nil, err
+	}
+	key.ID = model.ID
+	key.CreatedAt = model.CreatedAt
+	key.UpdatedAt = model.UpdatedAt
+	return disabled, nil
+}
+
+// CreateWithGeneration は世代番号と作成日時を保持したまま鍵を保存する。
+// バックアップからのインポートで使用する。
+func (r *KeyRepository) CreateWithGeneration(ctx context.Context, key *domain.EncryptionKey) error {
+	model := &EncryptionKeyModel{
+		ID:           r.keyID(key),
+		TenantID:     key.TenantID,
+		Generation:   key.Generation,
+		KeyType:      string(key.KeyType),
+		Bits:         key.Bits,
+		EncryptedKey: key.EncryptedKey,
+		KMSKeyName:   key.KMSKeyName,
+		TenantAAD:    key.TenantAAD,
+		Status:       string(key.Status),
+		ExpiresAt:    key.ExpiresAt,
+		CreatedAt:    key.CreatedAt,
+		UpdatedAt:    key.UpdatedAt,
+	}
+	if model.UpdatedAt.IsZero() {
+		model.UpdatedAt = model.CreatedAt
+	}
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		if r.isDuplicateKeyError(err) {
+			return fmt.Errorf("%w: generation %d", domain.ErrGenerationAlreadyExists, key.Generation)
+		}
+		slog.ErrorContext(ctx, "failed to create key with generation",
+			"operation", "create_with_generation",
+			"tenant_id", key.TenantID,
+			"generation", key.Generation,
+			"error", err,
+		)
+		return err
+	}
+	*key = *model.toDomain()
+	return nil
+}
+
 // FindByTenantIDAndGeneration は指定されたテナント・世代の鍵を取得する。
 func (r *KeyRepository) FindByTenantIDAndGeneration(ctx context.Context, tenantID string, generation uint) (*domain.EncryptionKey, error) {
 	var model EncryptionKeyModel
@@ -168,10 +345,240 @@ func (r *KeyRepository) FindAllByTenantID(ctx context.Context, tenantID string)
 	return keys, nil
 }
 
+// FindRecentByTenantID は指定されたテナントの新しい鍵n件を世代の降順で取得する。
+func (r *KeyRepository) FindRecentByTenantID(ctx context.Context, tenantID string, n int) ([]*domain.EncryptionKey, error) {
+	var models []EncryptionKeyModel
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("generation DESC").
+		Limit(n).
+		Find(&models).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find recent keys by tenant_id",
+			"operation", "find_recent_by_tenant_id",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	keys := make([]*domain.EncryptionKey, len(models))
+	for i, m := range models {
+		keys[i] = m.toDomain()
+	}
+	return keys, nil
+}
+
+// FindRecentUsableByTenantID は指定されたテナントの無効化されていないkeyTypeの鍵のうち、新しいn件を世代の降順で取得する。
+func (r *KeyRepository) FindRecentUsableByTenantID(ctx context.Context, tenantID string, keyType domain.KeyType, n int) ([]*domain.EncryptionKey, error) {
+	var models []EncryptionKeyModel
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND key_type = ? AND status <> ?", tenantID, string(keyType), string(domain.KeyStatusDisabled)).
+		Order("generation DESC").
+		Limit(n).
+		Find(&models).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find recent usable keys by tenant_id",
+			"operation", "find_recent_usable_by_tenant_id",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	keys := make([]*domain.EncryptionKey, len(models))
+	for i, m := range models {
+		keys[i] = m.toDomain()
+	}
+	return keys, nil
+}
+
+// FindByTenantIDAndGenerations は指定されたテナントの複数世代の鍵を1回のクエリで取得する。
+// 存在しない世代は結果に含まれない。
+func (r *KeyRepository) FindByTenantIDAndGenerations(ctx context.Context, tenantID string, generations []uint) ([]*domain.EncryptionKey, error) {
+	var models []EncryptionKeyModel
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND generation IN ?", tenantID, generations).
+		Order("generation ASC").
+		Find(&models).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find keys by generations",
+			"operation", "find_by_tenant_id_and_generations",
+			"tenant_id", tenantID,
+			"count", len(generations),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	keys := make([]*domain.EncryptionKey, len(models))
+	for i, m := range models {
+		keys[i] = m.toDomain()
+	}
+	return keys, nil
+}
+
+// FindByTenantIDWithFilter は指定されたテナントの鍵を絞り込み条件付きで取得する。
+func (r *KeyRepository) FindByTenantIDWithFilter(ctx context.Context, tenantID string, filter domain.KeyFilter) ([]*domain.EncryptionKey, error) {
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.MinGeneration > 0 {
+		query = query.Where("generation >= ?", filter.MinGeneration)
+	}
+	if filter.MaxGeneration > 0 {
+		query = query.Where("generation <= ?", filter.MaxGeneration)
+	}
+
+	var models []EncryptionKeyModel
+	if err := query.Order("generation ASC").Find(&models).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to find keys with filter",
+			"operation", "find_by_tenant_id_with_filter",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	keys := make([]*domain.EncryptionKey, len(models))
+	for i, m := range models {
+		keys[i] = m.toDomain()
+	}
+	return keys, nil
+}
+
+// CountByTenantIDGroupedByStatus は指定されたテナントの鍵数をステータスごとに集計する。
+func (r *KeyRepository) CountByTenantIDGroupedByStatus(ctx context.Context, tenantID string) (map[domain.KeyStatus]int, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&EncryptionKeyModel{}).
+		Select("status, COUNT(*) AS count").
+		Where("tenant_id = ?", tenantID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count keys by status",
+			"operation", "count_by_tenant_id_grouped_by_status",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	counts := make(map[domain.KeyStatus]int, len(rows))
+	for _, row := range rows {
+		counts[domain.KeyStatus(row.Status)] = row.Count
+	}
+	return counts, nil
+}
+
+// BackfillStatus はステータスが空（NULLを含む）の鍵にステータスを設定し、設定したステータスごとの件数を返す。
+// 無効化日時が記録されている鍵はdisabled、それ以外はactiveとする。
+// dryRunの場合は対象の件数のみを数えて更新しない。件数の集計と更新は1つのトランザクションで行う。
+func (r *KeyRepository) BackfillStatus(ctx context.Context, dryRun bool) (map[domain.KeyStatus]int, error) {
+	targets := []struct {
+		status    domain.KeyStatus
+		condition string
+	}{
+		{status: domain.KeyStatusActive, condition: "disabled_at IS NULL"},
+		{status: domain.KeyStatusDisabled, condition: "disabled_at IS NOT NULL"},
+	}
+	counts := make(map[domain.KeyStatus]int, len(targets))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, t := range targets {
+			query := tx.Model(&EncryptionKeyModel{}).
+				Where("(status IS NULL OR status = '') AND " + t.condition)
+			if dryRun {
+				var n int64
+				if err := query.Count(&n).Error; err != nil {
+					return err
+				}
+				counts[t.status] = int(n)
+				continue
+			}
+			result := query.Update("status", string(t.status))
+			if result.Error != nil {
+				return result.Error
+			}
+			counts[t.status] = int(result.RowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to backfill key status",
+			"operation", "backfill_status",
+			"dry_run", dryRun,
+			"error", err,
+		)
+		return nil, err
+	}
+	return counts, nil
+}
+
+// ListTenantIDs は鍵が存在するテナントIDをID順に取得する。
+// tenant_idのインデックスを利用できるようDISTINCTとORDER BYは同一カラムに限定する。
+func (r *KeyRepository) ListTenantIDs(ctx context.Context, limit, offset int) ([]string, error) {
+	var tenantIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&EncryptionKeyModel{}).
+		Distinct("tenant_id").
+		Order("tenant_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Pluck("tenant_id", &tenantIDs).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list tenant ids",
+			"operation", "list_tenant_ids",
+			"limit", limit,
+			"offset", offset,
+			"error", err,
+		)
+		return nil, err
+	}
+	return tenantIDs, nil
+}
+
+// CountByTenantIDs は指定された複数テナントの鍵数をまとめて取得する。
+func (r *KeyRepository) CountByTenantIDs(ctx context.Context, tenantIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(tenantIDs))
+	if len(tenantIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TenantID string
+		Count    int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&EncryptionKeyModel{}).
+		Select("tenant_id, COUNT(*) AS count").
+		Where("tenant_id IN ?", tenantIDs).
+		Group("tenant_id").
+		Scan(&rows).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count keys by tenant ids",
+			"operation", "count_by_tenant_ids",
+			"error", err,
+		)
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.TenantID] = row.Count
+	}
+	return counts, nil
+}
+
 // GetMaxGeneration は指定されたテナントの最大世代番号を取得する。
+// 論理削除した鍵も一意制約の対象のため含める（復元時に世代番号が重複しないようにする）。
 func (r *KeyRepository) GetMaxGeneration(ctx context.Context, tenantID string) (uint, error) {
 	var maxGen *uint
 	err := r.db.WithContext(ctx).
+		Unscoped().
 		Model(&EncryptionKeyModel{}).
 		Where("tenant_id = ?", tenantID).
 		Select("MAX(generation)").
@@ -190,8 +597,72 @@ func (r *KeyRepository) GetMaxGeneration(ctx context.Context, tenantID string) (
 	return *maxGen, nil
 }
 
+// Disable は指定されたIDの鍵を無効化し、無効化日時と理由を記録する。
+func (r *KeyRepository) Disable(ctx context.Context, id string, disabledAt time.Time, reason string) error {
+	err := r.db.WithContext(ctx).
+		Model(&EncryptionKeyModel{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          string(domain.KeyStatusDisabled),
+			"disabled_at":     disabledAt,
+			"disabled_reason": reason,
+			// 無効化日時と揃え、サービス層が返すメタデータと保存値を一致させる
+			"updated_at": disabledAt,
+		}).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to disable key",
+			"operation", "disable",
+			"id", id,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// SoftDelete は指定されたテナント・世代の鍵を論理削除し、削除したかどうかを返す。
+// 行は残るためRestoreで元に戻せる。存在しない・削除済みの場合はfalseを返す。
+func (r *KeyRepository) SoftDelete(ctx context.Context, tenantID string, generation uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND generation = ?", tenantID, generation).
+		Delete(&EncryptionKeyModel{})
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to soft delete key",
+			"operation", "soft_delete",
+			"tenant_id", tenantID,
+			"generation", generation,
+			"error", result.Error,
+		)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Restore は論理削除した鍵を元に戻し、戻したかどうかを返す。
+// 存在しない・削除されていない場合はfalseを返す。
+func (r *KeyRepository) Restore(ctx context.Context, tenantID string, generation uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&EncryptionKeyModel{}).
+		Where("tenant_id = ? AND generation = ? AND deleted_at IS NOT NULL", tenantID, generation).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to restore key",
+			"operation", "restore",
+			"tenant_id", tenantID,
+			"generation", generation,
+			"error", result.Error,
+		)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // UpdateStatus は指定されたIDの鍵のステータスを更新する。
 func (r *KeyRepository) UpdateStatus(ctx context.Context, id string, status domain.KeyStatus) error {
+	if !status.IsValid() {
+		return domain.ErrInvalidKeyStatus
+	}
 	err := r.db.WithContext(ctx).
 		Model(&EncryptionKeyModel{}).
 		Where("id = ?", id).
@@ -207,3 +678,125 @@ func (r *KeyRepository) UpdateStatus(ctx context.Context, id string, status doma
 	}
 	return nil
 }
+
+// UpdateStatusBatch は指定されたテナント・世代の無効化されていない鍵（有効・非推奨）を1トランザクションで無効化する。
+// 戻り値は更新前のステータスを世代ごとに保持したもので、存在しない世代は含まない。
+func (r *KeyRepository) UpdateStatusBatch(ctx context.Context, tenantID string, generations []uint, disabledAt time.Time, reason string) (map[uint]domain.KeyStatus, error) {
+	previous := make(map[uint]domain.KeyStatus, len(generations))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			Generation uint
+			Status     string
+		}
+		if err := tx.Model(&EncryptionKeyModel{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("generation", "status").
+			Where("tenant_id = ? AND generation IN ?", tenantID, generations).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		var active []uint
+		for _, row := range rows {
+			previous[row.Generation] = domain.KeyStatus(row.Status)
+			// 非推奨の鍵も復号には使えるため、有効な鍵と同様に無効化する
+			if row.Status != string(domain.KeyStatusDisabled) {
+				active = append(active, row.Generation)
+			}
+		}
+		if len(active) == 0 {
+			return nil
+		}
+		return tx.Model(&EncryptionKeyModel{}).
+			Where("tenant_id = ? AND generation IN ?", tenantID, active).
+			Updates(map[string]any{
+				"status":          string(domain.KeyStatusDisabled),
+				"disabled_at":     disabledAt,
+				"disabled_reason": reason,
+				"updated_at":      disabledAt,
+			}).Error
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to disable keys",
+			"operation", "update_status_batch",
+			"tenant_id", tenantID,
+			"count", len(generations),
+			"error", err,
+		)
+		return nil, err
+	}
+	return previous, nil
+}
+
+// FindByKMSKeyName は指定されたKMS鍵でラップされた鍵をテナント・世代順に取得する。
+// 無効化済みの鍵も対象とする。
+func (r *KeyRepository) FindByKMSKeyName(ctx context.Context, kmsKeyName string, limit, offset int) ([]*domain.EncryptionKey, error) {
+	var models []EncryptionKeyModel
+	err := r.db.WithContext(ctx).
+		Where("kms_key_name = ?", kmsKeyName).
+		Order("tenant_id ASC").
+		Order("generation ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&models).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find keys by kms_key_name",
+			"operation", "find_by_kms_key_name",
+			"kms_key_name", kmsKeyName,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	keys := make([]*domain.EncryptionKey, len(models))
+	for i, m := range models {
+		keys[i] = m.toDomain()
+	}
+	return keys, nil
+}
+
+// UpdateWrappedKey は再ラップした鍵の暗号文・KMS鍵名・テナントIDに紐づけたかを更新する。
+// 取得後に他の処理で更新された場合に上書きしないよう、元のKMS鍵名のままの行のみを更新し、
+// 更新したかどうかを返す。
+func (r *KeyRepository) UpdateWrappedKey(ctx context.Context, id, fromKMSKeyName string, encryptedKey []byte, kmsKeyName string, tenantAAD bool) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&EncryptionKeyModel{}).
+		Where("id = ? AND kms_key_name = ?", id, fromKMSKeyName).
+		Updates(map[string]any{
+			"encrypted_key": encryptedKey,
+			"kms_key_name":  kmsKeyName,
+			"tenant_aad":    tenantAAD,
+		})
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to update wrapped key",
+			"operation", "update_wrapped_key",
+			"id", id,
+			"error", result.Error,
+		)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateLastUsedAt は鍵IDごとの最終利用日時をまとめて更新する。
+// 既に記録されている日時より古い値では更新しない。最終利用日時は鍵の変更ではないため、updated_atは変更しない。
+func (r *KeyRepository) UpdateLastUsedAt(ctx context.Context, lastUsed map[string]time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, at := range lastUsed {
+			if err := tx.Model(&EncryptionKeyModel{}).
+				Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at).
+				UpdateColumn("last_used_at", at).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update last used time",
+			"operation", "update_last_used_at",
+			"keys", len(lastUsed),
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"key-management-service/internal/domain"
+	"key-management-service/internal/usecase"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -19,39 +24,36 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("failed to open test database: %v", err)
 	}
 
-	// encryption_keysテーブルを作成（SQLite用にENUM→TEXT変換）
-	sql := `
-		CREATE TABLE encryption_keys (
-			id TEXT PRIMARY KEY,
-			tenant_id TEXT NOT NULL,
-			generation INTEGER NOT NULL,
-			encrypted_key BLOB NOT NULL,
-			status TEXT NOT NULL DEFAULT 'active',
-			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(tenant_id, generation)
-		);
-		CREATE INDEX idx_tenant_id ON encryption_keys(tenant_id);
-		CREATE INDEX idx_tenant_status ON encryption_keys(tenant_id, status);
-	`
-
-	if err := db.Exec(sql).Error; err != nil {
-		t.Fatalf("failed to create encryption_keys table: %v", err)
+	// 本番と同じモデル定義からスキーマを作成する
+	if err := db.AutoMigrate(&EncryptionKeyModel{}); err != nil {
+		t.Fatalf("failed to migrate encryption_keys table: %v", err)
 	}
 
 	return db
 }
 
+// insertTestKey はモデル定義を通してテスト用の鍵を保存する。
+func insertTestKey(t *testing.T, db *gorm.DB, id, tenantID string, generation uint, status domain.KeyStatus) {
+	t.Helper()
+	model := &EncryptionKeyModel{
+		ID:           id,
+		TenantID:     tenantID,
+		Generation:   generation,
+		EncryptedKey: []byte("encrypted-key"),
+		Status:       string(status),
+	}
+	if err := db.Create(model).Error; err != nil {
+		t.Fatalf("failed to insert test data: %v", err)
+	}
+}
+
 func TestKeyRepository_ExistsByTenantID(t *testing.T) {
 	ctx := context.Background()
 	db := setupTestDB(t)
 	repo := NewKeyRepository(db)
 
 	// テストデータを挿入
-	if err := db.Exec("INSERT INTO encryption_keys (id, tenant_id, generation, encrypted_key, status) VALUES (?, ?, ?, ?, ?)",
-		"test-id-1", "tenant-1", 1, []byte("encrypted-key-1"), "active").Error; err != nil {
-		t.Fatalf("failed to insert test data: %v", err)
-	}
+	insertTestKey(t, db, "test-id-1", "tenant-1", 1, domain.KeyStatusActive)
 
 	// テナントに鍵が存在する場合
 	exists, err := repo.ExistsByTenantID(ctx, "tenant-1")
@@ -112,16 +114,171 @@ func TestKeyRepository_Create(t *testing.T) {
 	}
 }
 
+// sequenceIDGenerator は連番のIDを返すテスト用のIDGenerator。
+type sequenceIDGenerator struct {
+	n int
+}
+
+func (g *sequenceIDGenerator) NewID() string {
+	g.n++
+	return fmt.Sprintf("test-id-%d", g.n)
+}
+
+func TestKeyRepository_UsesIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db, WithIDGenerator(&sequenceIDGenerator{}))
+
+	newKey := func(generation uint) *domain.EncryptionKey {
+		return &domain.EncryptionKey{
+			TenantID:     "tenant-1",
+			Generation:   generation,
+			EncryptedKey: []byte("encrypted-key"),
+			Status:       domain.KeyStatusActive,
+			CreatedAt:    time.Now(),
+		}
+	}
+
+	// 作成経路によらず注入したIDGeneratorでIDを生成する
+	created := newKey(1)
+	if err := repo.Create(ctx, created); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	rotated := newKey(2)
+	if _, err := repo.CreateWithRetention(ctx, rotated, 10, time.Now(), "rotated"); err != nil {
+		t.Fatalf("CreateWithRetention failed: %v", err)
+	}
+	imported := newKey(3)
+	if err := repo.CreateWithGeneration(ctx, imported); err != nil {
+		t.Fatalf("CreateWithGeneration failed: %v", err)
+	}
+	for i, key := range []*domain.EncryptionKey{created, rotated, imported} {
+		if want := fmt.Sprintf("test-id-%d", i+1); key.ID != want {
+			t.Errorf("generation %d: want ID %s, got %s", key.Generation, want, key.ID)
+		}
+	}
+
+	// 呼び出し元が指定したIDはそのまま使用する
+	explicit := newKey(4)
+	explicit.ID = "explicit-id"
+	if err := repo.Create(ctx, explicit); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	found, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 4)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if found.ID != "explicit-id" {
+		t.Errorf("want ID explicit-id, got %s", found.ID)
+	}
+}
+
+func TestKeyRepository_Create_AutoMigratedSchema(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	// 本番と同じモデル定義で作成したスキーマに対して保存と取得ができる
+	key := &domain.EncryptionKey{
+		TenantID:     "tenant-1",
+		Generation:   1,
+		KeyType:      domain.KeyTypeHMAC,
+		Bits:         512,
+		EncryptedKey: []byte("encrypted-key-1"),
+		Status:       domain.KeyStatusActive,
+	}
+	if err := repo.Create(ctx, key); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	found, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 1)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if found.ID != key.ID || found.KeyType != domain.KeyTypeHMAC || found.Bits != 512 || found.Status != domain.KeyStatusActive {
+		t.Errorf("unexpected key: %+v", found)
+	}
+	if !found.CreatedAt.Equal(key.CreatedAt) {
+		t.Errorf("expected CreatedAt %v, got %v", key.CreatedAt, found.CreatedAt)
+	}
+	if found.DisabledAt != nil {
+		t.Errorf("expected DisabledAt nil, got %v", found.DisabledAt)
+	}
+
+	// 同一テナント・世代の重複はユニーク制約で拒否される
+	dup := &domain.EncryptionKey{TenantID: "tenant-1", Generation: 1, EncryptedKey: []byte("dup"), Status: domain.KeyStatusActive}
+	if err := repo.Create(ctx, dup); err == nil {
+		t.Error("expected unique constraint error, got nil")
+	}
+
+	// 定義外のステータスはアプリケーション側で拒否される
+	invalid := &domain.EncryptionKey{TenantID: "tenant-1", Generation: 2, EncryptedKey: []byte("invalid"), Status: "revoked"}
+	if err := repo.Create(ctx, invalid); !errors.Is(err, domain.ErrInvalidKeyStatus) {
+		t.Errorf("expected ErrInvalidKeyStatus, got %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, key.ID, "revoked"); !errors.Is(err, domain.ErrInvalidKeyStatus) {
+		t.Errorf("expected ErrInvalidKeyStatus from UpdateStatus, got %v", err)
+	}
+}
+
+func TestKeyRepository_KMSKeyName(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	const oldKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/old"
+	const newKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/new"
+
+	// 作成・ローテーション・インポートのいずれでもKMS鍵名が保存される
+	if err := repo.Create(ctx, &domain.EncryptionKey{
+		TenantID: "tenant-1", Generation: 1, EncryptedKey: []byte("k1"), KMSKeyName: oldKMSKey, Status: domain.KeyStatusActive,
+	}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := repo.CreateWithRetention(ctx, &domain.EncryptionKey{
+		TenantID: "tenant-1", Generation: 2, EncryptedKey: []byte("k2"), KMSKeyName: newKMSKey, Status: domain.KeyStatusActive,
+	}, 0, time.Now(), ""); err != nil {
+		t.Fatalf("CreateWithRetention failed: %v", err)
+	}
+	if err := repo.CreateWithGeneration(ctx, &domain.EncryptionKey{
+		TenantID: "tenant-1", Generation: 3, EncryptedKey: []byte("k3"), KMSKeyName: newKMSKey, Status: domain.KeyStatusActive, CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("CreateWithGeneration failed: %v", err)
+	}
+	// 記録前に作成された鍵は空文字として扱われる
+	insertTestKey(t, db, "legacy-id", "tenant-1", 4, domain.KeyStatusActive)
+
+	var stored string
+	if err := db.Model(&EncryptionKeyModel{}).Where("tenant_id = ? AND generation = ?", "tenant-1", 1).
+		Pluck("kms_key_name", &stored).Error; err != nil {
+		t.Fatalf("reading kms_key_name column: %v", err)
+	}
+	if stored != oldKMSKey {
+		t.Errorf("expected kms_key_name column %q, got %q", oldKMSKey, stored)
+	}
+
+	keys, err := repo.FindAllByTenantID(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("FindAllByTenantID failed: %v", err)
+	}
+	want := map[uint]string{1: oldKMSKey, 2: newKMSKey, 3: newKMSKey, 4: ""}
+	if len(keys) != len(want) {
+		t.Fatalf("expected %d keys, got %d", len(want), len(keys))
+	}
+	for _, k := range keys {
+		if k.KMSKeyName != want[k.Generation] {
+			t.Errorf("generation %d: expected KMSKeyName %q, got %q", k.Generation, want[k.Generation], k.KMSKeyName)
+		}
+	}
+}
+
 func TestKeyRepository_FindByTenantIDAndGeneration(t *testing.T) {
 	ctx := context.Background()
 	db := setupTestDB(t)
 	repo := NewKeyRepository(db)
 
 	// テストデータを挿入
-	if err := db.Exec("INSERT INTO encryption_keys (id, tenant_id, generation, encrypted_key, status) VALUES (?, ?, ?, ?, ?)",
-		"test-id-1", "tenant-1", 1, []byte("encrypted-key-1"), "active").Error; err != nil {
-		t.Fatalf("failed to insert test data: %v", err)
-	}
+	insertTestKey(t, db, "test-id-1", "tenant-1", 1, domain.KeyStatusActive)
 
 	// 鍵が存在する場合
 	key, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 1)
@@ -165,10 +322,7 @@ func TestKeyRepository_FindLatestActiveByTenantID(t *testing.T) {
 	}
 
 	for _, data := range testData {
-		if err := db.Exec("INSERT INTO encryption_keys (id, tenant_id, generation, encrypted_key, status) VALUES (?, ?, ?, ?, ?)",
-			data.id, "tenant-1", data.generation, []byte("encrypted-key"), data.status).Error; err != nil {
-			t.Fatalf("failed to insert test data: %v", err)
-		}
+		insertTestKey(t, db, data.id, "tenant-1", data.generation, domain.KeyStatus(data.status))
 	}
 
 	// 最新有効鍵を返す（generation=2）
@@ -196,6 +350,35 @@ func TestKeyRepository_FindLatestActiveByTenantID(t *testing.T) {
 	}
 }
 
+func TestKeyRepository_FindLatestActiveByTenantID_SkipsDeprecated(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+	insertTestKey(t, db, "id-1", "tenant-1", 1, domain.KeyStatusActive)
+	insertTestKey(t, db, "id-2", "tenant-1", 2, domain.KeyStatusDeprecated)
+
+	latest, err := repo.FindLatestActiveByTenantID(ctx, "tenant-1")
+	if err != nil || latest == nil || latest.Generation != 1 {
+		t.Errorf("want generation 1 as the current key, got %+v (%v)", latest, err)
+	}
+	key, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 2)
+	if err != nil || key == nil || key.Status != domain.KeyStatusDeprecated {
+		t.Errorf("want deprecated generation 2 to remain fetchable, got %+v (%v)", key, err)
+	}
+
+	// 非推奨の鍵も一括無効化の対象になる
+	previous, err := repo.UpdateStatusBatch(ctx, "tenant-1", []uint{2}, time.Now(), "retired")
+	if err != nil {
+		t.Fatalf("UpdateStatusBatch failed: %v", err)
+	}
+	if previous[2] != domain.KeyStatusDeprecated {
+		t.Errorf("want previous status deprecated, got %s", previous[2])
+	}
+	if key, _ := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 2); key == nil || key.Status != domain.KeyStatusDisabled {
+		t.Errorf("want generation 2 disabled, got %+v", key)
+	}
+}
+
 func TestKeyRepository_FindAllByTenantID(t *testing.T) {
 	ctx := context.Background()
 	db := setupTestDB(t)
@@ -204,10 +387,7 @@ func TestKeyRepository_FindAllByTenantID(t *testing.T) {
 	// テストデータを挿入（順不同）
 	testData := []uint{3, 1, 2}
 	for _, gen := range testData {
-		if err := db.Exec("INSERT INTO encryption_keys (id, tenant_id, generation, encrypted_key, status) VALUES (?, ?, ?, ?, ?)",
-			"test-id-"+string(rune(gen)), "tenant-1", gen, []byte("encrypted-key"), "active").Error; err != nil {
-			t.Fatalf("failed to insert test data: %v", err)
-		}
+		insertTestKey(t, db, "test-id-"+string(rune(gen)), "tenant-1", uint(gen), domain.KeyStatusActive)
 	}
 
 	// 複数鍵を世代順に返す
@@ -237,6 +417,87 @@ func TestKeyRepository_FindAllByTenantID(t *testing.T) {
 	}
 }
 
+func TestKeyRepository_FindRecentByTenantID(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	// テストデータを挿入（順不同）。他のテナントの鍵は含めない
+	for _, gen := range []uint{4, 1, 7, 3, 6, 2, 5} {
+		insertTestKey(t, db, fmt.Sprintf("tenant-1-%d", gen), "tenant-1", gen, domain.KeyStatusActive)
+	}
+	insertTestKey(t, db, "tenant-2-8", "tenant-2", 8, domain.KeyStatusActive)
+
+	tests := []struct {
+		name string
+		n    int
+		want []uint
+	}{
+		{name: "新しいn件を降順で返す", n: 3, want: []uint{7, 6, 5}},
+		{name: "1件", n: 1, want: []uint{7}},
+		{name: "鍵の数より多い場合は全件", n: 10, want: []uint{7, 6, 5, 4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := repo.FindRecentByTenantID(ctx, "tenant-1", tt.n)
+			if err != nil {
+				t.Fatalf("FindRecentByTenantID failed: %v", err)
+			}
+			got := make([]uint, len(keys))
+			for i, k := range keys {
+				if k.TenantID != "tenant-1" {
+					t.Errorf("keys[%d]: expected tenant-1, got %s", i, k.TenantID)
+				}
+				got[i] = k.Generation
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected generations %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// 鍵がない場合
+	keys, err := repo.FindRecentByTenantID(ctx, "tenant-3", 5)
+	if err != nil {
+		t.Fatalf("FindRecentByTenantID failed: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("expected empty slice, got %d keys", len(keys))
+	}
+}
+
+func TestKeyRepository_FindRecentUsableByTenantID(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	for gen := uint(1); gen <= 6; gen++ {
+		insertTestKey(t, db, fmt.Sprintf("tenant-1-%d", gen), "tenant-1", gen, domain.KeyStatusActive)
+	}
+	// 無効化された鍵とAES以外の鍵は件数に数えない
+	if err := db.Model(&EncryptionKeyModel{}).Where("generation = ?", 6).Update("status", string(domain.KeyStatusDisabled)).Error; err != nil {
+		t.Fatalf("failed to disable key: %v", err)
+	}
+	if err := db.Model(&EncryptionKeyModel{}).Where("generation = ?", 5).Update("key_type", string(domain.KeyTypeHMAC)).Error; err != nil {
+		t.Fatalf("failed to update key type: %v", err)
+	}
+	if err := db.Model(&EncryptionKeyModel{}).Where("generation = ?", 4).Update("status", string(domain.KeyStatusDeprecated)).Error; err != nil {
+		t.Fatalf("failed to deprecate key: %v", err)
+	}
+
+	keys, err := repo.FindRecentUsableByTenantID(ctx, "tenant-1", domain.KeyTypeAES, 2)
+	if err != nil {
+		t.Fatalf("FindRecentUsableByTenantID failed: %v", err)
+	}
+	got := make([]uint, len(keys))
+	for i, k := range keys {
+		got[i] = k.Generation
+	}
+	if want := []uint{4, 3}; !slices.Equal(got, want) {
+		t.Errorf("expected generations %v, got %v", want, got)
+	}
+}
+
 func TestKeyRepository_GetMaxGeneration(t *testing.T) {
 	ctx := context.Background()
 	db := setupTestDB(t)
@@ -244,10 +505,7 @@ func TestKeyRepository_GetMaxGeneration(t *testing.T) {
 
 	// テストデータを挿入
 	for gen := uint(1); gen <= 3; gen++ {
-		if err := db.Exec("INSERT INTO encryption_keys (id, tenant_id, generation, encrypted_key, status) VALUES (?, ?, ?, ?, ?)",
-			"test-id-"+string(rune(gen)), "tenant-1", gen, []byte("encrypted-key"), "active").Error; err != nil {
-			t.Fatalf("failed to insert test data: %v", err)
-		}
+		insertTestKey(t, db, "test-id-"+string(rune(gen)), "tenant-1", uint(gen), domain.KeyStatusActive)
 	}
 
 	// 鍵がある場合
@@ -269,18 +527,98 @@ func TestKeyRepository_GetMaxGeneration(t *testing.T) {
 	}
 }
 
-func TestKeyRepository_UpdateStatus(t *testing.T) {
+func TestKeyRepository_Disable(t *testing.T) {
 	ctx := context.Background()
 	db := setupTestDB(t)
 	repo := NewKeyRepository(db)
 
 	// テストデータを挿入
 	testID := "test-id-1"
-	if err := db.Exec("INSERT INTO encryption_keys (id, tenant_id, generation, encrypted_key, status) VALUES (?, ?, ?, ?, ?)",
-		testID, "tenant-1", 1, []byte("encrypted-key"), "active").Error; err != nil {
-		t.Fatalf("failed to insert test data: %v", err)
+	insertTestKey(t, db, testID, "tenant-1", 1, domain.KeyStatusActive)
+
+	disabledAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := repo.Disable(ctx, testID, disabledAt, "compromised"); err != nil {
+		t.Fatalf("Disable failed: %v", err)
+	}
+
+	key, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 1)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if key.Status != domain.KeyStatusDisabled {
+		t.Errorf("expected status=disabled, got %s", key.Status)
+	}
+	if key.DisabledAt == nil || !key.DisabledAt.Equal(disabledAt) {
+		t.Errorf("expected disabled_at=%v, got %v", disabledAt, key.DisabledAt)
+	}
+	if key.DisabledReason != "compromised" {
+		t.Errorf("expected disabled_reason=compromised, got %s", key.DisabledReason)
+	}
+}
+
+func TestKeyRepository_UpdateStatusBatch(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	insertTestKey(t, db, "id-1", "tenant-1", 1, domain.KeyStatusActive)
+	insertTestKey(t, db, "id-2", "tenant-1", 2, domain.KeyStatusDisabled)
+	insertTestKey(t, db, "id-3", "tenant-1", 3, domain.KeyStatusActive)
+	// 別テナントの同じ世代は対象外
+	insertTestKey(t, db, "id-other", "tenant-2", 1, domain.KeyStatusActive)
+
+	disabledAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	previous, err := repo.UpdateStatusBatch(ctx, "tenant-1", []uint{1, 2, 4}, disabledAt, "compromised")
+	if err != nil {
+		t.Fatalf("UpdateStatusBatch failed: %v", err)
+	}
+	if len(previous) != 2 || previous[1] != domain.KeyStatusActive || previous[2] != domain.KeyStatusDisabled {
+		t.Errorf("unexpected previous statuses: %v", previous)
+	}
+
+	key, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 1)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if key.Status != domain.KeyStatusDisabled || key.DisabledReason != "compromised" {
+		t.Errorf("expected generation 1 disabled with reason, got %s %q", key.Status, key.DisabledReason)
+	}
+	if key.DisabledAt == nil || !key.DisabledAt.Equal(disabledAt) {
+		t.Errorf("expected disabled_at=%v, got %v", disabledAt, key.DisabledAt)
+	}
+
+	// 既に無効化済みの世代は無効化日時・理由を上書きしない
+	key, err = repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 2)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if key.DisabledReason == "compromised" {
+		t.Error("expected already disabled key to keep its reason")
 	}
 
+	for _, tc := range []struct {
+		tenantID   string
+		generation uint
+	}{{"tenant-1", 3}, {"tenant-2", 1}} {
+		key, err := repo.FindByTenantIDAndGeneration(ctx, tc.tenantID, tc.generation)
+		if err != nil {
+			t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+		}
+		if key.Status != domain.KeyStatusActive {
+			t.Errorf("expected %s generation %d to stay active, got %s", tc.tenantID, tc.generation, key.Status)
+		}
+	}
+}
+
+func TestKeyRepository_UpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	// テストデータを挿入
+	testID := "test-id-1"
+	insertTestKey(t, db, testID, "tenant-1", 1, domain.KeyStatusActive)
+
 	// ステータスを更新
 	if err := repo.UpdateStatus(ctx, testID, domain.KeyStatusDisabled); err != nil {
 		t.Fatalf("UpdateStatus failed: %v", err)
@@ -295,3 +633,550 @@ func TestKeyRepository_UpdateStatus(t *testing.T) {
 		t.Errorf("expected status=disabled, got %s", model.Status)
 	}
 }
+
+func TestKeyRepository_CreateWithGeneration(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	// 世代番号と作成日時を保持して保存される
+	createdAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	key := &domain.EncryptionKey{
+		TenantID:     "tenant-1",
+		Generation:   7,
+		EncryptedKey: []byte("wrapped-key-7"),
+		Status:       domain.KeyStatusDisabled,
+		CreatedAt:    createdAt,
+	}
+	if err := repo.CreateWithGeneration(ctx, key); err != nil {
+		t.Fatalf("CreateWithGeneration failed: %v", err)
+	}
+	if key.ID == "" {
+		t.Error("expected ID to be generated, got empty")
+	}
+
+	found, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 7)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if found == nil {
+		t.Fatal("expected key, got nil")
+	}
+	if !found.CreatedAt.Equal(createdAt) {
+		t.Errorf("expected created_at=%v, got %v", createdAt, found.CreatedAt)
+	}
+	if found.Status != domain.KeyStatusDisabled {
+		t.Errorf("expected status=disabled, got %s", found.Status)
+	}
+
+	// 同じ世代は一意制約で拒否され、ErrGenerationAlreadyExistsとして返る
+	dup := &domain.EncryptionKey{
+		TenantID:     "tenant-1",
+		Generation:   7,
+		EncryptedKey: []byte("wrapped-key-dup"),
+		Status:       domain.KeyStatusActive,
+		CreatedAt:    createdAt,
+	}
+	if err := repo.CreateWithGeneration(ctx, dup); !errors.Is(err, domain.ErrGenerationAlreadyExists) {
+		t.Errorf("expected ErrGenerationAlreadyExists, got %v", err)
+	}
+}
+
+func TestKeyRepository_CountByTenantIDGroupedByStatus(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	// テストデータを挿入（ステータス混在）
+	testData := []struct {
+		id         string
+		generation uint
+		status     string
+	}{
+		{"test-id-1", 1, "disabled"},
+		{"test-id-2", 2, "disabled"},
+		{"test-id-3", 3, "active"},
+		{"test-id-4", 4, "active"},
+		{"test-id-5", 5, "active"},
+	}
+	for _, data := range testData {
+		insertTestKey(t, db, data.id, "tenant-1", data.generation, domain.KeyStatus(data.status))
+	}
+
+	counts, err := repo.CountByTenantIDGroupedByStatus(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("CountByTenantIDGroupedByStatus failed: %v", err)
+	}
+	if counts[domain.KeyStatusActive] != 3 {
+		t.Errorf("expected active=3, got %d", counts[domain.KeyStatusActive])
+	}
+	if counts[domain.KeyStatusDisabled] != 2 {
+		t.Errorf("expected disabled=2, got %d", counts[domain.KeyStatusDisabled])
+	}
+
+	// 鍵がない場合
+	counts, err = repo.CountByTenantIDGroupedByStatus(ctx, "tenant-2")
+	if err != nil {
+		t.Fatalf("CountByTenantIDGroupedByStatus failed: %v", err)
+	}
+	if len(counts) != 0 {
+		t.Errorf("expected empty counts, got %v", counts)
+	}
+}
+
+func TestKeyRepository_BackfillStatus(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	for _, k := range []*domain.EncryptionKey{
+		{TenantID: "tenant-001", Generation: 1, EncryptedKey: []byte("k1"), Status: domain.KeyStatusActive},
+		{TenantID: "tenant-001", Generation: 2, EncryptedKey: []byte("k2"), Status: domain.KeyStatusActive},
+		{TenantID: "tenant-001", Generation: 3, EncryptedKey: []byte("k3"), Status: domain.KeyStatusActive},
+		{TenantID: "tenant-002", Generation: 1, EncryptedKey: []byte("k4"), Status: domain.KeyStatusActive},
+	} {
+		if err := repo.Create(ctx, k); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	// 手動で投入された行を模して、tenant-001のステータスを空にする（世代3は無効化日時のみ記録されている）
+	if err := db.Exec("UPDATE encryption_keys SET status = '' WHERE tenant_id = ?", "tenant-001").Error; err != nil {
+		t.Fatalf("failed to clear status: %v", err)
+	}
+	if err := db.Exec("UPDATE encryption_keys SET disabled_at = ? WHERE tenant_id = ? AND generation = 3", time.Now(), "tenant-001").Error; err != nil {
+		t.Fatalf("failed to set disabled_at: %v", err)
+	}
+	if key, err := repo.FindLatestActiveByTenantID(ctx, "tenant-001"); err != nil || key != nil {
+		t.Fatalf("want no active key before backfill, got %v, %v", key, err)
+	}
+
+	// dry-runでは件数のみを返し、更新しない
+	counts, err := repo.BackfillStatus(ctx, true)
+	if err != nil {
+		t.Fatalf("BackfillStatus (dry run) failed: %v", err)
+	}
+	if counts[domain.KeyStatusActive] != 2 || counts[domain.KeyStatusDisabled] != 1 {
+		t.Errorf("dry run: want 2 active and 1 disabled, got %v", counts)
+	}
+	if key, _ := repo.FindLatestActiveByTenantID(ctx, "tenant-001"); key != nil {
+		t.Fatalf("want dry run not to update rows, got active generation %d", key.Generation)
+	}
+
+	counts, err = repo.BackfillStatus(ctx, false)
+	if err != nil {
+		t.Fatalf("BackfillStatus failed: %v", err)
+	}
+	if counts[domain.KeyStatusActive] != 2 || counts[domain.KeyStatusDisabled] != 1 {
+		t.Errorf("want 2 active and 1 disabled, got %v", counts)
+	}
+	keys, err := repo.FindAllByTenantID(ctx, "tenant-001")
+	if err != nil {
+		t.Fatalf("FindAllByTenantID failed: %v", err)
+	}
+	for _, k := range keys {
+		want := domain.KeyStatusActive
+		if k.DisabledAt != nil {
+			want = domain.KeyStatusDisabled
+		}
+		if k.Status != want {
+			t.Errorf("generation %d: want %s, got %s", k.Generation, want, k.Status)
+		}
+	}
+
+	// 補完済みの場合は何も更新しない
+	counts, err = repo.BackfillStatus(ctx, false)
+	if err != nil || counts[domain.KeyStatusActive] != 0 || counts[domain.KeyStatusDisabled] != 0 {
+		t.Errorf("want nothing to backfill, got %v, %v", counts, err)
+	}
+}
+
+func TestKeyRepository_ListTenantIDs(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	// 複数世代を持つテナントを含むテストデータを挿入
+	testData := []struct {
+		id         string
+		tenantID   string
+		generation uint
+	}{
+		{"test-id-1", "tenant-b", 1},
+		{"test-id-2", "tenant-b", 2},
+		{"test-id-3", "tenant-a", 1},
+		{"test-id-4", "tenant-c", 1},
+		{"test-id-5", "tenant-c", 2},
+		{"test-id-6", "tenant-c", 3},
+	}
+	for _, data := range testData {
+		insertTestKey(t, db, data.id, data.tenantID, data.generation, domain.KeyStatusActive)
+	}
+
+	tenantIDs, err := repo.ListTenantIDs(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("ListTenantIDs failed: %v", err)
+	}
+	want := []string{"tenant-a", "tenant-b", "tenant-c"}
+	if len(tenantIDs) != len(want) {
+		t.Fatalf("expected %d tenants, got %d: %v", len(want), len(tenantIDs), tenantIDs)
+	}
+	for i, id := range want {
+		if tenantIDs[i] != id {
+			t.Errorf("expected tenant %s at %d, got %s", id, i, tenantIDs[i])
+		}
+	}
+
+	// ページング
+	tenantIDs, err = repo.ListTenantIDs(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("ListTenantIDs failed: %v", err)
+	}
+	if len(tenantIDs) != 1 || tenantIDs[0] != "tenant-b" {
+		t.Errorf("expected [tenant-b], got %v", tenantIDs)
+	}
+
+	counts, err := repo.CountByTenantIDs(ctx, want)
+	if err != nil {
+		t.Fatalf("CountByTenantIDs failed: %v", err)
+	}
+	if counts["tenant-a"] != 1 || counts["tenant-b"] != 2 || counts["tenant-c"] != 3 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestKeyRepository_FindByTenantIDWithFilter(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for gen := uint(1); gen <= 5; gen++ {
+		createdAt := base.Add(time.Duration(gen) * 24 * time.Hour)
+		if err := db.Exec("INSERT INTO encryption_keys (id, tenant_id, generation, encrypted_key, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			fmt.Sprintf("test-id-%d", gen), "tenant-1", gen, []byte("encrypted-key"), "active", createdAt, createdAt).Error; err != nil {
+			t.Fatalf("failed to insert test data: %v", err)
+		}
+	}
+
+	createdAfter := base.Add(3 * 24 * time.Hour) // 世代3の作成日時
+	tests := []struct {
+		name    string
+		filter  domain.KeyFilter
+		wantGen []uint
+	}{
+		{"no filter", domain.KeyFilter{}, []uint{1, 2, 3, 4, 5}},
+		{"min generation inclusive", domain.KeyFilter{MinGeneration: 4}, []uint{4, 5}},
+		{"max generation inclusive", domain.KeyFilter{MaxGeneration: 2}, []uint{1, 2}},
+		{"generation range", domain.KeyFilter{MinGeneration: 2, MaxGeneration: 4}, []uint{2, 3, 4}},
+		{"created after inclusive", domain.KeyFilter{CreatedAfter: &createdAfter}, []uint{3, 4, 5}},
+		{"combined", domain.KeyFilter{CreatedAfter: &createdAfter, MaxGeneration: 4}, []uint{3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := repo.FindByTenantIDWithFilter(ctx, "tenant-1", tt.filter)
+			if err != nil {
+				t.Fatalf("FindByTenantIDWithFilter failed: %v", err)
+			}
+			if len(keys) != len(tt.wantGen) {
+				t.Fatalf("expected %d keys, got %d", len(tt.wantGen), len(keys))
+			}
+			for i, gen := range tt.wantGen {
+				if keys[i].Generation != gen {
+					t.Errorf("expected generation %d at %d, got %d", gen, i, keys[i].Generation)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyRepository_WithTx(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+	insertTestKey(t, db, "id-1", "tenant-1", 1, domain.KeyStatusActive)
+
+	newKey := func(gen uint) *domain.EncryptionKey {
+		return &domain.EncryptionKey{
+			TenantID:     "tenant-1",
+			Generation:   gen,
+			KeyType:      domain.KeyTypeAES,
+			Bits:         256,
+			EncryptedKey: []byte(fmt.Sprintf("encrypted-key-%d", gen)),
+			Status:       domain.KeyStatusActive,
+		}
+	}
+
+	// クロージャ内で失敗すると、それまでの書き込みもすべてロールバックされる
+	errAbort := errors.New("abort")
+	err := repo.WithTx(ctx, func(tx usecase.KeyRepository) error {
+		if err := tx.Create(ctx, newKey(2)); err != nil {
+			return err
+		}
+		if err := tx.Disable(ctx, "id-1", time.Now(), "rotated"); err != nil {
+			return err
+		}
+		// トランザクション内では書き込みが見える
+		if maxGen, err := tx.GetMaxGeneration(ctx, "tenant-1"); err != nil || maxGen != 2 {
+			t.Errorf("want max generation 2 inside the transaction, got %d (%v)", maxGen, err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("want the closure error, got %v", err)
+	}
+	keys, err := repo.FindAllByTenantID(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("FindAllByTenantID failed: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Status != domain.KeyStatusActive {
+		t.Errorf("want only the original active generation after rollback, got %+v", keys)
+	}
+
+	// 成功した場合はすべての書き込みがコミットされる
+	if err := repo.WithTx(ctx, func(tx usecase.KeyRepository) error {
+		if err := tx.Create(ctx, newKey(2)); err != nil {
+			return err
+		}
+		return tx.Disable(ctx, "id-1", time.Now(), "rotated")
+	}); err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	keys, err = repo.FindAllByTenantID(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("FindAllByTenantID failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0].Status != domain.KeyStatusDisabled || keys[1].Status != domain.KeyStatusActive {
+		t.Errorf("want generation 1 disabled and generation 2 active, got %+v", keys)
+	}
+}
+
+func TestKeyRepository_SoftDeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+	insertTestKey(t, db, "id-1", "tenant-1", 1, domain.KeyStatusActive)
+	insertTestKey(t, db, "id-2", "tenant-1", 2, domain.KeyStatusActive)
+
+	deleted, err := repo.SoftDelete(ctx, "tenant-1", 2)
+	if err != nil || !deleted {
+		t.Fatalf("want generation 2 soft deleted, got %v (%v)", deleted, err)
+	}
+	if deleted, err := repo.SoftDelete(ctx, "tenant-1", 2); err != nil || deleted {
+		t.Errorf("want an already soft deleted key to be reported as not deleted, got %v (%v)", deleted, err)
+	}
+
+	// 論理削除した鍵はすべての検索から除外される
+	if key, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 2); err != nil || key != nil {
+		t.Errorf("want soft deleted key to be hidden, got %+v (%v)", key, err)
+	}
+	latest, err := repo.FindLatestActiveByTenantID(ctx, "tenant-1")
+	if err != nil || latest == nil || latest.Generation != 1 {
+		t.Errorf("want generation 1 as the latest active key, got %+v (%v)", latest, err)
+	}
+	if keys, err := repo.FindAllByTenantID(ctx, "tenant-1"); err != nil || len(keys) != 1 {
+		t.Errorf("want 1 key listed, got %d (%v)", len(keys), err)
+	}
+	// 世代番号は占有したままのため、次の世代は3になる
+	if maxGen, err := repo.GetMaxGeneration(ctx, "tenant-1"); err != nil || maxGen != 2 {
+		t.Errorf("want max generation 2 including the soft deleted key, got %d (%v)", maxGen, err)
+	}
+
+	restored, err := repo.Restore(ctx, "tenant-1", 2)
+	if err != nil || !restored {
+		t.Fatalf("want generation 2 restored, got %v (%v)", restored, err)
+	}
+	key, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 2)
+	if err != nil || key == nil || key.Status != domain.KeyStatusActive {
+		t.Errorf("want restored active key, got %+v (%v)", key, err)
+	}
+	if restored, err := repo.Restore(ctx, "tenant-1", 1); err != nil || restored {
+		t.Errorf("want a key that is not soft deleted to be reported as not restored, got %v (%v)", restored, err)
+	}
+}
+
+func TestKeyRepository_CreateWithRetention(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	// 世代1〜3が有効、世代4は既に無効
+	for gen := uint(1); gen <= 4; gen++ {
+		status := domain.KeyStatusActive
+		if gen == 4 {
+			status = domain.KeyStatusDisabled
+		}
+		insertTestKey(t, db, fmt.Sprintf("id-%d", gen), "tenant-1", gen, status)
+	}
+
+	disabledAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := &domain.EncryptionKey{
+		TenantID:     "tenant-1",
+		Generation:   5,
+		KeyType:      domain.KeyTypeAES,
+		Bits:         256,
+		EncryptedKey: []byte("encrypted-key-5"),
+		Status:       domain.KeyStatusActive,
+	}
+	disabled, err := repo.CreateWithRetention(ctx, key, 2, disabledAt, domain.DisableReasonRetention)
+	if err != nil {
+		t.Fatalf("CreateWithRetention failed: %v", err)
+	}
+	if key.ID == "" {
+		t.Error("expected ID to be set")
+	}
+
+	// 有効な世代は5,3,2,1 → 保持数2を超える2,1が無効化される
+	if len(disabled) != 2 || disabled[0] != 2 || disabled[1] != 1 {
+		t.Errorf("expected disabled generations [2 1], got %v", disabled)
+	}
+
+	keys, err := repo.FindAllByTenantID(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("FindAllByTenantID failed: %v", err)
+	}
+	for _, k := range keys {
+		wantActive := k.Generation == 3 || k.Generation == 5
+		if gotActive := k.Status == domain.KeyStatusActive; gotActive != wantActive {
+			t.Errorf("generation %d: expected active=%v, got %s", k.Generation, wantActive, k.Status)
+		}
+		if k.Generation <= 2 {
+			if k.DisabledAt == nil || !k.DisabledAt.Equal(disabledAt) {
+				t.Errorf("generation %d: expected disabled_at=%v, got %v", k.Generation, disabledAt, k.DisabledAt)
+			}
+			if k.DisabledReason != domain.DisableReasonRetention {
+				t.Errorf("generation %d: expected retention reason, got %q", k.Generation, k.DisabledReason)
+			}
+		}
+	}
+}
+
+func TestKeyRepository_FindByTenantIDAndGenerations(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	for gen := uint(1); gen <= 3; gen++ {
+		insertTestKey(t, db, fmt.Sprintf("id-%d", gen), "tenant-1", gen, domain.KeyStatusActive)
+	}
+	// 別テナントの同じ世代は含まれない
+	insertTestKey(t, db, "other-1", "tenant-2", 1, domain.KeyStatusActive)
+
+	keys, err := repo.FindByTenantIDAndGenerations(ctx, "tenant-1", []uint{3, 1, 9})
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGenerations failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(keys))
+	}
+	if keys[0].Generation != 1 || keys[1].Generation != 3 {
+		t.Errorf("expected generations [1 3], got [%d %d]", keys[0].Generation, keys[1].Generation)
+	}
+	for _, k := range keys {
+		if k.TenantID != "tenant-1" {
+			t.Errorf("expected tenant-1, got %s", k.TenantID)
+		}
+	}
+}
+
+func TestKeyRepository_FindByKMSKeyName(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	const oldKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/old"
+	const newKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/new"
+
+	for _, k := range []*domain.EncryptionKey{
+		{TenantID: "tenant-b", Generation: 1, EncryptedKey: []byte("b1"), KMSKeyName: oldKMSKey, Status: domain.KeyStatusActive},
+		{TenantID: "tenant-a", Generation: 2, EncryptedKey: []byte("a2"), KMSKeyName: oldKMSKey, Status: domain.KeyStatusDisabled},
+		{TenantID: "tenant-a", Generation: 1, EncryptedKey: []byte("a1"), KMSKeyName: oldKMSKey, Status: domain.KeyStatusActive},
+		{TenantID: "tenant-a", Generation: 3, EncryptedKey: []byte("a3"), KMSKeyName: newKMSKey, Status: domain.KeyStatusActive},
+	} {
+		if err := repo.Create(ctx, k); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// 一致する鍵のみを、無効化済みを含めてテナント・世代順にページングして返す
+	first, err := repo.FindByKMSKeyName(ctx, oldKMSKey, 2, 0)
+	if err != nil {
+		t.Fatalf("FindByKMSKeyName failed: %v", err)
+	}
+	rest, err := repo.FindByKMSKeyName(ctx, oldKMSKey, 2, 2)
+	if err != nil {
+		t.Fatalf("FindByKMSKeyName failed: %v", err)
+	}
+	var got []string
+	for _, k := range append(first, rest...) {
+		got = append(got, fmt.Sprintf("%s/%d", k.TenantID, k.Generation))
+	}
+	if want := "tenant-a/1 tenant-a/2 tenant-b/1"; fmt.Sprint(got) != "["+want+"]" {
+		t.Errorf("want [%s], got %v", want, got)
+	}
+
+	// 元のKMS鍵名のままの行のみを更新する
+	target := first[0]
+	updated, err := repo.UpdateWrappedKey(ctx, target.ID, oldKMSKey, []byte("rewrapped"), newKMSKey, true)
+	if err != nil || !updated {
+		t.Fatalf("UpdateWrappedKey: want updated, got %v, %v", updated, err)
+	}
+	updated, err = repo.UpdateWrappedKey(ctx, target.ID, oldKMSKey, []byte("stale"), newKMSKey, true)
+	if err != nil || updated {
+		t.Errorf("UpdateWrappedKey on already rewrapped key: want not updated, got %v, %v", updated, err)
+	}
+	found, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-a", 1)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if string(found.EncryptedKey) != "rewrapped" || found.KMSKeyName != newKMSKey || !found.TenantAAD {
+		t.Errorf("unexpected key after rewrap: %q, %q, tenant AAD %v", found.EncryptedKey, found.KMSKeyName, found.TenantAAD)
+	}
+
+	remaining, err := repo.FindByKMSKeyName(ctx, oldKMSKey, 10, 0)
+	if err != nil {
+		t.Fatalf("FindByKMSKeyName failed: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("want 2 keys left under old KMS key, got %d", len(remaining))
+	}
+}
+
+func TestKeyRepository_UpdateLastUsedAt(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := NewKeyRepository(db)
+
+	insertTestKey(t, db, "key-1", "tenant-1", 1, domain.KeyStatusActive)
+	insertTestKey(t, db, "key-2", "tenant-1", 2, domain.KeyStatusActive)
+	before, err := repo.FindByTenantIDAndGeneration(ctx, "tenant-1", 1)
+	if err != nil {
+		t.Fatalf("FindByTenantIDAndGeneration failed: %v", err)
+	}
+	if before.LastUsedAt != nil {
+		t.Fatalf("want LastUsedAt nil before use, got %v", before.LastUsedAt)
+	}
+
+	used := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := repo.UpdateLastUsedAt(ctx, map[string]time.Time{"key-1": used}); err != nil {
+		t.Fatalf("UpdateLastUsedAt failed: %v", err)
+	}
+	// 古い日時では巻き戻さない
+	if err := repo.UpdateLastUsedAt(ctx, map[string]time.Time{"key-1": used.Add(-time.Hour)}); err != nil {
+		t.Fatalf("UpdateLastUsedAt failed: %v", err)
+	}
+
+	keys, err := repo.FindAllByTenantID(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("FindAllByTenantID failed: %v", err)
+	}
+	if keys[0].LastUsedAt == nil || !keys[0].LastUsedAt.Equal(used) {
+		t.Errorf("key-1: want LastUsedAt %v, got %v", used, keys[0].LastUsedAt)
+	}
+	if keys[1].LastUsedAt != nil {
+		t.Errorf("key-2: want LastUsedAt nil, got %v", keys[1].LastUsedAt)
+	}
+	// 最終利用日時の更新は鍵の変更として扱わない
+	if !keys[0].UpdatedAt.Equal(before.UpdatedAt) {
+		t.Errorf("want UpdatedAt unchanged %v, got %v", before.UpdatedAt, keys[0].UpdatedAt)
+	}
+}
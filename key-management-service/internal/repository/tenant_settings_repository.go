@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"key-management-service/internal/domain"
+)
+
+// TenantSettingsModel はtenant_settingsテーブルのモデル。
+type TenantSettingsModel struct {
+	TenantID                string    `gorm:"column:tenant_id;type:varchar(64);primaryKey"`
+	RotationIntervalSeconds int64     `gorm:"column:rotation_interval_seconds;not null;default:0;index:idx_tenant_settings_rotation"`
+	CreatedAt               time.Time `gorm:"column:created_at;precision:6;not null"`
+	UpdatedAt               time.Time `gorm:"column:updated_at;precision:6;not null"`
+}
+
+// TableName はテーブル名を返す。
+func (TenantSettingsModel) TableName() string {
+	return "tenant_settings"
+}
+
+func (m *TenantSettingsModel) toDomain() *domain.TenantSettings {
+	return &domain.TenantSettings{
+		TenantID:         m.TenantID,
+		RotationInterval: time.Duration(m.RotationIntervalSeconds) * time.Second,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
+
+// TenantSettingsRepository はテナントごとの設定の保存と参照を提供する。
+type TenantSettingsRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantSettingsRepository は新しいTenantSettingsRepositoryを生成する。
+func NewTenantSettingsRepository(db *gorm.DB) *TenantSettingsRepository {
+	return &TenantSettingsRepository{db: db}
+}
+
+// Find は指定されたテナントの設定を取得する。設定がない場合はnilを返す。
+func (r *TenantSettingsRepository) Find(ctx context.Context, tenantID string) (*domain.TenantSettings, error) {
+	var model TenantSettingsModel
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		slog.ErrorContext(ctx, "failed to find tenant settings",
+			"operation", "find_tenant_settings",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// Save はテナントの設定を保存する。既存の設定は上書きする。
+func (r *TenantSettingsRepository) Save(ctx context.Context, settings *domain.TenantSettings) error {
+	now := time.Now()
+	model := TenantSettingsModel{
+		TenantID:                settings.TenantID,
+		RotationIntervalSeconds: int64(settings.RotationInterval / time.Second),
+		CreatedAt:               now,
+		UpdatedAt:               now,
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rotation_interval_seconds", "updated_at"}),
+		}).
+		Create(&model).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to save tenant settings",
+			"operation", "save_tenant_settings",
+			"tenant_id", settings.TenantID,
+			"error", err,
+		)
+		return err
+	}
+	settings.UpdatedAt = now
+	return nil
+}
+
+// FindWithRotationInterval は自動ローテーション間隔が設定されたテナントの設定をテナントID順に取得する。
+func (r *TenantSettingsRepository) FindWithRotationInterval(ctx context.Context) ([]*domain.TenantSettings, error) {
+	var models []TenantSettingsModel
+	err := r.db.WithContext(ctx).
+		Where("rotation_interval_seconds > 0").
+		Order("tenant_id ASC").
+		Find(&models).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find tenant settings with rotation interval",
+			"operation", "find_rotation_settings",
+			"error", err,
+		)
+		return nil, err
+	}
+	settings := make([]*domain.TenantSettings, len(models))
+	for i := range models {
+		settings[i] = models[i].toDomain()
+	}
+	return settings, nil
+}
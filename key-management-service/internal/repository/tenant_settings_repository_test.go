@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"key-management-service/internal/domain"
+)
+
+func TestTenantSettingsRepository(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&TenantSettingsModel{}); err != nil {
+		t.Fatalf("failed to migrate tenant_settings table: %v", err)
+	}
+	repo := NewTenantSettingsRepository(db)
+
+	settings, err := repo.Find(ctx, "tenant-1")
+	if err != nil || settings != nil {
+		t.Fatalf("want nil settings before save, got %v (err: %v)", settings, err)
+	}
+
+	for _, s := range []*domain.TenantSettings{
+		{TenantID: "tenant-2", RotationInterval: 90 * 24 * time.Hour},
+		{TenantID: "tenant-1", RotationInterval: 30 * 24 * time.Hour},
+		{TenantID: "tenant-3", RotationInterval: 0},
+	} {
+		if err := repo.Save(ctx, s); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	// 既存の設定は上書きする
+	if err := repo.Save(ctx, &domain.TenantSettings{TenantID: "tenant-1", RotationInterval: 7 * 24 * time.Hour}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	settings, err = repo.Find(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if settings.RotationInterval != 7*24*time.Hour {
+		t.Errorf("want updated interval 168h, got %v", settings.RotationInterval)
+	}
+
+	// 間隔が0のテナントは自動ローテーションの対象外
+	list, err := repo.FindWithRotationInterval(ctx)
+	if err != nil {
+		t.Fatalf("FindWithRotationInterval failed: %v", err)
+	}
+	if len(list) != 2 || list[0].TenantID != "tenant-1" || list[1].TenantID != "tenant-2" {
+		t.Errorf("want tenant-1 and tenant-2, got %+v", list)
+	}
+}
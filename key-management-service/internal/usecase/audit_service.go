@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/internal/domain"
+)
+
+// AuditRepository は監査イベントの検索のインターフェース。
+type AuditRepository interface {
+	FindByTenantID(ctx context.Context, tenantID string, filter domain.AuditFilter) ([]*domain.AuditEvent, error)
+}
+
+// AuditService は永続化された監査イベントの検索を提供する。
+type AuditService struct {
+	repo      AuditRepository
+	dbTimeout time.Duration
+}
+
+// NewAuditService は新しいAuditServiceを生成する。dbTimeoutが0の場合はタイムアウトを設定しない。
+func NewAuditService(repo AuditRepository, dbTimeout time.Duration) *AuditService {
+	return &AuditService{repo: repo, dbTimeout: dbTimeout}
+}
+
+// ListEvents はテナントの監査イベントを条件で絞り込んで古い順に取得する。
+func (s *AuditService) ListEvents(ctx context.Context, tenantID string, filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	ctx, span := tracer.Start(ctx, "AuditService.ListEvents",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.String("audit.operation", filter.Operation),
+		),
+	)
+	defer span.End()
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	events, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]*domain.AuditEvent, error) {
+		return s.repo.FindByTenantID(ctx, tenantID, filter)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to list audit events",
+			"operation", "list_audit_events",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("listing audit events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"key-management-service/internal/domain"
+)
+
+// AuditOperationAutoRotate は自動ローテーションの監査ログの操作名。
+const AuditOperationAutoRotate = "AUTO_ROTATE_KEY"
+
+// AuditWriter は監査ログの書き込み先のインターフェース。
+type AuditWriter interface {
+	Write(ctx context.Context, operation string, tenantID string, generation uint, result string)
+}
+
+// ReadOnlyState は読み取り専用モードの状態を返すインターフェース。middleware.ReadOnlyModeが満たす。
+type ReadOnlyState interface {
+	Enabled() bool
+}
+
+// AutoRotator はテナントごとのローテーション間隔に従って鍵を自動でローテーションする。
+// 現在の鍵の作成日時から間隔が経過したテナントを定期的に検出し、現在の鍵と同じ種類・ビット長で新しい世代を作成する。
+// 複数のレプリカで実行しても、検出時に確認した最新の世代からのローテーションは1回だけ成功する。
+type AutoRotator struct {
+	keys     *KeyService
+	settings TenantSettingsRepository
+	audit    AuditWriter
+	interval time.Duration
+	readOnly ReadOnlyState
+	now      func() time.Time
+}
+
+// AutoRotatorOption はAutoRotatorの任意設定を行う。
+type AutoRotatorOption func(*AutoRotator)
+
+// WithAutoRotationReadOnly は読み取り専用モードの状態を設定する。有効な間はローテーションを行わない。
+func WithAutoRotationReadOnly(mode ReadOnlyState) AutoRotatorOption {
+	return func(a *AutoRotator) { a.readOnly = mode }
+}
+
+// NewAutoRotator は新しいAutoRotatorを生成する。intervalはローテーション対象を確認する間隔。
+func NewAutoRotator(keys *KeyService, settings TenantSettingsRepository, audit AuditWriter, interval time.Duration, opts ...AutoRotatorOption) *AutoRotator {
+	a := &AutoRotator{
+		keys:     keys,
+		settings: settings,
+		audit:    audit,
+		interval: interval,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// dueRotation はローテーション対象のテナントの現在の鍵と、検出時の最新の世代。
+type dueRotation struct {
+	current       *domain.EncryptionKey
+	maxGeneration uint
+}
+
+// Run はctxが終了するまで一定間隔でRunOnceを実行する。
+func (a *AutoRotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce はローテーション間隔を経過したテナントの鍵をローテーションし、成功・失敗した件数を返す。
+// 1テナントの失敗（対象の判定に必要な鍵の取得の失敗を含む）で他のテナントの処理は止めない。読み取り専用モードの間は何もしない。
+// 他のレプリカが先にローテーションしたテナントは成功・失敗のいずれにも数えない。
+func (a *AutoRotator) RunOnce(ctx context.Context) (rotated, failed int) {
+	if a.readOnly != nil && a.readOnly.Enabled() {
+		slog.InfoContext(ctx, "auto-rotation skipped in read-only mode",
+			"operation", "auto_rotate",
+		)
+		return 0, 0
+	}
+
+	due, failed, err := a.dueRotations(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find tenants due for rotation",
+			"operation", "auto_rotate",
+			"error", err,
+		)
+		return 0, 0
+	}
+
+	for _, d := range due {
+		current := d.current
+		spec := domain.KeySpec{Type: current.KeyType, Bits: current.Bits}
+		metadata, err := a.keys.RotateKeyFrom(ctx, current.TenantID, spec, d.maxGeneration)
+		if errors.Is(err, domain.ErrGenerationAlreadyExists) {
+			slog.InfoContext(ctx, "key already rotated by another replica",
+				"operation", "auto_rotate",
+				"tenant_id", current.TenantID,
+				"generation", d.maxGeneration,
+			)
+			continue
+		}
+		if err != nil {
+			failed++
+			slog.ErrorContext(ctx, "failed to auto-rotate key",
+				"operation", "auto_rotate",
+				"tenant_id", current.TenantID,
+				"generation", current.Generation,
+				"error", err,
+			)
+			a.audit.Write(ctx, AuditOperationAutoRotate, current.TenantID, current.Generation, "FAILED")
+			continue
+		}
+		rotated++
+		slog.InfoContext(ctx, "key auto-rotated",
+			"operation", "auto_rotate",
+			"tenant_id", current.TenantID,
+			"previous_generation", current.Generation,
+			"generation", metadata.Generation,
+		)
+		a.audit.Write(ctx, AuditOperationAutoRotate, current.TenantID, metadata.Generation, "SUCCESS")
+	}
+	if len(due) > 0 || failed > 0 {
+		slog.InfoContext(ctx, "auto-rotation finished",
+			"operation", "auto_rotate",
+			"due", len(due),
+			"rotated", rotated,
+			"failed", failed,
+		)
+	}
+	return rotated, failed
+}
+
+// dueRotations はローテーション間隔を経過した、または有効期限が切れたテナントの現在の鍵と最新の世代、判定に失敗したテナント数を返す。
+// 有効な鍵がないテナントは対象外とする。鍵の取得に失敗したテナントはFAILEDとして監査ログに記録して飛ばし、
+// 他のテナントの判定を続ける。エラーを返すのは間隔を設定したテナントの一覧を取得できない場合のみ。
+// 最新の世代は現在の鍵より先に取得する。間に他のレプリカがローテーションした場合は現在の鍵が新しくなり対象外となるため、
+// 取得した世代からのローテーションは他のレプリカと重複しない。
+func (a *AutoRotator) dueRotations(ctx context.Context) (due []dueRotation, failed int, err error) {
+	settings, err := callWithTimeout(ctx, a.keys.dbTimeout, func(ctx context.Context) ([]*domain.TenantSettings, error) {
+		return a.settings.FindWithRotationInterval(ctx)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := a.now()
+	for _, s := range settings {
+		d, err := a.findRotation(ctx, s.TenantID)
+		if err != nil {
+			failed++
+			slog.ErrorContext(ctx, "failed to check tenant for rotation",
+				"operation", "auto_rotate",
+				"tenant_id", s.TenantID,
+				"error", err,
+			)
+			a.audit.Write(ctx, AuditOperationAutoRotate, s.TenantID, 0, "FAILED")
+			continue
+		}
+		// 有効期限が切れた鍵は現在の鍵として使えないため、間隔にかかわらずローテーションする
+		if d.current == nil || !(s.RotationDue(d.current.CreatedAt, now) || d.current.Expired(now)) {
+			continue
+		}
+		due = append(due, d)
+	}
+	return due, failed, nil
+}
+
+// findRotation はテナントの最新の世代と現在の鍵を取得する。有効な鍵がない場合、currentはnilになる。
+func (a *AutoRotator) findRotation(ctx context.Context, tenantID string) (dueRotation, error) {
+	maxGen, err := callWithTimeout(ctx, a.keys.dbTimeout, func(ctx context.Context) (uint, error) {
+		return a.keys.repo.GetMaxGeneration(ctx, tenantID)
+	})
+	if err != nil {
+		return dueRotation{}, err
+	}
+	current, err := callWithTimeout(ctx, a.keys.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return a.keys.repo.FindLatestActiveByTenantID(ctx, tenantID)
+	})
+	if err != nil {
+		return dueRotation{}, err
+	}
+	return dueRotation{current: current, maxGeneration: maxGen}, nil
+}
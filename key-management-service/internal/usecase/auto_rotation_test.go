@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"key-management-service/internal/domain"
+)
+
+// tenantKeysRepository はテナントごとの現在の鍵を返すテスト用リポジトリ。
+// failingにあるテナントは鍵の取得に失敗する。
+type tenantKeysRepository struct {
+	mockKeyRepository
+	latest  map[string]*domain.EncryptionKey
+	failing map[string]bool
+}
+
+func (r *tenantKeysRepository) FindLatestActiveByTenantID(ctx context.Context, tenantID string) (*domain.EncryptionKey, error) {
+	if r.failing[tenantID] {
+		return nil, errors.New("db error")
+	}
+	return r.latest[tenantID], nil
+}
+
+func (r *tenantKeysRepository) GetMaxGeneration(ctx context.Context, tenantID string) (uint, error) {
+	if r.failing[tenantID] {
+		return 0, errors.New("db error")
+	}
+	if k := r.latest[tenantID]; k != nil {
+		return k.Generation, nil
+	}
+	return 0, nil
+}
+
+// fakeTenantSettingsRepository はメモリ上にテナント設定を保持するテスト用リポジトリ。
+type fakeTenantSettingsRepository struct {
+	settings []*domain.TenantSettings
+}
+
+func (f *fakeTenantSettingsRepository) Find(ctx context.Context, tenantID string) (*domain.TenantSettings, error) {
+	for _, s := range f.settings {
+		if s.TenantID == tenantID {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeTenantSettingsRepository) Save(ctx context.Context, settings *domain.TenantSettings) error {
+	f.settings = append(f.settings, settings)
+	return nil
+}
+
+func (f *fakeTenantSettingsRepository) FindWithRotationInterval(ctx context.Context) ([]*domain.TenantSettings, error) {
+	var list []*domain.TenantSettings
+	for _, s := range f.settings {
+		if s.RotationInterval > 0 {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+// recordingAuditWriter は書き込まれた監査ログを記録する。
+type recordingAuditWriter struct {
+	events []string
+}
+
+func (w *recordingAuditWriter) Write(ctx context.Context, operation string, tenantID string, generation uint, result string) {
+	w.events = append(w.events, operation+" "+tenantID+" "+result)
+}
+
+func newAutoRotatorForTest(now time.Time) (*AutoRotator, *tenantKeysRepository, *recordingAuditWriter) {
+	const day = 24 * time.Hour
+	repo := &tenantKeysRepository{latest: map[string]*domain.EncryptionKey{
+		// 間隔（30日）を経過している
+		"tenant-due": {TenantID: "tenant-due", Generation: 3, KeyType: domain.KeyTypeHMAC, Bits: 512, Status: domain.KeyStatusActive, CreatedAt: now.Add(-31 * day)},
+		// ちょうど間隔に達している
+		"tenant-boundary": {TenantID: "tenant-boundary", Generation: 1, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive, CreatedAt: now.Add(-30 * day)},
+		// 間隔に達していない
+		"tenant-fresh": {TenantID: "tenant-fresh", Generation: 5, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive, CreatedAt: now.Add(-29 * day)},
+		// 間隔の設定がない
+		"tenant-unset": {TenantID: "tenant-unset", Generation: 1, KeyType: domain.KeyTypeAES, Bits: 256, Status: domain.KeyStatusActive, CreatedAt: now.Add(-365 * day)},
+	}}
+	settings := &fakeTenantSettingsRepository{settings: []*domain.TenantSettings{
+		{TenantID: "tenant-boundary", RotationInterval: 30 * day},
+		{TenantID: "tenant-due", RotationInterval: 30 * day},
+		{TenantID: "tenant-fresh", RotationInterval: 30 * day},
+		{TenantID: "tenant-unset", RotationInterval: 0},
+		// 有効な鍵がない
+		{TenantID: "tenant-nokey", RotationInterval: 30 * day},
+	}}
+	audit := &recordingAuditWriter{}
+	rotator := NewAutoRotator(NewKeyService(repo, &mockKMSClient{}), settings, audit, time.Hour)
+	rotator.now = func() time.Time { return now }
+	return rotator, repo, audit
+}
+
+func TestAutoRotator_DueRotations(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rotator, _, _ := newAutoRotatorForTest(now)
+
+	due, failed, err := rotator.dueRotations(context.Background())
+	if err != nil || failed != 0 {
+		t.Fatalf("unexpected error: %v (%d failed)", err, failed)
+	}
+	var got []string
+	for _, d := range due {
+		got = append(got, d.current.TenantID)
+		if d.maxGeneration != d.current.Generation {
+			t.Errorf("%s: want max generation %d, got %d", d.current.TenantID, d.current.Generation, d.maxGeneration)
+		}
+	}
+	want := []string{"tenant-boundary", "tenant-due"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestAutoRotator_RunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rotator, repo, audit := newAutoRotatorForTest(now)
+
+	rotated, failed := rotator.RunOnce(context.Background())
+	if rotated != 2 || failed != 0 {
+		t.Fatalf("want 2 rotated and 0 failed, got %d and %d", rotated, failed)
+	}
+
+	// 現在の鍵と同じ種類・ビット長で次の世代を作成する
+	var hmacKey *domain.EncryptionKey
+	for _, k := range repo.createdKeys {
+		if k.TenantID == "tenant-due" {
+			hmacKey = k
+		}
+	}
+	if hmacKey == nil {
+		t.Fatal("want tenant-due to be rotated")
+	}
+	if hmacKey.Generation != 4 || hmacKey.KeyType != domain.KeyTypeHMAC || hmacKey.Bits != 512 {
+		t.Errorf("want hmac/512 generation 4, got %s/%d generation %d", hmacKey.KeyType, hmacKey.Bits, hmacKey.Generation)
+	}
+
+	want := []string{"AUTO_ROTATE_KEY tenant-boundary SUCCESS", "AUTO_ROTATE_KEY tenant-due SUCCESS"}
+	if len(audit.events) != len(want) {
+		t.Fatalf("want audit events %v, got %v", want, audit.events)
+	}
+	for i := range want {
+		if audit.events[i] != want[i] {
+			t.Errorf("want audit events %v, got %v", want, audit.events)
+		}
+	}
+}
+
+func TestAutoRotator_TenantLookupFailure(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rotator, repo, audit := newAutoRotatorForTest(now)
+	// 1テナントの鍵の取得に失敗しても、他のテナントの判定とローテーションは続ける
+	repo.failing = map[string]bool{"tenant-boundary": true}
+
+	due, failed, err := rotator.dueRotations(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if failed != 1 {
+		t.Errorf("want 1 failed tenant, got %d", failed)
+	}
+	if len(due) != 1 || due[0].current.TenantID != "tenant-due" {
+		t.Fatalf("want tenant-due still selected, got %+v", due)
+	}
+
+	audit.events = nil
+	rotated, failed := rotator.RunOnce(context.Background())
+	if rotated != 1 || failed != 1 {
+		t.Errorf("want 1 rotated and 1 failed, got %d and %d", rotated, failed)
+	}
+	want := []string{"AUTO_ROTATE_KEY tenant-boundary FAILED", "AUTO_ROTATE_KEY tenant-due SUCCESS"}
+	if !slices.Equal(audit.events, want) {
+		t.Errorf("want audit events %v, got %v", want, audit.events)
+	}
+}
+
+func TestAutoRotator_ExpiredKey(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rotator, repo, _ := newAutoRotatorForTest(now)
+	// 間隔に達していないが有効期限が切れている
+	expiresAt := now.Add(-time.Minute)
+	repo.latest["tenant-fresh"].ExpiresAt = &expiresAt
+
+	due, _, err := rotator.dueRotations(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, d := range due {
+		got = append(got, d.current.TenantID)
+	}
+	if want := []string{"tenant-boundary", "tenant-due", "tenant-fresh"}; !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestAutoRotator_ReadOnly(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rotator, repo, audit := newAutoRotatorForTest(now)
+	readOnly := &readOnlyState{enabled: true}
+	WithAutoRotationReadOnly(readOnly)(rotator)
+
+	// 読み取り専用モードの間はローテーションしない
+	if rotated, failed := rotator.RunOnce(context.Background()); rotated != 0 || failed != 0 {
+		t.Errorf("want nothing rotated in read-only mode, got %d rotated and %d failed", rotated, failed)
+	}
+	if len(repo.createdKeys) != 0 || len(audit.events) != 0 {
+		t.Errorf("want no keys and no audit events, got %d keys and %v", len(repo.createdKeys), audit.events)
+	}
+
+	// 実行中に無効化された場合は次の確認からローテーションする
+	readOnly.enabled = false
+	if rotated, _ := rotator.RunOnce(context.Background()); rotated != 2 {
+		t.Errorf("want 2 rotated after leaving read-only mode, got %d", rotated)
+	}
+}
+
+func TestAutoRotator_RotatedByAnotherReplica(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rotator, repo, audit := newAutoRotatorForTest(now)
+	// 他のレプリカが先に同じ世代を作成した
+	repo.createErr = fmt.Errorf("%w: generation 4", domain.ErrGenerationAlreadyExists)
+
+	rotated, failed := rotator.RunOnce(context.Background())
+	if rotated != 0 || failed != 0 {
+		t.Errorf("want rotations skipped without failure, got %d rotated and %d failed", rotated, failed)
+	}
+	if len(audit.events) != 0 {
+		t.Errorf("want no FAILED audit events, got %v", audit.events)
+	}
+}
+
+func TestKeyService_RotateKeyFrom(t *testing.T) {
+	repo := &mockKeyRepository{maxGenResult: 3}
+	svc := NewKeyService(repo, &mockKMSClient{})
+
+	// 最新の世代が変わっていた場合はローテーションしない
+	if _, err := svc.RotateKeyFrom(context.Background(), "tenant-001", domain.KeySpec{}, 2); !errors.Is(err, domain.ErrGenerationAlreadyExists) {
+		t.Errorf("want ErrGenerationAlreadyExists, got %v", err)
+	}
+	if len(repo.createdKeys) != 0 {
+		t.Errorf("want no key created, got %d", len(repo.createdKeys))
+	}
+
+	metadata, err := svc.RotateKeyFrom(context.Background(), "tenant-001", domain.KeySpec{}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.Generation != 4 {
+		t.Errorf("want generation 4, got %d", metadata.Generation)
+	}
+}
+
+// readOnlyState はテスト用の読み取り専用モードの状態。
+type readOnlyState struct {
+	enabled bool
+}
+
+func (s *readOnlyState) Enabled() bool { return s.enabled }
+
+func TestTenantSettingsService_SetRotationInterval(t *testing.T) {
+	svc := NewTenantSettingsService(&fakeTenantSettingsRepository{}, 0)
+
+	for _, interval := range []time.Duration{-time.Hour, time.Minute} {
+		if _, err := svc.SetRotationInterval(context.Background(), "tenant-001", interval); err != domain.ErrInvalidRotationInterval {
+			t.Errorf("%v: want ErrInvalidRotationInterval, got %v", interval, err)
+		}
+	}
+
+	settings, err := svc.SetRotationInterval(context.Background(), "tenant-001", 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.RotationInterval != 90*24*time.Hour {
+		t.Errorf("want 90 days, got %v", settings.RotationInterval)
+	}
+
+	// 未設定のテナントは自動ローテーションなし
+	settings, err = svc.GetSettings(context.Background(), "tenant-002")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.RotationInterval != 0 {
+		t.Errorf("want no rotation interval, got %v", settings.RotationInterval)
+	}
+}
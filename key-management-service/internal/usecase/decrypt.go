@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/internal/domain"
+)
+
+// Decrypt はテナントの指定した世代の鍵で、EncryptBatchの形式（nonce | 暗号文）の暗号文を復号する。
+func (s *KeyService) Decrypt(ctx context.Context, tenantID string, generation uint, ciphertext []byte) (_ *domain.DecryptResult, err error) {
+	ctx, span := tracer.Start(ctx, "KeyService.Decrypt",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("key.generation", int(generation)),
+		),
+	)
+	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "decrypt", start, err) }(time.Now())
+
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindByTenantIDAndGeneration(ctx, tenantID, generation)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find key by generation",
+			"operation", "decrypt",
+			"tenant_id", tenantID,
+			"generation", generation,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding key: %w", err)
+	}
+	if key == nil {
+		slog.WarnContext(ctx, "key not found",
+			"operation", "decrypt",
+			"tenant_id", tenantID,
+			"generation", generation,
+		)
+		return nil, domain.ErrKeyNotFound
+	}
+	if key.Status == domain.KeyStatusDisabled {
+		return nil, domain.ErrKeyDisabled
+	}
+	if key.KeyType != domain.KeyTypeAES {
+		return nil, domain.ErrUnsupportedKeyType
+	}
+
+	plaintext, err := s.openWithKey(ctx, key, ciphertext)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	s.recordLastUsed(key.ID)
+	return &domain.DecryptResult{TenantID: key.TenantID, Generation: key.Generation, Plaintext: plaintext}, nil
+}
+
+// DecryptAuto は世代が分からない暗号文を、テナントの無効化されていないAES鍵で新しい世代から順に復号する。
+// 認証に成功した時点で打ち切り、その世代を返す。試行はdomain.MaxAutoDecryptAttempts世代までで、
+// いずれでも復号できない場合はdomain.ErrDecryptionFailedを返す。KMSの失敗は復号の失敗として扱わずにそのまま返す。
+func (s *KeyService) DecryptAuto(ctx context.Context, tenantID string, ciphertext []byte) (_ *domain.DecryptResult, err error) {
+	ctx, span := tracer.Start(ctx, "KeyService.DecryptAuto",
+		trace.WithAttributes(attribute.String("tenant.id", tenantID)),
+	)
+	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "decrypt_auto", start, err) }(time.Now())
+
+	// 試行する世代だけをデータベースで絞り込んで取得する（テナントの全世代は読み込まない）
+	candidates, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]*domain.EncryptionKey, error) {
+		return s.repo.FindRecentUsableByTenantID(ctx, tenantID, domain.KeyTypeAES, domain.MaxAutoDecryptAttempts)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find keys",
+			"operation", "decrypt_auto",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding keys: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		slog.WarnContext(ctx, "no key available for decryption",
+			"operation", "decrypt_auto",
+			"tenant_id", tenantID,
+		)
+		return nil, domain.ErrKeyNotFound
+	}
+
+	for i, key := range candidates {
+		plaintext, err := s.openWithKey(ctx, key, ciphertext)
+		if errors.Is(err, domain.ErrDecryptionFailed) {
+			continue
+		}
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		span.SetAttributes(
+			attribute.Int("key.generation", int(key.Generation)),
+			attribute.Int("decrypt.attempts", i+1),
+		)
+		s.recordLastUsed(key.ID)
+		return &domain.DecryptResult{TenantID: key.TenantID, Generation: key.Generation, Plaintext: plaintext}, nil
+	}
+
+	slog.WarnContext(ctx, "ciphertext did not match any generation",
+		"operation", "decrypt_auto",
+		"tenant_id", tenantID,
+		"attempts", len(candidates),
+	)
+	return nil, domain.ErrDecryptionFailed
+}
+
+// openWithKey は鍵をKMSで復号し、暗号文を開く。認証に失敗した場合はdomain.ErrDecryptionFailedを返す。
+func (s *KeyService) openWithKey(ctx context.Context, key *domain.EncryptionKey, ciphertext []byte) ([]byte, error) {
+	plainKey, err := s.decryptKey(ctx, key)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decrypt key",
+			"operation", "decrypt",
+			"tenant_id", key.TenantID,
+			"generation", key.Generation,
+			"error", err,
+		)
+		return nil, fmt.Errorf("decrypting key: %w", err)
+	}
+	defer clear(plainKey)
+	return openSealed(plainKey, ciphertext)
+}
+
+// openSealed はnonceを先頭に付けたAES-GCMの暗号文を復号する（sealAllの逆）。
+func openSealed(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating GCM: %w", err)
+	}
+	if len(ciphertext) < aead.NonceSize()+aead.Overhead() {
+		return nil, domain.ErrDecryptionFailed
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, domain.ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"key-management-service/internal/domain"
+)
+
+// wrappingKMSClient は "wrapped:" を先頭に付けた鍵を元に戻すKMSクライアント。世代ごとに異なる鍵を扱える。
+type wrappingKMSClient struct {
+	decrypts int
+}
+
+func (c *wrappingKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return append([]byte("wrapped:"), plaintext...), nil
+}
+
+func (c *wrappingKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	c.decrypts++
+	plain, ok := bytes.CutPrefix(ciphertext, []byte("wrapped:"))
+	if !ok {
+		return nil, errors.New("not wrapped")
+	}
+	return bytes.Clone(plain), nil
+}
+
+// keyGenerations は世代1からnまでのAES鍵と、世代ごとの鍵の平文を返す。
+func keyGenerations(n int) ([]*domain.EncryptionKey, map[uint][]byte) {
+	keys := make([]*domain.EncryptionKey, n)
+	materials := make(map[uint][]byte, n)
+	for i := range keys {
+		gen := uint(i + 1)
+		materials[gen] = bytes.Repeat([]byte{byte(gen)}, 32)
+		keys[i] = &domain.EncryptionKey{
+			ID:           fmt.Sprintf("key-%d", gen),
+			TenantID:     "tenant-001",
+			Generation:   gen,
+			KeyType:      domain.KeyTypeAES,
+			Bits:         256,
+			EncryptedKey: append([]byte("wrapped:"), materials[gen]...),
+			Status:       domain.KeyStatusActive,
+		}
+	}
+	return keys, materials
+}
+
+func sealWith(t *testing.T, key, plaintext []byte) []byte {
+	t.Helper()
+	ciphertexts, err := sealAll(key, [][]byte{plaintext})
+	if err != nil {
+		t.Fatalf("sealAll failed: %v", err)
+	}
+	return ciphertexts[0]
+}
+
+func TestKeyService_Decrypt(t *testing.T) {
+	keys, materials := keyGenerations(3)
+	repo := &mockKeyRepository{findByGenResult: keys[1]}
+	svc := NewKeyService(repo, &wrappingKMSClient{})
+	ctx := context.Background()
+
+	result, err := svc.Decrypt(ctx, "tenant-001", 2, sealWith(t, materials[2], []byte("secret")))
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if result.Generation != 2 || string(result.Plaintext) != "secret" {
+		t.Errorf("want secret under generation 2, got %q under %d", result.Plaintext, result.Generation)
+	}
+
+	// 別の世代の鍵で暗号化したものは認証に失敗する
+	if _, err := svc.Decrypt(ctx, "tenant-001", 2, sealWith(t, materials[3], []byte("secret"))); !errors.Is(err, domain.ErrDecryptionFailed) {
+		t.Errorf("want ErrDecryptionFailed, got %v", err)
+	}
+
+	repo.findByGenResult = &domain.EncryptionKey{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeAES, Status: domain.KeyStatusDisabled}
+	if _, err := svc.Decrypt(ctx, "tenant-001", 2, sealWith(t, materials[2], []byte("secret"))); !errors.Is(err, domain.ErrKeyDisabled) {
+		t.Errorf("want ErrKeyDisabled, got %v", err)
+	}
+}
+
+func TestKeyService_DecryptAuto(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("新しい世代から順に試して一致した世代を返す", func(t *testing.T) {
+		keys, materials := keyGenerations(5)
+		keys[4].Status = domain.KeyStatusDisabled   // 世代5は無効化済みのため試さない
+		keys[3].Status = domain.KeyStatusDeprecated // 非推奨の鍵は復号に使える
+		kms := &wrappingKMSClient{}
+		svc := NewKeyService(&mockKeyRepository{findAllResult: keys}, kms)
+
+		result, err := svc.DecryptAuto(ctx, "tenant-001", sealWith(t, materials[2], []byte("lost generation")))
+		if err != nil {
+			t.Fatalf("DecryptAuto failed: %v", err)
+		}
+		if result.Generation != 2 || string(result.Plaintext) != "lost generation" {
+			t.Errorf("want plaintext under generation 2, got %q under %d", result.Plaintext, result.Generation)
+		}
+		// 世代4・3・2の順に試し、2で打ち切る
+		if kms.decrypts != 3 {
+			t.Errorf("want 3 key decrypts, got %d", kms.decrypts)
+		}
+	})
+
+	t.Run("一致する世代がない場合は上限まで試して失敗する", func(t *testing.T) {
+		keys, _ := keyGenerations(domain.MaxAutoDecryptAttempts + 2)
+		kms := &wrappingKMSClient{}
+		svc := NewKeyService(&mockKeyRepository{findAllResult: keys}, kms)
+
+		_, err := svc.DecryptAuto(ctx, "tenant-001", sealWith(t, bytes.Repeat([]byte{0xff}, 32), []byte("unknown")))
+		if !errors.Is(err, domain.ErrDecryptionFailed) {
+			t.Fatalf("want ErrDecryptionFailed, got %v", err)
+		}
+		if kms.decrypts != domain.MaxAutoDecryptAttempts {
+			t.Errorf("want %d attempts, got %d", domain.MaxAutoDecryptAttempts, kms.decrypts)
+		}
+	})
+
+	t.Run("古い世代は上限を超えると試さない", func(t *testing.T) {
+		keys, materials := keyGenerations(domain.MaxAutoDecryptAttempts + 1)
+		svc := NewKeyService(&mockKeyRepository{findAllResult: keys}, &wrappingKMSClient{})
+
+		_, err := svc.DecryptAuto(ctx, "tenant-001", sealWith(t, materials[1], []byte("too old")))
+		if !errors.Is(err, domain.ErrDecryptionFailed) {
+			t.Errorf("want ErrDecryptionFailed, got %v", err)
+		}
+	})
+
+	t.Run("KMSの失敗は復号の失敗として扱わない", func(t *testing.T) {
+		keys, materials := keyGenerations(2)
+		keys[1].EncryptedKey = []byte("corrupted")
+		svc := NewKeyService(&mockKeyRepository{findAllResult: keys}, &wrappingKMSClient{})
+
+		_, err := svc.DecryptAuto(ctx, "tenant-001", sealWith(t, materials[1], []byte("secret")))
+		if err == nil || errors.Is(err, domain.ErrDecryptionFailed) {
+			t.Errorf("want KMS error, got %v", err)
+		}
+	})
+
+	t.Run("鍵がない場合", func(t *testing.T) {
+		svc := NewKeyService(&mockKeyRepository{}, &wrappingKMSClient{})
+		if _, err := svc.DecryptAuto(ctx, "tenant-001", make([]byte, domain.MinCiphertextSize)); !errors.Is(err, domain.ErrKeyNotFound) {
+			t.Errorf("want ErrKeyNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/internal/domain"
+)
+
+// EncryptBatch はテナントの現在の鍵で複数の平文をAES-GCMで暗号化する。
+// 鍵はバッチ全体で1回だけKMSで復号するため、大量のデータを取り込む際のKMS呼び出しを抑えられる。
+// 暗号文は指定順に返し、各要素は nonce | 暗号文 の形式（domain.BatchEncryptResultを参照）。
+// 現在の鍵の有効期限が切れている場合はdomain.ErrKeyExpiredを返す。
+func (s *KeyService) EncryptBatch(ctx context.Context, tenantID string, plaintexts [][]byte) (_ *domain.BatchEncryptResult, err error) {
+	ctx, span := tracer.Start(ctx, "KeyService.EncryptBatch",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("batch.count", len(plaintexts)),
+		),
+	)
+	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "encrypt_batch", start, err) }(time.Now())
+
+	if len(plaintexts) == 0 || len(plaintexts) > domain.MaxBatchEncryptItems {
+		return nil, domain.ErrInvalidBatchSize
+	}
+	total := 0
+	for _, p := range plaintexts {
+		total += len(p)
+	}
+	if total > domain.MaxBatchEncryptBytes {
+		return nil, domain.ErrBatchPayloadTooLarge
+	}
+
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindLatestActiveByTenantID(ctx, tenantID)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find current key",
+			"operation", "encrypt_batch",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding current key: %w", err)
+	}
+	if key == nil {
+		slog.WarnContext(ctx, "key not found",
+			"operation", "encrypt_batch",
+			"tenant_id", tenantID,
+		)
+		return nil, domain.ErrKeyNotFound
+	}
+	if key.Expired(time.Now()) {
+		slog.WarnContext(ctx, "current key has expired",
+			"operation", "encrypt_batch",
+			"tenant_id", tenantID,
+			"generation", key.Generation,
+		)
+		return nil, domain.ErrKeyExpired
+	}
+	if key.KeyType != domain.KeyTypeAES {
+		return nil, domain.ErrUnsupportedKeyType
+	}
+
+	plainKey, err := s.decryptKey(ctx, key)
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to decrypt key",
+			"operation", "encrypt_batch",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("decrypting key: %w", err)
+	}
+	defer clear(plainKey)
+
+	ciphertexts, err := sealAll(plainKey, plaintexts)
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to encrypt batch",
+			"operation", "encrypt_batch",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("key.generation", int(key.Generation)))
+	s.recordLastUsed(key.ID)
+	return &domain.BatchEncryptResult{
+		TenantID:    key.TenantID,
+		Generation:  key.Generation,
+		Ciphertexts: ciphertexts,
+	}, nil
+}
+
+// sealAll は各平文をランダムなnonceでAES-GCMにより暗号化し、nonceを先頭に付けて返す。
+func sealAll(key []byte, plaintexts [][]byte) ([][]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating GCM: %w", err)
+	}
+
+	ciphertexts := make([][]byte, len(plaintexts))
+	for i, p := range plaintexts {
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(p)+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("generating nonce: %w", err)
+		}
+		ciphertexts[i] = aead.Seal(nonce, nonce, p, nil)
+	}
+	return ciphertexts, nil
+}
@@ -4,8 +4,11 @@ package usecase
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -14,44 +17,124 @@ import (
 	"key-management-service/internal/domain"
 )
 
-const keySize = 32 // AES-256 = 256 bits = 32 bytes
-
 var tracer = otel.Tracer("key-management-service")
 
 // KeyRepository はデータアクセスのインターフェース。
 type KeyRepository interface {
 	ExistsByTenantID(ctx context.Context, tenantID string) (bool, error)
 	Create(ctx context.Context, key *domain.EncryptionKey) error
+	CreateWithGeneration(ctx context.Context, key *domain.EncryptionKey) error
+	CreateWithRetention(ctx context.Context, key *domain.EncryptionKey, retention int, disabledAt time.Time, reason string) ([]uint, error)
 	FindByTenantIDAndGeneration(ctx context.Context, tenantID string, generation uint) (*domain.EncryptionKey, error)
+	FindByTenantIDAndGenerations(ctx context.Context, tenantID string, generations []uint) ([]*domain.EncryptionKey, error)
 	FindLatestActiveByTenantID(ctx context.Context, tenantID string) (*domain.EncryptionKey, error)
 	FindAllByTenantID(ctx context.Context, tenantID string) ([]*domain.EncryptionKey, error)
+	FindByTenantIDWithFilter(ctx context.Context, tenantID string, filter domain.KeyFilter) ([]*domain.EncryptionKey, error)
+	FindRecentByTenantID(ctx context.Context, tenantID string, n int) ([]*domain.EncryptionKey, error)
+	FindRecentUsableByTenantID(ctx context.Context, tenantID string, keyType domain.KeyType, n int) ([]*domain.EncryptionKey, error)
+	CountByTenantIDGroupedByStatus(ctx context.Context, tenantID string) (map[domain.KeyStatus]int, error)
+	ListTenantIDs(ctx context.Context, limit, offset int) ([]string, error)
+	CountByTenantIDs(ctx context.Context, tenantIDs []string) (map[string]int, error)
 	GetMaxGeneration(ctx context.Context, tenantID string) (uint, error)
+	Disable(ctx context.Context, id string, disabledAt time.Time, reason string) error
 	UpdateStatus(ctx context.Context, id string, status domain.KeyStatus) error
+	UpdateStatusBatch(ctx context.Context, tenantID string, generations []uint, disabledAt time.Time, reason string) (map[uint]domain.KeyStatus, error)
+	FindByKMSKeyName(ctx context.Context, kmsKeyName string, limit, offset int) ([]*domain.EncryptionKey, error)
+	UpdateWrappedKey(ctx context.Context, id, fromKMSKeyName string, encryptedKey []byte, kmsKeyName string, tenantAAD bool) (bool, error)
+	BackfillStatus(ctx context.Context, dryRun bool) (map[domain.KeyStatus]int, error)
+	// SoftDelete・Restore は鍵を論理削除・復元し、対象の行があったかを返す。論理削除した鍵は他のすべての検索から除外される。
+	SoftDelete(ctx context.Context, tenantID string, generation uint) (bool, error)
+	Restore(ctx context.Context, tenantID string, generation uint) (bool, error)
+	// WithTx はfnを1つのトランザクションで実行する。txRepoの操作はfnがエラーを返すとすべてロールバックされる。
+	WithTx(ctx context.Context, fn func(txRepo KeyRepository) error) error
 }
 
 // KMSClient は暗号化/復号のインターフェース。
+// aadは追加認証データで、暗号化時に指定した値と同じ値で復号する必要がある（nilの場合は指定しない）。
 type KMSClient interface {
-	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
-	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+	Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error)
 }
 
 // KeyService は暗号鍵に関するビジネスロジックを提供する。
 type KeyService struct {
-	repo      KeyRepository
-	kmsClient KMSClient
+	repo       KeyRepository
+	kmsClient  KMSClient
+	metrics    *serviceMetrics
+	kmsTimeout time.Duration
+	dbTimeout  time.Duration
+	retention  int
+	maxGen     uint
+	kmsKeyName string
+	lastUsed   *LastUsedTracker
+
+	// settings はテナントごとの設定の参照先。nilの場合は既定のローテーション期限のみを使用する。
+	settings TenantSettingsRepository
+	// rotationDue はテナントに自動ローテーション間隔がない場合のローテーション期限。0の場合は期限なし。
+	rotationDue time.Duration
+
+	// kmsMaxPlaintext はKMSで暗号化できる平文の最大バイト数。0の場合は検証しない。
+	kmsMaxPlaintext int
+
+	// legacyKMSKeys は復号のみに使用する移行元のKMS鍵（decryptKeyを参照）。
+	legacyKMSKeys []LegacyKMSKey
+	// tenantKMSKeys はテナントIDごとの専用のKMS鍵。設定されたテナントの鍵はkmsKeyNameの代わりにこのKMS鍵でラップする。
+	tenantKMSKeys map[string]TenantKMSKey
 }
 
 // NewKeyService は新しいKeyServiceを生成する。
-func NewKeyService(repo KeyRepository, kmsClient KMSClient) *KeyService {
-	return &KeyService{
+func NewKeyService(repo KeyRepository, kmsClient KMSClient, opts ...KeyServiceOption) *KeyService {
+	s := &KeyService{
 		repo:      repo,
 		kmsClient: kmsClient,
+		metrics:   newServiceMetrics(),
+		maxGen:    domain.MaxGenerationLimit,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// generateAESKey はAES-256鍵を生成する。
-func generateAESKey() ([]byte, error) {
-	key := make([]byte, keySize)
+// MaxGeneration は世代番号の上限を返す。
+func (s *KeyService) MaxGeneration() uint {
+	return s.maxGen
+}
+
+// resolveKeySpec は鍵生成指定に既定値を補い、種別と鍵長の組み合わせを検証する。
+func resolveKeySpec(spec domain.KeySpec) (domain.KeySpec, error) {
+	if spec.Type == "" {
+		spec.Type = domain.DefaultKeyType
+	}
+	if !spec.Type.IsValid() {
+		return spec, domain.ErrInvalidKeyType
+	}
+	if spec.Bits == 0 {
+		spec.Bits = spec.Type.DefaultBits()
+	}
+	if !spec.Type.IsValidBits(spec.Bits) {
+		return spec, domain.ErrInvalidKeySize
+	}
+	if spec.ExpiresAt != nil && !spec.ExpiresAt.After(time.Now()) {
+		return spec, domain.ErrInvalidExpiry
+	}
+	return spec, nil
+}
+
+// generateKeyMaterial は鍵種別に応じた鍵素材を生成する。
+func generateKeyMaterial(keyType domain.KeyType, bits int) ([]byte, error) {
+	switch keyType {
+	case domain.KeyTypeAES, domain.KeyTypeHMAC:
+		// いずれも暗号論的乱数列をそのまま鍵素材とする
+		return generateRandomBytes(bits / 8)
+	default:
+		return nil, domain.ErrInvalidKeyType
+	}
+}
+
+// generateRandomBytes は指定されたバイト数の乱数を生成する。
+func generateRandomBytes(size int) ([]byte, error) {
+	key := make([]byte, size)
 	_, err := rand.Read(key)
 	if err != nil {
 		return nil, fmt.Errorf("generating random key: %w", err)
@@ -60,16 +143,24 @@ func generateAESKey() ([]byte, error) {
 }
 
 // CreateKey は指定されたテナントに対して新しい暗号鍵を生成する。
-func (s *KeyService) CreateKey(ctx context.Context, tenantID string) (*domain.KeyMetadata, error) {
+func (s *KeyService) CreateKey(ctx context.Context, tenantID string, spec domain.KeySpec) (_ *domain.KeyMetadata, err error) {
 	ctx, span := tracer.Start(ctx, "KeyService.CreateKey",
 		trace.WithAttributes(
 			attribute.String("tenant.id", tenantID),
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "create_key", start, err) }(time.Now())
+
+	spec, err = resolveKeySpec(spec)
+	if err != nil {
+		return nil, err
+	}
 
 	// 既存チェック
-	exists, err := s.repo.ExistsByTenantID(ctx, tenantID)
+	exists, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (bool, error) {
+		return s.repo.ExistsByTenantID(ctx, tenantID)
+	})
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to check existing key",
@@ -87,14 +178,17 @@ func (s *KeyService) CreateKey(ctx context.Context, tenantID string) (*domain.Ke
 		return nil, domain.ErrKeyAlreadyExists
 	}
 
-	// AES-256鍵を生成
-	plainKey, err := generateAESKey()
+	// 鍵素材を生成
+	plainKey, err := generateKeyMaterial(spec.Type, spec.Bits)
 	if err != nil {
 		return nil, err
 	}
+	// 暗号化後は平文の鍵を保持しない
+	defer clear(plainKey)
 
-	// KMSで暗号化
-	encryptedKey, err := s.kmsClient.Encrypt(ctx, plainKey)
+	// KMSで暗号化（暗号文をテナントに紐づける）
+	kmsKeyName, kmsClient := s.wrappingKMSKey(tenantID)
+	encryptedKey, err := s.kmsEncryptWith(ctx, kmsClient, plainKey, domain.TenantAAD(tenantID))
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to encrypt key",
@@ -109,10 +203,17 @@ func (s *KeyService) CreateKey(ctx context.Context, tenantID string) (*domain.Ke
 	key := &domain.EncryptionKey{
 		TenantID:     tenantID,
 		Generation:   1,
+		KeyType:      spec.Type,
+		Bits:         spec.Bits,
 		EncryptedKey: encryptedKey,
+		KMSKeyName:   kmsKeyName,
+		TenantAAD:    true,
 		Status:       domain.KeyStatusActive,
+		ExpiresAt:    spec.ExpiresAt,
 	}
-	if err := s.repo.Create(ctx, key); err != nil {
+	if err := s.withDBTimeout(ctx, func(ctx context.Context) error {
+		return s.repo.Create(ctx, key)
+	}); err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to create key in database",
 			"operation", "create_key",
@@ -122,25 +223,39 @@ func (s *KeyService) CreateKey(ctx context.Context, tenantID string) (*domain.Ke
 		return nil, fmt.Errorf("creating key: %w", err)
 	}
 
-	span.SetAttributes(attribute.Int("key.generation", 1))
+	span.SetAttributes(
+		attribute.Int("key.generation", 1),
+		attribute.String("key.type", string(spec.Type)),
+		attribute.Int("key.bits", spec.Bits),
+	)
 	return &domain.KeyMetadata{
 		TenantID:   key.TenantID,
 		Generation: key.Generation,
+		KeyType:    key.KeyType,
+		Bits:       key.Bits,
 		Status:     key.Status,
+		KMSKeyName: key.KMSKeyName,
 		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		IsCurrent:  true,
 	}, nil
 }
 
 // GetCurrentKey は指定されたテナントの現在有効な鍵を取得する。
-func (s *KeyService) GetCurrentKey(ctx context.Context, tenantID string) (*domain.Key, error) {
+// 現在の鍵の有効期限が切れている場合はdomain.ErrKeyExpiredを返す（新しいデータを期限切れの鍵で暗号化させないため。ローテーションが必要）。
+func (s *KeyService) GetCurrentKey(ctx context.Context, tenantID string) (_ *domain.Key, err error) {
 	ctx, span := tracer.Start(ctx, "KeyService.GetCurrentKey",
 		trace.WithAttributes(
 			attribute.String("tenant.id", tenantID),
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "get_current_key", start, err) }(time.Now())
 
-	key, err := s.repo.FindLatestActiveByTenantID(ctx, tenantID)
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindLatestActiveByTenantID(ctx, tenantID)
+	})
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to find current key",
@@ -157,9 +272,17 @@ func (s *KeyService) GetCurrentKey(ctx context.Context, tenantID string) (*domai
 		)
 		return nil, domain.ErrKeyNotFound
 	}
+	if key.Expired(time.Now()) {
+		slog.WarnContext(ctx, "current key has expired",
+			"operation", "get_current_key",
+			"tenant_id", tenantID,
+			"generation", key.Generation,
+		)
+		return nil, domain.ErrKeyExpired
+	}
 
 	// KMSで復号
-	plainKey, err := s.kmsClient.Decrypt(ctx, key.EncryptedKey)
+	plainKey, err := s.decryptKey(ctx, key)
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to decrypt key",
@@ -171,15 +294,21 @@ func (s *KeyService) GetCurrentKey(ctx context.Context, tenantID string) (*domai
 	}
 
 	span.SetAttributes(attribute.Int("key.generation", int(key.Generation)))
+	s.recordLastUsed(key.ID)
 	return &domain.Key{
 		TenantID:   key.TenantID,
 		Generation: key.Generation,
+		KeyType:    key.KeyType,
+		Bits:       key.Bits,
 		Key:        plainKey,
+		Status:     key.Status,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
 	}, nil
 }
 
 // GetKeyByGeneration は指定されたテナント・世代の鍵を取得する。
-func (s *KeyService) GetKeyByGeneration(ctx context.Context, tenantID string, generation uint) (*domain.Key, error) {
+func (s *KeyService) GetKeyByGeneration(ctx context.Context, tenantID string, generation uint) (_ *domain.Key, err error) {
 	ctx, span := tracer.Start(ctx, "KeyService.GetKeyByGeneration",
 		trace.WithAttributes(
 			attribute.String("tenant.id", tenantID),
@@ -187,8 +316,11 @@ func (s *KeyService) GetKeyByGeneration(ctx context.Context, tenantID string, ge
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "get_key_by_generation", start, err) }(time.Now())
 
-	key, err := s.repo.FindByTenantIDAndGeneration(ctx, tenantID, generation)
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindByTenantIDAndGeneration(ctx, tenantID, generation)
+	})
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to find key by generation",
@@ -217,7 +349,7 @@ func (s *KeyService) GetKeyByGeneration(ctx context.Context, tenantID string, ge
 	}
 
 	// KMSで復号
-	plainKey, err := s.kmsClient.Decrypt(ctx, key.EncryptedKey)
+	plainKey, err := s.decryptKey(ctx, key)
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to decrypt key",
@@ -229,24 +361,185 @@ func (s *KeyService) GetKeyByGeneration(ctx context.Context, tenantID string, ge
 		return nil, fmt.Errorf("decrypting key: %w", err)
 	}
 
+	s.recordLastUsed(key.ID)
 	return &domain.Key{
 		TenantID:   key.TenantID,
 		Generation: key.Generation,
+		KeyType:    key.KeyType,
+		Bits:       key.Bits,
 		Key:        plainKey,
+		Status:     key.Status,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}, nil
+}
+
+// GetKeyMetadata は指定された世代の鍵メタデータを取得する。KMSは呼び出さず、無効化済みの鍵も返す。
+// 条件付きGETで鍵が変更されていないかを復号前に判定するために使う。
+func (s *KeyService) GetKeyMetadata(ctx context.Context, tenantID string, generation uint) (*domain.KeyMetadata, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.GetKeyMetadata",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("key.generation", int(generation)),
+		),
+	)
+	defer span.End()
+
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindByTenantIDAndGeneration(ctx, tenantID, generation)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find key by generation",
+			"operation", "get_key_metadata",
+			"tenant_id", tenantID,
+			"generation", generation,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding key: %w", err)
+	}
+	if key == nil {
+		return nil, domain.ErrKeyNotFound
+	}
+	return &domain.KeyMetadata{
+		TenantID:       key.TenantID,
+		Generation:     key.Generation,
+		KeyType:        key.KeyType,
+		Bits:           key.Bits,
+		Status:         key.Status,
+		KMSKeyName:     key.KMSKeyName,
+		CreatedAt:      key.CreatedAt,
+		UpdatedAt:      key.UpdatedAt,
+		ExpiresAt:      key.ExpiresAt,
+		LastUsedAt:     key.LastUsedAt,
+		DisabledAt:     key.DisabledAt,
+		DisabledReason: key.DisabledReason,
 	}, nil
 }
 
+// BatchGetKeys は指定されたテナントの複数世代の鍵を一括で取得する。
+// 結果は重複を除いた指定順で返し、無効化済み・存在しない世代は鍵を含まないステータスのみとする。
+func (s *KeyService) BatchGetKeys(ctx context.Context, tenantID string, generations []uint) (_ []*domain.BatchKeyResult, err error) {
+	ctx, span := tracer.Start(ctx, "KeyService.BatchGetKeys",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("batch.count", len(generations)),
+		),
+	)
+	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "batch_get_keys", start, err) }(time.Now())
+
+	if len(generations) == 0 || len(generations) > domain.MaxBatchGetGenerations {
+		return nil, domain.ErrInvalidBatchSize
+	}
+	requested := make([]uint, 0, len(generations))
+	seen := make(map[uint]struct{}, len(generations))
+	for _, gen := range generations {
+		if gen < 1 || gen > s.maxGen {
+			return nil, domain.ErrInvalidGeneration
+		}
+		if _, dup := seen[gen]; dup {
+			continue
+		}
+		seen[gen] = struct{}{}
+		requested = append(requested, gen)
+	}
+
+	keys, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]*domain.EncryptionKey, error) {
+		return s.repo.FindByTenantIDAndGenerations(ctx, tenantID, requested)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find keys for batch get",
+			"operation", "batch_get_keys",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding keys: %w", err)
+	}
+	found := make(map[uint]*domain.EncryptionKey, len(keys))
+	for _, k := range keys {
+		found[k.Generation] = k
+	}
+
+	results := make([]*domain.BatchKeyResult, len(requested))
+	for i, gen := range requested {
+		key, ok := found[gen]
+		switch {
+		case !ok:
+			results[i] = &domain.BatchKeyResult{Generation: gen, Status: domain.BatchKeyStatusNotFound}
+		case key.Status == domain.KeyStatusDisabled:
+			results[i] = &domain.BatchKeyResult{Generation: gen, Status: domain.BatchKeyStatusDisabled}
+		default:
+			plainKey, err := s.decryptKey(ctx, key)
+			if err != nil {
+				span.RecordError(err)
+				slog.ErrorContext(ctx, "failed to decrypt key",
+					"operation", "batch_get_keys",
+					"tenant_id", tenantID,
+					"generation", gen,
+					"error", err,
+				)
+				// 途中で失敗した場合は復号済みの鍵を返さないため消去する
+				for _, res := range results[:i] {
+					res.Key.Zero()
+				}
+				return nil, fmt.Errorf("decrypting key: %w", err)
+			}
+			s.recordLastUsed(key.ID)
+			results[i] = &domain.BatchKeyResult{
+				Generation: gen,
+				Status:     domain.BatchKeyStatusOK,
+				Key: &domain.Key{
+					TenantID:   key.TenantID,
+					Generation: key.Generation,
+					KeyType:    key.KeyType,
+					Bits:       key.Bits,
+					Key:        plainKey,
+					Status:     key.Status,
+					CreatedAt:  key.CreatedAt,
+					UpdatedAt:  key.UpdatedAt,
+				},
+			}
+		}
+	}
+	return results, nil
+}
+
 // RotateKey は指定されたテナントに対して新しい世代の鍵を生成する。
-func (s *KeyService) RotateKey(ctx context.Context, tenantID string) (*domain.KeyMetadata, error) {
+func (s *KeyService) RotateKey(ctx context.Context, tenantID string, spec domain.KeySpec) (*domain.KeyMetadata, error) {
+	return s.rotateKey(ctx, tenantID, spec, 0)
+}
+
+// RotateKeyFrom は最新の世代がfromGenerationである場合のみ、世代fromGeneration+1の鍵を生成する。
+// 最新の世代が変わっていた場合や、同じ世代が同時に作成された場合はdomain.ErrGenerationAlreadyExistsを返す。
+// 複数のレプリカが同じ状態を見てローテーションした場合に、世代が重複して進まないようにするために使用する。
+func (s *KeyService) RotateKeyFrom(ctx context.Context, tenantID string, spec domain.KeySpec, fromGeneration uint) (*domain.KeyMetadata, error) {
+	if fromGeneration == 0 {
+		return nil, domain.ErrInvalidGeneration
+	}
+	return s.rotateKey(ctx, tenantID, spec, fromGeneration)
+}
+
+// rotateKey はRotateKey・RotateKeyFromの実装。fromGenerationが0の場合は最新の世代を確認しない。
+func (s *KeyService) rotateKey(ctx context.Context, tenantID string, spec domain.KeySpec, fromGeneration uint) (_ *domain.KeyMetadata, err error) {
 	ctx, span := tracer.Start(ctx, "KeyService.RotateKey",
 		trace.WithAttributes(
 			attribute.String("tenant.id", tenantID),
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "rotate_key", start, err) }(time.Now())
+
+	spec, err = resolveKeySpec(spec)
+	if err != nil {
+		return nil, err
+	}
 
 	// 既存鍵の確認
-	maxGen, err := s.repo.GetMaxGeneration(ctx, tenantID)
+	maxGen, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (uint, error) {
+		return s.repo.GetMaxGeneration(ctx, tenantID)
+	})
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to get max generation",
@@ -263,15 +556,36 @@ func (s *KeyService) RotateKey(ctx context.Context, tenantID string) (*domain.Ke
 		)
 		return nil, domain.ErrKeyNotFound
 	}
+	if maxGen >= s.maxGen {
+		slog.WarnContext(ctx, "max generation reached",
+			"operation", "rotate_key",
+			"tenant_id", tenantID,
+			"generation", maxGen,
+			"max_generation", s.maxGen,
+		)
+		return nil, domain.ErrMaxGenerationReached
+	}
+	if fromGeneration != 0 && maxGen != fromGeneration {
+		slog.InfoContext(ctx, "latest generation changed; rotation skipped",
+			"operation", "rotate_key",
+			"tenant_id", tenantID,
+			"generation", maxGen,
+			"expected_generation", fromGeneration,
+		)
+		return nil, fmt.Errorf("%w: latest generation is %d, expected %d", domain.ErrGenerationAlreadyExists, maxGen, fromGeneration)
+	}
 
-	// AES-256鍵を生成
-	plainKey, err := generateAESKey()
+	// 鍵素材を生成
+	plainKey, err := generateKeyMaterial(spec.Type, spec.Bits)
 	if err != nil {
 		return nil, err
 	}
+	// 暗号化後は平文の鍵を保持しない
+	defer clear(plainKey)
 
-	// KMSで暗号化
-	encryptedKey, err := s.kmsClient.Encrypt(ctx, plainKey)
+	// KMSで暗号化（暗号文をテナントに紐づける）
+	kmsKeyName, kmsClient := s.wrappingKMSKey(tenantID)
+	encryptedKey, err := s.kmsEncryptWith(ctx, kmsClient, plainKey, domain.TenantAAD(tenantID))
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to encrypt key",
@@ -282,16 +596,38 @@ func (s *KeyService) RotateKey(ctx context.Context, tenantID string) (*domain.Ke
 		return nil, fmt.Errorf("encrypting key: %w", err)
 	}
 
-	// DBに保存
+	// DBに保存（保持世代数が設定されている場合は、超過した古い世代を同じトランザクションで無効化）
 	newGen := maxGen + 1
 	key := &domain.EncryptionKey{
 		TenantID:     tenantID,
 		Generation:   newGen,
+		KeyType:      spec.Type,
+		Bits:         spec.Bits,
 		EncryptedKey: encryptedKey,
+		KMSKeyName:   kmsKeyName,
+		TenantAAD:    true,
 		Status:       domain.KeyStatusActive,
+		ExpiresAt:    spec.ExpiresAt,
 	}
-	if err := s.repo.Create(ctx, key); err != nil {
+	var retired []uint
+	if err := s.withDBTimeout(ctx, func(ctx context.Context) error {
+		if s.retention <= 0 {
+			return s.repo.Create(ctx, key)
+		}
+		var err error
+		retired, err = s.repo.CreateWithRetention(ctx, key, s.retention, time.Now(), domain.DisableReasonRetention)
+		return err
+	}); err != nil {
 		span.RecordError(err)
+		if errors.Is(err, domain.ErrGenerationAlreadyExists) {
+			// 同じテナントのローテーションが同時に行われ、先に同じ世代が作成された
+			slog.WarnContext(ctx, "generation created concurrently",
+				"operation", "rotate_key",
+				"tenant_id", tenantID,
+				"generation", newGen,
+			)
+			return nil, err
+		}
 		slog.ErrorContext(ctx, "failed to create rotated key in database",
 			"operation", "rotate_key",
 			"tenant_id", tenantID,
@@ -299,18 +635,37 @@ func (s *KeyService) RotateKey(ctx context.Context, tenantID string) (*domain.Ke
 		)
 		return nil, fmt.Errorf("creating key: %w", err)
 	}
+	if len(retired) > 0 {
+		slog.InfoContext(ctx, "disabled generations beyond retention window",
+			"operation", "rotate_key",
+			"tenant_id", tenantID,
+			"retention", s.retention,
+			"generations", retired,
+		)
+	}
 
-	span.SetAttributes(attribute.Int("key.generation", int(newGen)))
+	span.SetAttributes(
+		attribute.Int("key.generation", int(newGen)),
+		attribute.String("key.type", string(spec.Type)),
+		attribute.Int("key.bits", spec.Bits),
+	)
 	return &domain.KeyMetadata{
 		TenantID:   key.TenantID,
 		Generation: key.Generation,
+		KeyType:    key.KeyType,
+		Bits:       key.Bits,
 		Status:     key.Status,
+		KMSKeyName: key.KMSKeyName,
 		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		IsCurrent:  true,
 	}, nil
 }
 
-// ListKeys は指定されたテナントの全世代の鍵メタデータを取得する。
-func (s *KeyService) ListKeys(ctx context.Context, tenantID string) ([]*domain.KeyMetadata, error) {
+// ListKeys は指定されたテナントの鍵メタデータを絞り込み条件に従って取得する。
+// 条件を指定しない場合は全世代を返す。
+func (s *KeyService) ListKeys(ctx context.Context, tenantID string, filter domain.KeyFilter) ([]*domain.KeyMetadata, error) {
 	ctx, span := tracer.Start(ctx, "KeyService.ListKeys",
 		trace.WithAttributes(
 			attribute.String("tenant.id", tenantID),
@@ -318,7 +673,13 @@ func (s *KeyService) ListKeys(ctx context.Context, tenantID string) ([]*domain.K
 	)
 	defer span.End()
 
-	keys, err := s.repo.FindAllByTenantID(ctx, tenantID)
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	keys, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]*domain.EncryptionKey, error) {
+		return s.repo.FindByTenantIDWithFilter(ctx, tenantID, filter)
+	})
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to find all keys",
@@ -332,17 +693,204 @@ func (s *KeyService) ListKeys(ctx context.Context, tenantID string) ([]*domain.K
 	metadata := make([]*domain.KeyMetadata, len(keys))
 	for i, k := range keys {
 		metadata[i] = &domain.KeyMetadata{
-			TenantID:   k.TenantID,
-			Generation: k.Generation,
-			Status:     k.Status,
-			CreatedAt:  k.CreatedAt,
+			TenantID:       k.TenantID,
+			Generation:     k.Generation,
+			KeyType:        k.KeyType,
+			Bits:           k.Bits,
+			Status:         k.Status,
+			KMSKeyName:     k.KMSKeyName,
+			CreatedAt:      k.CreatedAt,
+			UpdatedAt:      k.UpdatedAt,
+			ExpiresAt:      k.ExpiresAt,
+			LastUsedAt:     k.LastUsedAt,
+			DisabledAt:     k.DisabledAt,
+			DisabledReason: k.DisabledReason,
+		}
+	}
+
+	current, err := s.currentGeneration(ctx, tenantID, filter, keys)
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find current key",
+			"operation", "list_keys",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding current key: %w", err)
+	}
+	for _, m := range metadata {
+		m.IsCurrent = current > 0 && m.Generation == current
+	}
+	return metadata, nil
+}
+
+// ListRecentKeys は指定されたテナントの新しい鍵n件のメタデータを世代の降順で返す。鍵の復号は行わない。
+// 全世代を取得してから切り出すのではなく、データベースで件数を絞り込む。
+func (s *KeyService) ListRecentKeys(ctx context.Context, tenantID string, n int) ([]*domain.KeyMetadata, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.ListRecentKeys",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("keys.limit", n),
+		),
+	)
+	defer span.End()
+
+	keys, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]*domain.EncryptionKey, error) {
+		return s.repo.FindRecentByTenantID(ctx, tenantID, n)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find recent keys",
+			"operation", "list_recent_keys",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding recent keys: %w", err)
+	}
+
+	// 新しい順のn件に有効な鍵があれば、その最新のものがテナントの現在の鍵になる。
+	// 有効な鍵がなければ現在の鍵はn件より古いため、どの鍵も現在の鍵ではない
+	currentFound := false
+	metadata := make([]*domain.KeyMetadata, len(keys))
+	for i, k := range keys {
+		isCurrent := !currentFound && k.Status == domain.KeyStatusActive
+		currentFound = currentFound || isCurrent
+		metadata[i] = &domain.KeyMetadata{
+			TenantID:       k.TenantID,
+			Generation:     k.Generation,
+			KeyType:        k.KeyType,
+			Bits:           k.Bits,
+			Status:         k.Status,
+			KMSKeyName:     k.KMSKeyName,
+			CreatedAt:      k.CreatedAt,
+			UpdatedAt:      k.UpdatedAt,
+			ExpiresAt:      k.ExpiresAt,
+			LastUsedAt:     k.LastUsedAt,
+			DisabledAt:     k.DisabledAt,
+			DisabledReason: k.DisabledReason,
+			IsCurrent:      isCurrent,
 		}
 	}
 	return metadata, nil
 }
 
-// DisableKey は指定されたテナント・世代の鍵を無効化する。
-func (s *KeyService) DisableKey(ctx context.Context, tenantID string, generation uint) error {
+// currentGeneration は現在の鍵（最新の有効な鍵）の世代を返す。有効な鍵がない場合は0を返す。
+// 絞り込み条件がない場合は一覧に全世代が含まれるため、一覧から求めてデータベースの問い合わせを省く。
+func (s *KeyService) currentGeneration(ctx context.Context, tenantID string, filter domain.KeyFilter, keys []*domain.EncryptionKey) (uint, error) {
+	if filter.IsZero() {
+		var current uint
+		for _, k := range keys {
+			if k.Status == domain.KeyStatusActive && k.Generation > current {
+				current = k.Generation
+			}
+		}
+		return current, nil
+	}
+
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindLatestActiveByTenantID(ctx, tenantID)
+	})
+	if err != nil || key == nil {
+		return 0, err
+	}
+	return key.Generation, nil
+}
+
+// CountKeys は指定されたテナントの鍵数をステータスごとに取得する。
+func (s *KeyService) CountKeys(ctx context.Context, tenantID string) (map[domain.KeyStatus]int, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.CountKeys",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+		),
+	)
+	defer span.End()
+
+	counts, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (map[domain.KeyStatus]int, error) {
+		return s.repo.CountByTenantIDGroupedByStatus(ctx, tenantID)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to count keys",
+			"operation", "count_keys",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("counting keys: %w", err)
+	}
+	return counts, nil
+}
+
+// TenantExists は指定されたテナントに鍵が1件以上存在するかを返す。ステータスは問わない。
+func (s *KeyService) TenantExists(ctx context.Context, tenantID string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.TenantExists",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+		),
+	)
+	defer span.End()
+
+	exists, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (bool, error) {
+		return s.repo.ExistsByTenantID(ctx, tenantID)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to check tenant existence",
+			"operation", "tenant_exists",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return false, fmt.Errorf("checking tenant existence: %w", err)
+	}
+	return exists, nil
+}
+
+// ListTenants は鍵が存在するテナントの一覧を鍵数付きで取得する。
+func (s *KeyService) ListTenants(ctx context.Context, limit, offset int) ([]*domain.TenantSummary, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.ListTenants",
+		trace.WithAttributes(
+			attribute.Int("page.limit", limit),
+			attribute.Int("page.offset", offset),
+		),
+	)
+	defer span.End()
+
+	tenantIDs, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]string, error) {
+		return s.repo.ListTenantIDs(ctx, limit, offset)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to list tenant ids",
+			"operation", "list_tenants",
+			"error", err,
+		)
+		return nil, fmt.Errorf("listing tenants: %w", err)
+	}
+
+	counts, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (map[string]int, error) {
+		return s.repo.CountByTenantIDs(ctx, tenantIDs)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to count keys for tenants",
+			"operation", "list_tenants",
+			"error", err,
+		)
+		return nil, fmt.Errorf("counting keys: %w", err)
+	}
+
+	tenants := make([]*domain.TenantSummary, len(tenantIDs))
+	for i, id := range tenantIDs {
+		tenants[i] = &domain.TenantSummary{
+			TenantID: id,
+			KeyCount: counts[id],
+		}
+	}
+	return tenants, nil
+}
+
+// DisableKey は指定されたテナント・世代の鍵を無効化し、無効化日時と理由を記録する。
+// 戻り値は無効化後に保存された状態のメタデータ。
+func (s *KeyService) DisableKey(ctx context.Context, tenantID string, generation uint, reason string) (_ *domain.KeyMetadata, err error) {
 	ctx, span := tracer.Start(ctx, "KeyService.DisableKey",
 		trace.WithAttributes(
 			attribute.String("tenant.id", tenantID),
@@ -350,8 +898,15 @@ func (s *KeyService) DisableKey(ctx context.Context, tenantID string, generation
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "disable_key", start, err) }(time.Now())
+
+	if utf8.RuneCountInString(reason) > domain.MaxDisableReasonLen {
+		return nil, domain.ErrInvalidDisableReason
+	}
 
-	key, err := s.repo.FindByTenantIDAndGeneration(ctx, tenantID, generation)
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindByTenantIDAndGeneration(ctx, tenantID, generation)
+	})
 	if err != nil {
 		span.RecordError(err)
 		slog.ErrorContext(ctx, "failed to find key for disable",
@@ -360,7 +915,7 @@ func (s *KeyService) DisableKey(ctx context.Context, tenantID string, generation
 			"generation", generation,
 			"error", err,
 		)
-		return fmt.Errorf("finding key: %w", err)
+		return nil, fmt.Errorf("finding key: %w", err)
 	}
 	if key == nil {
 		slog.WarnContext(ctx, "key not found",
@@ -368,7 +923,7 @@ func (s *KeyService) DisableKey(ctx context.Context, tenantID string, generation
 			"tenant_id", tenantID,
 			"generation", generation,
 		)
-		return domain.ErrKeyNotFound
+		return nil, domain.ErrKeyNotFound
 	}
 	if key.Status == domain.KeyStatusDisabled {
 		slog.WarnContext(ctx, "key is already disabled",
@@ -376,19 +931,285 @@ func (s *KeyService) DisableKey(ctx context.Context, tenantID string, generation
 			"tenant_id", tenantID,
 			"generation", generation,
 		)
-		return domain.ErrKeyAlreadyDisabled
+		return nil, domain.ErrKeyAlreadyDisabled
 	}
 
-	if err := s.repo.UpdateStatus(ctx, key.ID, domain.KeyStatusDisabled); err != nil {
+	disabledAt := time.Now()
+	if err := s.withDBTimeout(ctx, func(ctx context.Context) error {
+		return s.repo.Disable(ctx, key.ID, disabledAt, reason)
+	}); err != nil {
 		span.RecordError(err)
-		slog.ErrorContext(ctx, "failed to update key status",
+		slog.ErrorContext(ctx, "failed to disable key",
 			"operation", "disable_key",
 			"tenant_id", tenantID,
 			"generation", generation,
 			"error", err,
 		)
-		return fmt.Errorf("updating status: %w", err)
+		return nil, fmt.Errorf("disabling key: %w", err)
+	}
+
+	return &domain.KeyMetadata{
+		TenantID:       key.TenantID,
+		Generation:     key.Generation,
+		KeyType:        key.KeyType,
+		Bits:           key.Bits,
+		Status:         domain.KeyStatusDisabled,
+		KMSKeyName:     key.KMSKeyName,
+		CreatedAt:      key.CreatedAt,
+		UpdatedAt:      disabledAt,
+		ExpiresAt:      key.ExpiresAt,
+		LastUsedAt:     key.LastUsedAt,
+		DisabledAt:     &disabledAt,
+		DisabledReason: reason,
+	}, nil
+}
+
+// DeprecateKey は指定された世代の鍵を非推奨にする。
+// 非推奨の鍵は世代を指定した取得（既存データの復号）には引き続き使えるが、現在の鍵には選ばれない。
+func (s *KeyService) DeprecateKey(ctx context.Context, tenantID string, generation uint) (_ *domain.KeyMetadata, err error) {
+	ctx, span := tracer.Start(ctx, "KeyService.DeprecateKey",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("key.generation", int(generation)),
+		),
+	)
+	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "deprecate_key", start, err) }(time.Now())
+
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindByTenantIDAndGeneration(ctx, tenantID, generation)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find key for deprecate",
+			"operation", "deprecate_key",
+			"tenant_id", tenantID,
+			"generation", generation,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding key: %w", err)
+	}
+	if key == nil {
+		slog.WarnContext(ctx, "key not found",
+			"operation", "deprecate_key",
+			"tenant_id", tenantID,
+			"generation", generation,
+		)
+		return nil, domain.ErrKeyNotFound
+	}
+	switch key.Status {
+	case domain.KeyStatusDisabled:
+		return nil, domain.ErrKeyDisabled
+	case domain.KeyStatusDeprecated:
+		return nil, domain.ErrKeyAlreadyDeprecated
+	}
+
+	if err := s.withDBTimeout(ctx, func(ctx context.Context) error {
+		return s.repo.UpdateStatus(ctx, key.ID, domain.KeyStatusDeprecated)
+	}); err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to deprecate key",
+			"operation", "deprecate_key",
+			"tenant_id", tenantID,
+			"generation", generation,
+			"error", err,
+		)
+		return nil, fmt.Errorf("deprecating key: %w", err)
+	}
+
+	slog.InfoContext(ctx, "key deprecated",
+		"operation", "deprecate_key",
+		"tenant_id", tenantID,
+		"generation", generation,
+	)
+	return s.GetKeyMetadata(ctx, tenantID, generation)
+}
+
+// DisableKeys は指定されたテナントの複数世代の鍵を1トランザクションで無効化する。
+// 結果は重複を除いた指定順で返し、既に無効化済み・存在しない世代はそのステータスのみとする。
+func (s *KeyService) DisableKeys(ctx context.Context, tenantID string, generations []uint, reason string) (_ []*domain.BatchDisableResult, err error) {
+	ctx, span := tracer.Start(ctx, "KeyService.DisableKeys",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("batch.count", len(generations)),
+		),
+	)
+	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "disable_keys", start, err) }(time.Now())
+
+	if utf8.RuneCountInString(reason) > domain.MaxDisableReasonLen {
+		return nil, domain.ErrInvalidDisableReason
+	}
+	if len(generations) == 0 || len(generations) > domain.MaxBatchDisableGenerations {
+		return nil, domain.ErrInvalidBatchSize
+	}
+	requested := make([]uint, 0, len(generations))
+	seen := make(map[uint]struct{}, len(generations))
+	for _, gen := range generations {
+		if gen < 1 || gen > s.maxGen {
+			return nil, domain.ErrInvalidGeneration
+		}
+		if _, dup := seen[gen]; dup {
+			continue
+		}
+		seen[gen] = struct{}{}
+		requested = append(requested, gen)
+	}
+
+	previous, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (map[uint]domain.KeyStatus, error) {
+		return s.repo.UpdateStatusBatch(ctx, tenantID, requested, time.Now(), reason)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to disable keys",
+			"operation", "disable_keys",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("disabling keys: %w", err)
+	}
+
+	results := make([]*domain.BatchDisableResult, len(requested))
+	disabled := 0
+	for i, gen := range requested {
+		status, ok := previous[gen]
+		switch {
+		case !ok:
+			results[i] = &domain.BatchDisableResult{Generation: gen, Status: domain.BatchDisableStatusNotFound}
+		case status == domain.KeyStatusDisabled:
+			results[i] = &domain.BatchDisableResult{Generation: gen, Status: domain.BatchDisableStatusAlreadyDisabled}
+		default:
+			results[i] = &domain.BatchDisableResult{Generation: gen, Status: domain.BatchDisableStatusDisabled}
+			disabled++
+		}
 	}
+	slog.InfoContext(ctx, "keys disabled",
+		"operation", "disable_keys",
+		"tenant_id", tenantID,
+		"requested", len(requested),
+		"disabled", disabled,
+	)
+	return results, nil
+}
+
+// ImportKeys はバックアップされたラップ済み鍵を世代番号・作成日時を保持したまま取り込む。
+// いずれかの世代が既に存在する場合は何も保存せずにエラーを返す。
+// KMS鍵名は呼び出し元が指定したものをそのまま保存し、現在のKMS鍵名で補完しない。
+func (s *KeyService) ImportKeys(ctx context.Context, tenantID string, keys []*domain.EncryptionKey) ([]*domain.KeyMetadata, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.ImportKeys",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.Int("import.count", len(keys)),
+		),
+	)
+	defer span.End()
 
-	return nil
+	// 入力値と世代の重複を検証
+	seen := make(map[uint]struct{}, len(keys))
+	for _, k := range keys {
+		if k.Generation < 1 {
+			return nil, domain.ErrInvalidGeneration
+		}
+		if _, dup := seen[k.Generation]; dup {
+			return nil, fmt.Errorf("%w: duplicate generation %d in bundle", domain.ErrInvalidGeneration, k.Generation)
+		}
+		seen[k.Generation] = struct{}{}
+		if !k.Status.IsValid() {
+			return nil, domain.ErrInvalidKeyStatus
+		}
+		spec, err := resolveKeySpec(domain.KeySpec{Type: k.KeyType, Bits: k.Bits})
+		if err != nil {
+			return nil, err
+		}
+		k.KeyType = spec.Type
+		k.Bits = spec.Bits
+		if err := s.checkCiphertextSize(k.EncryptedKey); err != nil {
+			return nil, fmt.Errorf("generation %d: %w", k.Generation, err)
+		}
+	}
+
+	// 既存世代との衝突の確認とすべての世代の保存を1つのトランザクションで行い、
+	// 確認後に同じ世代が作成された場合も一意制約の違反として全体をロールバックする
+	// KMS鍵名はバックアップに記録されたものを保持する。記録がない鍵は実際にラップしたKMS鍵が不明なため空のまま保存し、
+	// 復号時は現在のKMS鍵・移行元のKMS鍵の順に試行する
+	for _, k := range keys {
+		k.TenantID = tenantID
+	}
+	err := s.withDBTimeout(ctx, func(ctx context.Context) error {
+		return s.repo.WithTx(ctx, func(txRepo KeyRepository) error {
+			for _, k := range keys {
+				existing, err := txRepo.FindByTenantIDAndGeneration(ctx, tenantID, k.Generation)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to check existing generation",
+						"operation", "import_keys",
+						"tenant_id", tenantID,
+						"generation", k.Generation,
+						"error", err,
+					)
+					return fmt.Errorf("finding key: %w", err)
+				}
+				if existing != nil {
+					return fmt.Errorf("%w: generation %d", domain.ErrGenerationAlreadyExists, k.Generation)
+				}
+			}
+			for _, k := range keys {
+				if err := txRepo.CreateWithGeneration(ctx, k); err != nil {
+					if errors.Is(err, domain.ErrGenerationAlreadyExists) {
+						return err
+					}
+					slog.ErrorContext(ctx, "failed to import key",
+						"operation", "import_keys",
+						"tenant_id", tenantID,
+						"generation", k.Generation,
+						"error", err,
+					)
+					return fmt.Errorf("importing key: %w", err)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, domain.ErrGenerationAlreadyExists) {
+			slog.WarnContext(ctx, "generation already exists",
+				"operation", "import_keys",
+				"tenant_id", tenantID,
+				"error", err,
+			)
+		}
+		return nil, err
+	}
+
+	metadata := make([]*domain.KeyMetadata, 0, len(keys))
+	for _, k := range keys {
+		metadata = append(metadata, &domain.KeyMetadata{
+			TenantID:   k.TenantID,
+			Generation: k.Generation,
+			KeyType:    k.KeyType,
+			Bits:       k.Bits,
+			Status:     k.Status,
+			KMSKeyName: k.KMSKeyName,
+			CreatedAt:  k.CreatedAt,
+			UpdatedAt:  k.UpdatedAt,
+			ExpiresAt:  k.ExpiresAt,
+		})
+	}
+
+	// 取り込んだ世代が現在の鍵になったかは既存の世代にもよるため、取り込み後に確認する
+	current, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindLatestActiveByTenantID(ctx, tenantID)
+	})
+	if err != nil {
+		// 取り込みは完了しているため、現在の鍵が不明なまま結果を返す
+		slog.WarnContext(ctx, "failed to find current key after import",
+			"operation", "import_keys",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+	}
+	for _, m := range metadata {
+		m.IsCurrent = current != nil && m.Generation == current.Generation
+	}
+	return metadata, nil
 }
@@ -1,8 +1,14 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,16 +26,58 @@ type mockKeyRepository struct {
 	findLatestErr    error
 	findAllResult    []*domain.EncryptionKey
 	findAllErr       error
+	lastFilter       domain.KeyFilter
+	countResult      map[domain.KeyStatus]int
+	countErr         error
+	tenantIDsResult  []string
+	tenantIDsErr     error
+	tenantCounts     map[string]int
+	tenantCountsErr  error
 	maxGenResult     uint
 	maxGenErr        error
-	updateStatusErr  error
+	disableErr       error
+	disabledAt       time.Time
+	disabledReason   string
+	updatedStatus    domain.KeyStatus
 	createdKeys      []*domain.EncryptionKey
+	block            bool
+	findByGensResult []*domain.EncryptionKey
+	softDeleteResult bool
+	restoreResult    bool
+	backfillCounts   map[domain.KeyStatus]int
+	backfillErr      error
+	backfillDryRun   bool
 }
 
 func (m *mockKeyRepository) ExistsByTenantID(ctx context.Context, tenantID string) (bool, error) {
 	return m.existsResult, m.existsErr
 }
 
+// CreateWithRetention はcreatedKeysを保存済みの鍵として扱い、保持数を超えた有効鍵を無効化する。
+func (m *mockKeyRepository) CreateWithRetention(ctx context.Context, key *domain.EncryptionKey, retention int, disabledAt time.Time, reason string) ([]uint, error) {
+	if err := m.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	m.maxGenResult = key.Generation
+
+	var disabled []uint
+	active := 0
+	for i := len(m.createdKeys) - 1; i >= 0; i-- {
+		k := m.createdKeys[i]
+		if k.Status != domain.KeyStatusActive {
+			continue
+		}
+		active++
+		if active > retention {
+			k.Status = domain.KeyStatusDisabled
+			k.DisabledAt = &disabledAt
+			k.DisabledReason = reason
+			disabled = append(disabled, k.Generation)
+		}
+	}
+	return disabled, nil
+}
+
 func (m *mockKeyRepository) Create(ctx context.Context, key *domain.EncryptionKey) error {
 	if m.createErr != nil {
 		return m.createErr
@@ -39,11 +87,26 @@ func (m *mockKeyRepository) Create(ctx context.Context, key *domain.EncryptionKe
 	return nil
 }
 
+func (m *mockKeyRepository) CreateWithGeneration(ctx context.Context, key *domain.EncryptionKey) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	m.createdKeys = append(m.createdKeys, key)
+	return nil
+}
+
 func (m *mockKeyRepository) FindByTenantIDAndGeneration(ctx context.Context, tenantID string, generation uint) (*domain.EncryptionKey, error) {
 	return m.findByGenResult, m.findByGenErr
 }
 
 func (m *mockKeyRepository) FindLatestActiveByTenantID(ctx context.Context, tenantID string) (*domain.EncryptionKey, error) {
+	if m.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	return m.findLatestResult, m.findLatestErr
 }
 
@@ -51,12 +114,137 @@ func (m *mockKeyRepository) FindAllByTenantID(ctx context.Context, tenantID stri
 	return m.findAllResult, m.findAllErr
 }
 
+func (m *mockKeyRepository) FindByTenantIDWithFilter(ctx context.Context, tenantID string, filter domain.KeyFilter) ([]*domain.EncryptionKey, error) {
+	m.lastFilter = filter
+	return m.findAllResult, m.findAllErr
+}
+
+func (m *mockKeyRepository) FindRecentByTenantID(ctx context.Context, tenantID string, n int) ([]*domain.EncryptionKey, error) {
+	if m.findAllErr != nil {
+		return nil, m.findAllErr
+	}
+	// findAllResultは世代の昇順のため、末尾から新しい順に取り出す
+	recent := slices.Clone(m.findAllResult)
+	slices.Reverse(recent)
+	return recent[:min(n, len(recent))], nil
+}
+
+func (m *mockKeyRepository) FindRecentUsableByTenantID(ctx context.Context, tenantID string, keyType domain.KeyType, n int) ([]*domain.EncryptionKey, error) {
+	if m.findAllErr != nil {
+		return nil, m.findAllErr
+	}
+	var recent []*domain.EncryptionKey
+	for _, k := range slices.Backward(m.findAllResult) {
+		if k.Status != domain.KeyStatusDisabled && k.KeyType == keyType && len(recent) < n {
+			recent = append(recent, k)
+		}
+	}
+	return recent, nil
+}
+
+func (m *mockKeyRepository) CountByTenantIDGroupedByStatus(ctx context.Context, tenantID string) (map[domain.KeyStatus]int, error) {
+	return m.countResult, m.countErr
+}
+
+func (m *mockKeyRepository) ListTenantIDs(ctx context.Context, limit, offset int) ([]string, error) {
+	return m.tenantIDsResult, m.tenantIDsErr
+}
+
+func (m *mockKeyRepository) CountByTenantIDs(ctx context.Context, tenantIDs []string) (map[string]int, error) {
+	return m.tenantCounts, m.tenantCountsErr
+}
+
+func (m *mockKeyRepository) FindByTenantIDAndGenerations(ctx context.Context, tenantID string, generations []uint) ([]*domain.EncryptionKey, error) {
+	return m.findByGensResult, m.findByGenErr
+}
+
 func (m *mockKeyRepository) GetMaxGeneration(ctx context.Context, tenantID string) (uint, error) {
 	return m.maxGenResult, m.maxGenErr
 }
 
+func (m *mockKeyRepository) Disable(ctx context.Context, id string, disabledAt time.Time, reason string) error {
+	m.disabledAt = disabledAt
+	m.disabledReason = reason
+	return m.disableErr
+}
+
+// UpdateStatusBatch はfindAllResultのうち指定された世代の有効な鍵を無効化し、更新前のステータスを返す。
+func (m *mockKeyRepository) UpdateStatusBatch(ctx context.Context, tenantID string, generations []uint, disabledAt time.Time, reason string) (map[uint]domain.KeyStatus, error) {
+	if m.disableErr != nil {
+		return nil, m.disableErr
+	}
+	previous := make(map[uint]domain.KeyStatus)
+	for _, k := range m.findAllResult {
+		for _, gen := range generations {
+			if k.Generation != gen {
+				continue
+			}
+			previous[gen] = k.Status
+			if k.Status == domain.KeyStatusActive {
+				k.Status = domain.KeyStatusDisabled
+				k.DisabledAt = &disabledAt
+				k.DisabledReason = reason
+			}
+		}
+	}
+	return previous, nil
+}
+
+// FindByKMSKeyName はfindAllResultのうち指定されたKMS鍵名の鍵を返す。
+func (m *mockKeyRepository) FindByKMSKeyName(ctx context.Context, kmsKeyName string, limit, offset int) ([]*domain.EncryptionKey, error) {
+	var matched []*domain.EncryptionKey
+	for _, k := range m.findAllResult {
+		if k.KMSKeyName == kmsKeyName {
+			matched = append(matched, k)
+		}
+	}
+	if offset >= len(matched) {
+		return nil, nil
+	}
+	matched = matched[offset:]
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+// UpdateWrappedKey はfindAllResultの該当する鍵を更新する。
+func (m *mockKeyRepository) UpdateWrappedKey(ctx context.Context, id, fromKMSKeyName string, encryptedKey []byte, kmsKeyName string, tenantAAD bool) (bool, error) {
+	for _, k := range m.findAllResult {
+		if k.ID == id && k.KMSKeyName == fromKMSKeyName {
+			k.EncryptedKey = encryptedKey
+			k.KMSKeyName = kmsKeyName
+			k.TenantAAD = tenantAAD
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// BackfillStatus はbackfillCountsを返し、dry-runの指定を記録する。
+func (m *mockKeyRepository) BackfillStatus(ctx context.Context, dryRun bool) (map[domain.KeyStatus]int, error) {
+	m.backfillDryRun = dryRun
+	return m.backfillCounts, m.backfillErr
+}
+
 func (m *mockKeyRepository) UpdateStatus(ctx context.Context, id string, status domain.KeyStatus) error {
-	return m.updateStatusErr
+	m.updatedStatus = status
+	if m.findByGenResult != nil && m.findByGenResult.ID == id {
+		m.findByGenResult.Status = status
+	}
+	return nil
+}
+
+func (m *mockKeyRepository) SoftDelete(ctx context.Context, tenantID string, generation uint) (bool, error) {
+	return m.softDeleteResult, nil
+}
+
+func (m *mockKeyRepository) Restore(ctx context.Context, tenantID string, generation uint) (bool, error) {
+	return m.restoreResult, nil
+}
+
+func (m *mockKeyRepository) WithTx(ctx context.Context, fn func(txRepo KeyRepository) error) error {
+	return fn(m)
 }
 
 // mockKMSClient はテスト用のモックKMSクライアント。
@@ -65,9 +253,14 @@ type mockKMSClient struct {
 	encryptErr    error
 	decryptResult []byte
 	decryptErr    error
+	block         bool
 }
 
-func (m *mockKMSClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+func (m *mockKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	if m.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	if m.encryptErr != nil {
 		return nil, m.encryptErr
 	}
@@ -77,14 +270,14 @@ func (m *mockKMSClient) Encrypt(ctx context.Context, plaintext []byte) ([]byte,
 	return append([]byte("encrypted:"), plaintext...), nil
 }
 
-func (m *mockKMSClient) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+func (m *mockKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
 	if m.decryptErr != nil {
 		return nil, m.decryptErr
 	}
 	if m.decryptResult != nil {
 		return m.decryptResult, nil
 	}
-	return []byte("decrypted-key"), nil
+	return []byte("decrypted-key-0123456789abcdefgh"), nil
 }
 
 func TestKeyService_CreateKey_Success(t *testing.T) {
@@ -92,7 +285,7 @@ func TestKeyService_CreateKey_Success(t *testing.T) {
 	kms := &mockKMSClient{}
 	svc := NewKeyService(repo, kms)
 
-	metadata, err := svc.CreateKey(context.Background(), "tenant-001")
+	metadata, err := svc.CreateKey(context.Background(), "tenant-001", domain.KeySpec{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -116,22 +309,109 @@ func TestKeyService_CreateKey_AlreadyExists(t *testing.T) {
 	kms := &mockKMSClient{}
 	svc := NewKeyService(repo, kms)
 
-	_, err := svc.CreateKey(context.Background(), "tenant-001")
+	_, err := svc.CreateKey(context.Background(), "tenant-001", domain.KeySpec{})
 	if !errors.Is(err, domain.ErrKeyAlreadyExists) {
 		t.Errorf("want ErrKeyAlreadyExists, got %v", err)
 	}
 }
 
+func TestKeyService_CreateKey_KeyBits(t *testing.T) {
+	tests := []struct {
+		name     string
+		bits     int
+		wantBits int
+	}{
+		{"default", 0, 256},
+		{"aes-128", 128, 128},
+		{"aes-192", 192, 192},
+		{"aes-256", 256, 256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{existsResult: false}
+			kms := &mockKMSClient{}
+			svc := NewKeyService(repo, kms)
+
+			metadata, err := svc.CreateKey(context.Background(), "tenant-001", domain.KeySpec{Bits: tt.bits})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if metadata.Bits != tt.wantBits {
+				t.Errorf("want bits %d, got %d", tt.wantBits, metadata.Bits)
+			}
+			// モックKMSは平文に"encrypted:"を付与するだけなので平文長を確認できる
+			plainLen := len(repo.createdKeys[0].EncryptedKey) - len("encrypted:")
+			if plainLen != tt.wantBits/8 {
+				t.Errorf("want key length %d bytes, got %d", tt.wantBits/8, plainLen)
+			}
+		})
+	}
+}
+
+func TestKeyService_CreateKey_HMAC(t *testing.T) {
+	repo := &mockKeyRepository{existsResult: false}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	metadata, err := svc.CreateKey(context.Background(), "tenant-001", domain.KeySpec{Type: domain.KeyTypeHMAC})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.KeyType != domain.KeyTypeHMAC {
+		t.Errorf("want key type hmac, got %s", metadata.KeyType)
+	}
+	if metadata.Bits != 512 {
+		t.Errorf("want bits 512, got %d", metadata.Bits)
+	}
+	plainLen := len(repo.createdKeys[0].EncryptedKey) - len("encrypted:")
+	if plainLen != 64 {
+		t.Errorf("want key length 64 bytes, got %d", plainLen)
+	}
+}
+
+func TestKeyService_CreateKey_InvalidKeyType(t *testing.T) {
+	repo := &mockKeyRepository{existsResult: false}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	_, err := svc.CreateKey(context.Background(), "tenant-001", domain.KeySpec{Type: "rsa"})
+	if !errors.Is(err, domain.ErrInvalidKeyType) {
+		t.Errorf("want ErrInvalidKeyType, got %v", err)
+	}
+
+	// AES用の鍵長はHMACでは許可しない
+	_, err = svc.CreateKey(context.Background(), "tenant-001", domain.KeySpec{Type: domain.KeyTypeHMAC, Bits: 128})
+	if !errors.Is(err, domain.ErrInvalidKeySize) {
+		t.Errorf("want ErrInvalidKeySize, got %v", err)
+	}
+}
+
+func TestKeyService_CreateKey_InvalidKeyBits(t *testing.T) {
+	repo := &mockKeyRepository{existsResult: false}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	_, err := svc.CreateKey(context.Background(), "tenant-001", domain.KeySpec{Bits: 64})
+	if !errors.Is(err, domain.ErrInvalidKeySize) {
+		t.Errorf("want ErrInvalidKeySize, got %v", err)
+	}
+	if len(repo.createdKeys) != 0 {
+		t.Errorf("want no created keys, got %d", len(repo.createdKeys))
+	}
+}
+
 func TestKeyService_GetCurrentKey_Success(t *testing.T) {
+	createdAt := time.Date(2025, 1, 28, 10, 30, 0, 0, time.UTC)
 	repo := &mockKeyRepository{
 		findLatestResult: &domain.EncryptionKey{
 			TenantID:     "tenant-001",
 			Generation:   3,
 			EncryptedKey: []byte("encrypted"),
 			Status:       domain.KeyStatusActive,
+			CreatedAt:    createdAt,
 		},
 	}
-	kms := &mockKMSClient{decryptResult: []byte("plain-key")}
+	kms := &mockKMSClient{decryptResult: []byte("plain-key-0123456789abcdefghijkl")}
 	svc := NewKeyService(repo, kms)
 
 	key, err := svc.GetCurrentKey(context.Background(), "tenant-001")
@@ -145,8 +425,11 @@ func TestKeyService_GetCurrentKey_Success(t *testing.T) {
 	if key.Generation != 3 {
 		t.Errorf("want generation 3, got %d", key.Generation)
 	}
-	if string(key.Key) != "plain-key" {
-		t.Errorf("want key plain-key, got %s", string(key.Key))
+	if string(key.Key) != "plain-key-0123456789abcdefghijkl" {
+		t.Errorf("want key plain-key-0123456789abcdefghijkl, got %s", string(key.Key))
+	}
+	if !key.CreatedAt.Equal(createdAt) || key.Status != domain.KeyStatusActive {
+		t.Errorf("want metadata created_at %v and status active, got %v and %s", createdAt, key.CreatedAt, key.Status)
 	}
 }
 
@@ -161,6 +444,36 @@ func TestKeyService_GetCurrentKey_NotFound(t *testing.T) {
 	}
 }
 
+func TestKeyService_GetCurrentKey_Expired(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Hour)
+	expired := &domain.EncryptionKey{
+		TenantID:     "tenant-001",
+		Generation:   3,
+		EncryptedKey: []byte("encrypted"),
+		Status:       domain.KeyStatusActive,
+		ExpiresAt:    &expiresAt,
+	}
+	repo := &mockKeyRepository{findLatestResult: expired, findByGenResult: expired}
+	kms := &mockKMSClient{decryptResult: []byte("plain-key-0123456789abcdefghijkl")}
+	svc := NewKeyService(repo, kms)
+
+	if _, err := svc.GetCurrentKey(context.Background(), "tenant-001"); !errors.Is(err, domain.ErrKeyExpired) {
+		t.Errorf("want ErrKeyExpired, got %v", err)
+	}
+	if _, err := svc.EncryptBatch(context.Background(), "tenant-001", [][]byte{[]byte("data")}); !errors.Is(err, domain.ErrKeyExpired) {
+		t.Errorf("want ErrKeyExpired from EncryptBatch, got %v", err)
+	}
+
+	// 期限切れの鍵で暗号化したデータを復号できるよう、世代を指定した取得は引き続き許可する
+	key, err := svc.GetKeyByGeneration(context.Background(), "tenant-001", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.Generation != 3 {
+		t.Errorf("want generation 3, got %d", key.Generation)
+	}
+}
+
 func TestKeyService_GetKeyByGeneration_Success(t *testing.T) {
 	repo := &mockKeyRepository{
 		findByGenResult: &domain.EncryptionKey{
@@ -170,7 +483,7 @@ func TestKeyService_GetKeyByGeneration_Success(t *testing.T) {
 			Status:       domain.KeyStatusActive,
 		},
 	}
-	kms := &mockKMSClient{decryptResult: []byte("plain-key")}
+	kms := &mockKMSClient{decryptResult: []byte("plain-key-0123456789abcdefghijkl")}
 	svc := NewKeyService(repo, kms)
 
 	key, err := svc.GetKeyByGeneration(context.Background(), "tenant-001", 2)
@@ -183,6 +496,46 @@ func TestKeyService_GetKeyByGeneration_Success(t *testing.T) {
 	}
 }
 
+func TestKeyService_GetKey_CorruptKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       *domain.EncryptionKey
+		plaintext []byte
+	}{
+		{name: "AES-256で短い", key: &domain.EncryptionKey{KeyType: domain.KeyTypeAES, Bits: 256}, plaintext: make([]byte, 31)},
+		{name: "AES-256で長い", key: &domain.EncryptionKey{KeyType: domain.KeyTypeAES, Bits: 256}, plaintext: make([]byte, 33)},
+		{name: "AES-128", key: &domain.EncryptionKey{KeyType: domain.KeyTypeAES, Bits: 128}, plaintext: make([]byte, 32)},
+		{name: "HMAC-512", key: &domain.EncryptionKey{KeyType: domain.KeyTypeHMAC, Bits: 512}, plaintext: make([]byte, 32)},
+		{name: "鍵長の記録がない場合は種別の既定値", key: &domain.EncryptionKey{KeyType: domain.KeyTypeHMAC}, plaintext: make([]byte, 32)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := *tt.key
+			key.TenantID = "tenant-001"
+			key.Generation = 2
+			key.EncryptedKey = []byte("encrypted")
+			key.Status = domain.KeyStatusActive
+			repo := &mockKeyRepository{findLatestResult: &key, findByGenResult: &key}
+			kms := &mockKMSClient{decryptResult: tt.plaintext}
+			svc := NewKeyService(repo, kms)
+
+			if _, err := svc.GetCurrentKey(context.Background(), "tenant-001"); !errors.Is(err, domain.ErrCorruptKey) {
+				t.Errorf("GetCurrentKey: want ErrCorruptKey, got %v", err)
+			}
+			if _, err := svc.GetKeyByGeneration(context.Background(), "tenant-001", 2); !errors.Is(err, domain.ErrCorruptKey) {
+				t.Errorf("GetKeyByGeneration: want ErrCorruptKey, got %v", err)
+			}
+		})
+	}
+
+	// 長さが一致する場合は鍵を返す
+	key := &domain.EncryptionKey{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeHMAC, Bits: 384, EncryptedKey: []byte("encrypted"), Status: domain.KeyStatusActive}
+	svc := NewKeyService(&mockKeyRepository{findByGenResult: key}, &mockKMSClient{decryptResult: make([]byte, 48)})
+	if _, err := svc.GetKeyByGeneration(context.Background(), "tenant-001", 2); err != nil {
+		t.Errorf("want 48-byte HMAC-384 key to be accepted, got %v", err)
+	}
+}
+
 func TestKeyService_GetKeyByGeneration_Disabled(t *testing.T) {
 	repo := &mockKeyRepository{
 		findByGenResult: &domain.EncryptionKey{
@@ -205,7 +558,7 @@ func TestKeyService_RotateKey_Success(t *testing.T) {
 	kms := &mockKMSClient{}
 	svc := NewKeyService(repo, kms)
 
-	metadata, err := svc.RotateKey(context.Background(), "tenant-001")
+	metadata, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -215,34 +568,223 @@ func TestKeyService_RotateKey_Success(t *testing.T) {
 	}
 }
 
+func TestKeyService_RotateKey_KeyBits(t *testing.T) {
+	repo := &mockKeyRepository{maxGenResult: 1}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	metadata, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{Bits: 128})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.Bits != 128 {
+		t.Errorf("want bits 128, got %d", metadata.Bits)
+	}
+
+	_, err = svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{Bits: 64})
+	if !errors.Is(err, domain.ErrInvalidKeySize) {
+		t.Errorf("want ErrInvalidKeySize, got %v", err)
+	}
+}
+
 func TestKeyService_RotateKey_NoExistingKey(t *testing.T) {
 	repo := &mockKeyRepository{maxGenResult: 0}
 	kms := &mockKMSClient{}
 	svc := NewKeyService(repo, kms)
 
-	_, err := svc.RotateKey(context.Background(), "tenant-001")
+	_, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{})
 	if !errors.Is(err, domain.ErrKeyNotFound) {
 		t.Errorf("want ErrKeyNotFound, got %v", err)
 	}
 }
 
+func TestKeyService_RotateKey_MaxGeneration(t *testing.T) {
+	repo := &mockKeyRepository{maxGenResult: 2}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms, WithMaxGeneration(3))
+
+	// 上限と同じ世代まではローテーションできる
+	metadata, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.Generation != 3 {
+		t.Errorf("want generation 3, got %d", metadata.Generation)
+	}
+
+	// 上限の世代に到達した後は新しい世代を生成しない
+	repo.maxGenResult = metadata.Generation
+	_, err = svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{})
+	if !errors.Is(err, domain.ErrMaxGenerationReached) {
+		t.Errorf("want ErrMaxGenerationReached, got %v", err)
+	}
+	if len(repo.createdKeys) != 1 {
+		t.Errorf("want 1 created key, got %d", len(repo.createdKeys))
+	}
+}
+
+func TestKeyService_RotateKey_BeyondMaxGeneration(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxGen uint
+		opts   []KeyServiceOption
+	}{
+		// 上限を下げた後など、既存の世代が上限を超えている場合
+		{name: "existing generation above configured cap", maxGen: 5, opts: []KeyServiceOption{WithMaxGeneration(3)}},
+		{name: "default cap is the column maximum", maxGen: domain.MaxGenerationLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{maxGenResult: tt.maxGen}
+			svc := NewKeyService(repo, &mockKMSClient{}, tt.opts...)
+
+			_, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{})
+			if !errors.Is(err, domain.ErrMaxGenerationReached) {
+				t.Errorf("want ErrMaxGenerationReached, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyService_DeprecateKey(t *testing.T) {
+	key := &domain.EncryptionKey{
+		ID:           "key-2",
+		TenantID:     "tenant-001",
+		Generation:   2,
+		KeyType:      domain.KeyTypeAES,
+		EncryptedKey: []byte("wrapped"),
+		Status:       domain.KeyStatusActive,
+	}
+	repo := &mockKeyRepository{findByGenResult: key}
+	svc := NewKeyService(repo, &mockKMSClient{})
+	ctx := context.Background()
+
+	metadata, err := svc.DeprecateKey(ctx, "tenant-001", 2)
+	if err != nil {
+		t.Fatalf("DeprecateKey failed: %v", err)
+	}
+	if metadata.Status != domain.KeyStatusDeprecated || repo.updatedStatus != domain.KeyStatusDeprecated {
+		t.Errorf("want status deprecated, got %s (stored %s)", metadata.Status, repo.updatedStatus)
+	}
+
+	// 非推奨の鍵も世代を指定すれば復号に使える
+	got, err := svc.GetKeyByGeneration(ctx, "tenant-001", 2)
+	if err != nil {
+		t.Fatalf("want deprecated key to remain fetchable, got %v", err)
+	}
+	if got.Status != domain.KeyStatusDeprecated {
+		t.Errorf("want status deprecated, got %s", got.Status)
+	}
+
+	if _, err := svc.DeprecateKey(ctx, "tenant-001", 2); !errors.Is(err, domain.ErrKeyAlreadyDeprecated) {
+		t.Errorf("want ErrKeyAlreadyDeprecated, got %v", err)
+	}
+	key.Status = domain.KeyStatusDisabled
+	if _, err := svc.DeprecateKey(ctx, "tenant-001", 2); !errors.Is(err, domain.ErrKeyDisabled) {
+		t.Errorf("want ErrKeyDisabled, got %v", err)
+	}
+	repo.findByGenResult = nil
+	if _, err := svc.DeprecateKey(ctx, "tenant-001", 3); !errors.Is(err, domain.ErrKeyNotFound) {
+		t.Errorf("want ErrKeyNotFound, got %v", err)
+	}
+}
+
 func TestKeyService_ListKeys_Success(t *testing.T) {
 	repo := &mockKeyRepository{
 		findAllResult: []*domain.EncryptionKey{
-			{TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
-			{TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusDisabled},
+			{TenantID: "tenant-001", Generation: 1, KeyType: domain.KeyTypeAES, Status: domain.KeyStatusActive},
+			{TenantID: "tenant-001", Generation: 2, KeyType: domain.KeyTypeHMAC, Status: domain.KeyStatusDisabled},
 		},
 	}
 	kms := &mockKMSClient{}
 	svc := NewKeyService(repo, kms)
 
-	keys, err := svc.ListKeys(context.Background(), "tenant-001")
+	keys, err := svc.ListKeys(context.Background(), "tenant-001", domain.KeyFilter{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	if len(keys) != 2 {
-		t.Errorf("want 2 keys, got %d", len(keys))
+		t.Fatalf("want 2 keys, got %d", len(keys))
+	}
+	if keys[0].KeyType != domain.KeyTypeAES || keys[1].KeyType != domain.KeyTypeHMAC {
+		t.Errorf("want key types [aes hmac], got [%s %s]", keys[0].KeyType, keys[1].KeyType)
+	}
+}
+
+func TestKeyService_ListKeys_MarksCurrent(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []domain.KeyStatus // 世代1から順のステータス
+		filter      domain.KeyFilter
+		latest      *domain.EncryptionKey
+		wantCurrent uint // 0の場合は現在の鍵なし
+	}{
+		{
+			name:        "newest generation is active",
+			statuses:    []domain.KeyStatus{domain.KeyStatusActive, domain.KeyStatusActive, domain.KeyStatusActive},
+			wantCurrent: 3,
+		},
+		{
+			name:        "newest generation is disabled",
+			statuses:    []domain.KeyStatus{domain.KeyStatusActive, domain.KeyStatusActive, domain.KeyStatusDisabled},
+			wantCurrent: 2,
+		},
+		{
+			name:        "newest generation is deprecated",
+			statuses:    []domain.KeyStatus{domain.KeyStatusActive, domain.KeyStatusActive, domain.KeyStatusDeprecated},
+			wantCurrent: 2,
+		},
+		{
+			name:     "all generations are disabled",
+			statuses: []domain.KeyStatus{domain.KeyStatusDisabled, domain.KeyStatusDisabled},
+		},
+		{
+			// 絞り込みで現在の鍵が一覧から外れる場合、一覧内の最新の有効な鍵を現在の鍵としない
+			name:     "current generation filtered out",
+			statuses: []domain.KeyStatus{domain.KeyStatusActive, domain.KeyStatusActive},
+			filter:   domain.KeyFilter{MaxGeneration: 2},
+			latest:   &domain.EncryptionKey{TenantID: "tenant-001", Generation: 3, Status: domain.KeyStatusActive},
+		},
+		{
+			name:        "current generation within filter",
+			statuses:    []domain.KeyStatus{domain.KeyStatusActive, domain.KeyStatusActive},
+			filter:      domain.KeyFilter{MaxGeneration: 2},
+			latest:      &domain.EncryptionKey{TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusActive},
+			wantCurrent: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockKeyRepository{findLatestResult: tt.latest}
+			for i, status := range tt.statuses {
+				repo.findAllResult = append(repo.findAllResult, &domain.EncryptionKey{
+					TenantID: "tenant-001", Generation: uint(i + 1), Status: status,
+				})
+			}
+			svc := NewKeyService(repo, &mockKMSClient{})
+
+			keys, err := svc.ListKeys(context.Background(), "tenant-001", tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, k := range keys {
+				if want := k.Generation == tt.wantCurrent; k.IsCurrent != want {
+					t.Errorf("generation %d: want IsCurrent %v, got %v", k.Generation, want, k.IsCurrent)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyService_ListKeys_InvalidFilter(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	_, err := svc.ListKeys(context.Background(), "tenant-001", domain.KeyFilter{MinGeneration: 5, MaxGeneration: 3})
+	if !errors.Is(err, domain.ErrInvalidKeyFilter) {
+		t.Errorf("want ErrInvalidKeyFilter, got %v", err)
 	}
 }
 
@@ -258,10 +800,64 @@ func TestKeyService_DisableKey_Success(t *testing.T) {
 	kms := &mockKMSClient{}
 	svc := NewKeyService(repo, kms)
 
-	err := svc.DisableKey(context.Background(), "tenant-001", 1)
+	metadata, err := svc.DisableKey(context.Background(), "tenant-001", 1, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.Status != domain.KeyStatusDisabled {
+		t.Errorf("want status disabled, got %s", metadata.Status)
+	}
+	if metadata.TenantID != "tenant-001" || metadata.Generation != 1 {
+		t.Errorf("want tenant-001 generation 1, got %s generation %d", metadata.TenantID, metadata.Generation)
+	}
+}
+
+func TestKeyService_DisableKey_RecordsTimestampAndReason(t *testing.T) {
+	repo := &mockKeyRepository{
+		findByGenResult: &domain.EncryptionKey{
+			ID:         "key-id",
+			TenantID:   "tenant-001",
+			Generation: 1,
+			Status:     domain.KeyStatusActive,
+		},
+	}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	before := time.Now()
+	metadata, err := svc.DisableKey(context.Background(), "tenant-001", 1, "compromised")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+
+	if repo.disabledAt.Before(before) {
+		t.Errorf("want disabledAt >= %v, got %v", before, repo.disabledAt)
+	}
+	if repo.disabledReason != "compromised" {
+		t.Errorf("want reason %q, got %q", "compromised", repo.disabledReason)
+	}
+	// 返却値は保存した値と一致する
+	if metadata.Status != domain.KeyStatusDisabled {
+		t.Errorf("want status disabled, got %s", metadata.Status)
+	}
+	if metadata.DisabledAt == nil || !metadata.DisabledAt.Equal(repo.disabledAt) {
+		t.Errorf("want DisabledAt %v, got %v", repo.disabledAt, metadata.DisabledAt)
+	}
+	if metadata.DisabledReason != "compromised" {
+		t.Errorf("want DisabledReason compromised, got %q", metadata.DisabledReason)
+	}
+}
+
+func TestKeyService_DisableKey_ReasonTooLong(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	reason := strings.Repeat("あ", domain.MaxDisableReasonLen+1)
+	_, err := svc.DisableKey(context.Background(), "tenant-001", 1, reason)
+	if !errors.Is(err, domain.ErrInvalidDisableReason) {
+		t.Errorf("want ErrInvalidDisableReason, got %v", err)
+	}
 }
 
 func TestKeyService_DisableKey_AlreadyDisabled(t *testing.T) {
@@ -276,8 +872,908 @@ func TestKeyService_DisableKey_AlreadyDisabled(t *testing.T) {
 	kms := &mockKMSClient{}
 	svc := NewKeyService(repo, kms)
 
-	err := svc.DisableKey(context.Background(), "tenant-001", 1)
+	_, err := svc.DisableKey(context.Background(), "tenant-001", 1, "")
 	if !errors.Is(err, domain.ErrKeyAlreadyDisabled) {
 		t.Errorf("want ErrKeyAlreadyDisabled, got %v", err)
 	}
 }
+
+func TestKeyService_DisableKeys_Mixed(t *testing.T) {
+	repo := &mockKeyRepository{
+		findAllResult: []*domain.EncryptionKey{
+			{ID: "id-1", TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
+			{ID: "id-2", TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusDisabled},
+			{ID: "id-3", TenantID: "tenant-001", Generation: 3, Status: domain.KeyStatusActive},
+		},
+	}
+	svc := NewKeyService(repo, &mockKMSClient{})
+
+	results, err := svc.DisableKeys(context.Background(), "tenant-001", []uint{3, 2, 3, 4, 1}, "compromised")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.BatchDisableResult{
+		{Generation: 3, Status: domain.BatchDisableStatusDisabled},
+		{Generation: 2, Status: domain.BatchDisableStatusAlreadyDisabled},
+		{Generation: 4, Status: domain.BatchDisableStatusNotFound},
+		{Generation: 1, Status: domain.BatchDisableStatusDisabled},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("want %d results, got %d", len(want), len(results))
+	}
+	for i, w := range want {
+		if *results[i] != w {
+			t.Errorf("results[%d]: want %+v, got %+v", i, w, *results[i])
+		}
+	}
+	if repo.findAllResult[0].DisabledReason != "compromised" {
+		t.Errorf("want reason recorded, got %q", repo.findAllResult[0].DisabledReason)
+	}
+}
+
+func TestKeyService_DisableKeys_Invalid(t *testing.T) {
+	svc := NewKeyService(&mockKeyRepository{}, &mockKMSClient{})
+
+	tests := []struct {
+		name        string
+		generations []uint
+		reason      string
+		wantErr     error
+	}{
+		{name: "empty", generations: nil, wantErr: domain.ErrInvalidBatchSize},
+		{name: "too many", generations: make([]uint, domain.MaxBatchDisableGenerations+1), wantErr: domain.ErrInvalidBatchSize},
+		{name: "zero generation", generations: []uint{0}, wantErr: domain.ErrInvalidGeneration},
+		{name: "reason too long", generations: []uint{1}, reason: strings.Repeat("a", domain.MaxDisableReasonLen+1), wantErr: domain.ErrInvalidDisableReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.DisableKeys(context.Background(), "tenant-001", tt.generations, tt.reason)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKeyService_ImportKeys_Success(t *testing.T) {
+	repo := &mockKeyRepository{findByGenResult: nil}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := []*domain.EncryptionKey{
+		{Generation: 1, EncryptedKey: []byte("wrapped-1"), Status: domain.KeyStatusDisabled, CreatedAt: createdAt},
+		{Generation: 2, EncryptedKey: []byte("wrapped-2"), Status: domain.KeyStatusActive, CreatedAt: createdAt},
+	}
+
+	metadata, err := svc.ImportKeys(context.Background(), "tenant-001", keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(metadata) != 2 {
+		t.Fatalf("want 2 imported keys, got %d", len(metadata))
+	}
+	if len(repo.createdKeys) != 2 {
+		t.Errorf("want 2 created keys, got %d", len(repo.createdKeys))
+	}
+	for i, m := range metadata {
+		if m.Generation != keys[i].Generation {
+			t.Errorf("want generation %d, got %d", keys[i].Generation, m.Generation)
+		}
+		if !m.CreatedAt.Equal(createdAt) {
+			t.Errorf("want created_at %v, got %v", createdAt, m.CreatedAt)
+		}
+		if m.TenantID != "tenant-001" {
+			t.Errorf("want tenant_id tenant-001, got %s", m.TenantID)
+		}
+	}
+}
+
+func TestKeyService_ImportKeys_KeepsKMSKeyName(t *testing.T) {
+	repo := &mockKeyRepository{}
+	svc := NewKeyService(repo, &mockKMSClient{}, WithKMSKeyName("current-kms-key"))
+
+	keys := []*domain.EncryptionKey{
+		{Generation: 1, EncryptedKey: []byte("wrapped-1"), Status: domain.KeyStatusActive, KMSKeyName: "old-kms-key"},
+		{Generation: 2, EncryptedKey: []byte("wrapped-2"), Status: domain.KeyStatusActive},
+	}
+	if _, err := svc.ImportKeys(context.Background(), "tenant-001", keys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 実際にラップしたKMS鍵が不明な鍵に現在のKMS鍵名を記録しない
+	want := []string{"old-kms-key", ""}
+	for i, k := range repo.createdKeys {
+		if k.KMSKeyName != want[i] {
+			t.Errorf("generation %d: want kms_key_name %q, got %q", k.Generation, want[i], k.KMSKeyName)
+		}
+	}
+}
+
+func TestKeyService_ImportKeys_GenerationConflict(t *testing.T) {
+	repo := &mockKeyRepository{
+		findByGenResult: &domain.EncryptionKey{
+			TenantID:   "tenant-001",
+			Generation: 1,
+			Status:     domain.KeyStatusActive,
+		},
+	}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	keys := []*domain.EncryptionKey{
+		{Generation: 1, EncryptedKey: []byte("wrapped-1"), Status: domain.KeyStatusActive},
+	}
+
+	_, err := svc.ImportKeys(context.Background(), "tenant-001", keys)
+	if !errors.Is(err, domain.ErrGenerationAlreadyExists) {
+		t.Errorf("want ErrGenerationAlreadyExists, got %v", err)
+	}
+	if len(repo.createdKeys) != 0 {
+		t.Errorf("want no created keys, got %d", len(repo.createdKeys))
+	}
+}
+
+func TestKeyService_ImportKeys_ConcurrentInsert(t *testing.T) {
+	// 確認後に別のリクエストが同じ世代を作成した場合、一意制約の違反を409となるエラーで返す
+	repo := &mockKeyRepository{
+		createErr: fmt.Errorf("%w: generation 1", domain.ErrGenerationAlreadyExists),
+	}
+	svc := NewKeyService(repo, &mockKMSClient{})
+
+	keys := []*domain.EncryptionKey{
+		{Generation: 1, EncryptedKey: []byte("wrapped-1"), Status: domain.KeyStatusActive},
+	}
+
+	_, err := svc.ImportKeys(context.Background(), "tenant-001", keys)
+	if !errors.Is(err, domain.ErrGenerationAlreadyExists) {
+		t.Errorf("want ErrGenerationAlreadyExists, got %v", err)
+	}
+}
+
+func TestKeyService_ImportKeys_DuplicateGenerationInBundle(t *testing.T) {
+	repo := &mockKeyRepository{}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	keys := []*domain.EncryptionKey{
+		{Generation: 1, EncryptedKey: []byte("wrapped-1"), Status: domain.KeyStatusActive},
+		{Generation: 1, EncryptedKey: []byte("wrapped-2"), Status: domain.KeyStatusActive},
+	}
+
+	_, err := svc.ImportKeys(context.Background(), "tenant-001", keys)
+	if !errors.Is(err, domain.ErrInvalidGeneration) {
+		t.Errorf("want ErrInvalidGeneration, got %v", err)
+	}
+}
+
+func TestKeyService_RotateKey_KMSTimeout(t *testing.T) {
+	repo := &mockKeyRepository{maxGenResult: 1}
+	kms := &mockKMSClient{block: true}
+	svc := NewKeyService(repo, kms, WithKMSTimeout(20*time.Millisecond))
+
+	_, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{})
+	if !errors.Is(err, domain.ErrUpstreamTimeout) {
+		t.Errorf("want ErrUpstreamTimeout, got %v", err)
+	}
+	if len(repo.createdKeys) != 0 {
+		t.Errorf("want no key created after timeout, got %d", len(repo.createdKeys))
+	}
+}
+
+func TestKeyService_GetCurrentKey_DBTimeout(t *testing.T) {
+	repo := &mockKeyRepository{block: true}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms, WithDBTimeout(20*time.Millisecond))
+
+	_, err := svc.GetCurrentKey(context.Background(), "tenant-001")
+	if !errors.Is(err, domain.ErrUpstreamTimeout) {
+		t.Errorf("want ErrUpstreamTimeout, got %v", err)
+	}
+}
+
+func TestKeyService_CallerCancelIsNotTimeout(t *testing.T) {
+	repo := &mockKeyRepository{block: true}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms, WithDBTimeout(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := svc.GetCurrentKey(ctx, "tenant-001")
+	if errors.Is(err, domain.ErrUpstreamTimeout) {
+		t.Errorf("want caller cancellation not to be reported as timeout, got %v", err)
+	}
+}
+
+func TestKeyService_RotateKey_Retention(t *testing.T) {
+	repo := &mockKeyRepository{
+		maxGenResult: 1,
+		createdKeys: []*domain.EncryptionKey{
+			{TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
+		},
+	}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms, WithKeyRetention(3))
+
+	// 世代2〜6までローテーション
+	for i := 0; i < 5; i++ {
+		if _, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{}); err != nil {
+			t.Fatalf("rotation %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	// 最新3世代のみ有効、それより古い世代は保持期間超過の理由付きで無効化
+	for _, k := range repo.createdKeys {
+		wantActive := k.Generation >= 4
+		if gotActive := k.Status == domain.KeyStatusActive; gotActive != wantActive {
+			t.Errorf("generation %d: want active=%v, got status %s", k.Generation, wantActive, k.Status)
+		}
+		if !wantActive && k.DisabledReason != domain.DisableReasonRetention {
+			t.Errorf("generation %d: want reason %q, got %q", k.Generation, domain.DisableReasonRetention, k.DisabledReason)
+		}
+	}
+	if repo.maxGenResult != 6 {
+		t.Errorf("want newest generation 6, got %d", repo.maxGenResult)
+	}
+}
+
+func TestKeyService_RotateKey_NoRetentionKeepsAllActive(t *testing.T) {
+	repo := &mockKeyRepository{maxGenResult: 1}
+	kms := &mockKMSClient{}
+	svc := NewKeyService(repo, kms)
+
+	if _, err := svc.RotateKey(context.Background(), "tenant-001", domain.KeySpec{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, k := range repo.createdKeys {
+		if k.Status != domain.KeyStatusActive {
+			t.Errorf("generation %d: want active, got %s", k.Generation, k.Status)
+		}
+	}
+}
+
+// sourceKMSClient は旧KMS鍵を模したモックで、"old:"で始まる暗号文のみ復号できる。
+type sourceKMSClient struct{}
+
+func (sourceKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return nil, errors.New("unexpected encrypt with source KMS key")
+}
+
+func (sourceKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	plaintext, ok := bytes.CutPrefix(ciphertext, []byte("old:"))
+	if !ok {
+		return nil, errors.New("ciphertext was not wrapped under the source KMS key")
+	}
+	return plaintext, nil
+}
+
+func TestKeyService_RewrapByKMSKey(t *testing.T) {
+	const oldKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/old"
+	const newKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/new"
+	const otherKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/other"
+
+	repo := &mockKeyRepository{findAllResult: []*domain.EncryptionKey{
+		{ID: "1", TenantID: "tenant-a", Generation: 1, EncryptedKey: []byte("old:key-a1"), KMSKeyName: oldKMSKey},
+		{ID: "2", TenantID: "tenant-a", Generation: 2, EncryptedKey: []byte("other:key-a2"), KMSKeyName: otherKMSKey},
+		{ID: "3", TenantID: "tenant-b", Generation: 1, EncryptedKey: []byte("corrupted"), KMSKeyName: oldKMSKey},
+		{ID: "4", TenantID: "tenant-b", Generation: 2, EncryptedKey: []byte("old:key-b2"), KMSKeyName: oldKMSKey, Status: domain.KeyStatusDisabled},
+		{ID: "5", TenantID: "tenant-c", Generation: 1, EncryptedKey: []byte("old:key-c1"), KMSKeyName: oldKMSKey},
+		{ID: "6", TenantID: "tenant-c", Generation: 2, EncryptedKey: []byte("new:key-c2"), KMSKeyName: newKMSKey},
+	}}
+	svc := NewKeyService(repo, &mockKMSClient{}, WithKMSKeyName(newKMSKey))
+
+	// 復号できない鍵を含めても処理を続け、バッチをまたいで一致する鍵のみを再ラップする
+	result, err := svc.RewrapByKMSKey(context.Background(), oldKMSKey, sourceKMSClient{}, 2, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Rewrapped != 3 || result.Failed != 1 {
+		t.Errorf("want 3 rewrapped and 1 failed, got %+v", result)
+	}
+	if result.FromKMSKeyName != oldKMSKey || result.ToKMSKeyName != newKMSKey {
+		t.Errorf("unexpected KMS key names: %+v", result)
+	}
+
+	want := map[string]struct {
+		encryptedKey string
+		kmsKeyName   string
+	}{
+		"1": {"encrypted:key-a1", newKMSKey},
+		"2": {"other:key-a2", otherKMSKey},
+		"3": {"corrupted", oldKMSKey},
+		"4": {"encrypted:key-b2", newKMSKey},
+		"5": {"encrypted:key-c1", newKMSKey},
+		"6": {"new:key-c2", newKMSKey},
+	}
+	for _, k := range repo.findAllResult {
+		w := want[k.ID]
+		if string(k.EncryptedKey) != w.encryptedKey || k.KMSKeyName != w.kmsKeyName {
+			t.Errorf("key %s: want (%q, %q), got (%q, %q)", k.ID, w.encryptedKey, w.kmsKeyName, k.EncryptedKey, k.KMSKeyName)
+		}
+		// 再ラップした鍵はテナントIDに紐づける
+		if rewrapped := w.kmsKeyName == newKMSKey && k.ID != "6"; k.TenantAAD != rewrapped {
+			t.Errorf("key %s: want TenantAAD %v, got %v", k.ID, rewrapped, k.TenantAAD)
+		}
+	}
+}
+
+func TestKeyService_RewrapByKMSKey_IncludeUnrecorded(t *testing.T) {
+	const oldKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/old"
+	const newKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/new"
+
+	newRepo := func() *mockKeyRepository {
+		return &mockKeyRepository{findAllResult: []*domain.EncryptionKey{
+			{ID: "1", TenantID: "tenant-a", Generation: 1, EncryptedKey: []byte("old:key-a1"), KMSKeyName: oldKMSKey},
+			// KMS鍵名の記録前に作成された鍵
+			{ID: "2", TenantID: "tenant-a", Generation: 2, EncryptedKey: []byte("old:key-a2")},
+			// 記録はないが移行元のKMS鍵では復号できない鍵
+			{ID: "3", TenantID: "tenant-b", Generation: 1, EncryptedKey: []byte("new:key-b1")},
+		}}
+	}
+
+	// 指定しない場合は記録のない鍵を対象にしない
+	repo := newRepo()
+	svc := NewKeyService(repo, &mockKMSClient{}, WithKMSKeyName(newKMSKey))
+	result, err := svc.RewrapByKMSKey(context.Background(), oldKMSKey, sourceKMSClient{}, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Rewrapped != 1 || result.Failed != 0 || repo.findAllResult[1].KMSKeyName != "" {
+		t.Errorf("want only the recorded key rewrapped, got %+v", result)
+	}
+
+	// 指定した場合は記録のない鍵も移行元のKMS鍵で復号して再ラップする
+	repo = newRepo()
+	svc = NewKeyService(repo, &mockKMSClient{}, WithKMSKeyName(newKMSKey))
+	result, err = svc.RewrapByKMSKey(context.Background(), oldKMSKey, sourceKMSClient{}, 1, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Rewrapped != 2 || result.Failed != 1 {
+		t.Errorf("want 2 rewrapped and 1 failed, got %+v", result)
+	}
+	if k := repo.findAllResult[1]; k.KMSKeyName != newKMSKey || string(k.EncryptedKey) != "encrypted:key-a2" {
+		t.Errorf("want unrecorded key rewrapped with %s, got (%q, %q)", newKMSKey, k.EncryptedKey, k.KMSKeyName)
+	}
+	if k := repo.findAllResult[2]; k.KMSKeyName != "" || string(k.EncryptedKey) != "new:key-b1" {
+		t.Errorf("want key that fails to decrypt left unchanged, got (%q, %q)", k.EncryptedKey, k.KMSKeyName)
+	}
+}
+
+// aadKMSClient は追加認証データを暗号文に含め、復号時に一致しない場合は失敗するテスト用KMSクライアント。
+type aadKMSClient struct{}
+
+func (aadKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return append(append([]byte{byte(len(aad))}, aad...), plaintext...), nil
+}
+
+func (aadKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	n := int(ciphertext[0])
+	if !bytes.Equal(ciphertext[1:1+n], aad) {
+		return nil, errors.New("additional authenticated data mismatch")
+	}
+	return ciphertext[1+n:], nil
+}
+
+func TestKeyService_TenantAAD(t *testing.T) {
+	repo := &mockKeyRepository{}
+	svc := NewKeyService(repo, aadKMSClient{})
+	ctx := context.Background()
+
+	if _, err := svc.CreateKey(ctx, "tenant-a", domain.KeySpec{}); err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	key := repo.createdKeys[0]
+	if !key.TenantAAD {
+		t.Fatal("want new keys wrapped with the tenant ID as AAD")
+	}
+	if _, err := svc.decryptKey(ctx, key); err != nil {
+		t.Fatalf("want key to decrypt for its own tenant, got %v", err)
+	}
+
+	// 別テナントの行に移し替えた暗号文は復号できない
+	swapped := *key
+	swapped.TenantID = "tenant-b"
+	if _, err := svc.decryptKey(ctx, &swapped); err == nil {
+		t.Error("want decryption to fail for another tenant's ciphertext")
+	}
+
+	// AADなしでラップされた既存の鍵はAADを指定せずに復号する
+	legacyCiphertext, _ := aadKMSClient{}.Encrypt(ctx, []byte("legacy-key-0123456789abcdefghijk"), nil)
+	legacy := &domain.EncryptionKey{TenantID: "tenant-a", Generation: 1, EncryptedKey: legacyCiphertext}
+	plain, err := svc.decryptKey(ctx, legacy)
+	if err != nil || string(plain) != "legacy-key-0123456789abcdefghijk" {
+		t.Errorf("want legacy key to decrypt without AAD, got %q, %v", plain, err)
+	}
+}
+
+func TestKeyService_ImportKeys_TenantAADRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := &mockKeyRepository{}
+	if _, err := NewKeyService(source, aadKMSClient{}).CreateKey(ctx, "tenant-a", domain.KeySpec{}); err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	exported := source.createdKeys[0]
+	want, err := aadKMSClient{}.Decrypt(ctx, exported.EncryptedKey, domain.TenantAAD("tenant-a"))
+	if err != nil {
+		t.Fatalf("failed to unwrap exported key: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		tenantAAD bool
+	}{
+		{name: "tenant_aadを記録した鍵", tenantAAD: true},
+		// 記録のないバックアップから取り込んだ鍵は、AADなしで失敗した後にテナントIDを指定して復号する
+		{name: "tenant_aadを省略した鍵", tenantAAD: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &mockKeyRepository{}
+			svc := NewKeyService(target, aadKMSClient{})
+			imported := []*domain.EncryptionKey{{
+				Generation:   exported.Generation,
+				KeyType:      exported.KeyType,
+				Bits:         exported.Bits,
+				EncryptedKey: exported.EncryptedKey,
+				TenantAAD:    tt.tenantAAD,
+				Status:       domain.KeyStatusActive,
+			}}
+			if _, err := svc.ImportKeys(ctx, "tenant-a", imported); err != nil {
+				t.Fatalf("ImportKeys failed: %v", err)
+			}
+			stored := target.createdKeys[0]
+			if stored.TenantAAD != tt.tenantAAD {
+				t.Errorf("want tenant_aad %v stored, got %v", tt.tenantAAD, stored.TenantAAD)
+			}
+			plain, err := svc.decryptKey(ctx, stored)
+			if err != nil {
+				t.Fatalf("want imported key to decrypt, got %v", err)
+			}
+			if !bytes.Equal(plain, want) {
+				t.Error("want the exported key material")
+			}
+		})
+	}
+}
+
+func TestKeyService_BackfillStatus(t *testing.T) {
+	repo := &mockKeyRepository{backfillCounts: map[domain.KeyStatus]int{domain.KeyStatusActive: 3, domain.KeyStatusDisabled: 1}}
+	svc := NewKeyService(repo, &mockKMSClient{})
+
+	result, err := svc.BackfillStatus(context.Background(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.backfillDryRun || !result.DryRun {
+		t.Error("want dry run passed through to the repository and result")
+	}
+	if result.Activated != 3 || result.Disabled != 1 || result.Total() != 4 {
+		t.Errorf("want 3 activated and 1 disabled, got %+v", result)
+	}
+
+	repo.backfillErr = errors.New("db down")
+	if _, err := svc.BackfillStatus(context.Background(), false); !errors.Is(err, repo.backfillErr) {
+		t.Errorf("want repository error, got %v", err)
+	}
+}
+
+func TestKeyService_CheckTenantIntegrity(t *testing.T) {
+	key := func(gen uint, status domain.KeyStatus) *domain.EncryptionKey {
+		return &domain.EncryptionKey{TenantID: "tenant-001", Generation: gen, Status: status}
+	}
+	tests := []struct {
+		name          string
+		keys          []*domain.EncryptionKey
+		wantMissing   []domain.GenerationRange
+		wantDuplicate []uint
+		wantDupActive []uint
+		wantOK        bool
+		wantMaxGen    uint
+	}{
+		{
+			name:       "consistent",
+			keys:       []*domain.EncryptionKey{key(1, domain.KeyStatusDisabled), key(2, domain.KeyStatusActive), key(3, domain.KeyStatusActive)},
+			wantOK:     true,
+			wantMaxGen: 3,
+		},
+		{
+			name:        "gaps",
+			keys:        []*domain.EncryptionKey{key(2, domain.KeyStatusActive), key(3, domain.KeyStatusActive), key(7, domain.KeyStatusActive)},
+			wantMissing: []domain.GenerationRange{{From: 1, To: 1}, {From: 4, To: 6}},
+			wantMaxGen:  7,
+		},
+		{
+			name:          "duplicate generation with multiple active keys",
+			keys:          []*domain.EncryptionKey{key(1, domain.KeyStatusActive), key(2, domain.KeyStatusActive), key(2, domain.KeyStatusActive), key(3, domain.KeyStatusActive), key(3, domain.KeyStatusDisabled)},
+			wantDuplicate: []uint{2, 3},
+			wantDupActive: []uint{2},
+			wantMaxGen:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewKeyService(&mockKeyRepository{findAllResult: tt.keys}, &mockKMSClient{})
+
+			report, err := svc.CheckTenantIntegrity(context.Background(), "tenant-001")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if report.OK() != tt.wantOK || report.MaxGeneration != tt.wantMaxGen || report.KeyCount != len(tt.keys) {
+				t.Errorf("want ok=%v max=%d count=%d, got %+v", tt.wantOK, tt.wantMaxGen, len(tt.keys), report)
+			}
+			if !slices.Equal(report.MissingGenerations, tt.wantMissing) {
+				t.Errorf("want missing %v, got %v", tt.wantMissing, report.MissingGenerations)
+			}
+			if !slices.Equal(report.DuplicateGenerations, tt.wantDuplicate) {
+				t.Errorf("want duplicates %v, got %v", tt.wantDuplicate, report.DuplicateGenerations)
+			}
+			if !slices.Equal(report.DuplicateActiveGenerations, tt.wantDupActive) {
+				t.Errorf("want duplicate active %v, got %v", tt.wantDupActive, report.DuplicateActiveGenerations)
+			}
+		})
+	}
+
+	svc := NewKeyService(&mockKeyRepository{}, &mockKMSClient{})
+	if _, err := svc.CheckTenantIntegrity(context.Background(), "tenant-001"); !errors.Is(err, domain.ErrKeyNotFound) {
+		t.Errorf("want ErrKeyNotFound for a tenant without keys, got %v", err)
+	}
+}
+
+func TestKeyService_RewrapByKMSKey_InvalidSource(t *testing.T) {
+	const kmsKey = "projects/p/locations/global/keyRings/r/cryptoKeys/current"
+
+	tests := []struct {
+		name    string
+		opts    []KeyServiceOption
+		fromKey string
+	}{
+		{name: "empty source", opts: []KeyServiceOption{WithKMSKeyName(kmsKey)}, fromKey: ""},
+		{name: "same as current", opts: []KeyServiceOption{WithKMSKeyName(kmsKey)}, fromKey: kmsKey},
+		{name: "current not configured", fromKey: "projects/p/locations/global/keyRings/r/cryptoKeys/old"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewKeyService(&mockKeyRepository{}, &mockKMSClient{}, tt.opts...)
+			if _, err := svc.RewrapByKMSKey(context.Background(), tt.fromKey, sourceKMSClient{}, 0, false); !errors.Is(err, domain.ErrInvalidRewrapSource) {
+				t.Errorf("want ErrInvalidRewrapSource, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyService_RewrapByKMSKey_TenantKMSKey(t *testing.T) {
+	const oldKMSKey = "projects/p/locations/global/keyRings/r/cryptoKeys/old"
+
+	repo := &mockKeyRepository{findAllResult: []*domain.EncryptionKey{
+		{ID: "1", TenantID: "tenant-byok", Generation: 1, EncryptedKey: []byte("old:key-byok"), KMSKeyName: oldKMSKey},
+		{ID: "2", TenantID: "tenant-other", Generation: 1, EncryptedKey: []byte("old:key-other"), KMSKeyName: oldKMSKey},
+	}}
+	global, dedicated := &countingKMSClient{}, &countingKMSClient{}
+	svc := NewKeyService(repo, global,
+		WithKMSKeyName("global-key"),
+		WithTenantKMSKeys(TenantKMSKey{TenantID: "tenant-byok", Name: "byok-key", Client: dedicated}),
+	)
+
+	// テナント専用のKMS鍵が設定されたテナントの鍵はそのKMS鍵で再ラップする
+	result, err := svc.RewrapByKMSKey(context.Background(), oldKMSKey, sourceKMSClient{}, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Rewrapped != 2 {
+		t.Errorf("want 2 rewrapped, got %+v", result)
+	}
+	if got := repo.findAllResult[0].KMSKeyName; got != "byok-key" {
+		t.Errorf("want tenant-byok rewrapped with byok-key, got %q", got)
+	}
+	if got := repo.findAllResult[1].KMSKeyName; got != "global-key" {
+		t.Errorf("want tenant-other rewrapped with global-key, got %q", got)
+	}
+	if dedicated.encrypts != 1 || global.encrypts != 1 {
+		t.Errorf("want 1 encrypt with each KMS key, got dedicated %d and global %d", dedicated.encrypts, global.encrypts)
+	}
+
+	// テナント専用のKMS鍵は移行元に指定できない
+	if _, err := svc.RewrapByKMSKey(context.Background(), "byok-key", sourceKMSClient{}, 0, false); !errors.Is(err, domain.ErrInvalidRewrapSource) {
+		t.Errorf("want ErrInvalidRewrapSource for a tenant KMS key, got %v", err)
+	}
+}
+
+// recordingKMSClient は復号の試行順を記録し、decryptableの場合のみ復号に成功するテスト用KMSクライアント。
+type recordingKMSClient struct {
+	mockKMSClient
+	name        string
+	decryptable bool
+	calls       *[]string
+}
+
+func (c *recordingKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	*c.calls = append(*c.calls, c.name)
+	if !c.decryptable {
+		return nil, errors.New(c.name + ": decryption failed")
+	}
+	return []byte("plain-key-0123456789abcdefghijkl"), nil
+}
+
+func newLegacyKMSKeyService(storedKMSKeyName string, decryptableBy string) (*KeyService, *[]string) {
+	calls := &[]string{}
+	client := func(name string) *recordingKMSClient {
+		return &recordingKMSClient{name: name, decryptable: name == decryptableBy, calls: calls}
+	}
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{
+			TenantID:     "tenant-001",
+			Generation:   1,
+			EncryptedKey: []byte("encrypted"),
+			KMSKeyName:   storedKMSKeyName,
+			Status:       domain.KeyStatusActive,
+		},
+	}
+	svc := NewKeyService(repo, client("new"),
+		WithKMSKeyName("new"),
+		WithLegacyKMSKeys(
+			LegacyKMSKey{Name: "old-1", Client: client("old-1")},
+			LegacyKMSKey{Name: "old-2", Client: client("old-2")},
+		),
+	)
+	return svc, calls
+}
+
+func TestKeyService_GetCurrentKey_LegacyKMSKeyFallback(t *testing.T) {
+	// 現在のKMS鍵で記録されているが、実際には移行元のKMS鍵でラップされている
+	svc, calls := newLegacyKMSKeyService("new", "old-2")
+
+	key, err := svc.GetCurrentKey(context.Background(), "tenant-001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(key.Key) != "plain-key-0123456789abcdefghijkl" {
+		t.Errorf("want key plain-key-0123456789abcdefghijkl, got %s", key.Key)
+	}
+	if want := []string{"new", "old-1", "old-2"}; !slices.Equal(*calls, want) {
+		t.Errorf("want decrypt attempts %v, got %v", want, *calls)
+	}
+}
+
+func TestKeyService_GetCurrentKey_StoredLegacyKMSKeyFirst(t *testing.T) {
+	svc, calls := newLegacyKMSKeyService("old-2", "old-2")
+
+	if _, err := svc.GetCurrentKey(context.Background(), "tenant-001"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"old-2"}; !slices.Equal(*calls, want) {
+		t.Errorf("want decrypt attempts %v, got %v", want, *calls)
+	}
+}
+
+func TestKeyService_GetCurrentKey_LegacyKMSKeysExhausted(t *testing.T) {
+	svc, calls := newLegacyKMSKeyService("old-1", "")
+
+	_, err := svc.GetCurrentKey(context.Background(), "tenant-001")
+	if err == nil || !strings.Contains(err.Error(), "old-1: decryption failed") {
+		t.Errorf("want error from the stored KMS key, got %v", err)
+	}
+	// テナントIDに紐づけたか記録されていない鍵は、テナントIDを指定してもう一巡試行する
+	if want := []string{"old-1", "new", "old-2", "old-1", "new", "old-2"}; !slices.Equal(*calls, want) {
+		t.Errorf("want decrypt attempts %v, got %v", want, *calls)
+	}
+}
+
+func TestKeyService_TenantKMSKey(t *testing.T) {
+	global, dedicated := &countingKMSClient{}, &countingKMSClient{}
+	repo := &mockKeyRepository{maxGenResult: 1}
+	svc := NewKeyService(repo, global,
+		WithKMSKeyName("global-key"),
+		WithTenantKMSKeys(TenantKMSKey{TenantID: "tenant-byok", Name: "byok-key", Client: dedicated}),
+	)
+	ctx := context.Background()
+
+	created, err := svc.CreateKey(ctx, "tenant-byok", domain.KeySpec{})
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	rotated, err := svc.RotateKey(ctx, "tenant-byok", domain.KeySpec{})
+	if err != nil {
+		t.Fatalf("RotateKey failed: %v", err)
+	}
+	if created.KMSKeyName != "byok-key" || rotated.KMSKeyName != "byok-key" {
+		t.Errorf("want tenant with an override wrapped with byok-key, got %q and %q", created.KMSKeyName, rotated.KMSKeyName)
+	}
+	if dedicated.encrypts != 2 || global.encrypts != 0 {
+		t.Errorf("want 2 encrypts with the dedicated key and none with the global key, got %d and %d", dedicated.encrypts, global.encrypts)
+	}
+
+	other, err := svc.CreateKey(ctx, "tenant-other", domain.KeySpec{})
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	if other.KMSKeyName != "global-key" || global.encrypts != 1 || dedicated.encrypts != 2 {
+		t.Errorf("want tenant without an override wrapped with global-key, got %q (global %d, dedicated %d)",
+			other.KMSKeyName, global.encrypts, dedicated.encrypts)
+	}
+	for _, k := range repo.createdKeys {
+		want := "global-key"
+		if k.TenantID == "tenant-byok" {
+			want = "byok-key"
+		}
+		if k.KMSKeyName != want {
+			t.Errorf("%s generation %d: want stored KMS key %q, got %q", k.TenantID, k.Generation, want, k.KMSKeyName)
+		}
+	}
+}
+
+func TestKeyService_GetCurrentKey_TenantKMSKeyFirst(t *testing.T) {
+	calls := &[]string{}
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{
+			TenantID:     "tenant-byok",
+			Generation:   1,
+			EncryptedKey: []byte("encrypted"),
+			KMSKeyName:   "byok-key",
+			Status:       domain.KeyStatusActive,
+		},
+	}
+	svc := NewKeyService(repo, &recordingKMSClient{name: "global-key", calls: calls},
+		WithKMSKeyName("global-key"),
+		WithTenantKMSKeys(TenantKMSKey{
+			TenantID: "tenant-byok",
+			Name:     "byok-key",
+			Client:   &recordingKMSClient{name: "byok-key", decryptable: true, calls: calls},
+		}),
+	)
+
+	if _, err := svc.GetCurrentKey(context.Background(), "tenant-byok"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"byok-key"}; !slices.Equal(*calls, want) {
+		t.Errorf("want decrypt attempts %v, got %v", want, *calls)
+	}
+}
+
+// countingKMSClient はEncrypt・Decryptの呼び出し回数を記録するテスト用のKMSClient。
+type countingKMSClient struct {
+	mockKMSClient
+	encrypts int
+	decrypts int
+}
+
+func (c *countingKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	c.encrypts++
+	return c.mockKMSClient.Encrypt(ctx, plaintext, aad)
+}
+
+func (c *countingKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	c.decrypts++
+	return c.mockKMSClient.Decrypt(ctx, ciphertext, aad)
+}
+
+func TestKeyService_EncryptBatch(t *testing.T) {
+	keyMaterial := bytes.Repeat([]byte{0x42}, 32)
+	kms := &countingKMSClient{mockKMSClient: mockKMSClient{decryptResult: bytes.Clone(keyMaterial)}}
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{
+			ID:           "key-3",
+			TenantID:     "tenant-001",
+			Generation:   3,
+			KeyType:      domain.KeyTypeAES,
+			Bits:         256,
+			EncryptedKey: []byte("wrapped"),
+			Status:       domain.KeyStatusActive,
+		},
+	}
+	svc := NewKeyService(repo, kms)
+
+	plaintexts := [][]byte{[]byte("alpha"), []byte("bravo"), {}, []byte("charlie")}
+	result, err := svc.EncryptBatch(context.Background(), "tenant-001", plaintexts)
+	if err != nil {
+		t.Fatalf("EncryptBatch failed: %v", err)
+	}
+	// 鍵の復号はバッチ全体で1回のみ
+	if kms.decrypts != 1 {
+		t.Errorf("want exactly 1 KMS decrypt for the batch, got %d", kms.decrypts)
+	}
+	if result.Generation != 3 || len(result.Ciphertexts) != len(plaintexts) {
+		t.Fatalf("want %d ciphertexts under generation 3, got %d under %d", len(plaintexts), len(result.Ciphertexts), result.Generation)
+	}
+
+	block, err := aes.NewCipher(keyMaterial)
+	if err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("NewGCM failed: %v", err)
+	}
+	for i, c := range result.Ciphertexts {
+		got, err := aead.Open(nil, c[:aead.NonceSize()], c[aead.NonceSize():], nil)
+		if err != nil {
+			t.Fatalf("ciphertexts[%d] does not decrypt: %v", i, err)
+		}
+		if !bytes.Equal(got, plaintexts[i]) {
+			t.Errorf("ciphertexts[%d] = %q, want %q", i, got, plaintexts[i])
+		}
+	}
+}
+
+func TestKeyService_EncryptBatch_Limits(t *testing.T) {
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{TenantID: "tenant-001", Generation: 1, KeyType: domain.KeyTypeHMAC, Status: domain.KeyStatusActive},
+	}
+	kms := &countingKMSClient{}
+	svc := NewKeyService(repo, kms)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		plaintexts [][]byte
+		wantErr    error
+	}{
+		{"empty", nil, domain.ErrInvalidBatchSize},
+		{"too many items", make([][]byte, domain.MaxBatchEncryptItems+1), domain.ErrInvalidBatchSize},
+		{"too many bytes", [][]byte{make([]byte, domain.MaxBatchEncryptBytes), {0}}, domain.ErrBatchPayloadTooLarge},
+		{"hmac key", [][]byte{[]byte("x")}, domain.ErrUnsupportedKeyType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.EncryptBatch(ctx, "tenant-001", tt.plaintexts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if kms.decrypts != 0 {
+		t.Errorf("want no KMS decrypt for rejected batches, got %d", kms.decrypts)
+	}
+}
+
+func TestKMSEncrypt_PayloadSizeLimit(t *testing.T) {
+	const limit = 64
+	kms := &countingKMSClient{}
+	service := NewKeyService(&mockKeyRepository{}, kms, WithKMSMaxPlaintextBytes(limit))
+
+	// 上限ちょうどはKMSを呼び出す
+	if _, err := service.kmsEncrypt(context.Background(), make([]byte, limit), nil); err != nil {
+		t.Fatalf("at limit: unexpected error: %v", err)
+	}
+	if kms.encrypts != 1 {
+		t.Fatalf("at limit: want 1 KMS call, got %d", kms.encrypts)
+	}
+
+	// 上限を超える場合はKMSを呼び出さない
+	_, err := service.kmsEncrypt(context.Background(), make([]byte, limit+1), nil)
+	if !errors.Is(err, domain.ErrPayloadTooLarge) {
+		t.Errorf("above limit: want ErrPayloadTooLarge, got %v", err)
+	}
+	if kms.encrypts != 1 {
+		t.Errorf("above limit: want no KMS call, got %d calls", kms.encrypts)
+	}
+
+	// 0の場合は検証しない
+	unchecked := NewKeyService(&mockKeyRepository{}, kms)
+	if _, err := unchecked.kmsEncrypt(context.Background(), make([]byte, limit+1), nil); err != nil {
+		t.Errorf("unchecked: unexpected error: %v", err)
+	}
+}
+
+func TestImportKeys_CiphertextSizeLimit(t *testing.T) {
+	const limit = 64
+	newKey := func(size int) []*domain.EncryptionKey {
+		return []*domain.EncryptionKey{{Generation: 1, EncryptedKey: make([]byte, size), Status: domain.KeyStatusActive}}
+	}
+
+	repo := &mockKeyRepository{}
+	service := NewKeyService(repo, &mockKMSClient{}, WithKMSMaxPlaintextBytes(limit))
+	if _, err := service.ImportKeys(context.Background(), "tenant-001", newKey(limit+kmsCiphertextOverhead)); err != nil {
+		t.Fatalf("at limit: unexpected error: %v", err)
+	}
+
+	repo = &mockKeyRepository{}
+	service = NewKeyService(repo, &mockKMSClient{}, WithKMSMaxPlaintextBytes(limit))
+	_, err := service.ImportKeys(context.Background(), "tenant-001", newKey(limit+kmsCiphertextOverhead+1))
+	if !errors.Is(err, domain.ErrPayloadTooLarge) {
+		t.Fatalf("above limit: want ErrPayloadTooLarge, got %v", err)
+	}
+	if len(repo.createdKeys) != 0 {
+		t.Errorf("above limit: want nothing imported, got %d keys", len(repo.createdKeys))
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/internal/domain"
+)
+
+// GetCurrentKeyStatus はテナントの現在の鍵の経過時間とローテーション期限を返す。
+// 鍵は復号しないため、KMSを呼び出さず最終利用日時も更新しない。
+// 期限はテナントの自動ローテーション間隔、未設定の場合はWithRotationDueの既定値で判定する。
+// 鍵の有効期限が切れている場合は、間隔にかかわらずローテーション期限とみなす。
+func (s *KeyService) GetCurrentKeyStatus(ctx context.Context, tenantID string) (_ *domain.CurrentKeyStatus, err error) {
+	ctx, span := tracer.Start(ctx, "KeyService.GetCurrentKeyStatus",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+		),
+	)
+	defer span.End()
+	defer func(start time.Time) { s.metrics.record(ctx, "get_current_key_status", start, err) }(time.Now())
+
+	key, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.EncryptionKey, error) {
+		return s.repo.FindLatestActiveByTenantID(ctx, tenantID)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find current key",
+			"operation", "get_current_key_status",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding current key: %w", err)
+	}
+	if key == nil {
+		return nil, domain.ErrKeyNotFound
+	}
+
+	interval := s.rotationDue
+	if s.settings != nil {
+		settings, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.TenantSettings, error) {
+			return s.settings.Find(ctx, tenantID)
+		})
+		if err != nil {
+			span.RecordError(err)
+			slog.ErrorContext(ctx, "failed to get tenant settings",
+				"operation", "get_current_key_status",
+				"tenant_id", tenantID,
+				"error", err,
+			)
+			return nil, fmt.Errorf("finding tenant settings: %w", err)
+		}
+		if settings != nil && settings.RotationInterval > 0 {
+			interval = settings.RotationInterval
+		}
+	}
+
+	now := time.Now()
+	due := domain.TenantSettings{RotationInterval: interval}.RotationDue(key.CreatedAt, now) || key.Expired(now)
+	span.SetAttributes(
+		attribute.Int("key.generation", int(key.Generation)),
+		attribute.Bool("key.rotation_due", due),
+	)
+	return &domain.CurrentKeyStatus{
+		TenantID:         key.TenantID,
+		Generation:       key.Generation,
+		CreatedAt:        key.CreatedAt,
+		Age:              now.Sub(key.CreatedAt),
+		RotationInterval: interval,
+		RotationDue:      due,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"key-management-service/internal/domain"
+)
+
+// LegacyKMSKey は移行元として復号のみに使用するKMS鍵。
+// KMS鍵の移行中、旧KMS鍵でラップされたままの鍵を無停止で復号するために使用する。
+type LegacyKMSKey struct {
+	Name   string
+	Client KMSClient
+}
+
+// decryptCandidates は鍵の復号を試行するKMS鍵を順に返す。
+// 鍵に記録されたKMS鍵を最初に、次に現在のKMS鍵、残りの移行元のKMS鍵を設定順に試行する。
+// テナント専用のKMS鍵が設定されている場合は、記録されたKMS鍵と一致すれば最初に、それ以外は現在のKMS鍵の次に試行する。
+func (s *KeyService) decryptCandidates(tenantID, storedKMSKeyName string) []LegacyKMSKey {
+	primary := LegacyKMSKey{Name: s.kmsKeyName, Client: s.kmsClient}
+	tenantKey, hasTenantKey := s.tenantKMSKeys[tenantID]
+	if len(s.legacyKMSKeys) == 0 && !hasTenantKey {
+		return []LegacyKMSKey{primary}
+	}
+
+	candidates := make([]LegacyKMSKey, 0, len(s.legacyKMSKeys)+2)
+	tenant := LegacyKMSKey{Name: tenantKey.Name, Client: tenantKey.Client}
+	if hasTenantKey && tenantKey.Name == storedKMSKeyName {
+		candidates = append(candidates, tenant)
+	}
+	for _, k := range s.legacyKMSKeys {
+		if storedKMSKeyName != "" && k.Name == storedKMSKeyName {
+			candidates = append(candidates, k)
+		}
+	}
+	candidates = append(candidates, primary)
+	if hasTenantKey && tenantKey.Name != storedKMSKeyName {
+		candidates = append(candidates, tenant)
+	}
+	for _, k := range s.legacyKMSKeys {
+		if storedKMSKeyName == "" || k.Name != storedKMSKeyName {
+			candidates = append(candidates, k)
+		}
+	}
+	return candidates
+}
+
+// decryptKey は鍵素材をKMSで復号する。テナントIDに紐づけてラップした鍵はテナントIDを追加認証データとして指定する。
+// 復号した鍵素材の長さが記録された鍵長と一致しない場合はdomain.ErrCorruptKeyを返す。
+// 移行元のKMS鍵が設定されている場合、復号に失敗すると次のKMS鍵で再試行し、すべて失敗した場合は最初のエラーを返す。
+// テナントIDに紐づけたかが記録されていない鍵（tenant_aadを省略してインポートした鍵など）は、
+// すべてのKMS鍵でAADなしの復号に失敗した場合に、テナントIDを指定してもう一巡試行する。
+// タイムアウト・キャンセルの場合は以降のKMS鍵を試行しない。
+func (s *KeyService) decryptKey(ctx context.Context, key *domain.EncryptionKey) ([]byte, error) {
+	aads := [][]byte{key.AAD()}
+	if !key.TenantAAD && key.TenantID != "" {
+		aads = append(aads, domain.TenantAAD(key.TenantID))
+	}
+	candidates := s.decryptCandidates(key.TenantID, key.KMSKeyName)
+	var firstErr error
+	for pass, aad := range aads {
+		for i, candidate := range candidates {
+			plainKey, err := callWithTimeout(ctx, s.kmsTimeout, func(ctx context.Context) ([]byte, error) {
+				return candidate.Client.Decrypt(ctx, key.EncryptedKey, aad)
+			})
+			if err == nil {
+				// KMSの復号に成功しても長さが合わない鍵素材は鍵として返さない
+				if len(plainKey) != key.KeySize() {
+					slog.ErrorContext(ctx, "decrypted key has unexpected length",
+						"operation", "decrypt_key",
+						"tenant_id", key.TenantID,
+						"generation", key.Generation,
+						"want_bytes", key.KeySize(),
+						"got_bytes", len(plainKey),
+					)
+					clear(plainKey)
+					return nil, domain.ErrCorruptKey
+				}
+				if i > 0 {
+					slog.InfoContext(ctx, "decrypted key with fallback KMS key",
+						"operation", "decrypt_key",
+						"tenant_id", key.TenantID,
+						"generation", key.Generation,
+						"stored_kms_key_name", key.KMSKeyName,
+						"kms_key_name", candidate.Name,
+					)
+				}
+				if pass > 0 {
+					slog.InfoContext(ctx, "decrypted key with tenant AAD not recorded on the key",
+						"operation", "decrypt_key",
+						"tenant_id", key.TenantID,
+						"generation", key.Generation,
+					)
+				}
+				return plainKey, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil || errors.Is(err, domain.ErrUpstreamTimeout) {
+				return nil, firstErr
+			}
+		}
+	}
+	return nil, firstErr
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import "context"
+
+// limitedKMSClient は同時に実行するKMS呼び出しの数を制限するKMSClient。
+// リクエスト数によらずKMSへの同時呼び出しを上限以下に抑え、KMSのクォータ超過を防ぐ。
+type limitedKMSClient struct {
+	next KMSClient
+	sem  chan struct{}
+}
+
+// newLimitedKMSClient は同時呼び出し数をnに制限するKMSClientを生成する。
+func newLimitedKMSClient(next KMSClient, n int) *limitedKMSClient {
+	return &limitedKMSClient{next: next, sem: make(chan struct{}, n)}
+}
+
+// acquire は実行枠を確保する。枠が空く前にctxが終了した場合はctxのエラーを返す。
+func (c *limitedKMSClient) acquire(ctx context.Context) error {
+	select {
+	case c.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *limitedKMSClient) release() {
+	<-c.sem
+}
+
+// Encrypt は実行枠を確保してから暗号化する。
+func (c *limitedKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.next.Encrypt(ctx, plaintext, aad)
+}
+
+// Decrypt は実行枠を確保してから復号する。
+func (c *limitedKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.next.Decrypt(ctx, ciphertext, aad)
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"key-management-service/internal/domain"
+)
+
+// concurrencyKMSClient は同時に実行中の呼び出し数の最大値を記録するテスト用KMSクライアント。
+type concurrencyKMSClient struct {
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	delay    time.Duration
+}
+
+func (c *concurrencyKMSClient) call() {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(c.delay)
+}
+
+func (c *concurrencyKMSClient) Encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	c.call()
+	return plaintext, nil
+}
+
+func (c *concurrencyKMSClient) Decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	c.call()
+	return ciphertext, nil
+}
+
+func TestKeyService_KMSMaxConcurrency(t *testing.T) {
+	kms := &concurrencyKMSClient{delay: 10 * time.Millisecond}
+	svc := NewKeyService(&mockKeyRepository{}, kms, WithKMSMaxConcurrency(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			if i%2 == 0 {
+				_, err = svc.kmsEncrypt(context.Background(), []byte("plain"), nil)
+			} else {
+				_, err = svc.decryptKey(context.Background(), &domain.EncryptionKey{EncryptedKey: make([]byte, 32)})
+			}
+			if err != nil {
+				t.Errorf("call %d: unexpected error: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if peak := kms.peak.Load(); peak > 2 {
+		t.Errorf("want at most 2 concurrent KMS calls, got %d", peak)
+	}
+	if peak := kms.peak.Load(); peak < 2 {
+		t.Errorf("want calls to run in parallel up to the limit, got peak %d", peak)
+	}
+}
+
+func TestKeyService_KMSMaxConcurrency_WaitCountsTowardTimeout(t *testing.T) {
+	kms := &mockKMSClient{block: true}
+	svc := NewKeyService(&mockKeyRepository{}, kms, WithKMSMaxConcurrency(1), WithKMSTimeout(20*time.Millisecond))
+
+	// 1件目が枠を占有している間、2件目は枠待ちのままタイムアウトする
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.kmsEncrypt(context.Background(), []byte("plain"), nil)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if !errors.Is(err, domain.ErrUpstreamTimeout) {
+			t.Errorf("call %d: want ErrUpstreamTimeout, got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+// TenantKMSKey はテナント専用のKMS鍵。
+// 大口の顧客の鍵を専用のKMS鍵（顧客管理の鍵を含む）でラップし、他のテナントから分離するために使用する。
+type TenantKMSKey struct {
+	TenantID string
+	Name     string
+	Client   KMSClient
+}
+
+// wrappingKMSKey はテナントの鍵のラップに使用するKMS鍵名とクライアントを返す。
+// テナント専用のKMS鍵が設定されていない場合はKMS_KEY_NAMEの鍵を返す。
+func (s *KeyService) wrappingKMSKey(tenantID string) (string, KMSClient) {
+	if k, ok := s.tenantKMSKeys[tenantID]; ok {
+		return k.Name, k.Client
+	}
+	return s.kmsKeyName, s.kmsClient
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LastUsedRepository は鍵の最終利用日時を保存するリポジトリのインターフェース。
+type LastUsedRepository interface {
+	UpdateLastUsedAt(ctx context.Context, lastUsed map[string]time.Time) error
+}
+
+// LastUsedTracker は鍵の最終利用日時をメモリに集約し、一定間隔でまとめて保存する。
+// 取得のたびにデータベースへ書き込まないよう、鍵ごとに最新の日時のみを保持する。
+type LastUsedTracker struct {
+	repo      LastUsedRepository
+	interval  time.Duration
+	dbTimeout time.Duration
+
+	mu      sync.Mutex
+	pending map[string]time.Time
+}
+
+// NewLastUsedTracker は新しいLastUsedTrackerを生成する。dbTimeoutが0の場合は書き込みにタイムアウトを設定しない。
+func NewLastUsedTracker(repo LastUsedRepository, interval, dbTimeout time.Duration) *LastUsedTracker {
+	return &LastUsedTracker{
+		repo:      repo,
+		interval:  interval,
+		dbTimeout: dbTimeout,
+		pending:   make(map[string]time.Time),
+	}
+}
+
+// Record は鍵の利用を記録する。保存は次回のFlushで行う。
+func (t *LastUsedTracker) Record(keyID string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if prev, ok := t.pending[keyID]; !ok || at.After(prev) {
+		t.pending[keyID] = at
+	}
+}
+
+// Flush は記録済みの最終利用日時を保存する。
+// 保存に失敗した場合は次回のFlushで再試行できるよう、記録を戻す。
+func (t *LastUsedTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = make(map[string]time.Time)
+	t.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	_, err := callWithTimeout(ctx, t.dbTimeout, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, t.repo.UpdateLastUsedAt(ctx, batch)
+	})
+	if err != nil {
+		t.mu.Lock()
+		for id, at := range t.pending {
+			if prev, ok := batch[id]; !ok || at.After(prev) {
+				batch[id] = at
+			}
+		}
+		t.pending = batch
+		t.mu.Unlock()
+		return err
+	}
+	slog.DebugContext(ctx, "flushed last used time",
+		"operation", "flush_last_used",
+		"keys", len(batch),
+	)
+	return nil
+}
+
+// Run はctxがキャンセルされるまで一定間隔でFlushする。
+// 終了時に残った記録は保存しないため、呼び出し側で最後にFlushする。
+func (t *LastUsedTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				slog.WarnContext(ctx, "failed to flush last used time",
+					"operation", "flush_last_used",
+					"error", err,
+				)
+			}
+		}
+	}
+}
+
+// recordLastUsed は鍵の利用を記録する。トラッカーが設定されていない場合は何もしない。
+func (s *KeyService) recordLastUsed(keyID string) {
+	if s.lastUsed != nil {
+		s.lastUsed.Record(keyID, time.Now())
+	}
+}
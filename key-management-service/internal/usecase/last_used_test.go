@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"key-management-service/internal/domain"
+)
+
+// fakeLastUsedRepository は保存された最終利用日時を記録するテスト用リポジトリ。
+type fakeLastUsedRepository struct {
+	mu      sync.Mutex
+	err     error
+	calls   int
+	updated map[string]time.Time
+}
+
+func (f *fakeLastUsedRepository) UpdateLastUsedAt(ctx context.Context, lastUsed map[string]time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	if f.updated == nil {
+		f.updated = make(map[string]time.Time)
+	}
+	for id, at := range lastUsed {
+		f.updated[id] = at
+	}
+	return nil
+}
+
+func (f *fakeLastUsedRepository) get(id string) (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	at, ok := f.updated[id]
+	return at, ok
+}
+
+func TestLastUsedTracker_Flush(t *testing.T) {
+	repo := &fakeLastUsedRepository{}
+	tracker := NewLastUsedTracker(repo, time.Minute, 0)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 同じ鍵の利用は最新の日時のみを1回で保存する
+	tracker.Record("key-1", base.Add(2*time.Second))
+	tracker.Record("key-1", base.Add(time.Second))
+	tracker.Record("key-2", base)
+
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if at, _ := repo.get("key-1"); !at.Equal(base.Add(2 * time.Second)) {
+		t.Errorf("key-1: want newest time %v, got %v", base.Add(2*time.Second), at)
+	}
+	if _, ok := repo.get("key-2"); !ok {
+		t.Error("key-2: want last used time saved")
+	}
+
+	// 記録がなければ書き込まない
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if repo.calls != 1 {
+		t.Errorf("want 1 write, got %d", repo.calls)
+	}
+}
+
+func TestLastUsedTracker_FlushRetriesAfterFailure(t *testing.T) {
+	repo := &fakeLastUsedRepository{err: errors.New("db down")}
+	tracker := NewLastUsedTracker(repo, time.Minute, 0)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.Record("key-1", base)
+	if err := tracker.Flush(context.Background()); err == nil {
+		t.Fatal("want error from Flush, got nil")
+	}
+
+	// 失敗した記録は保持され、次回のFlushで保存される
+	repo.err = nil
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if at, _ := repo.get("key-1"); !at.Equal(base) {
+		t.Errorf("want %v saved after retry, got %v", base, at)
+	}
+}
+
+func TestKeyService_RecordsLastUsed(t *testing.T) {
+	repo := &mockKeyRepository{
+		findLatestResult: &domain.EncryptionKey{ID: "key-current", TenantID: "tenant-001", Generation: 2, Status: domain.KeyStatusActive},
+		findByGenResult:  &domain.EncryptionKey{ID: "key-gen-1", TenantID: "tenant-001", Generation: 1, Status: domain.KeyStatusActive},
+	}
+	lastUsedRepo := &fakeLastUsedRepository{}
+	tracker := NewLastUsedTracker(lastUsedRepo, 10*time.Millisecond, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.Run(ctx)
+
+	svc := NewKeyService(repo, &mockKMSClient{}, WithLastUsedTracker(tracker))
+	before := time.Now()
+	if _, err := svc.GetCurrentKey(context.Background(), "tenant-001"); err != nil {
+		t.Fatalf("GetCurrentKey failed: %v", err)
+	}
+	if _, err := svc.GetKeyByGeneration(context.Background(), "tenant-001", 1); err != nil {
+		t.Fatalf("GetKeyByGeneration failed: %v", err)
+	}
+
+	// 取得時には書き込まず、定期的な書き込みで反映される
+	deadline := time.Now().Add(2 * time.Second)
+	for _, id := range []string{"key-current", "key-gen-1"} {
+		for {
+			if at, ok := lastUsedRepo.get(id); ok {
+				if at.Before(before) {
+					t.Errorf("%s: want last used at or after %v, got %v", id, before, at)
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: last used time was not saved", id)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
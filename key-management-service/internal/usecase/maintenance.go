@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/internal/domain"
+)
+
+// BackfillStatus はステータスが空の鍵（手動で投入された行など）にステータスを設定する。
+// ステータスが空の鍵はFindLatestActiveByTenantIDなどの検索から漏れるため、一度だけ実行する保守操作として使用する。
+// 無効化日時が記録されている鍵は誤って有効に戻さないようdisabledとする。dryRunの場合は件数のみを返す。
+func (s *KeyService) BackfillStatus(ctx context.Context, dryRun bool) (*domain.StatusBackfillResult, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.BackfillStatus",
+		trace.WithAttributes(
+			attribute.Bool("backfill.dry_run", dryRun),
+		),
+	)
+	defer span.End()
+
+	counts, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (map[domain.KeyStatus]int, error) {
+		return s.repo.BackfillStatus(ctx, dryRun)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to backfill key status",
+			"operation", "backfill_status",
+			"dry_run", dryRun,
+			"error", err,
+		)
+		return nil, fmt.Errorf("backfilling status: %w", err)
+	}
+
+	result := &domain.StatusBackfillResult{
+		Activated: counts[domain.KeyStatusActive],
+		Disabled:  counts[domain.KeyStatusDisabled],
+		DryRun:    dryRun,
+	}
+	slog.InfoContext(ctx, "backfilled key status",
+		"operation", "backfill_status",
+		"dry_run", dryRun,
+		"activated", result.Activated,
+		"disabled", result.Disabled,
+	)
+	return result, nil
+}
+
+// CheckTenantIntegrity はテナントの鍵の世代の欠番・重複と、同じ世代に有効な鍵が複数あるかを確認する。
+// 診断のみを目的とし、鍵は変更しない。鍵が1つもない場合はdomain.ErrKeyNotFoundを返す。
+func (s *KeyService) CheckTenantIntegrity(ctx context.Context, tenantID string) (*domain.IntegrityReport, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.CheckTenantIntegrity",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+		),
+	)
+	defer span.End()
+
+	keys, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]*domain.EncryptionKey, error) {
+		return s.repo.FindAllByTenantID(ctx, tenantID)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to find keys",
+			"operation", "check_tenant_integrity",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, domain.ErrKeyNotFound
+	}
+
+	report := checkGenerations(tenantID, keys)
+	span.SetAttributes(attribute.Bool("integrity.ok", report.OK()))
+	if !report.OK() {
+		slog.WarnContext(ctx, "tenant key integrity problems found",
+			"operation", "check_tenant_integrity",
+			"tenant_id", tenantID,
+			"missing_generations", len(report.MissingGenerations),
+			"duplicate_generations", len(report.DuplicateGenerations),
+			"duplicate_active_generations", len(report.DuplicateActiveGenerations),
+		)
+	}
+	return report, nil
+}
+
+// checkGenerations はテナントの全鍵から世代の整合性を確認する。
+func checkGenerations(tenantID string, keys []*domain.EncryptionKey) *domain.IntegrityReport {
+	rows := make(map[uint]int, len(keys))
+	active := make(map[uint]int, len(keys))
+	report := &domain.IntegrityReport{TenantID: tenantID, KeyCount: len(keys)}
+	for _, k := range keys {
+		rows[k.Generation]++
+		if k.Status == domain.KeyStatusActive {
+			active[k.Generation]++
+		}
+		report.MaxGeneration = max(report.MaxGeneration, k.Generation)
+	}
+
+	generations := make([]uint, 0, len(rows))
+	for gen, n := range rows {
+		generations = append(generations, gen)
+		if n > 1 {
+			report.DuplicateGenerations = append(report.DuplicateGenerations, gen)
+		}
+		if active[gen] > 1 {
+			report.DuplicateActiveGenerations = append(report.DuplicateActiveGenerations, gen)
+		}
+	}
+	slices.Sort(generations)
+	slices.Sort(report.DuplicateGenerations)
+	slices.Sort(report.DuplicateActiveGenerations)
+
+	// 世代は1から連番で採番されるため、直前の世代との間が空いていれば欠番
+	var prev uint
+	for _, gen := range generations {
+		if gen > prev+1 {
+			report.MissingGenerations = append(report.MissingGenerations, domain.GenerationRange{From: prev + 1, To: gen - 1})
+		}
+		prev = gen
+	}
+	return report
+}
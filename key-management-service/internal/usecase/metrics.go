@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+var meter = otel.Meter("key-management-service")
+
+// serviceMetrics は鍵操作のメトリクスを記録する。
+type serviceMetrics struct {
+	operations metric.Int64Counter
+	duration   metric.Float64Histogram
+}
+
+// newServiceMetrics はメトリクス計器を生成する。
+// 計器の生成に失敗した場合はメトリクスを記録しない計器にフォールバックする。
+func newServiceMetrics() *serviceMetrics {
+	operations, err := meter.Int64Counter("key.operations",
+		metric.WithDescription("Number of key operations"),
+	)
+	if err != nil {
+		slog.Warn("failed to create key operations counter", "error", err)
+		operations = noop.Int64Counter{}
+	}
+	duration, err := meter.Float64Histogram("key.operation.duration",
+		metric.WithDescription("Key operation latency"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		slog.Warn("failed to create key operation histogram", "error", err)
+		duration = noop.Float64Histogram{}
+	}
+	return &serviceMetrics{
+		operations: operations,
+		duration:   duration,
+	}
+}
+
+// record は操作の件数と所要時間を記録する。
+func (m *serviceMetrics) record(ctx context.Context, operation string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("operation", operation),
+		attribute.String("result", result),
+	)
+	m.operations.Add(ctx, 1, attrs)
+	m.duration.Record(ctx, time.Since(start).Seconds(), attrs)
+}
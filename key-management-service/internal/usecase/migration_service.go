@@ -1,12 +1,14 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
 
 	"key-management-service/internal/domain"
@@ -38,6 +40,7 @@ func NewMigrationService(repo MigrationRepository, db *gorm.DB, migrationsDir st
 }
 
 // scanMigrationFiles はmigrationsディレクトリから.sqlファイルをスキャンする。
+// 同じバージョンのファイルが複数ある場合は*domain.DuplicateMigrationVersionErrorを返す。
 func (s *MigrationService) scanMigrationFiles(ctx context.Context) ([]*domain.Migration, error) {
 	entries, err := os.ReadDir(s.migrationsDir)
 	if err != nil {
@@ -45,6 +48,7 @@ func (s *MigrationService) scanMigrationFiles(ctx context.Context) ([]*domain.Mi
 	}
 
 	var migrations []*domain.Migration
+	seen := make(map[string]string)
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
 			continue
@@ -56,6 +60,10 @@ func (s *MigrationService) scanMigrationFiles(ctx context.Context) ([]*domain.Mi
 		}
 
 		filePath := filepath.Join(s.migrationsDir, entry.Name())
+		if prev, ok := seen[version]; ok {
+			return nil, &domain.DuplicateMigrationVersionError{Version: version, Files: [2]string{prev, filePath}}
+		}
+		seen[version] = filePath
 		migrations = append(migrations, &domain.Migration{
 			Version:  version,
 			Name:     name,
@@ -64,14 +72,28 @@ func (s *MigrationService) scanMigrationFiles(ctx context.Context) ([]*domain.Mi
 		})
 	}
 
-	// バージョン順にソート
-	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].Version < migrations[j].Version
+	// バージョン順にソート（数値のバージョンは桁数によらず数値順）
+	slices.SortStableFunc(migrations, func(a, b *domain.Migration) int {
+		return compareMigrationVersions(a.Version, b.Version)
 	})
 
 	return migrations, nil
 }
 
+// compareMigrationVersions はマイグレーションのバージョンを比較する。
+// 両方が数字のみの場合は数値として比較し（"9" < "10"、"001" < "010" < "100"）、
+// 数値が等しい場合（"1"と"001"）や数字以外を含む場合は文字列として比較する。
+func compareMigrationVersions(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	if errA == nil && errB == nil {
+		if c := cmp.Compare(na, nb); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a, b)
+}
+
 // parseMigrationFileName はファイル名からバージョンと名前を抽出する。
 // ファイル名のフォーマット: {version}_{name}.sql (例: 001_create_users.sql)
 func parseMigrationFileName(filename string) (version, name string, err error) {
@@ -132,7 +154,11 @@ func (s *MigrationService) ApplyMigrations(ctx context.Context) (int, error) {
 				"version", migration.Version,
 				"error", err,
 			)
-			return appliedCount, fmt.Errorf("%w: version %s: %v", domain.ErrMigrationFailed, migration.Version, err)
+			return appliedCount, &domain.MigrationError{
+				Version: migration.Version,
+				Applied: appliedCount,
+				Err:     err,
+			}
 		}
 		appliedCount++
 	}
@@ -140,6 +166,43 @@ func (s *MigrationService) ApplyMigrations(ctx context.Context) (int, error) {
 	return appliedCount, nil
 }
 
+// PlanMigrations は未適用マイグレーションを実行順に取得する（ドライラン用）。
+// 適用済みの判定には履歴の読み込みのみを行い、データベースへの書き込みは一切行わない。
+func (s *MigrationService) PlanMigrations(ctx context.Context) ([]*domain.Migration, error) {
+	allMigrations, err := s.scanMigrationFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedMigrations, err := s.repo.FindAllApplied(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch applied migrations",
+			"operation", "plan_migrations",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to fetch applied migrations: %w", err)
+	}
+	applied := make(map[string]struct{}, len(appliedMigrations))
+	for _, migration := range appliedMigrations {
+		applied[migration.Version] = struct{}{}
+	}
+
+	var pendingMigrations []*domain.Migration
+	for _, migration := range allMigrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		sqlBytes, err := os.ReadFile(migration.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file: %w", err)
+		}
+		migration.SQL = string(sqlBytes)
+		pendingMigrations = append(pendingMigrations, migration)
+	}
+
+	return pendingMigrations, nil
+}
+
 // applyMigration は単一のマイグレーションを実行する。
 func (s *MigrationService) applyMigration(ctx context.Context, migration *domain.Migration) error {
 	// SQLファイルを読み込み
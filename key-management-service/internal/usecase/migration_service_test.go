@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +21,8 @@ import (
 type mockMigrationRepository struct {
 	appliedMigrations map[string]*domain.Migration
 	recordError       error
+	recordCalls       int
+	isAppliedCalls    int
 }
 
 func newMockMigrationRepository() *mockMigrationRepository {
@@ -34,6 +40,7 @@ func (m *mockMigrationRepository) FindAllApplied(ctx context.Context) ([]*domain
 }
 
 func (m *mockMigrationRepository) RecordMigration(ctx context.Context, version string) error {
+	m.recordCalls++
 	if m.recordError != nil {
 		return m.recordError
 	}
@@ -47,6 +54,7 @@ func (m *mockMigrationRepository) RecordMigration(ctx context.Context, version s
 }
 
 func (m *mockMigrationRepository) IsMigrationApplied(ctx context.Context, version string) (bool, error) {
+	m.isAppliedCalls++
 	_, exists := m.appliedMigrations[version]
 	return exists, nil
 }
@@ -175,9 +183,49 @@ func TestMigrationService_ApplyMigrations_Error(t *testing.T) {
 	}
 
 	// マイグレーションを実行（エラーが発生することを期待）
-	_, err := service.ApplyMigrations(ctx)
+	count, err := service.ApplyMigrations(ctx)
 	if err == nil {
-		t.Error("expected error for invalid SQL, but got nil")
+		t.Fatal("expected error for invalid SQL, but got nil")
+	}
+	if count != 3 {
+		t.Errorf("expected 3 migrations applied before failure, got %d", count)
+	}
+
+	var migErr *domain.MigrationError
+	if !errors.As(err, &migErr) {
+		t.Fatalf("expected *domain.MigrationError, got %T", err)
+	}
+	if migErr.Version != "004" {
+		t.Errorf("expected failing version 004, got %s", migErr.Version)
+	}
+	if migErr.Applied != 3 {
+		t.Errorf("expected Applied 3, got %d", migErr.Applied)
+	}
+	if !errors.Is(err, domain.ErrMigrationFailed) {
+		t.Error("expected error to wrap ErrMigrationFailed")
+	}
+}
+
+func TestMigrationError_ErrorsAs(t *testing.T) {
+	cause := errors.New("syntax error")
+	var err error = fmt.Errorf("migration failed: %w", &domain.MigrationError{
+		Version: "002",
+		Applied: 1,
+		Err:     cause,
+	})
+
+	var migErr *domain.MigrationError
+	if !errors.As(err, &migErr) {
+		t.Fatal("expected errors.As to find *domain.MigrationError")
+	}
+	if migErr.Version != "002" || migErr.Applied != 1 {
+		t.Errorf("unexpected fields: version=%s applied=%d", migErr.Version, migErr.Applied)
+	}
+	if !errors.Is(err, domain.ErrMigrationFailed) {
+		t.Error("expected error to wrap ErrMigrationFailed")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected error to wrap the cause")
 	}
 }
 
@@ -226,3 +274,129 @@ func TestMigrationService_GetMigrationStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestMigrationService_PlanMigrations(t *testing.T) {
+	ctx := context.Background()
+	migrationsDir := setupTestMigrationsDir(t)
+	db := setupTestDB(t)
+	repo := newMockMigrationRepository()
+
+	// 001は適用済み
+	now := time.Now()
+	repo.appliedMigrations["001"] = &domain.Migration{
+		Version:   "001",
+		AppliedAt: &now,
+		Status:    domain.MigrationStatusApplied,
+	}
+
+	service := NewMigrationService(repo, db, migrationsDir)
+
+	plan, err := service.PlanMigrations(ctx)
+	if err != nil {
+		t.Fatalf("PlanMigrations failed: %v", err)
+	}
+
+	if len(plan) != 2 {
+		t.Fatalf("expected 2 pending migrations, got %d", len(plan))
+	}
+	if plan[0].Version != "002" || plan[1].Version != "003" {
+		t.Errorf("expected versions [002 003], got [%s %s]", plan[0].Version, plan[1].Version)
+	}
+	if plan[0].SQL != "CREATE TABLE posts (id INT);" {
+		t.Errorf("unexpected SQL preview: %q", plan[0].SQL)
+	}
+	if plan[0].FilePath != filepath.Join(migrationsDir, "002_create_posts.sql") {
+		t.Errorf("unexpected file path: %s", plan[0].FilePath)
+	}
+
+	// 書き込み・適用判定は行われない
+	if repo.isAppliedCalls != 0 {
+		t.Errorf("expected IsMigrationApplied not to be called, got %d calls", repo.isAppliedCalls)
+	}
+	if repo.recordCalls != 0 {
+		t.Errorf("expected RecordMigration not to be called, got %d calls", repo.recordCalls)
+	}
+
+	// テーブルが作成されていないことを確認
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='posts'").Scan(&count)
+	if count != 0 {
+		t.Error("expected posts table not to be created by dry run")
+	}
+}
+
+func TestMigrationService_ScanMigrationFiles_NumericOrder(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"100_d.sql", "10_c.sql", "2_b.sql", "1_a.sql", "beta_f.sql", "alpha_e.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0644); err != nil {
+			t.Fatalf("failed to create test migration file: %v", err)
+		}
+	}
+	service := NewMigrationService(newMockMigrationRepository(), nil, dir)
+
+	migrations, err := service.scanMigrationFiles(context.Background())
+	if err != nil {
+		t.Fatalf("scanMigrationFiles failed: %v", err)
+	}
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.Version)
+	}
+	// 数値のバージョンは数値順、数字以外を含むバージョンは文字列順
+	want := []string{"1", "2", "10", "100", "alpha", "beta"}
+	if !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestMigrationService_ScanMigrationFiles_DuplicateVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"001_a.sql", "001_b.sql", "002_c.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0644); err != nil {
+			t.Fatalf("failed to create test migration file: %v", err)
+		}
+	}
+	service := NewMigrationService(newMockMigrationRepository(), nil, dir)
+
+	_, err := service.scanMigrationFiles(context.Background())
+	if !errors.Is(err, domain.ErrDuplicateMigrationVersion) {
+		t.Fatalf("want ErrDuplicateMigrationVersion, got %v", err)
+	}
+	var dupErr *domain.DuplicateMigrationVersionError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("want *domain.DuplicateMigrationVersionError, got %T", err)
+	}
+	if dupErr.Version != "001" {
+		t.Errorf("want version 001, got %s", dupErr.Version)
+	}
+	want := [2]string{filepath.Join(dir, "001_a.sql"), filepath.Join(dir, "001_b.sql")}
+	if dupErr.Files != want {
+		t.Errorf("want files %v, got %v", want, dupErr.Files)
+	}
+	for _, f := range want {
+		if !strings.Contains(err.Error(), f) {
+			t.Errorf("want error message to name %s, got %q", f, err)
+		}
+	}
+}
+
+func TestCompareMigrationVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "001", b: "002", want: -1},
+		{a: "009", b: "010", want: -1},
+		{a: "9", b: "10", want: -1},
+		{a: "100", b: "10", want: 1},
+		{a: "20250101120000", b: "20241231235959", want: 1},
+		{a: "012", b: "12", want: -1},
+		{a: "012", b: "012", want: 0},
+		{a: "abc", b: "abd", want: -1},
+	}
+	for _, tt := range tests {
+		if got := compareMigrationVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareMigrationVersions(%q, %q): want %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
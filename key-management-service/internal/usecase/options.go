@@ -0,0 +1,85 @@
+package usecase
+
+import "time"
+
+// KeyServiceOption はKeyServiceの任意設定を行う。
+type KeyServiceOption func(*KeyService)
+
+// WithKMSTimeout はKMS呼び出し1回あたりのタイムアウトを設定する。0の場合は設定しない。
+func WithKMSTimeout(d time.Duration) KeyServiceOption {
+	return func(s *KeyService) { s.kmsTimeout = d }
+}
+
+// WithDBTimeout はリポジトリ呼び出し1回あたりのタイムアウトを設定する。0の場合は設定しない。
+func WithDBTimeout(d time.Duration) KeyServiceOption {
+	return func(s *KeyService) { s.dbTimeout = d }
+}
+
+// WithKeyRetention はローテーション時に有効なまま保持する世代数を設定する。
+// 超過した古い世代は自動的に無効化される。0の場合は無効化しない。
+func WithKeyRetention(n int) KeyServiceOption {
+	return func(s *KeyService) { s.retention = n }
+}
+
+// WithMaxGeneration は世代番号の上限を設定する。上限に達したテナントはローテーションできない。
+// 0の場合はdomain.MaxGenerationLimitを上限とする。
+func WithMaxGeneration(n uint) KeyServiceOption {
+	return func(s *KeyService) {
+		if n > 0 {
+			s.maxGen = n
+		}
+	}
+}
+
+// WithKMSMaxConcurrency はKMSの暗号化・復号を同時に実行する数の上限を設定する。0の場合は制限しない。
+// 枠が空くまでの待ち時間もKMSタイムアウトに含まれる。
+func WithKMSMaxConcurrency(n int) KeyServiceOption {
+	return func(s *KeyService) {
+		if n > 0 {
+			s.kmsClient = newLimitedKMSClient(s.kmsClient, n)
+		}
+	}
+}
+
+// WithKMSKeyName は鍵のラップに使用するKMS鍵名を設定する。
+// 作成・ローテーション・インポート時に鍵ごとに記録され、メタデータとして返す。
+func WithKMSKeyName(name string) KeyServiceOption {
+	return func(s *KeyService) { s.kmsKeyName = name }
+}
+
+// WithLegacyKMSKeys は復号のみに使用する移行元のKMS鍵を設定する。
+// 鍵に記録されたKMS鍵で復号できない場合に、現在のKMS鍵、移行元のKMS鍵の順に復号を試行する。
+func WithLegacyKMSKeys(keys ...LegacyKMSKey) KeyServiceOption {
+	return func(s *KeyService) { s.legacyKMSKeys = keys }
+}
+
+// WithTenantKMSKeys はテナント専用のKMS鍵を設定する。
+// 設定されたテナントの鍵は作成・ローテーション時にKMS_KEY_NAMEの代わりにこのKMS鍵でラップし、鍵ごとにKMS鍵名を記録する。
+func WithTenantKMSKeys(keys ...TenantKMSKey) KeyServiceOption {
+	return func(s *KeyService) {
+		s.tenantKMSKeys = make(map[string]TenantKMSKey, len(keys))
+		for _, k := range keys {
+			s.tenantKMSKeys[k.TenantID] = k
+		}
+	}
+}
+
+// WithLastUsedTracker は鍵の取得時に最終利用日時を記録するトラッカーを設定する。nilの場合は記録しない。
+func WithLastUsedTracker(t *LastUsedTracker) KeyServiceOption {
+	return func(s *KeyService) { s.lastUsed = t }
+}
+
+// WithRotationDue は現在の鍵のステータスでローテーション期限とみなす経過時間を設定する。
+// settingsが指定された場合は、テナントの自動ローテーション間隔をdより優先する。dが0の場合は既定の期限を設けない。
+func WithRotationDue(d time.Duration, settings TenantSettingsRepository) KeyServiceOption {
+	return func(s *KeyService) {
+		s.rotationDue = d
+		s.settings = settings
+	}
+}
+
+// WithKMSMaxPlaintextBytes はKMSで暗号化できる平文の最大バイト数を設定する。0の場合は検証しない。
+// 上限を超える平文はKMSを呼び出さずにdomain.ErrPayloadTooLargeとする。
+func WithKMSMaxPlaintextBytes(n int) KeyServiceOption {
+	return func(s *KeyService) { s.kmsMaxPlaintext = n }
+}
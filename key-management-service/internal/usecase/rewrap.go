@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/internal/domain"
+)
+
+// DefaultRewrapBatchSize は再ラップで1回に取得する鍵の数の既定値。
+const DefaultRewrapBatchSize = 100
+
+// RewrapByKMSKey は指定されたKMS鍵でラップされた鍵をsourceで復号し、現在のKMS鍵で再ラップする。
+// テナント専用のKMS鍵が設定されたテナントの鍵は、KMS_KEY_NAMEの代わりにそのKMS鍵で再ラップする。
+// includeUnrecordedの場合は、KMS鍵名の記録前に作成された鍵（kms_key_nameが空）もsourceで復号して再ラップする。
+// 鍵はbatchSize件ずつ処理し、バッチごとに進捗をログに出力する。
+// 個々の鍵の失敗は集計して処理を続ける。失敗した鍵は元のKMS鍵のまま残るため、再実行で再試行できる。
+func (s *KeyService) RewrapByKMSKey(ctx context.Context, fromKMSKeyName string, source KMSClient, batchSize int, includeUnrecorded bool) (*domain.RewrapResult, error) {
+	ctx, span := tracer.Start(ctx, "KeyService.RewrapByKMSKey",
+		trace.WithAttributes(
+			attribute.String("kms.from_key_name", fromKMSKeyName),
+			attribute.Bool("rewrap.include_unrecorded", includeUnrecorded),
+		),
+	)
+	defer span.End()
+
+	if s.kmsKeyName == "" {
+		return nil, fmt.Errorf("%w: current KMS key name is not configured", domain.ErrInvalidRewrapSource)
+	}
+	if fromKMSKeyName == "" || fromKMSKeyName == s.kmsKeyName {
+		return nil, domain.ErrInvalidRewrapSource
+	}
+	// テナント専用のKMS鍵は再ラップ先でもあるため、移行元には指定できない
+	for _, k := range s.tenantKMSKeys {
+		if fromKMSKeyName == k.Name {
+			return nil, fmt.Errorf("%w: %s is the configured KMS key of tenant %s", domain.ErrInvalidRewrapSource, fromKMSKeyName, k.TenantID)
+		}
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultRewrapBatchSize
+	}
+
+	result := &domain.RewrapResult{FromKMSKeyName: fromKMSKeyName, ToKMSKeyName: s.kmsKeyName}
+	recorded := []string{fromKMSKeyName}
+	if includeUnrecorded {
+		recorded = append(recorded, "")
+	}
+	for _, name := range recorded {
+		if err := s.rewrapRecordedAs(ctx, name, source, batchSize, result); err != nil {
+			span.RecordError(err)
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// rewrapRecordedAs はkms_key_nameがrecordedKMSKeyNameの鍵をbatchSize件ずつ再ラップし、件数をresultに加算する。
+func (s *KeyService) rewrapRecordedAs(ctx context.Context, recordedKMSKeyName string, source KMSClient, batchSize int, result *domain.RewrapResult) error {
+	failed := 0
+	for {
+		// 再ラップした鍵は検索条件から外れるため、失敗して残った鍵の数だけ読み飛ばす
+		keys, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) ([]*domain.EncryptionKey, error) {
+			return s.repo.FindByKMSKeyName(ctx, recordedKMSKeyName, batchSize, failed)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to find keys to rewrap",
+				"operation", "rewrap_by_kms_key",
+				"from_kms_key_name", recordedKMSKeyName,
+				"error", err,
+			)
+			return fmt.Errorf("finding keys: %w", err)
+		}
+
+		for _, k := range keys {
+			updated, err := s.rewrapKey(ctx, k, recordedKMSKeyName, source)
+			if err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("rewrapping key: %w", err)
+				}
+				failed++
+				result.Failed++
+				slog.WarnContext(ctx, "failed to rewrap key",
+					"operation", "rewrap_by_kms_key",
+					"tenant_id", k.TenantID,
+					"generation", k.Generation,
+					"from_kms_key_name", recordedKMSKeyName,
+					"error", err,
+				)
+				continue
+			}
+			if updated {
+				result.Rewrapped++
+			}
+		}
+
+		slog.InfoContext(ctx, "rewrap progress",
+			"operation", "rewrap_by_kms_key",
+			"from_kms_key_name", recordedKMSKeyName,
+			"to_kms_key_name", s.kmsKeyName,
+			"batch", len(keys),
+			"rewrapped", result.Rewrapped,
+			"failed", result.Failed,
+		)
+		if len(keys) < batchSize {
+			return nil
+		}
+	}
+}
+
+// rewrapKey は1件の鍵をテナントのラップに使用するKMS鍵で再ラップして保存する。
+// fromKMSKeyNameは鍵に記録されたKMS鍵名で、保存時に他の処理で更新されていないかの確認に使う。
+// 再ラップ後の暗号文はテナントIDに紐づけるため、テナントIDに紐づけずにラップされていた鍵もここで移行される。
+// 取得後に他の処理で更新されていた場合は保存せずfalseを返す。
+func (s *KeyService) rewrapKey(ctx context.Context, key *domain.EncryptionKey, fromKMSKeyName string, source KMSClient) (bool, error) {
+	plainKey, err := callWithTimeout(ctx, s.kmsTimeout, func(ctx context.Context) ([]byte, error) {
+		return source.Decrypt(ctx, key.EncryptedKey, key.AAD())
+	})
+	if err != nil {
+		return false, fmt.Errorf("decrypting with source KMS key: %w", err)
+	}
+	defer clear(plainKey)
+
+	kmsKeyName, kmsClient := s.wrappingKMSKey(key.TenantID)
+	encryptedKey, err := s.kmsEncryptWith(ctx, kmsClient, plainKey, domain.TenantAAD(key.TenantID))
+	if err != nil {
+		return false, fmt.Errorf("encrypting with current KMS key: %w", err)
+	}
+
+	updated, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (bool, error) {
+		return s.repo.UpdateWrappedKey(ctx, key.ID, fromKMSKeyName, encryptedKey, kmsKeyName, true)
+	})
+	if err != nil {
+		return false, fmt.Errorf("saving rewrapped key: %w", err)
+	}
+	if !updated {
+		slog.InfoContext(ctx, "key was updated concurrently; skipped",
+			"operation", "rewrap_by_kms_key",
+			"tenant_id", key.TenantID,
+			"generation", key.Generation,
+		)
+	}
+	return updated, nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"key-management-service/internal/domain"
+)
+
+// TenantSettingsRepository はテナントごとの設定の永続化のインターフェース。
+type TenantSettingsRepository interface {
+	Find(ctx context.Context, tenantID string) (*domain.TenantSettings, error)
+	Save(ctx context.Context, settings *domain.TenantSettings) error
+	FindWithRotationInterval(ctx context.Context) ([]*domain.TenantSettings, error)
+}
+
+// TenantSettingsService はテナントごとの設定の参照と変更を提供する。
+type TenantSettingsService struct {
+	repo      TenantSettingsRepository
+	dbTimeout time.Duration
+}
+
+// NewTenantSettingsService は新しいTenantSettingsServiceを生成する。dbTimeoutが0の場合はタイムアウトを設定しない。
+func NewTenantSettingsService(repo TenantSettingsRepository, dbTimeout time.Duration) *TenantSettingsService {
+	return &TenantSettingsService{repo: repo, dbTimeout: dbTimeout}
+}
+
+// GetSettings はテナントの設定を取得する。設定が保存されていない場合は既定値（自動ローテーションなし）を返す。
+func (s *TenantSettingsService) GetSettings(ctx context.Context, tenantID string) (*domain.TenantSettings, error) {
+	ctx, span := tracer.Start(ctx, "TenantSettingsService.GetSettings",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+		),
+	)
+	defer span.End()
+
+	settings, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (*domain.TenantSettings, error) {
+		return s.repo.Find(ctx, tenantID)
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to get tenant settings",
+			"operation", "get_tenant_settings",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("finding tenant settings: %w", err)
+	}
+	if settings == nil {
+		settings = &domain.TenantSettings{TenantID: tenantID}
+	}
+	return settings, nil
+}
+
+// SetRotationInterval はテナントの自動ローテーション間隔を設定する。0で自動ローテーションを無効にする。
+func (s *TenantSettingsService) SetRotationInterval(ctx context.Context, tenantID string, interval time.Duration) (*domain.TenantSettings, error) {
+	ctx, span := tracer.Start(ctx, "TenantSettingsService.SetRotationInterval",
+		trace.WithAttributes(
+			attribute.String("tenant.id", tenantID),
+			attribute.String("rotation.interval", interval.String()),
+		),
+	)
+	defer span.End()
+
+	if interval < 0 || (interval > 0 && interval < domain.MinRotationInterval) {
+		return nil, domain.ErrInvalidRotationInterval
+	}
+
+	settings := &domain.TenantSettings{TenantID: tenantID, RotationInterval: interval}
+	if _, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, s.repo.Save(ctx, settings)
+	}); err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to save tenant settings",
+			"operation", "set_rotation_interval",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return nil, fmt.Errorf("saving tenant settings: %w", err)
+	}
+	slog.InfoContext(ctx, "rotation interval updated",
+		"operation", "set_rotation_interval",
+		"tenant_id", tenantID,
+		"rotation_interval", interval.String(),
+	)
+	return settings, nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"key-management-service/internal/domain"
+)
+
+// callWithTimeout はタイムアウト付きのコンテキストでfnを呼び出す。
+// 期限切れで失敗した場合はdomain.ErrUpstreamTimeoutでラップしたエラーを返す。
+func callWithTimeout[T any](ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	result, err := fn(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return result, fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, err)
+	}
+	return result, err
+}
+
+// kmsEncrypt はKMS_KEY_NAMEの鍵で暗号化する（kmsEncryptWithを参照）。
+func (s *KeyService) kmsEncrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return s.kmsEncryptWith(ctx, s.kmsClient, plaintext, aad)
+}
+
+// kmsEncryptWith はKMSタイムアウトを適用し、clientのKMS鍵でaadを追加認証データとして暗号化する。
+// 平文がKMSのサイズ上限を超える場合はKMSを呼び出さずにdomain.ErrPayloadTooLargeを返す。
+func (s *KeyService) kmsEncryptWith(ctx context.Context, client KMSClient, plaintext, aad []byte) ([]byte, error) {
+	if s.kmsMaxPlaintext > 0 && len(plaintext) > s.kmsMaxPlaintext {
+		slog.WarnContext(ctx, "plaintext exceeds KMS size limit",
+			"operation", "kms_encrypt",
+			"bytes", len(plaintext),
+			"limit", s.kmsMaxPlaintext,
+		)
+		return nil, fmt.Errorf("%w: plaintext is %d bytes, limit is %d", domain.ErrPayloadTooLarge, len(plaintext), s.kmsMaxPlaintext)
+	}
+	return callWithTimeout(ctx, s.kmsTimeout, func(ctx context.Context) ([]byte, error) {
+		return client.Encrypt(ctx, plaintext, aad)
+	})
+}
+
+// kmsCiphertextOverhead はKMSの暗号文に平文から加わるサイズの見積もり（ヘッダー・認証タグなど）。
+const kmsCiphertextOverhead = 1024
+
+// checkCiphertextSize は暗号文がKMSで復号できるサイズかを検証する。
+// インポートされた暗号文のように呼び出し元から受け取った値を、保存前に検証するために使用する。
+func (s *KeyService) checkCiphertextSize(ciphertext []byte) error {
+	if s.kmsMaxPlaintext <= 0 {
+		return nil
+	}
+	if limit := s.kmsMaxPlaintext + kmsCiphertextOverhead; len(ciphertext) > limit {
+		return fmt.Errorf("%w: ciphertext is %d bytes, limit is %d", domain.ErrPayloadTooLarge, len(ciphertext), limit)
+	}
+	return nil
+}
+
+// withDBTimeout はDBタイムアウトを適用して結果を返さないリポジトリ操作を呼び出す。
+func (s *KeyService) withDBTimeout(ctx context.Context, fn func(ctx context.Context) error) error {
+	_, err := callWithTimeout(ctx, s.dbTimeout, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
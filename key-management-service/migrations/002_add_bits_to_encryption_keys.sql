@@ -0,0 +1,3 @@
+-- 鍵長（ビット）カラムの追加（既存行はAES-256）
+ALTER TABLE encryption_keys
+    ADD COLUMN bits SMALLINT UNSIGNED NOT NULL DEFAULT 256 AFTER generation;
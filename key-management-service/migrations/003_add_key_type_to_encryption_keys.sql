@@ -0,0 +1,3 @@
+-- 鍵種別カラムの追加（既存行はAES）
+ALTER TABLE encryption_keys
+    ADD COLUMN key_type VARCHAR(16) NOT NULL DEFAULT 'aes' AFTER generation;
@@ -0,0 +1,4 @@
+-- 無効化の監査情報カラムの追加（無効化日時と理由）
+ALTER TABLE encryption_keys
+    ADD COLUMN disabled_at DATETIME(6) NULL AFTER status,
+    ADD COLUMN disabled_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER disabled_at;
@@ -0,0 +1,9 @@
+-- 冪等性キーの保存テーブル（同一キーの再送時に初回の結果を返すため）
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_path VARCHAR(255) NOT NULL,
+    status_code SMALLINT UNSIGNED NOT NULL,
+    response_body BLOB NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (request_path, idempotency_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
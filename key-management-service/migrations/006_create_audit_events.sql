@@ -0,0 +1,13 @@
+-- 監査イベントの保存テーブル（AUDIT_PERSIST=trueの場合のみ書き込む）
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    tenant_id VARCHAR(255) NOT NULL,
+    operation VARCHAR(64) NOT NULL,
+    generation INT UNSIGNED NOT NULL DEFAULT 0,
+    result VARCHAR(16) NOT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (id),
+    INDEX idx_audit_tenant_created (tenant_id, created_at),
+    INDEX idx_audit_tenant_operation_created (tenant_id, operation, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
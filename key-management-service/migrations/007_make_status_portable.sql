@@ -0,0 +1,5 @@
+-- status列をMySQL固有のENUMからVARCHARとCHECK制約に変更する（PostgreSQLと共通の型定義にするため）
+-- 既存の値（active/disabled）はそのまま保持される
+ALTER TABLE encryption_keys
+    MODIFY COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active',
+    ADD CONSTRAINT chk_encryption_keys_status CHECK (status IN ('active', 'disabled'));
@@ -0,0 +1,3 @@
+-- ラップに使用したKMS鍵名カラムの追加（既存行は不明のため空文字）
+ALTER TABLE encryption_keys
+    ADD COLUMN kms_key_name VARCHAR(512) NOT NULL DEFAULT '' AFTER encrypted_key;
@@ -0,0 +1,2 @@
+-- 再ラップ対象をKMS鍵名で検索するためのインデックス
+CREATE INDEX idx_kms_key_name ON encryption_keys (kms_key_name);
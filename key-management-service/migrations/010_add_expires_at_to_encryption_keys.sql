@@ -0,0 +1,3 @@
+-- 鍵の有効期限カラムの追加（既存行は期限なし）
+ALTER TABLE encryption_keys
+    ADD COLUMN expires_at DATETIME(6) NULL AFTER status;
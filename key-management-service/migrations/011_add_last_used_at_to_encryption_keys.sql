@@ -0,0 +1,3 @@
+-- 鍵の最終利用日時カラムの追加（既存行は未使用として扱う）
+ALTER TABLE encryption_keys
+    ADD COLUMN last_used_at DATETIME(6) NULL AFTER expires_at;
@@ -0,0 +1,9 @@
+-- テナントごとの設定テーブル（自動ローテーション間隔など。行がないテナントは既定の動作）
+CREATE TABLE IF NOT EXISTS tenant_settings (
+    tenant_id VARCHAR(64) NOT NULL,
+    rotation_interval_seconds BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (tenant_id),
+    INDEX idx_tenant_settings_rotation (rotation_interval_seconds)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
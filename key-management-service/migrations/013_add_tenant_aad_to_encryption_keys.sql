@@ -0,0 +1,4 @@
+-- テナントIDを追加認証データ（AAD）としてラップしたかのカラムの追加
+-- 既存行はAADなしでラップされているためFALSE（keyctl rewrapで再ラップするとTRUEになる）
+ALTER TABLE encryption_keys
+    ADD COLUMN tenant_aad BOOLEAN NOT NULL DEFAULT FALSE AFTER kms_key_name;
@@ -0,0 +1,5 @@
+-- 鍵の論理削除日時カラムの追加（既存行は削除されていない鍵として扱う）
+-- 論理削除した鍵も世代番号を占有するため、uk_tenant_generationはそのまま維持する
+ALTER TABLE encryption_keys
+    ADD COLUMN deleted_at DATETIME(6) NULL AFTER updated_at;
+CREATE INDEX idx_deleted_at ON encryption_keys (deleted_at);
@@ -0,0 +1,5 @@
+-- 非推奨（deprecated）ステータスの追加
+-- 既存の復号には使えるが、現在の鍵には選ばれない状態。status列はVARCHARのためCHECK制約のみを変更する
+ALTER TABLE encryption_keys
+    DROP CHECK chk_encryption_keys_status,
+    ADD CONSTRAINT chk_encryption_keys_status CHECK (status IN ('active', 'deprecated', 'disabled'));
@@ -0,0 +1,6 @@
+-- 冪等性キーの予約と内容の照合、期限切れの記録の削除のための変更
+-- status_code=0は初回リクエストの処理中を表す。request_hashはクエリ文字列とボディのSHA-256で、異なる内容での再利用の検出に使う。
+-- 既存の記録はrequest_hashが空のため内容を照合しない。created_atのインデックスはIDEMPOTENCY_KEY_TTLによる削除に使う
+ALTER TABLE idempotency_keys
+    ADD COLUMN request_hash CHAR(64) NOT NULL DEFAULT '' AFTER request_path,
+    ADD INDEX idx_idempotency_keys_created_at (created_at);
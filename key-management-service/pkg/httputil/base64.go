@@ -0,0 +1,28 @@
+package httputil
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidBase64 はリクエストの値がbase64として不正な場合のエラー。
+	ErrInvalidBase64 = errors.New("invalid base64")
+
+	// ErrDecodedTooShort はbase64をデコードした値が最小長に満たない場合のエラー。
+	ErrDecodedTooShort = errors.New("decoded value too short")
+)
+
+// DecodeBase64 はリクエストの値を標準のbase64としてデコードし、minLenバイト以上であることを検証する。
+// 暗号文や鍵などの機密値を扱うため、返すエラーには入力値を含めない（ログに出力しても安全）。
+func DecodeBase64(s string, minLen int) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidBase64
+	}
+	if len(decoded) < minLen {
+		return nil, fmt.Errorf("%w: %d bytes, at least %d required", ErrDecodedTooShort, len(decoded), minLen)
+	}
+	return decoded, nil
+}
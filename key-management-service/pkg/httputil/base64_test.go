@@ -0,0 +1,51 @@
+package httputil
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBase64(t *testing.T) {
+	ciphertext := make([]byte, 28)
+	for i := range ciphertext {
+		ciphertext[i] = byte(i)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		minLen  int
+		want    []byte
+		wantErr error
+	}{
+		{name: "正常", input: "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGw==", minLen: 28, want: ciphertext},
+		{name: "base64として不正", input: "not base64!!", minLen: 0, wantErr: ErrInvalidBase64},
+		{name: "パディングの不足", input: "AAE", minLen: 0, wantErr: ErrInvalidBase64},
+		{name: "base64として正しいが短すぎる", input: "AAECAw==", minLen: 28, wantErr: ErrDecodedTooShort},
+		{name: "空文字列で最小長あり", input: "", minLen: 1, wantErr: ErrDecodedTooShort},
+		{name: "空文字列で最小長なし", input: "", minLen: 0, want: []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeBase64(tt.input, tt.minLen)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("want %v, got %v", tt.wantErr, err)
+				}
+				// エラーに入力値を含めない
+				if tt.input != "" && strings.Contains(err.Error(), tt.input) {
+					t.Errorf("error must not contain the input: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("want %x, got %x", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package httputil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ETag は値の組み合わせから強いETag（引用符付き）を生成する。
+// 同じ値の組み合わせからは常に同じETagを返す。
+func ETag(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		// 区切りを入れて ("ab","c") と ("a","bc") を区別する
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// IfNoneMatch はリクエストのIf-None-MatchヘッダーがETagに一致するかを返す。
+// カンマ区切りの複数指定と"*"に対応し、GETの条件付きリクエストのため弱い比較（W/を無視）で判定する。
+func IfNoneMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// NotModified はETagを付与して304 Not Modifiedを返す。ボディは返さない。
+func NotModified(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusNotModified)
+}
@@ -0,0 +1,69 @@
+package httputil
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Negotiate はAcceptヘッダーに従い、offersのうちクライアントが受け入れる最も優先度（q値）の高いメディアタイプを返す。
+// Acceptヘッダーがない場合はoffers[0]を返し、いずれも受け入れられない場合は空文字を返す。
+// 優先度が同じ場合はoffersの順を優先する。メディアタイプごとに最も具体的な指定（type/subtype > type/* > */*）のq値を使用する。
+func Negotiate(r *http.Request, offers ...string) string {
+	header := strings.Join(r.Header.Values("Accept"), ",")
+	if strings.TrimSpace(header) == "" {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+
+	type acceptRange struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(name), "q") {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		offerType, _, _ := strings.Cut(offer, "/")
+		q, specificity := 0.0, -1
+		for _, ar := range ranges {
+			var s int
+			switch {
+			case ar.mediaType == strings.ToLower(offer):
+				s = 2
+			case ar.mediaType == strings.ToLower(offerType)+"/*":
+				s = 1
+			case ar.mediaType == "*/*":
+				s = 0
+			default:
+				continue
+			}
+			if s > specificity {
+				q, specificity = ar.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,49 @@
+package httputil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	// ErrEmptyBody はリクエストボディが空の場合のエラー。
+	ErrEmptyBody = errors.New("request body is empty")
+
+	// ErrBodyTooLarge はリクエストボディが上限を超えた場合のエラー。
+	ErrBodyTooLarge = errors.New("request body too large")
+
+	// ErrInvalidBody はリクエストボディのJSONが不正な場合のエラー。
+	ErrInvalidBody = errors.New("invalid request body")
+)
+
+// DecodeJSON はリクエストボディを厳格にJSONデコードする。
+// 未知のフィールドや後続データを含むボディは不正として扱う。
+func DecodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			return ErrBodyTooLarge
+		case errors.Is(err, io.EOF):
+			return ErrEmptyBody
+		default:
+			return fmt.Errorf("%w: %v", ErrInvalidBody, err)
+		}
+	}
+
+	// 1つのJSON値のみを許可する
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return ErrBodyTooLarge
+		}
+		return fmt.Errorf("%w: unexpected data after JSON value", ErrInvalidBody)
+	}
+	return nil
+}
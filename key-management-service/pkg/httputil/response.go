@@ -8,8 +8,12 @@ import (
 
 // ErrorResponse はエラーレスポンスの形式。
 type ErrorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+	// Details はフィールド単位の検証エラー（ValidationErrorの場合のみ）。
+	Details []FieldError `json:"details,omitempty"`
 }
 
 // JSON はJSONレスポンスを返す。
@@ -33,3 +37,14 @@ func Error(w http.ResponseWriter, status int, code string, message string) {
 		Message: message,
 	})
 }
+
+// ErrorWithIDs はリクエストIDとトレースIDを含むエラーレスポンスを返す。
+// 空のIDはレスポンスに含めない。
+func ErrorWithIDs(w http.ResponseWriter, status int, code string, message string, requestID string, traceID string) {
+	JSON(w, status, ErrorResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: requestID,
+		TraceID:   traceID,
+	})
+}
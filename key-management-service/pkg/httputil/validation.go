@@ -0,0 +1,55 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FieldError はリクエストボディのフィールド単位の検証エラー。
+// Fieldは "keys[0].wrapped_key" のようにJSON上の位置を表す。
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError はリクエストボディの検証エラーを蓄積する。
+// 最初のエラーで止めずにすべての不正なフィールドをまとめてクライアントに返すために使う。
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add はフィールドの検証エラーを追加する。
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Addf は書式付きのメッセージでフィールドの検証エラーを追加する。
+func (e *ValidationError) Addf(field, format string, args ...any) {
+	e.Add(field, fmt.Sprintf(format, args...))
+}
+
+// HasErrors は検証エラーが1件以上あるかを返す。
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+// Error はすべての検証エラーを1行にまとめた文字列を返す。
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// ErrorWithDetails はリクエストID・トレースIDとフィールド単位の検証エラーを含むエラーレスポンスを返す。
+func ErrorWithDetails(w http.ResponseWriter, status int, code string, message string, requestID string, traceID string, details []FieldError) {
+	JSON(w, status, ErrorResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: requestID,
+		TraceID:   traceID,
+		Details:   details,
+	})
+}